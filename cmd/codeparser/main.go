@@ -7,6 +7,7 @@ import (
 	"flag"
 	"goParse/internal/driver"
 	"goParse/internal/embeddings"
+	"goParse/internal/export"
 	"goParse/internal/model"
 	"goParse/internal/monitor"
 	"io/ioutil"
@@ -18,84 +19,31 @@ import (
 	"sync"
 )
 
-// GraphClient interface that both Neo4j and AGE clients implement
-type GraphClient interface {
-	Close(ctx context.Context) error
-	CreateIndexes(ctx context.Context) error
-	UpsertFile(ctx context.Context, path, language string) error
-	UpsertFunction(ctx context.Context, fn model.FunctionEntity) error
-	UpsertImport(ctx context.Context, imp model.ImportEntity) error
-	UpsertVariable(ctx context.Context, variable model.VariableEntity) error
-	UpsertType(ctx context.Context, typeEntity model.TypeEntity) error
-	UpsertInterface(ctx context.Context, iface model.InterfaceEntity) error
-	UpsertClass(ctx context.Context, class model.ClassEntity) error
-	UpsertConstant(ctx context.Context, constant model.ConstantEntity) error
-	UpsertJSXElement(ctx context.Context, jsx model.JSXElementEntity) error
-	UpsertCSSRule(ctx context.Context, css model.CSSRuleEntity) error
-	UpsertFunctionCall(ctx context.Context, call model.FunctionCallEntity) error
-	UpsertTypeUsage(ctx context.Context, usage model.TypeUsageEntity) error
-	UpsertExtends(ctx context.Context, extends model.ExtendsEntity) error
-	UpsertImplements(ctx context.Context, implements model.ImplementsEntity) error
-	UpsertReference(ctx context.Context, ref model.ReferenceEntity) error
-}
-
-var supportedExts = map[string]bool{
-	".ts":   true,
-	".tsx":  true,
-	".js":   true,
-	".jsx":  true,
-	".css":  true,
-	".scss": true,
-}
-
-// extractContent extracts content from file bytes based on line numbers
-func extractContent(fileContent []byte, startLine, endLine int) string {
-	if startLine <= 0 || endLine <= 0 {
-		return ""
-	}
-
-	lines := strings.Split(string(fileContent), "\n")
-	if startLine > len(lines) {
-		return ""
-	}
-
-	if endLine > len(lines) {
-		endLine = len(lines)
-	}
-
-	return strings.Join(lines[startLine-1:endLine], "\n")
-}
-
-var skipDirs = map[string]bool{
-	"node_modules": true,
-	"out":          true,
-	"dist":         true,
-	"build":        true,
-	".git":         true,
-	".vscode-test": true,
-	"resources":    true,
-	"coverage":     true,
-	".next":        true,
-}
-
 func main() {
 	// 1) Read command-line flags
 	var root string
 	var createIndexes bool
-	var useAGE bool
-	var useOracle bool
+	var backend string
 	var generateEmbeddings bool
+	var embeddingProvider string
 	var embeddingModel string
 	var embeddingDim int
 	var workers int
+	var batchOpts model.BatchOptions
+	var outputPath string
+	var outputFormat string
 	flag.StringVar(&root, "root", ".", "Root directory of codebase to parse (e.g. ~/projects/vscode)")
 	flag.BoolVar(&createIndexes, "create-indexes", true, "Create database indexes for better performance")
-	flag.BoolVar(&useAGE, "use-age", false, "Use Apache AGE instead of Neo4j")
-	flag.BoolVar(&useOracle, "use-oracle", false, "Use Oracle Graph instead of Neo4j")
+	flag.StringVar(&backend, "backend", "neo4j", "Graph backend to use ("+strings.Join(model.Backends(), ", ")+")")
 	flag.BoolVar(&generateEmbeddings, "embeddings", false, "Generate embeddings for code chunks")
-	flag.StringVar(&embeddingModel, "embedding-model", "text-embedding-3-small", "OpenAI embedding model to use")
-	flag.IntVar(&embeddingDim, "embedding-dim", 1536, "Embedding dimension")
+	flag.StringVar(&embeddingProvider, "embedding-provider", "openai", "Embedding provider to use ("+strings.Join(embeddings.Providers(), ", ")+")")
+	flag.StringVar(&embeddingModel, "embedding-model", "", "Embedding model to use (default depends on the provider)")
+	flag.IntVar(&embeddingDim, "embedding-dim", 0, "Embedding dimension (0 for the model's default)")
 	flag.IntVar(&workers, "workers", runtime.NumCPU(), "Number of parallel workers")
+	flag.IntVar(&batchOpts.BatchSize, "write-batch-size", model.DefaultBatchOptions.BatchSize, "Rows per batched write statement (backends with batch writes only)")
+	flag.IntVar(&batchOpts.Concurrency, "write-concurrency", model.DefaultBatchOptions.Concurrency, "Batched write transactions run in parallel")
+	flag.StringVar(&outputPath, "output", "", "Write parsed files to this path (\"-\" for stdout) instead of a graph database")
+	flag.StringVar(&outputFormat, "format", string(export.FormatNDJSON), "Output format for -output (ndjson, json)")
 	flag.Parse()
 
 	// Ensure the root path exists
@@ -103,39 +51,54 @@ func main() {
 		log.Fatalf("Root path does not exist: %v", err)
 	}
 
-	// 2) Connect to the appropriate graph database
-	ctx := context.Background()
-	var graphClient GraphClient
-	var err error
-
-	if useOracle {
-		log.Println("Using Oracle Graph database")
-		graphClient, err = model.NewOracleGraphClient()
-		if err != nil {
-			log.Fatalf("Failed to create Oracle Graph client: %v", err)
+	// In output mode every file is written to a JSON stream and no graph,
+	// embedding store or state file is touched
+	offline := outputPath != ""
+	var exporter *export.Writer
+	if offline {
+		if generateEmbeddings {
+			log.Fatalf("-embeddings cannot be combined with -output")
 		}
-	} else if useAGE {
-		log.Println("Using Apache AGE graph database")
-		graphClient, err = model.NewAGEClient()
+		format, err := export.ParseFormat(outputFormat)
 		if err != nil {
-			log.Fatalf("Failed to create AGE client: %v", err)
+			log.Fatalf("Invalid -format: %v", err)
 		}
-	} else {
-		log.Println("Using Neo4j graph database")
-		graphClient, err = model.NewNeo4jClient()
+
+		out := os.Stdout
+		if outputPath != "-" {
+			out, err = os.Create(outputPath)
+			if err != nil {
+				log.Fatalf("Failed to create output file: %v", err)
+			}
+			defer out.Close()
+		}
+		exporter, err = export.NewWriter(out, format, root)
 		if err != nil {
-			log.Fatalf("Failed to create Neo4j client: %v", err)
+			log.Fatalf("Failed to start output: %v", err)
 		}
+		log.Printf("Writing %s output to %s", format, outputPath)
 	}
 
-	defer func() {
-		if err := graphClient.Close(ctx); err != nil {
-			log.Printf("Error closing graph database driver: %v", err)
+	// 2) Connect to the appropriate graph database
+	ctx := context.Background()
+	var graphClient model.GraphClient
+	var err error
+	if !offline {
+		graphClient, err = model.NewGraphClient(backend)
+		if err != nil {
+			log.Fatalf("Failed to create graph client: %v", err)
 		}
-	}()
+		log.Printf("Using %s graph database", model.BackendDescription(backend))
+
+		defer func() {
+			if err := graphClient.Close(ctx); err != nil {
+				log.Printf("Error closing graph database driver: %v", err)
+			}
+		}()
+	}
 
 	// 3) Create indexes if requested
-	if createIndexes {
+	if createIndexes && !offline {
 		log.Println("Creating database indexes...")
 		if err := graphClient.CreateIndexes(ctx); err != nil {
 			log.Printf("Warning: Failed to create some indexes: %v", err)
@@ -144,28 +107,35 @@ func main() {
 		}
 	}
 
-	// 4) Instantiate the Tree-sitter driver
-	tsDriver := driver.NewTreeSitterDriver()
+	// 4) Instantiate the Tree-sitter driver and module resolver
+	tsDriver, err := driver.NewTreeSitterDriver()
+	if err != nil {
+		log.Fatalf("Failed to initialize Tree-sitter driver: %v", err)
+	}
+	modResolver := driver.NewModuleResolver(root)
 
 	// Initialize file tracker for resume capability
 	fileTracker := monitor.NewFileTracker(root)
-	if err := fileTracker.LoadState(); err != nil {
-		log.Printf("Warning: failed to load previous state: %v", err)
+	if !offline {
+		if err := fileTracker.LoadState(); err != nil {
+			log.Printf("Warning: failed to load previous state: %v", err)
+		}
 	}
 
 	// 5) Set up embedding generator if requested
 	var embeddingGen *embeddings.CodeEmbeddingGenerator
 	if generateEmbeddings {
-		log.Printf("Setting up embedding generation (model: %s, dim: %d)...", embeddingModel, embeddingDim)
+		log.Printf("Setting up %s embedding generation...", embeddings.ProviderDescription(embeddingProvider))
 
 		// Determine which embedding store to use based on graph database choice
-		useOracleEmbeddings := useOracle // Oracle Graph pairs with Oracle embeddings
+		useOracleEmbeddings := backend == "oracle" // Oracle Graph pairs with Oracle embeddings
 		// Neo4j and Apache AGE both pair with PostgreSQL embeddings
 
-		provider, err := embeddings.NewOpenAIProvider(embeddingModel, embeddingDim)
+		provider, err := embeddings.NewProvider(embeddingProvider, embeddingModel, embeddingDim)
 		if err != nil {
 			log.Fatalf("Failed to create embedding provider: %v", err)
 		}
+		log.Printf("Embedding dimension: %d", provider.GetDimension())
 
 		embeddingGen, err = embeddings.NewCodeEmbeddingGenerator(provider, useOracleEmbeddings)
 		if err != nil {
@@ -189,6 +159,9 @@ func main() {
 		Types         int
 		Interfaces    int
 		Classes       int
+		Members       int
+		Decorators    int
+		Components    int
 		Constants     int
 		JSXElements   int
 		CSSRules      int
@@ -196,278 +169,207 @@ func main() {
 		TypeUsages    int
 		Extends       int
 		Implements    int
+		Exports       int
+		Renders       int
+		HookUsages    int
+		ClassUsages   int
+		StyleRefs     int
 		Errors        int
-		Embeddings    int
+		ChunksStored  int
+		Removed       int
 	}{}
 	var statsMu sync.Mutex
 	var stateMu sync.Mutex
 
-	processFile := func(path string) {
-		// Convert to relative path
-		relPath, err := filepath.Rel(root, path)
-		if err != nil {
-			relPath = path
-		}
+	// walkedFile is a supported source file found while walking root.
+	type walkedFile struct {
+		path    string
+		changed bool
+	}
 
-		// Parse the file with Tree-sitter
-		pf, parseErr := tsDriver.Parse(path)
-		if parseErr != nil {
-			log.Printf("Parse error (%s): %v", relPath, parseErr)
-			statsMu.Lock()
-			stats.Errors++
-			statsMu.Unlock()
-			return
-		}
+	// countEntities adds the entities of a written file to the statistics.
+	countEntities := func(pf driver.ParsedFile) {
+		statsMu.Lock()
+		stats.Files++
+		stats.Imports += len(pf.Imports)
+		stats.Functions += len(pf.Funcs)
+		stats.Variables += len(pf.Variables)
+		stats.Types += len(pf.Types)
+		stats.Interfaces += len(pf.Interfaces)
+		stats.Classes += len(pf.Classes)
+		stats.Members += len(pf.Members)
+		stats.Decorators += len(pf.Decorators)
+		stats.Components += len(pf.Components)
+		stats.Constants += len(pf.Constants)
+		stats.JSXElements += len(pf.JSXElements)
+		stats.CSSRules += len(pf.CSSRules)
+		statsMu.Unlock()
 
-		// Update file path to relative path
-		pf.FilePath = relPath
+		log.Printf("Processed %s: %d functions, %d imports, %d types, %d classes, %d JSX elements, %d CSS rules",
+			pf.FilePath, len(pf.Funcs), len(pf.Imports), len(pf.Types), len(pf.Classes),
+			len(pf.JSXElements), len(pf.CSSRules))
+	}
 
-		// 7) Upsert the File node
-		if err := graphClient.UpsertFile(ctx, pf.FilePath, pf.Language); err != nil {
-			log.Printf("Failed to upsert file %s: %v", pf.FilePath, err)
-		} else {
-			statsMu.Lock()
-			stats.Files++
-			statsMu.Unlock()
-		}
+	// writeEntities replaces the file's previous graph contents with its File
+	// node and every entity defined in it, in a single transaction. It reports
+	// whether the transaction succeeded.
+	writeEntities := func(pf driver.ParsedFile) bool {
+		// 7) Relationships are left out here and written by writeRelationships
+		// once every changed file's entities exist
+		entities := pf
+		entities.FunctionCalls = nil
+		entities.TypeUsages = nil
+		entities.Extends = nil
+		entities.Implements = nil
+		entities.References = nil
+		entities.Exports = nil
+		entities.Renders = nil
+		entities.HookUsages = nil
+		entities.ClassUsages = nil
+		entities.StyleReferences = nil
+
+		// 8) Replace File, Imports, Functions, Variables, Types, Interfaces,
+		// Classes with their members and decorators, Components, Constants,
+		// JSX Elements and CSS Rules
+		if err := graphClient.ReplaceFileEntities(ctx, entities); err != nil {
+			log.Printf("Failed to replace entities of %s: %v", pf.FilePath, err)
+			return false
+		}
+
+		countEntities(pf)
+		return true
+	}
 
-		// 8) Upsert Imports
-		for _, imp := range pf.Imports {
-			imp.FilePath = relPath
-			if err := graphClient.UpsertImport(ctx, imp); err != nil {
-				log.Printf("Failed to upsert import %s in %s: %v", imp.Module, pf.FilePath, err)
+	// writeRelationships upserts calls, signature types, type usages,
+	// inheritance, export, render and hook edges. It runs after every file's entities exist so
+	// cross-file targets can be matched.
+	writeRelationships := func(pf driver.ParsedFile) {
+		// 9) Upsert Function Calls
+		for _, fc := range pf.FunctionCalls {
+			if err := graphClient.UpsertFunctionCall(ctx, fc); err != nil {
+				log.Printf("Failed to upsert function call %s->%s in %s: %v", fc.CallerFunc, fc.CalledFunc, pf.FilePath, err)
 			} else {
 				statsMu.Lock()
-				stats.Imports++
+				stats.FunctionCalls++
 				statsMu.Unlock()
 			}
 		}
 
-		// 9) Upsert Functions
+		// 10) Upsert parameter and return types
 		for _, fn := range pf.Funcs {
-			fn.FilePath = relPath
-			if err := graphClient.UpsertFunction(ctx, fn); err != nil {
-				log.Printf("Failed to upsert function %s in %s: %v", fn.Name, pf.FilePath, err)
-			} else {
-				statsMu.Lock()
-				stats.Functions++
-				statsMu.Unlock()
-			}
-		}
-
-		// 10) Upsert Variables
-		for _, v := range pf.Variables {
-			v.FilePath = relPath
-			if err := graphClient.UpsertVariable(ctx, v); err != nil {
-				log.Printf("Failed to upsert variable %s in %s: %v", v.Name, pf.FilePath, err)
-			} else {
-				statsMu.Lock()
-				stats.Variables++
-				statsMu.Unlock()
+			if err := graphClient.UpsertFunctionTypes(ctx, fn); err != nil {
+				log.Printf("Failed to upsert types of function %s in %s: %v", fn.Name, pf.FilePath, err)
 			}
 		}
 
-		// 11) Upsert Types
-		for _, t := range pf.Types {
-			t.FilePath = relPath
-			if err := graphClient.UpsertType(ctx, t); err != nil {
-				log.Printf("Failed to upsert type %s in %s: %v", t.Name, pf.FilePath, err)
-			} else {
-				statsMu.Lock()
-				stats.Types++
-				statsMu.Unlock()
-			}
-		}
-
-		// 12) Upsert Interfaces
-		for _, i := range pf.Interfaces {
-			i.FilePath = relPath
-			if err := graphClient.UpsertInterface(ctx, i); err != nil {
-				log.Printf("Failed to upsert interface %s in %s: %v", i.Name, pf.FilePath, err)
+		// 11) Upsert Type Usages
+		for _, tu := range pf.TypeUsages {
+			if err := graphClient.UpsertTypeUsage(ctx, tu); err != nil {
+				log.Printf("Failed to upsert type usage %s in %s: %v", tu.UsedType, pf.FilePath, err)
 			} else {
 				statsMu.Lock()
-				stats.Interfaces++
+				stats.TypeUsages++
 				statsMu.Unlock()
 			}
 		}
 
-		// 13) Upsert Classes
-		for _, c := range pf.Classes {
-			c.FilePath = relPath
-			if err := graphClient.UpsertClass(ctx, c); err != nil {
-				log.Printf("Failed to upsert class %s in %s: %v", c.Name, pf.FilePath, err)
+		// 12) Upsert Extends relationships
+		for _, e := range pf.Extends {
+			if err := graphClient.UpsertExtends(ctx, e); err != nil {
+				log.Printf("Failed to upsert extends %s->%s in %s: %v", e.ChildName, e.ParentName, pf.FilePath, err)
 			} else {
 				statsMu.Lock()
-				stats.Classes++
+				stats.Extends++
 				statsMu.Unlock()
 			}
 		}
 
-		// 14) Upsert Constants
-		for _, c := range pf.Constants {
-			c.FilePath = relPath
-			if err := graphClient.UpsertConstant(ctx, c); err != nil {
-				log.Printf("Failed to upsert constant %s in %s: %v", c.Name, pf.FilePath, err)
+		// 13) Upsert Implements relationships
+		for _, i := range pf.Implements {
+			if err := graphClient.UpsertImplements(ctx, i); err != nil {
+				log.Printf("Failed to upsert implements %s->%s in %s: %v", i.ClassName, i.InterfaceName, pf.FilePath, err)
 			} else {
 				statsMu.Lock()
-				stats.Constants++
+				stats.Implements++
 				statsMu.Unlock()
 			}
 		}
 
-		// 15) Upsert JSX Elements
-		for _, jsx := range pf.JSXElements {
-			jsx.FilePath = relPath
-			if err := graphClient.UpsertJSXElement(ctx, jsx); err != nil {
-				log.Printf("Failed to upsert JSX element %s in %s: %v", jsx.TagName, pf.FilePath, err)
+		// 14) Upsert Exports, including re-exports of other modules
+		for _, e := range pf.Exports {
+			if err := graphClient.UpsertExport(ctx, e); err != nil {
+				log.Printf("Failed to upsert export %s in %s: %v", e.Name, pf.FilePath, err)
 			} else {
 				statsMu.Lock()
-				stats.JSXElements++
+				stats.Exports++
 				statsMu.Unlock()
 			}
 		}
 
-		// 16) Upsert CSS Rules
-		for _, css := range pf.CSSRules {
-			css.FilePath = relPath
-			if err := graphClient.UpsertCSSRule(ctx, css); err != nil {
-				log.Printf("Failed to upsert CSS rule %s in %s: %v", css.Selector, pf.FilePath, err)
+		// 15) Upsert component renders and hook usages
+		for _, r := range pf.Renders {
+			if err := graphClient.UpsertRender(ctx, r); err != nil {
+				log.Printf("Failed to upsert render %s->%s in %s: %v", r.Component, r.TagName, pf.FilePath, err)
 			} else {
 				statsMu.Lock()
-				stats.CSSRules++
+				stats.Renders++
 				statsMu.Unlock()
 			}
 		}
-
-		// 17) Upsert Function Calls
-		for _, fc := range pf.FunctionCalls {
-			fc.CallerFile = relPath
-			if fc.TargetFile != "" {
-				targetRel, err := filepath.Rel(root, fc.TargetFile)
-				if err == nil {
-					fc.TargetFile = targetRel
-				}
-			}
-			if err := graphClient.UpsertFunctionCall(ctx, fc); err != nil {
-				log.Printf("Failed to upsert function call %s->%s in %s: %v", fc.CallerFunc, fc.CalledFunc, pf.FilePath, err)
+		for _, h := range pf.HookUsages {
+			if err := graphClient.UpsertHookUsage(ctx, h); err != nil {
+				log.Printf("Failed to upsert hook usage %s->%s in %s: %v", h.Component, h.Hook, pf.FilePath, err)
 			} else {
 				statsMu.Lock()
-				stats.FunctionCalls++
+				stats.HookUsages++
 				statsMu.Unlock()
 			}
 		}
 
-		// 18) Upsert Type Usages
-		for _, tu := range pf.TypeUsages {
-			tu.UsingFile = relPath
-			if err := graphClient.UpsertTypeUsage(ctx, tu); err != nil {
-				log.Printf("Failed to upsert type usage %s in %s: %v", tu.UsedType, pf.FilePath, err)
+		// 16) Upsert STYLED_BY from JSX class names to CSS rules
+		for _, u := range pf.ClassUsages {
+			if err := graphClient.UpsertClassUsage(ctx, u); err != nil {
+				log.Printf("Failed to upsert class usage %s.%s in %s: %v", u.TagName, u.ClassName, pf.FilePath, err)
 			} else {
 				statsMu.Lock()
-				stats.TypeUsages++
+				stats.ClassUsages++
 				statsMu.Unlock()
 			}
 		}
 
-		// 19) Upsert Extends relationships
-		for _, e := range pf.Extends {
-			e.FilePath = relPath
-			if err := graphClient.UpsertExtends(ctx, e); err != nil {
-				log.Printf("Failed to upsert extends %s->%s in %s: %v", e.ChildName, e.ParentName, pf.FilePath, err)
+		// 17) Upsert SCSS @include and @extend between CSS rules
+		for _, ref := range pf.StyleReferences {
+			if err := graphClient.UpsertStyleReference(ctx, ref); err != nil {
+				log.Printf("Failed to upsert @%s %s in %s: %v", ref.Kind, ref.Target, pf.FilePath, err)
 			} else {
 				statsMu.Lock()
-				stats.Extends++
+				stats.StyleRefs++
 				statsMu.Unlock()
 			}
 		}
+	}
 
-		// 20) Upsert Implements relationships
-		for _, i := range pf.Implements {
-			i.FilePath = relPath
-			if err := graphClient.UpsertImplements(ctx, i); err != nil {
-				log.Printf("Failed to upsert implements %s->%s in %s: %v", i.ClassName, i.InterfaceName, pf.FilePath, err)
-			} else {
-				statsMu.Lock()
-				stats.Implements++
-				statsMu.Unlock()
-			}
-		}
+	// finishFile embeds a written file and records its state so the next run
+	// skips it while it is unchanged.
+	finishFile := func(path string, pf driver.ParsedFile) {
+		relPath := pf.FilePath
 
-		// 21) Generate embeddings if requested
+		// 18) Generate embeddings if requested
 		if generateEmbeddings && embeddingGen != nil {
 			fileContent, err := ioutil.ReadFile(path)
 			if err != nil {
 				log.Printf("Failed to read file content for embeddings %s: %v", relPath, err)
 			} else {
-				parsedFileData := embeddings.ParsedFileData{
-					FilePath:    relPath,
-					Language:    pf.Language,
-					FileContent: string(fileContent),
-				}
-
-				for _, fn := range pf.Funcs {
-					parsedFileData.Functions = append(parsedFileData.Functions, embeddings.FunctionData{
-						Name:      fn.Name,
-						Content:   extractContent(fileContent, fn.StartLine, fn.EndLine),
-						StartLine: fn.StartLine,
-						EndLine:   fn.EndLine,
-						Signature: fn.Signature,
-						IsAsync:   fn.IsAsync,
-						IsExport:  fn.IsExport,
-					})
-				}
-
-				for _, class := range pf.Classes {
-					parsedFileData.Classes = append(parsedFileData.Classes, embeddings.ClassData{
-						Name:       class.Name,
-						Content:    extractContent(fileContent, class.StartLine, class.EndLine),
-						StartLine:  class.StartLine,
-						EndLine:    class.EndLine,
-						IsExport:   class.IsExport,
-						IsAbstract: class.IsAbstract,
-						Methods:    class.Methods,
-					})
-				}
-
-				for _, iface := range pf.Interfaces {
-					parsedFileData.Interfaces = append(parsedFileData.Interfaces, embeddings.InterfaceData{
-						Name:       iface.Name,
-						Content:    "",
-						IsExport:   iface.IsExport,
-						Properties: iface.Properties,
-					})
-				}
-
-				for _, typ := range pf.Types {
-					parsedFileData.Types = append(parsedFileData.Types, embeddings.TypeData{
-						Name:       typ.Name,
-						Definition: typ.Definition,
-						Kind:       typ.Kind,
-						IsExport:   typ.IsExport,
-					})
-				}
-
-				for _, jsx := range pf.JSXElements {
-					parsedFileData.JSXElements = append(parsedFileData.JSXElements, embeddings.JSXData{
-						TagName:             jsx.TagName,
-						ContainingComponent: jsx.ContainingComponent,
-						Props:               jsx.Props,
-						Line:                jsx.Line,
-					})
-				}
-
-				for _, imp := range pf.Imports {
-					parsedFileData.Imports = append(parsedFileData.Imports, embeddings.ImportData{
-						Module: imp.Module,
-					})
-				}
+				parsedFileData := embeddings.NewParsedFileData(pf, fileContent)
 
+				// ProcessFile replaces the file's previous chunks, re-embedding
+				// only the ones whose content changed
 				if err := embeddingGen.ProcessFile(ctx, parsedFileData); err != nil {
 					log.Printf("Failed to generate embeddings for %s: %v", relPath, err)
 				} else {
 					chunks := embeddings.CreateCodeChunks(parsedFileData)
 					statsMu.Lock()
-					stats.Embeddings += len(chunks)
+					stats.ChunksStored += len(chunks)
 					statsMu.Unlock()
 				}
 			}
@@ -482,74 +384,217 @@ func main() {
 			}
 			stateMu.Unlock()
 		}
-
-		log.Printf("Processed %s: %d functions, %d imports, %d types, %d classes, %d JSX elements, %d CSS rules",
-			relPath, len(pf.Funcs), len(pf.Imports), len(pf.Types), len(pf.Classes),
-			len(pf.JSXElements), len(pf.CSSRules))
 	}
 
-	// 5) Walk the directory tree and dispatch files to workers
-	fileChan := make(chan string, workers*2)
-	var walkWg sync.WaitGroup
-
-	// Worker goroutines
-	for i := 0; i < workers; i++ {
-		walkWg.Add(1)
-		go func() {
-			defer walkWg.Done()
-			for p := range fileChan {
-				processFile(p)
-			}
-		}()
+	// runWorkers fans the indices 0..n-1 out to the worker pool and waits.
+	runWorkers := func(n int, fn func(i int)) {
+		idxChan := make(chan int, workers*2)
+		var wg sync.WaitGroup
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range idxChan {
+					fn(i)
+				}
+			}()
+		}
+		for i := 0; i < n; i++ {
+			idxChan <- i
+		}
+		close(idxChan)
+		wg.Wait()
 	}
 
-	// Walk the filesystem and send files to be processed
+	// 6) Walk the directory tree. Unchanged files are still parsed so calls
+	// into them can be resolved, but only changed files are written.
+	var files []walkedFile
 	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
 
 		if info.IsDir() {
-			if skipDirs[info.Name()] {
+			if tsDriver.SkipDir(info.Name()) {
 				log.Printf("Skipping directory: %s", path)
 				return filepath.SkipDir
 			}
 			return nil
 		}
 
-		if !supportedExts[filepath.Ext(path)] {
+		if !tsDriver.Supports(path) {
 			return nil
 		}
 
-		changed, chErr := fileTracker.HasChanged(path)
-		if chErr != nil {
-			relPath, _ := filepath.Rel(root, path)
-			log.Printf("State check failed for %s: %v", relPath, chErr)
-			return nil
-		}
-		if !changed {
-			return nil
+		changed := true
+		if !offline {
+			var chErr error
+			changed, chErr = fileTracker.HasChanged(path)
+			if chErr != nil {
+				relPath, _ := filepath.Rel(root, path)
+				log.Printf("State check failed for %s: %v", relPath, chErr)
+				return nil
+			}
 		}
 
-		fileChan <- path
+		files = append(files, walkedFile{path: path, changed: changed})
 		return nil
 	})
 
-	close(fileChan)
-	walkWg.Wait()
-
 	if err != nil {
 		log.Fatalf("Error walking directory: %v", err)
 	}
 
+	// Remove files tracked by a previous run that no longer exist on disk. No
+	// state is loaded in output mode, so nothing is removed there.
+	for _, state := range fileTracker.GetAllStates() {
+		if _, statErr := os.Stat(state.Path); !os.IsNotExist(statErr) {
+			continue
+		}
+		relPath, relErr := filepath.Rel(root, state.Path)
+		if relErr != nil {
+			relPath = state.Path
+		}
+		if err := graphClient.DeleteFile(ctx, relPath); err != nil {
+			log.Printf("Failed to delete removed file %s: %v", relPath, err)
+			continue
+		}
+		if embeddingGen != nil {
+			if err := embeddingGen.DeleteFile(ctx, relPath); err != nil {
+				log.Printf("Failed to delete embeddings for removed file %s: %v", relPath, err)
+			}
+		}
+		fileTracker.RemoveState(state.Path)
+		stats.Removed++
+		log.Printf("Removed %s", relPath)
+	}
+
+	// Parse every file with Tree-sitter and resolve its import specifiers
+	results := make([]*driver.ParsedFile, len(files))
+	runWorkers(len(files), func(i int) {
+		pf, parseErr := tsDriver.Parse(files[i].path)
+		if parseErr != nil {
+			relPath, _ := filepath.Rel(root, files[i].path)
+			log.Printf("Parse error (%s): %v", relPath, parseErr)
+			statsMu.Lock()
+			stats.Errors++
+			statsMu.Unlock()
+			return
+		}
+		modResolver.ResolveImports(&pf)
+		results[i] = &pf
+	})
+
+	var project []driver.ParsedFile
+	var projectFiles []walkedFile
+	for i, pf := range results {
+		if pf != nil {
+			project = append(project, *pf)
+			projectFiles = append(projectFiles, files[i])
+		}
+	}
+
+	// Project-wide pass: follow exports through barrel files, then resolve
+	// calls and rendered components across module boundaries
+	reExports := driver.ResolveProjectExports(project)
+	log.Printf("Resolved %d exports to declarations in other files", reExports)
+	crossFile := driver.ResolveProjectCalls(project)
+	log.Printf("Resolved %d cross-file function calls", crossFile)
+	components := driver.ResolveProjectComponents(project)
+	log.Printf("Resolved %d component renders and hook usages across files", components)
+	styles := driver.ResolveProjectStyles(project)
+	log.Printf("Resolved %d SCSS includes and extends across files", styles)
+	implementations := driver.ResolveGoInterfaces(project)
+	log.Printf("Resolved %d implicit Go interface implementations", implementations)
+
+	// Write entities first, then relationships, for changed files only
+	var pending []int
+	for i, f := range projectFiles {
+		if f.changed {
+			project[i].RelativeTo(root)
+			pending = append(pending, i)
+		}
+	}
+	if offline {
+		// Stream every file in walk order; nothing is tracked, so there is
+		// nothing to finish afterwards
+		for _, i := range pending {
+			pf := project[i]
+			if err := exporter.Write(pf); err != nil {
+				log.Fatalf("Failed to write output: %v", err)
+			}
+			countEntities(pf)
+			stats.FunctionCalls += len(pf.FunctionCalls)
+			stats.TypeUsages += len(pf.TypeUsages)
+			stats.Extends += len(pf.Extends)
+			stats.Implements += len(pf.Implements)
+			stats.Exports += len(pf.Exports)
+			stats.Renders += len(pf.Renders)
+			stats.HookUsages += len(pf.HookUsages)
+			stats.ClassUsages += len(pf.ClassUsages)
+			stats.StyleRefs += len(pf.StyleReferences)
+		}
+		if err := exporter.Close(); err != nil {
+			log.Fatalf("Failed to write output: %v", err)
+		}
+		pending = nil
+	} else if batchWriter, ok := graphClient.(model.BatchWriter); ok {
+		// Write every changed file with set-based statements, entities before
+		// relationships across the whole batch
+		batch := make([]driver.ParsedFile, 0, len(pending))
+		for _, i := range pending {
+			batch = append(batch, project[i])
+		}
+		log.Printf("Writing %d files in batches of %d rows", len(batch), batchOpts.BatchSize)
+		if err := batchWriter.WriteParsedFiles(ctx, batch, batchOpts); err != nil {
+			log.Printf("Failed to write batch: %v", err)
+			pending = nil
+		}
+		for _, i := range pending {
+			pf := project[i]
+			countEntities(pf)
+			stats.FunctionCalls += len(pf.FunctionCalls)
+			stats.TypeUsages += len(pf.TypeUsages)
+			stats.Extends += len(pf.Extends)
+			stats.Implements += len(pf.Implements)
+			stats.Exports += len(pf.Exports)
+			stats.Renders += len(pf.Renders)
+			stats.HookUsages += len(pf.HookUsages)
+			stats.ClassUsages += len(pf.ClassUsages)
+			stats.StyleRefs += len(pf.StyleReferences)
+		}
+	} else {
+		written := make([]bool, len(pending))
+		runWorkers(len(pending), func(j int) {
+			i := pending[j]
+			written[j] = writeEntities(project[i])
+		})
+
+		// Files whose replace failed keep their state, so the next run
+		// retries them; their relationships would have no entities to join
+		var succeeded []int
+		for j, i := range pending {
+			if written[j] {
+				succeeded = append(succeeded, i)
+			}
+		}
+		pending = succeeded
+		runWorkers(len(pending), func(j int) {
+			i := pending[j]
+			writeRelationships(project[i])
+		})
+	}
+	runWorkers(len(pending), func(j int) {
+		i := pending[j]
+		finishFile(projectFiles[i].path, project[i])
+	})
+
 	// Print final statistics
-	dbType := "Neo4j"
-	if useOracle {
-		dbType = "Oracle Graph"
-	} else if useAGE {
-		dbType = "Apache AGE"
+	destination := model.BackendDescription(backend)
+	if offline {
+		destination = outputFormat + " output"
 	}
-	log.Printf("\n=== Parsing Complete (%s) ===", dbType)
+	log.Printf("\n=== Parsing Complete (%s) ===", destination)
 	log.Printf("Files parsed: %d", stats.Files)
 	log.Printf("Functions found: %d", stats.Functions)
 	log.Printf("Imports found: %d", stats.Imports)
@@ -557,6 +602,9 @@ func main() {
 	log.Printf("Types found: %d", stats.Types)
 	log.Printf("Interfaces found: %d", stats.Interfaces)
 	log.Printf("Classes found: %d", stats.Classes)
+	log.Printf("Class members found: %d", stats.Members)
+	log.Printf("Decorators found: %d", stats.Decorators)
+	log.Printf("React components found: %d", stats.Components)
 	log.Printf("Constants found: %d", stats.Constants)
 	log.Printf("JSX elements found: %d", stats.JSXElements)
 	log.Printf("CSS rules found: %d", stats.CSSRules)
@@ -564,11 +612,23 @@ func main() {
 	log.Printf("Type usages found: %d", stats.TypeUsages)
 	log.Printf("Extends relationships found: %d", stats.Extends)
 	log.Printf("Implements relationships found: %d", stats.Implements)
+	log.Printf("Exports found: %d", stats.Exports)
+	log.Printf("Component renders found: %d", stats.Renders)
+	log.Printf("Hook usages found: %d", stats.HookUsages)
+	log.Printf("CSS class usages found: %d", stats.ClassUsages)
+	log.Printf("SCSS includes and extends found: %d", stats.StyleRefs)
+	log.Printf("Files removed: %d", stats.Removed)
 	log.Printf("Parse errors: %d", stats.Errors)
 
 	if generateEmbeddings {
 		log.Printf("\n=== Embeddings ===")
-		log.Printf("Code chunks embedded: %d", stats.Embeddings)
+		log.Printf("Code chunks stored: %d", stats.ChunksStored)
+		if embeddingGen != nil {
+			// Only cache misses reach the provider; hits reuse the stored vector
+			hits, misses := embeddingGen.CacheStats()
+			log.Printf("Code chunks embedded: %d", misses)
+			log.Printf("Embedding cache hits: %d", hits)
+		}
 
 		// Print embedding statistics
 		if embeddingGen != nil {
@@ -595,7 +655,9 @@ func main() {
 	}
 
 	// Final state save
-	if err := fileTracker.SaveState(); err != nil {
-		log.Printf("Failed to save state: %v", err)
+	if !offline {
+		if err := fileTracker.SaveState(); err != nil {
+			log.Printf("Failed to save state: %v", err)
+		}
 	}
 }
@@ -10,6 +10,7 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -22,9 +23,9 @@ import (
 func main() {
 	// Command line flags
 	var root string
-	var useAGE bool
-	var useOracle bool
+	var backend string
 	var generateEmbeddings bool
+	var embeddingProvider string
 	var embeddingModel string
 	var embeddingDim int
 
@@ -32,21 +33,24 @@ func main() {
 	var enableBatch bool
 	var batchSize int
 	var batchInterval int
+	var writeOpts model.BatchOptions
 	var enableDiff bool
 	var enableGit bool
 	var apiPort int
 
 	flag.StringVar(&root, "root", ".", "Root directory of codebase to monitor")
-	flag.BoolVar(&useAGE, "use-age", false, "Use Apache AGE instead of Neo4j")
-	flag.BoolVar(&useOracle, "use-oracle", false, "Use Oracle Graph instead of Neo4j")
+	flag.StringVar(&backend, "backend", "neo4j", "Graph backend to use ("+strings.Join(model.Backends(), ", ")+")")
 	flag.BoolVar(&generateEmbeddings, "embeddings", false, "Generate embeddings for code chunks")
-	flag.StringVar(&embeddingModel, "embedding-model", "text-embedding-3-small", "OpenAI embedding model to use")
-	flag.IntVar(&embeddingDim, "embedding-dim", 1536, "Embedding dimension")
+	flag.StringVar(&embeddingProvider, "embedding-provider", "openai", "Embedding provider to use ("+strings.Join(embeddings.Providers(), ", ")+")")
+	flag.StringVar(&embeddingModel, "embedding-model", "", "Embedding model to use (default depends on the provider)")
+	flag.IntVar(&embeddingDim, "embedding-dim", 0, "Embedding dimension (0 for the model's default)")
 
 	// Enhanced feature flags
 	flag.BoolVar(&enableBatch, "enable-batch", false, "Enable batch processing")
 	flag.IntVar(&batchSize, "batch-size", 50, "Batch size for processing")
 	flag.IntVar(&batchInterval, "batch-interval", 10, "Batch flush interval in seconds")
+	flag.IntVar(&writeOpts.BatchSize, "write-batch-size", model.DefaultBatchOptions.BatchSize, "Rows per batched write statement (backends with batch writes only)")
+	flag.IntVar(&writeOpts.Concurrency, "write-concurrency", model.DefaultBatchOptions.Concurrency, "Batched write transactions run in parallel")
 	flag.BoolVar(&enableDiff, "enable-diff", false, "Enable diff analysis")
 	flag.BoolVar(&enableGit, "enable-git", false, "Enable git integration")
 	flag.IntVar(&apiPort, "api-port", 8080, "API server port (0 to disable)")
@@ -60,23 +64,11 @@ func main() {
 
 	// Initialize graph client
 	ctx := context.Background()
-	var graphClient monitor.GraphClient
-	var err error
-
-	if useOracle {
-		log.Println("Using Oracle Graph database")
-		graphClient, err = model.NewOracleGraphClient()
-	} else if useAGE {
-		log.Println("Using Apache AGE graph database")
-		graphClient, err = model.NewAGEClient()
-	} else {
-		log.Println("Using Neo4j graph database")
-		graphClient, err = model.NewNeo4jClient()
-	}
-
+	graphClient, err := model.NewGraphClient(backend)
 	if err != nil {
 		log.Fatalf("Failed to create graph client: %v", err)
 	}
+	log.Printf("Using %s graph database", model.BackendDescription(backend))
 
 	defer func() {
 		if err := graphClient.Close(ctx); err != nil {
@@ -87,14 +79,15 @@ func main() {
 	// Initialize embedding generator if requested
 	var embeddingGen *embeddings.CodeEmbeddingGenerator
 	if generateEmbeddings {
-		log.Printf("Setting up embedding generation (model: %s, dim: %d)...", embeddingModel, embeddingDim)
+		log.Printf("Setting up %s embedding generation...", embeddings.ProviderDescription(embeddingProvider))
 
-		provider, err := embeddings.NewOpenAIProvider(embeddingModel, embeddingDim)
+		provider, err := embeddings.NewProvider(embeddingProvider, embeddingModel, embeddingDim)
 		if err != nil {
 			log.Fatalf("Failed to create embedding provider: %v", err)
 		}
+		log.Printf("Embedding dimension: %d", provider.GetDimension())
 
-		embeddingGen, err = embeddings.NewCodeEmbeddingGenerator(provider, useOracle)
+		embeddingGen, err = embeddings.NewCodeEmbeddingGenerator(provider, backend == "oracle")
 		if err != nil {
 			log.Fatalf("Failed to create embedding generator: %v", err)
 		}
@@ -107,6 +100,7 @@ func main() {
 			RootPath:     root,
 			GraphClient:  graphClient,
 			EmbeddingGen: embeddingGen,
+			BatchOptions: writeOpts,
 		},
 		EnableBatching:     enableBatch,
 		BatchSize:          batchSize,
@@ -8,6 +8,7 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
 	"goParse/internal/embeddings"
@@ -15,41 +16,20 @@ import (
 	"goParse/internal/monitor"
 )
 
-// GraphClient interface that both Neo4j, AGE, and Oracle clients implement
-type GraphClient interface {
-	Close(ctx context.Context) error
-	CreateIndexes(ctx context.Context) error
-	UpsertFile(ctx context.Context, path, language string) error
-	UpsertFunction(ctx context.Context, fn model.FunctionEntity) error
-	UpsertImport(ctx context.Context, imp model.ImportEntity) error
-	UpsertVariable(ctx context.Context, variable model.VariableEntity) error
-	UpsertType(ctx context.Context, typeEntity model.TypeEntity) error
-	UpsertInterface(ctx context.Context, iface model.InterfaceEntity) error
-	UpsertClass(ctx context.Context, class model.ClassEntity) error
-	UpsertConstant(ctx context.Context, constant model.ConstantEntity) error
-	UpsertJSXElement(ctx context.Context, jsx model.JSXElementEntity) error
-	UpsertCSSRule(ctx context.Context, css model.CSSRuleEntity) error
-	UpsertFunctionCall(ctx context.Context, call model.FunctionCallEntity) error
-	UpsertTypeUsage(ctx context.Context, usage model.TypeUsageEntity) error
-	UpsertExtends(ctx context.Context, extends model.ExtendsEntity) error
-	UpsertImplements(ctx context.Context, implements model.ImplementsEntity) error
-	UpsertReference(ctx context.Context, ref model.ReferenceEntity) error
-}
-
 func main() {
 	var root string
-	var useAGE bool
-	var useOracle bool
+	var backend string
 	var generateEmbeddings bool
+	var embeddingProvider string
 	var embeddingModel string
 	var embeddingDim int
 
 	flag.StringVar(&root, "root", ".", "Root directory of codebase to monitor")
-	flag.BoolVar(&useAGE, "use-age", false, "Use Apache AGE instead of Neo4j")
-	flag.BoolVar(&useOracle, "use-oracle", false, "Use Oracle Graph instead of Neo4j")
+	flag.StringVar(&backend, "backend", "neo4j", "Graph backend to use ("+strings.Join(model.Backends(), ", ")+")")
 	flag.BoolVar(&generateEmbeddings, "embeddings", false, "Generate embeddings for code chunks")
-	flag.StringVar(&embeddingModel, "embedding-model", "text-embedding-3-small", "OpenAI embedding model to use")
-	flag.IntVar(&embeddingDim, "embedding-dim", 1536, "Embedding dimension")
+	flag.StringVar(&embeddingProvider, "embedding-provider", "openai", "Embedding provider to use ("+strings.Join(embeddings.Providers(), ", ")+")")
+	flag.StringVar(&embeddingModel, "embedding-model", "", "Embedding model to use (default depends on the provider)")
+	flag.IntVar(&embeddingDim, "embedding-dim", 0, "Embedding dimension (0 for the model's default)")
 	flag.Parse()
 
 	// Ensure the root path exists
@@ -59,28 +39,11 @@ func main() {
 
 	// Initialize graph client
 	ctx := context.Background()
-	var graphClient monitor.GraphClient
-	var err error
-
-	if useOracle {
-		log.Println("Using Oracle Graph database")
-		graphClient, err = model.NewOracleGraphClient()
-		if err != nil {
-			log.Fatalf("Failed to create Oracle Graph client: %v", err)
-		}
-	} else if useAGE {
-		log.Println("Using Apache AGE graph database")
-		graphClient, err = model.NewAGEClient()
-		if err != nil {
-			log.Fatalf("Failed to create AGE client: %v", err)
-		}
-	} else {
-		log.Println("Using Neo4j graph database")
-		graphClient, err = model.NewNeo4jClient()
-		if err != nil {
-			log.Fatalf("Failed to create Neo4j client: %v", err)
-		}
+	graphClient, err := model.NewGraphClient(backend)
+	if err != nil {
+		log.Fatalf("Failed to create graph client: %v", err)
 	}
+	log.Printf("Using %s graph database", model.BackendDescription(backend))
 
 	defer func() {
 		if err := graphClient.Close(ctx); err != nil {
@@ -91,14 +54,15 @@ func main() {
 	// Initialize embedding generator if requested
 	var embeddingGen *embeddings.CodeEmbeddingGenerator
 	if generateEmbeddings {
-		log.Printf("Setting up embedding generation (model: %s, dim: %d)...", embeddingModel, embeddingDim)
+		log.Printf("Setting up %s embedding generation...", embeddings.ProviderDescription(embeddingProvider))
 
-		provider, err := embeddings.NewOpenAIProvider(embeddingModel, embeddingDim)
+		provider, err := embeddings.NewProvider(embeddingProvider, embeddingModel, embeddingDim)
 		if err != nil {
 			log.Fatalf("Failed to create embedding provider: %v", err)
 		}
+		log.Printf("Embedding dimension: %d", provider.GetDimension())
 
-		embeddingGen, err = embeddings.NewCodeEmbeddingGenerator(provider, useOracle)
+		embeddingGen, err = embeddings.NewCodeEmbeddingGenerator(provider, backend == "oracle")
 		if err != nil {
 			log.Fatalf("Failed to create embedding generator: %v", err)
 		}
@@ -0,0 +1,123 @@
+// internal/driver/decorators.go
+
+package driver
+
+import (
+	"goParse/internal/model"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// addClassDecorators adds the decorators applied to a TS/JS class. They are
+// children of the class declaration, or of the export statement wrapping it
+// when written before export.
+func (t *TreeSitterDriver) addClassDecorators(pf *ParsedFile, class model.ClassEntity, classNode *sitter.Node, src []byte) {
+	decorators := childDecorators(classNode)
+	if parent := classNode.Parent(); parent != nil && parent.Type() == "export_statement" {
+		decorators = append(childDecorators(parent), decorators...)
+	}
+	for _, node := range decorators {
+		pf.Decorators = append(pf.Decorators, t.decorator(node, src, model.DecoratorEntity{
+			TargetKind: "class",
+			TargetName: class.Name,
+			TargetLine: class.StartLine,
+			ClassName:  class.Name,
+			FilePath:   pf.FilePath,
+		}))
+	}
+}
+
+// addMemberDecorators adds the decorators applied to a class member and to the
+// parameters of a method. Fields hold their decorators as children, while a
+// method's decorators precede it in the class body.
+func (t *TreeSitterDriver) addMemberDecorators(pf *ParsedFile, member model.MemberEntity, node *sitter.Node, src []byte) {
+	target := model.DecoratorEntity{
+		TargetKind: member.Kind,
+		TargetName: member.Name,
+		TargetLine: member.StartLine,
+		ClassName:  member.ClassName,
+		FilePath:   pf.FilePath,
+	}
+	if member.Kind != "property" {
+		target.TargetKind = "method" // Constructors and accessors too
+	}
+
+	decorators := childDecorators(node)
+	for prev := node.PrevSibling(); prev != nil && prev.Type() == "decorator"; prev = prev.PrevSibling() {
+		decorators = append([]*sitter.Node{prev}, decorators...)
+	}
+	for _, decorator := range decorators {
+		pf.Decorators = append(pf.Decorators, t.decorator(decorator, src, target))
+	}
+
+	params := node.ChildByFieldName("parameters")
+	if params == nil {
+		return
+	}
+	for i := 0; i < int(params.NamedChildCount()); i++ {
+		param := params.NamedChild(i)
+		decorators := childDecorators(param)
+		if len(decorators) == 0 {
+			continue
+		}
+		paramTarget := target
+		paramTarget.TargetKind = "parameter"
+		paramTarget.Parameter = nodeText(param.ChildByFieldName("pattern"), src)
+		for _, decorator := range decorators {
+			pf.Decorators = append(pf.Decorators, t.decorator(decorator, src, paramTarget))
+		}
+	}
+}
+
+// decorator reads the name and arguments of a decorator node into target:
+// Get and [":id"] for @Get(':id'), Injectable for @Injectable.
+func (t *TreeSitterDriver) decorator(node *sitter.Node, src []byte, target model.DecoratorEntity) model.DecoratorEntity {
+	target.Line = int(node.StartPoint().Row) + 1
+	if node.NamedChildCount() == 0 {
+		return target
+	}
+
+	expr := node.NamedChild(0)
+	if expr.Type() == "call_expression" {
+		if args := expr.ChildByFieldName("arguments"); args != nil {
+			for i := 0; i < int(args.NamedChildCount()); i++ {
+				if arg := args.NamedChild(i); arg.Type() != "comment" {
+					target.Arguments = append(target.Arguments, literalArgument(arg, src))
+				}
+			}
+		}
+		expr = expr.ChildByFieldName("function")
+	}
+	target.Name = nodeText(expr, src)
+	return target
+}
+
+// literalArgument returns the value of a string literal argument without its
+// quotes, and any other argument as written.
+func literalArgument(arg *sitter.Node, src []byte) string {
+	text := nodeText(arg, src)
+	switch arg.Type() {
+	case "string":
+		return text[1 : len(text)-1]
+	case "template_string":
+		for i := 0; i < int(arg.NamedChildCount()); i++ {
+			if arg.NamedChild(i).Type() == "template_substitution" {
+				return text
+			}
+		}
+		return strings.Trim(text, "`")
+	}
+	return text
+}
+
+// childDecorators returns the decorator children of node.
+func childDecorators(node *sitter.Node) []*sitter.Node {
+	var decorators []*sitter.Node
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		if child := node.NamedChild(i); child.Type() == "decorator" {
+			decorators = append(decorators, child)
+		}
+	}
+	return decorators
+}
@@ -0,0 +1,258 @@
+// internal/driver/doc.go
+
+package driver
+
+import (
+	"goParse/internal/model"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// jsDocComment returns the JSDoc block written directly above a TS/JS
+// declaration, or nil when it has none. def is the declaration, or the
+// variable_declarator of a function assigned to a variable; the comment
+// precedes the enclosing variable declaration and export statement, and the
+// decorators of a method.
+func jsDocComment(def *sitter.Node, src []byte) *model.DocComment {
+	node := def
+	for prev := node.PrevSibling(); prev != nil && prev.Type() == "decorator"; prev = prev.PrevSibling() {
+		node = prev
+	}
+	if parent := node.Parent(); node.Type() == "variable_declarator" && parent != nil &&
+		(parent.Type() == "lexical_declaration" || parent.Type() == "variable_declaration") {
+		node = parent
+	}
+	if parent := node.Parent(); parent != nil && parent.Type() == "export_statement" {
+		node = parent
+	}
+
+	comments := leadingComments(node)
+	if len(comments) == 0 {
+		return nil
+	}
+	text := nodeText(comments[len(comments)-1], src)
+	if !strings.HasPrefix(text, "/**") || text == "/**/" {
+		return nil // Plain block and line comments are not documentation
+	}
+	return parseJSDoc(text)
+}
+
+// leadingComments returns the comments directly above node, outermost first,
+// stopping at the first blank line or code between them.
+func leadingComments(node *sitter.Node) []*sitter.Node {
+	var comments []*sitter.Node
+	row := node.StartPoint().Row
+	for prev := node.PrevSibling(); prev != nil && prev.Type() == "comment"; prev = prev.PrevSibling() {
+		if prev.EndPoint().Row+1 < row {
+			break
+		}
+		comments = append([]*sitter.Node{prev}, comments...)
+		row = prev.StartPoint().Row
+	}
+	return comments
+}
+
+// jsDocTag is a block tag of a JSDoc comment with the text following it.
+type jsDocTag struct {
+	name string
+	text string
+}
+
+// parseJSDoc splits a /** ... */ block into its description and the @param,
+// @returns, @throws, @example, @see and @deprecated tags. Types in braces are
+// kept on params and prefixed to returns and throws; other tags are ignored.
+func parseJSDoc(text string) *model.DocComment {
+	text = strings.TrimSuffix(strings.TrimPrefix(text, "/**"), "*/")
+
+	var description []string
+	var tags []jsDocTag
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimLeft(line, " \t")
+		if rest, ok := strings.CutPrefix(line, "*"); ok {
+			line = strings.TrimPrefix(rest, " ") // Keep the indentation of examples
+		}
+		if strings.HasPrefix(line, "@") {
+			name, rest, _ := strings.Cut(line[1:], " ")
+			tags = append(tags, jsDocTag{name: name, text: rest})
+			continue
+		}
+		if len(tags) > 0 {
+			tags[len(tags)-1].text += "\n" + line
+		} else {
+			description = append(description, line)
+		}
+	}
+
+	doc := &model.DocComment{Description: strings.TrimSpace(strings.Join(description, "\n"))}
+	for _, tag := range tags {
+		switch tag.name {
+		case "param", "arg", "argument":
+			doc.Params = append(doc.Params, jsDocParam(tag.text))
+		case "returns", "return":
+			doc.Returns = jsDocTypedText(tag.text)
+		case "throws", "exception":
+			doc.Throws = append(doc.Throws, jsDocTypedText(tag.text))
+		case "example":
+			doc.Examples = append(doc.Examples, strings.Trim(tag.text, "\n"))
+		case "see":
+			doc.See = append(doc.See, strings.TrimSpace(tag.text))
+		case "deprecated":
+			doc.Deprecated = true
+			doc.DeprecationNote = strings.TrimSpace(tag.text)
+		}
+	}
+
+	if doc.Description == "" && len(tags) == 0 {
+		return nil
+	}
+	return doc
+}
+
+// jsDocParam reads "{type} name - description". Optional parameters are
+// written [name] or [name=default] and keep only their name.
+func jsDocParam(text string) model.DocParam {
+	var param model.DocParam
+	param.Type, text = jsDocType(text)
+
+	name, rest, _ := strings.Cut(text, " ")
+	if strings.HasPrefix(name, "[") {
+		// Defaults may contain spaces, so read up to the closing bracket
+		end := strings.Index(text, "]")
+		if end < 0 {
+			end = len(text) - 1
+		}
+		name, rest = text[1:end], text[end+1:]
+		name, _, _ = strings.Cut(name, "=")
+	}
+	param.Name = strings.TrimSpace(name)
+
+	rest = strings.TrimSpace(rest)
+	rest = strings.TrimSpace(strings.TrimPrefix(rest, "-"))
+	param.Description = collapseSpace(rest)
+	return param
+}
+
+// jsDocTypedText renders "{type} description" as "type description".
+func jsDocTypedText(text string) string {
+	typ, rest := jsDocType(text)
+	return strings.TrimSpace(typ + " " + collapseSpace(rest))
+}
+
+// jsDocType splits a leading {type} off text, braces balanced so object types
+// such as {{id: string}} stay whole.
+func jsDocType(text string) (string, string) {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "{") {
+		return "", text
+	}
+	depth := 0
+	for i, r := range text {
+		switch r {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return strings.TrimSpace(text[1:i]), strings.TrimSpace(text[i+1:])
+			}
+		}
+	}
+	return "", text
+}
+
+// collapseSpace joins the lines of a tag's text with single spaces.
+func collapseSpace(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// pyDocstring returns the docstring of a Python function or class: the string
+// literal opening its body, with its common indentation removed.
+func pyDocstring(def *sitter.Node, src []byte) *model.DocComment {
+	body := def.ChildByFieldName("body")
+	if body == nil || body.NamedChildCount() == 0 {
+		return nil
+	}
+	stmt := body.NamedChild(0)
+	if stmt.Type() != "expression_statement" || stmt.NamedChildCount() != 1 || stmt.NamedChild(0).Type() != "string" {
+		return nil
+	}
+
+	text := strings.TrimLeft(nodeText(stmt.NamedChild(0), src), "rRuU")
+	for _, quote := range []string{`"""`, `'''`, `"`, `'`} {
+		if strings.HasPrefix(text, quote) && strings.HasSuffix(text, quote) && len(text) >= 2*len(quote) {
+			text = text[len(quote) : len(text)-len(quote)]
+			break
+		}
+	}
+
+	lines := strings.Split(text, "\n")
+	indent := -1
+	for _, line := range lines[1:] {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if n := len(line) - len(strings.TrimLeft(line, " \t")); indent < 0 || n < indent {
+			indent = n
+		}
+	}
+	for i := 1; i < len(lines) && indent > 0; i++ {
+		if len(lines[i]) >= indent {
+			lines[i] = lines[i][indent:]
+		}
+	}
+
+	description := strings.TrimSpace(strings.Join(lines, "\n"))
+	if description == "" {
+		return nil
+	}
+	return &model.DocComment{Description: description}
+}
+
+// goDocComment returns the // comment lines directly above a Go declaration.
+// Specs alone in their declaration, as in type T struct{}, are documented
+// above the declaration keyword. A paragraph starting "Deprecated:" marks the
+// declaration deprecated, as go doc does, and //go: directives are skipped.
+func goDocComment(node *sitter.Node, src []byte) *model.DocComment {
+	comments := leadingComments(node)
+	if parent := node.Parent(); len(comments) == 0 && parent != nil && parent.NamedChildCount() == 1 {
+		switch parent.Type() {
+		case "type_declaration", "const_declaration", "var_declaration":
+			comments = leadingComments(parent)
+		}
+	}
+
+	var lines []string
+	for _, comment := range comments {
+		text := nodeText(comment, src)
+		if block, ok := strings.CutPrefix(text, "/*"); ok {
+			lines = append(lines, strings.Split(strings.TrimSuffix(block, "*/"), "\n")...)
+			continue
+		}
+		if strings.HasPrefix(text, "//go:") || strings.HasPrefix(text, "//nolint") {
+			continue
+		}
+		text = strings.TrimPrefix(text, "//")
+		lines = append(lines, strings.TrimPrefix(text, " "))
+	}
+
+	doc := &model.DocComment{}
+	var paragraphs []string
+	for _, paragraph := range strings.Split(strings.Join(lines, "\n"), "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if note, ok := strings.CutPrefix(paragraph, "Deprecated:"); ok {
+			doc.Deprecated = true
+			doc.DeprecationNote = collapseSpace(note)
+			continue
+		}
+		if paragraph != "" {
+			paragraphs = append(paragraphs, paragraph)
+		}
+	}
+	doc.Description = strings.Join(paragraphs, "\n\n")
+
+	if doc.Description == "" && !doc.Deprecated {
+		return nil
+	}
+	return doc
+}
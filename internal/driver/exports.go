@@ -0,0 +1,120 @@
+// internal/driver/exports.go
+
+package driver
+
+import (
+	"goParse/internal/model"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// extractExports records the ES module exports of a TS/JS file: exported
+// declarations, export clauses, default exports and re-exports. Only
+// top-level export statements are module exports; those inside namespaces
+// are not.
+func (t *TreeSitterDriver) extractExports(pf *ParsedFile, src []byte, root *sitter.Node) {
+	for i := 0; i < int(root.NamedChildCount()); i++ {
+		stmt := root.NamedChild(i)
+		if stmt.Type() != "export_statement" {
+			continue
+		}
+
+		export := model.ExportEntity{
+			FilePath: pf.FilePath,
+			Line:     int(stmt.StartPoint().Row) + 1,
+		}
+		if source := stmt.ChildByFieldName("source"); source != nil {
+			export.Module = literalArgument(source, src)
+		}
+		isDefault := false
+		for j := 0; j < int(stmt.ChildCount()); j++ {
+			if stmt.Child(j).Type() == "default" {
+				isDefault = true
+			}
+		}
+
+		// export function f() {}, export default class C {}, export const a = 1
+		if decl := stmt.ChildByFieldName("declaration"); decl != nil {
+			for _, name := range declarationNames(decl, src) {
+				export.Name, export.LocalName = name, name
+				if isDefault {
+					export.Name = "default"
+				}
+				pf.Exports = append(pf.Exports, export)
+			}
+			continue
+		}
+
+		// export default expression; only a plain identifier names a binding
+		if value := stmt.ChildByFieldName("value"); value != nil {
+			export.Name = "default"
+			if value.Type() == "identifier" {
+				export.LocalName = nodeText(value, src)
+			}
+			pf.Exports = append(pf.Exports, export)
+			continue
+		}
+
+		named := false
+		for j := 0; j < int(stmt.NamedChildCount()); j++ {
+			child := stmt.NamedChild(j)
+			switch child.Type() {
+			case "export_clause":
+				// export { a, b as c } and export { a } from './a'
+				named = true
+				for k := 0; k < int(child.NamedChildCount()); k++ {
+					spec := child.NamedChild(k)
+					if spec.Type() != "export_specifier" {
+						continue
+					}
+					export.LocalName = nodeText(spec.ChildByFieldName("name"), src)
+					export.Name = export.LocalName
+					if alias := spec.ChildByFieldName("alias"); alias != nil {
+						export.Name = nodeText(alias, src)
+					}
+					pf.Exports = append(pf.Exports, export)
+				}
+			case "namespace_export":
+				// export * as ns from './a'
+				named = true
+				for k := 0; k < int(child.NamedChildCount()); k++ {
+					if ns := child.NamedChild(k); ns.Type() == "identifier" || ns.Type() == "string" {
+						export.Name = literalArgument(ns, src)
+					}
+				}
+				export.LocalName = "*"
+				pf.Exports = append(pf.Exports, export)
+			}
+		}
+
+		// export * from './a'
+		if export.Module != "" && !named {
+			export.Name, export.LocalName = "*", "*"
+			pf.Exports = append(pf.Exports, export)
+		}
+	}
+}
+
+// declarationNames returns the names bound by an exported declaration: the
+// declared name, or every variable of a const, let or var declaration.
+// Anonymous declarations and destructuring patterns bind no single name.
+func declarationNames(decl *sitter.Node, src []byte) []string {
+	if decl.Type() == "ambient_declaration" && decl.NamedChildCount() > 0 {
+		decl = decl.NamedChild(0) // export declare function f(): void
+	}
+	switch decl.Type() {
+	case "lexical_declaration", "variable_declaration":
+		var names []string
+		for i := 0; i < int(decl.NamedChildCount()); i++ {
+			declarator := decl.NamedChild(i)
+			if name := declarator.ChildByFieldName("name"); declarator.Type() == "variable_declarator" && name != nil && name.Type() == "identifier" {
+				names = append(names, nodeText(name, src))
+			}
+		}
+		return names
+	}
+	if name := decl.ChildByFieldName("name"); name != nil {
+		return []string{nodeText(name, src)}
+	}
+	return []string{""}
+}
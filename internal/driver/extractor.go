@@ -0,0 +1,131 @@
+// internal/driver/extractor.go
+
+package driver
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// LanguageExtractor extracts the entities of the files of one language. The
+// driver picks an extractor by file extension, parses the file with its
+// grammar and hands it the syntax tree; it then identifies the returned
+// entities and resolves calls, exports and rendered components itself, as for
+// the built-in languages. Extract is called from several workers at once and
+// must be safe for concurrent use.
+type LanguageExtractor interface {
+	// Extensions returns the file extensions the extractor handles, with
+	// their leading dots.
+	Extensions() []string
+
+	// Grammar returns the Tree-sitter grammar files are parsed with, or nil
+	// for a language read without one, whose Extract is given a nil tree.
+	Grammar() *sitter.Language
+
+	// SkipDirs returns the names of directories holding the language's
+	// dependencies, caches and build output, which are never walked.
+	SkipDirs() []string
+
+	// Extract returns the entities of src, the contents of the file at path,
+	// with the ParsedFile's FilePath and Language set.
+	Extract(path string, src []byte, tree *sitter.Tree) ParsedFile
+}
+
+var (
+	registryMu sync.Mutex
+	registered []LanguageExtractor
+)
+
+// Register adds an extractor to every driver created afterwards, so that a
+// package can add a language from its init function without changing the
+// driver. NewTreeSitterDriver fails when two extractors claim an extension.
+func Register(e LanguageExtractor) {
+	if e == nil {
+		panic("driver: Register extractor is nil")
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registered = append(registered, e)
+}
+
+// commonSkipDirs are directories skipped whatever languages a project uses:
+// version control, editor state and build output.
+var commonSkipDirs = []string{
+	".git", ".idea", ".vscode", ".vscode-test", "build", "coverage", "dist", "out", "resources",
+}
+
+// Skipped directories of the built-in languages
+var (
+	scriptSkipDirs = []string{"node_modules", ".next"}
+	pythonSkipDirs = []string{"__pycache__", ".pytest_cache", ".venv", "venv"}
+	goSkipDirs     = []string{"vendor"}
+)
+
+// builtinExtractor is a language the driver extracts with one of its own
+// parse methods, which share the driver's compiled queries.
+type builtinExtractor struct {
+	exts     []string
+	grammar  *sitter.Language
+	skipDirs []string
+	parse    func(pf *ParsedFile, src []byte, root *sitter.Node, lang *sitter.Language)
+}
+
+func (e builtinExtractor) Extensions() []string      { return e.exts }
+func (e builtinExtractor) Grammar() *sitter.Language { return e.grammar }
+func (e builtinExtractor) SkipDirs() []string        { return e.skipDirs }
+
+// Extract names the file's language after its extension, e.g. "ts", "tsx",
+// "js", "jsx", "css", "scss", "py" or "go", and runs the parse method.
+func (e builtinExtractor) Extract(path string, src []byte, tree *sitter.Tree) ParsedFile {
+	pf := ParsedFile{
+		FilePath: path,
+		Language: strings.TrimPrefix(filepath.Ext(path), "."),
+	}
+	var root *sitter.Node
+	if tree != nil {
+		root = tree.RootNode()
+	}
+	e.parse(&pf, src, root, e.grammar)
+	return pf
+}
+
+// register makes e the extractor of its extensions and adds its skipped
+// directories.
+func (t *TreeSitterDriver) register(e LanguageExtractor) error {
+	for _, ext := range e.Extensions() {
+		if _, ok := t.extractors[ext]; ok {
+			return fmt.Errorf("extension %s has more than one language extractor", ext)
+		}
+		t.extractors[ext] = e
+	}
+	for _, name := range e.SkipDirs() {
+		t.skipDirs[name] = true
+	}
+	return nil
+}
+
+// Extensions returns the file extensions the driver can parse, sorted.
+func (t *TreeSitterDriver) Extensions() []string {
+	exts := make([]string, 0, len(t.extractors))
+	for ext := range t.extractors {
+		exts = append(exts, ext)
+	}
+	slices.Sort(exts)
+	return exts
+}
+
+// Supports reports whether the driver can parse the file at path.
+func (t *TreeSitterDriver) Supports(path string) bool {
+	return t.extractors[filepath.Ext(path)] != nil
+}
+
+// SkipDir reports whether directories with the given name are left out when
+// walking a project.
+func (t *TreeSitterDriver) SkipDir(name string) bool {
+	return t.skipDirs[name]
+}
@@ -0,0 +1,603 @@
+// internal/driver/go_driver.go
+
+package driver
+
+import (
+	"goParse/internal/model"
+	"strings"
+	"unicode"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// parseGo extracts all entities and relationships from Go files
+func (t *TreeSitterDriver) parseGo(pf *ParsedFile, src []byte, root *sitter.Node, lang *sitter.Language) {
+	// Extract imports
+	t.extractGoImports(pf, src, root, lang)
+
+	// Extract structs, interfaces and named types
+	t.extractGoTypes(pf, src, root, lang)
+
+	// Extract functions and methods
+	t.extractGoFunctions(pf, src, root, lang)
+
+	// Extract package-level constants and variables
+	t.extractGoDeclarations(pf, src, root)
+
+	// Extract function calls
+	t.extractGoFunctionCalls(pf, src, root, lang)
+}
+
+// Go extraction methods
+
+func (t *TreeSitterDriver) extractGoImports(pf *ParsedFile, src []byte, root *sitter.Node, lang *sitter.Language) {
+	qs := t.query(lang, goImportQuery)
+	for match := range t.matches(qs, root) {
+		var pathNode, specNode *sitter.Node
+		for _, capture := range match.Captures {
+			capName := qs.CaptureNameForId(capture.Index)
+			if capName == "import.path" {
+				pathNode = capture.Node
+			} else if capName == "import.spec" {
+				specNode = capture.Node
+			}
+		}
+		if pathNode == nil || specNode == nil {
+			continue
+		}
+
+		importPath := strings.Trim(string(src[pathNode.StartByte():pathNode.EndByte()]), "\"")
+		imp := model.ImportEntity{
+			Module:      importPath,
+			FilePath:    pf.FilePath,
+			IsNamespace: true,
+		}
+
+		// The package is bound under its alias, or the last path element
+		localName := importPath[strings.LastIndex(importPath, "/")+1:]
+		if nameNode := specNode.ChildByFieldName("name"); nameNode != nil {
+			localName = string(src[nameNode.StartByte():nameNode.EndByte()])
+		}
+		switch localName {
+		case "_":
+			// Side-effect import binds nothing
+		case ".":
+			imp.ImportedNames = []string{"*"}
+		default:
+			imp.ImportedNames = []string{localName}
+		}
+
+		pf.Imports = append(pf.Imports, imp)
+	}
+}
+
+func (t *TreeSitterDriver) extractGoTypes(pf *ParsedFile, src []byte, root *sitter.Node, lang *sitter.Language) {
+	qs := t.query(lang, goTypeQuery)
+	for match := range t.matches(qs, root) {
+		var nameNode, defNode *sitter.Node
+		for _, capture := range match.Captures {
+			capName := qs.CaptureNameForId(capture.Index)
+			if capName == "type.name" {
+				nameNode = capture.Node
+			} else if capName == "type.def" {
+				defNode = capture.Node
+			}
+		}
+		if nameNode == nil || defNode == nil {
+			continue
+		}
+
+		// Only package-level types; types declared inside functions are local
+		if decl := defNode.Parent(); decl == nil || decl.Parent() == nil || decl.Parent().Type() != "source_file" {
+			continue
+		}
+
+		typeName := string(src[nameNode.StartByte():nameNode.EndByte()])
+		typeNode := defNode.ChildByFieldName("type")
+		if typeNode == nil {
+			continue
+		}
+
+		switch {
+		case defNode.Type() == "type_spec" && typeNode.Type() == "struct_type":
+			// Structs are modelled as classes so they can carry methods and IMPLEMENTS edges
+			pf.Classes = append(pf.Classes, model.ClassEntity{
+				Name:          typeName,
+				QualifiedName: typeName,
+				FilePath:      pf.FilePath,
+				StartLine:     int(defNode.StartPoint().Row) + 1,
+				EndLine:       int(defNode.EndPoint().Row) + 1,
+				IsExport:      isGoExported(typeName),
+				Doc:           goDocComment(defNode, src),
+			})
+			t.extractGoFields(pf, typeName, typeNode, src)
+
+		case defNode.Type() == "type_spec" && typeNode.Type() == "interface_type":
+			iface := model.InterfaceEntity{
+				Name:     typeName,
+				FilePath: pf.FilePath,
+				IsExport: isGoExported(typeName),
+				Doc:      goDocComment(defNode, src),
+			}
+
+			// Properties hold method specs as written; embedded interfaces become EXTENDS
+			for i := 0; i < int(typeNode.NamedChildCount()); i++ {
+				elem := typeNode.NamedChild(i)
+				switch elem.Type() {
+				case "method_elem", "method_spec":
+					iface.Properties = append(iface.Properties, string(src[elem.StartByte():elem.EndByte()]))
+				case "type_elem", "constraint_elem", "qualified_type", "type_identifier":
+					// Newer grammars wrap embedded interfaces in a type_elem
+					embedded := elem
+					if elem.Type() == "type_elem" || elem.Type() == "constraint_elem" {
+						if elem.NamedChildCount() != 1 {
+							continue // Union constraints are not embedded interfaces
+						}
+						embedded = elem.NamedChild(0)
+					}
+					if embedded.Type() != "type_identifier" && embedded.Type() != "qualified_type" {
+						continue
+					}
+					embeddedName := string(src[embedded.StartByte():embedded.EndByte()])
+					iface.Properties = append(iface.Properties, embeddedName)
+					pf.Extends = append(pf.Extends, model.ExtendsEntity{
+						ChildName:  typeName,
+						ParentName: embeddedName,
+						FilePath:   pf.FilePath,
+					})
+				}
+			}
+
+			pf.Interfaces = append(pf.Interfaces, iface)
+
+		default:
+			kind := "named"
+			if defNode.Type() == "type_alias" {
+				kind = "type_alias"
+			}
+			pf.Types = append(pf.Types, model.TypeEntity{
+				Name:       typeName,
+				FilePath:   pf.FilePath,
+				Kind:       kind,
+				Definition: string(src[defNode.StartByte():defNode.EndByte()]),
+				IsExport:   isGoExported(typeName),
+				Doc:        goDocComment(defNode, src),
+			})
+		}
+	}
+}
+
+func (t *TreeSitterDriver) extractGoFunctions(pf *ParsedFile, src []byte, root *sitter.Node, lang *sitter.Language) {
+	qs := t.query(lang, goFunctionQuery)
+	for match := range t.matches(qs, root) {
+		var nameNode, defNode *sitter.Node
+		for _, capture := range match.Captures {
+			capName := qs.CaptureNameForId(capture.Index)
+			if capName == "func.name" {
+				nameNode = capture.Node
+			} else if capName == "func.def" {
+				defNode = capture.Node
+			}
+		}
+		if nameNode == nil || defNode == nil {
+			continue
+		}
+
+		fnName := string(src[nameNode.StartByte():nameNode.EndByte()])
+		_, receiverType := t.goReceiver(defNode, src)
+
+		// Methods are qualified by their receiver, as in Server.Run
+		qualifiedName := fnName
+		if receiverType != "" {
+			qualifiedName = receiverType + "." + fnName
+		}
+
+		pf.Funcs = append(pf.Funcs, model.FunctionEntity{
+			Name:           fnName,
+			QualifiedName:  qualifiedName,
+			FilePath:       pf.FilePath,
+			StartLine:      int(defNode.StartPoint().Row) + 1,
+			EndLine:        int(defNode.EndPoint().Row) + 1,
+			Signature:      t.goSignature(defNode, src),
+			Parameters:     t.goParameters(defNode.ChildByFieldName("parameters"), src),
+			ReturnType:     t.goResultType(defNode.ChildByFieldName("result"), src),
+			TypeParameters: t.goTypeParameters(defNode.ChildByFieldName("type_parameters"), src),
+			IsExport:       isGoExported(fnName),
+			Receiver:       receiverType,
+			Doc:            goDocComment(defNode, src),
+		})
+
+		// Methods declared in the same file as their struct are listed on it
+		// and become its members; ResolveGoInterfaces lists those declared
+		// elsewhere in the package.
+		if receiverType != "" {
+			for i := range pf.Classes {
+				if pf.Classes[i].Name == receiverType {
+					pf.Classes[i].Methods = append(pf.Classes[i].Methods, fnName)
+					pf.Members = append(pf.Members, model.MemberEntity{
+						Name:       fnName,
+						Kind:       "method",
+						ClassName:  receiverType,
+						FilePath:   pf.FilePath,
+						StartLine:  int(defNode.StartPoint().Row) + 1,
+						EndLine:    int(defNode.EndPoint().Row) + 1,
+						Visibility: goVisibility(fnName),
+					})
+					break
+				}
+			}
+		}
+	}
+}
+
+// extractGoFields adds the named fields of a struct as its property members.
+// Embedded fields are left out; their methods are promoted, not declared.
+func (t *TreeSitterDriver) extractGoFields(pf *ParsedFile, structName string, structType *sitter.Node, src []byte) {
+	var fields *sitter.Node
+	for i := 0; i < int(structType.NamedChildCount()); i++ {
+		if child := structType.NamedChild(i); child.Type() == "field_declaration_list" {
+			fields = child
+		}
+	}
+	if fields == nil {
+		return
+	}
+
+	for i := 0; i < int(fields.NamedChildCount()); i++ {
+		field := fields.NamedChild(i)
+		if field.Type() != "field_declaration" {
+			continue
+		}
+		var fieldType string
+		if typeNode := field.ChildByFieldName("type"); typeNode != nil {
+			fieldType = string(src[typeNode.StartByte():typeNode.EndByte()])
+		}
+
+		// One declaration may name several fields, as in x, y int
+		for j := 0; j < int(field.ChildCount()); j++ {
+			if field.FieldNameForChild(j) != "name" {
+				continue
+			}
+			nameNode := field.Child(j)
+			name := string(src[nameNode.StartByte():nameNode.EndByte()])
+			pf.Members = append(pf.Members, model.MemberEntity{
+				Name:       name,
+				Kind:       "property",
+				ClassName:  structName,
+				FilePath:   pf.FilePath,
+				StartLine:  int(field.StartPoint().Row) + 1,
+				EndLine:    int(field.EndPoint().Row) + 1,
+				Type:       fieldType,
+				Visibility: goVisibility(name),
+			})
+		}
+	}
+}
+
+// goVisibility is "public" for exported names and "private" otherwise.
+func goVisibility(name string) string {
+	if isGoExported(name) {
+		return "public"
+	}
+	return "private"
+}
+
+// goSignature returns the parameter list followed by the result, if any.
+func (t *TreeSitterDriver) goSignature(def *sitter.Node, src []byte) string {
+	params := def.ChildByFieldName("parameters")
+	if params == nil {
+		return ""
+	}
+	signature := string(src[params.StartByte():params.EndByte()])
+	if result := def.ChildByFieldName("result"); result != nil {
+		signature += " " + string(src[result.StartByte():result.EndByte()])
+	}
+	return signature
+}
+
+// goParameters reads a parameter_list, one Parameter per name so `a, b int`
+// gives two. Unnamed parameters have an empty name. The variadic parameter is
+// marked rest and typed by its element type.
+func (t *TreeSitterDriver) goParameters(params *sitter.Node, src []byte) []model.Parameter {
+	if params == nil {
+		return nil
+	}
+
+	var out []model.Parameter
+	for i := 0; i < int(params.NamedChildCount()); i++ {
+		decl := params.NamedChild(i)
+		if decl.Type() != "parameter_declaration" && decl.Type() != "variadic_parameter_declaration" {
+			continue
+		}
+		typeText := nodeText(decl.ChildByFieldName("type"), src)
+		isRest := decl.Type() == "variadic_parameter_declaration"
+
+		names := goFieldNames(decl, "name", src)
+		if len(names) == 0 {
+			names = []string{""}
+		}
+		for _, name := range names {
+			out = append(out, model.Parameter{Name: name, Type: typeText, IsRest: isRest})
+		}
+	}
+	return out
+}
+
+// goResultType returns the result of a function as written, with names
+// dropped from a result list: "(*Order, error)" for (o *Order, err error).
+func (t *TreeSitterDriver) goResultType(result *sitter.Node, src []byte) string {
+	if result == nil || result.Type() != "parameter_list" {
+		return nodeText(result, src)
+	}
+
+	var types []string
+	for _, param := range t.goParameters(result, src) {
+		types = append(types, param.Type)
+	}
+	if len(types) == 1 {
+		return types[0]
+	}
+	return "(" + strings.Join(types, ", ") + ")"
+}
+
+// goTypeParameters reads a type_parameter_list, one TypeParameter per name.
+func (t *TreeSitterDriver) goTypeParameters(typeParams *sitter.Node, src []byte) []model.TypeParameter {
+	if typeParams == nil {
+		return nil
+	}
+
+	var out []model.TypeParameter
+	for i := 0; i < int(typeParams.NamedChildCount()); i++ {
+		decl := typeParams.NamedChild(i)
+		if decl.Type() != "type_parameter_declaration" {
+			continue
+		}
+		constraint := nodeText(decl.ChildByFieldName("type"), src)
+		for _, name := range goFieldNames(decl, "name", src) {
+			out = append(out, model.TypeParameter{Name: name, Constraint: constraint})
+		}
+	}
+	return out
+}
+
+// goFieldNames returns the text of every child of node in field, for fields
+// that repeat such as the names of `a, b int`.
+func goFieldNames(node *sitter.Node, field string, src []byte) []string {
+	var names []string
+	for i := 0; i < int(node.ChildCount()); i++ {
+		if node.FieldNameForChild(i) == field {
+			names = append(names, nodeText(node.Child(i), src))
+		}
+	}
+	return names
+}
+
+// goReceiver returns the receiver variable and base type name of a method
+// declaration, e.g. ("s", "Server") for `func (s *Server[T]) Run()`.
+func (t *TreeSitterDriver) goReceiver(def *sitter.Node, src []byte) (string, string) {
+	receiver := def.ChildByFieldName("receiver")
+	if receiver == nil || receiver.NamedChildCount() == 0 {
+		return "", ""
+	}
+	param := receiver.NamedChild(0)
+
+	var varName string
+	if nameNode := param.ChildByFieldName("name"); nameNode != nil {
+		varName = string(src[nameNode.StartByte():nameNode.EndByte()])
+	}
+
+	typeNode := param.ChildByFieldName("type")
+	for typeNode != nil && typeNode.Type() != "type_identifier" {
+		switch typeNode.Type() {
+		case "pointer_type":
+			typeNode = typeNode.NamedChild(0)
+		case "generic_type":
+			typeNode = typeNode.ChildByFieldName("type")
+		default:
+			typeNode = nil
+		}
+	}
+	if typeNode == nil {
+		return varName, ""
+	}
+	return varName, string(src[typeNode.StartByte():typeNode.EndByte()])
+}
+
+// extractGoDeclarations records package-level const and var specs.
+func (t *TreeSitterDriver) extractGoDeclarations(pf *ParsedFile, src []byte, root *sitter.Node) {
+	for i := 0; i < int(root.NamedChildCount()); i++ {
+		decl := root.NamedChild(i)
+		switch decl.Type() {
+		case "const_declaration":
+			for j := 0; j < int(decl.NamedChildCount()); j++ {
+				if spec := decl.NamedChild(j); spec.Type() == "const_spec" {
+					t.addGoConstSpec(pf, src, spec)
+				}
+			}
+		case "var_declaration":
+			for _, spec := range goVarSpecs(decl) {
+				t.addGoVarSpec(pf, src, spec)
+			}
+		}
+	}
+}
+
+// goVarSpecs returns the var_spec nodes of a single or grouped var declaration.
+func goVarSpecs(decl *sitter.Node) []*sitter.Node {
+	var specs []*sitter.Node
+	for i := 0; i < int(decl.NamedChildCount()); i++ {
+		child := decl.NamedChild(i)
+		switch child.Type() {
+		case "var_spec":
+			specs = append(specs, child)
+		case "var_spec_list":
+			for j := 0; j < int(child.NamedChildCount()); j++ {
+				if spec := child.NamedChild(j); spec.Type() == "var_spec" {
+					specs = append(specs, spec)
+				}
+			}
+		}
+	}
+	return specs
+}
+
+func (t *TreeSitterDriver) addGoConstSpec(pf *ParsedFile, src []byte, spec *sitter.Node) {
+	var values []*sitter.Node
+	if valueList := spec.ChildByFieldName("value"); valueList != nil {
+		for i := 0; i < int(valueList.NamedChildCount()); i++ {
+			values = append(values, valueList.NamedChild(i))
+		}
+	}
+
+	names := goSpecNames(spec, src)
+	for i, name := range names {
+		if name == "_" {
+			continue
+		}
+		// Specs without values repeat the previous expression (iota); keep them valueless
+		var value string
+		if i < len(values) {
+			value = string(src[values[i].StartByte():values[i].EndByte()])
+		}
+		pf.Constants = append(pf.Constants, model.ConstantEntity{
+			Name:     name,
+			FilePath: pf.FilePath,
+			Value:    value,
+			Doc:      goDocComment(spec, src),
+		})
+	}
+}
+
+func (t *TreeSitterDriver) addGoVarSpec(pf *ParsedFile, src []byte, spec *sitter.Node) {
+	varType := "variable"
+	if typeNode := spec.ChildByFieldName("type"); typeNode != nil {
+		varType = string(src[typeNode.StartByte():typeNode.EndByte()])
+	}
+
+	for _, name := range goSpecNames(spec, src) {
+		if name == "_" {
+			continue
+		}
+		pf.Variables = append(pf.Variables, model.VariableEntity{
+			Name:      name,
+			FilePath:  pf.FilePath,
+			Type:      varType,
+			StartLine: int(spec.StartPoint().Row) + 1,
+			Doc:       goDocComment(spec, src),
+		})
+	}
+}
+
+// goSpecNames returns every name declared by a const or var spec. Names are
+// the spec's direct identifier children; types and values are other nodes.
+func goSpecNames(spec *sitter.Node, src []byte) []string {
+	var names []string
+	for i := 0; i < int(spec.NamedChildCount()); i++ {
+		if child := spec.NamedChild(i); child.Type() == "identifier" {
+			names = append(names, string(src[child.StartByte():child.EndByte()]))
+		}
+	}
+	return names
+}
+
+func (t *TreeSitterDriver) extractGoFunctionCalls(pf *ParsedFile, src []byte, root *sitter.Node, lang *sitter.Language) {
+	qs := t.query(lang, goCallQuery)
+	for match := range t.matches(qs, root) {
+		var fnNode, exprNode *sitter.Node
+		for _, capture := range match.Captures {
+			capName := qs.CaptureNameForId(capture.Index)
+			if capName == "call.function" {
+				fnNode = capture.Node
+			} else if capName == "call.expr" {
+				exprNode = capture.Node
+			}
+		}
+		if fnNode == nil || exprNode == nil {
+			continue
+		}
+
+		callerFunc, receiverVar, receiverType := t.goContainingFunction(exprNode, src)
+		call := model.FunctionCallEntity{
+			CallerFile:   pf.FilePath,
+			CallerFunc:   callerFunc,
+			CallLocation: int(fnNode.StartPoint().Row) + 1,
+		}
+
+		// Type arguments are a separate field of a call, as in New[int](a, b).
+		// With a single argument the grammar cannot tell New[int](a) from a
+		// conversion to a generic type, so it is read as one, and its type
+		// names the function: New or pkg.Map without their type arguments.
+		// Real conversions such as List[int](xs) then name no function and
+		// stay unresolved.
+		switch fnNode.Type() {
+		case "identifier", "type_identifier":
+			call.CalledFunc = string(src[fnNode.StartByte():fnNode.EndByte()])
+		case "qualified_type":
+			pkg, name := fnNode.ChildByFieldName("package"), fnNode.ChildByFieldName("name")
+			if pkg == nil || name == nil {
+				continue
+			}
+			call.CallContext = string(src[pkg.StartByte():pkg.EndByte()])
+			call.CalledFunc = call.CallContext + "." + string(src[name.StartByte():name.EndByte()])
+		default:
+			operand := fnNode.ChildByFieldName("operand")
+			field := fnNode.ChildByFieldName("field")
+			if field == nil {
+				continue
+			}
+			methodName := string(src[field.StartByte():field.EndByte()])
+
+			// Calls on the receiver are attributed to the receiver type
+			var context string
+			if operand != nil && operand.Type() == "identifier" {
+				context = string(src[operand.StartByte():operand.EndByte()])
+				if receiverVar != "" && context == receiverVar {
+					context = receiverType
+				}
+			}
+
+			call.CalledFunc = methodName
+			if context != "" {
+				call.CalledFunc = context + "." + methodName
+			}
+			call.CallContext = context
+		}
+
+		pf.FunctionCalls = append(pf.FunctionCalls, call)
+	}
+}
+
+// goContainingFunction returns the enclosing function or method name, plus the
+// receiver variable and type when it is a method. Function literals are
+// attributed to the declaration that contains them.
+func (t *TreeSitterDriver) goContainingFunction(node *sitter.Node, src []byte) (string, string, string) {
+	for current := node.Parent(); current != nil; current = current.Parent() {
+		switch current.Type() {
+		case "function_declaration", "method_declaration":
+			nameNode := current.ChildByFieldName("name")
+			if nameNode == nil {
+				return "", "", ""
+			}
+			receiverVar, receiverType := t.goReceiver(current, src)
+			return string(src[nameNode.StartByte():nameNode.EndByte()]), receiverVar, receiverType
+		}
+	}
+	return "", "", "" // Package-level initializer
+}
+
+// goMethodMatches reports whether fn can be a method of class. Go methods are
+// matched on their receiver; other languages have no receiver to check.
+func goMethodMatches(pf *ParsedFile, fn model.FunctionEntity, class string) bool {
+	if pf.Language != "go" {
+		return true
+	}
+	return fn.Receiver == class
+}
+
+// isGoExported reports whether name starts with an upper-case letter.
+func isGoExported(name string) bool {
+	for _, r := range name {
+		return unicode.IsUpper(r)
+	}
+	return false
+}
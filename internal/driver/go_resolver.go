@@ -0,0 +1,242 @@
+// internal/driver/go_resolver.go
+
+package driver
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/types"
+	"goParse/internal/model"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// goBuiltinInterfaces lists the method sets of predeclared interfaces that may
+// be embedded without being declared in the project.
+var goBuiltinInterfaces = map[string][]string{
+	"error": {"Error() string"},
+	"any":   {},
+}
+
+// goInterface is an interface declaration with its embedded interfaces expanded.
+type goInterface struct {
+	name     string
+	dir      string
+	specs    []string // Method specs and embedded interface names, as extracted
+	methods  map[string]string
+	resolved bool
+	complete bool // False when an embedded interface could not be found
+}
+
+// ResolveGoInterfaces runs a project-wide pass over the Go files. Methods are
+// gathered onto their receiver struct across the files of its package, and an
+// ImplementsEntity is added wherever a struct's method set (pointer receivers
+// included) satisfies a non-empty interface declared in the project. It returns
+// the number of implementations found.
+func ResolveGoInterfaces(files []ParsedFile) int {
+	type structRef struct{ file, class int }
+
+	interfaces := make(map[string][]*goInterface) // Interface name -> declarations
+	structs := make(map[string]structRef)         // dir + "." + struct name -> declaration
+	methods := make(map[string]map[string]string) // dir + "." + receiver -> method name -> signature key
+
+	for i := range files {
+		pf := &files[i]
+		if pf.Language != "go" {
+			continue
+		}
+		dir := filepath.Dir(pf.FilePath)
+
+		for j, class := range pf.Classes {
+			structs[dir+"."+class.Name] = structRef{file: i, class: j}
+		}
+		for _, iface := range pf.Interfaces {
+			interfaces[iface.Name] = append(interfaces[iface.Name], &goInterface{
+				name:  iface.Name,
+				dir:   dir,
+				specs: iface.Properties,
+			})
+		}
+		for _, fn := range pf.Funcs {
+			if fn.Receiver == "" {
+				continue
+			}
+			key := dir + "." + fn.Receiver
+			if methods[key] == nil {
+				methods[key] = make(map[string]string)
+			}
+			methods[key][fn.Name] = goSignatureKey(fn.Signature)
+		}
+	}
+
+	// List methods declared in other files of the package on their struct
+	for key, methodSet := range methods {
+		ref, ok := structs[key]
+		if !ok {
+			continue
+		}
+		class := &files[ref.file].Classes[ref.class]
+		var added []string
+		for name := range methodSet {
+			if !slices.Contains(class.Methods, name) {
+				added = append(added, name)
+			}
+		}
+		sort.Strings(added)
+		class.Methods = append(class.Methods, added...)
+	}
+
+	interfaceNames := make([]string, 0, len(interfaces))
+	for name := range interfaces {
+		interfaceNames = append(interfaceNames, name)
+	}
+	sort.Strings(interfaceNames)
+	structKeys := make([]string, 0, len(structs))
+	for key := range structs {
+		structKeys = append(structKeys, key)
+	}
+	sort.Strings(structKeys)
+
+	found := 0
+	for _, key := range structKeys {
+		ref := structs[key]
+		methodSet := methods[key]
+		if len(methodSet) == 0 {
+			continue
+		}
+		pf := &files[ref.file]
+		class := pf.Classes[ref.class]
+
+		for _, ifaceName := range interfaceNames {
+			for _, iface := range interfaces[ifaceName] {
+				resolveGoInterface(iface, interfaces)
+				if !iface.complete || len(iface.methods) == 0 || !goSatisfies(methodSet, iface.methods) {
+					continue
+				}
+				if hasImplements(pf.Implements, class.Name, iface.name) {
+					continue
+				}
+				pf.Implements = append(pf.Implements, model.ImplementsEntity{
+					ClassName:     class.Name,
+					InterfaceName: iface.name,
+					FilePath:      pf.FilePath,
+				})
+				found++
+			}
+		}
+	}
+
+	return found
+}
+
+// resolveGoInterface expands the method set of iface, following embedded
+// interfaces declared in the project or predeclared by the language.
+func resolveGoInterface(iface *goInterface, interfaces map[string][]*goInterface) {
+	if iface.resolved {
+		return
+	}
+	iface.resolved = true // Set first so embedding cycles terminate
+	iface.methods = make(map[string]string)
+	iface.complete = true
+
+	for _, spec := range iface.specs {
+		open := strings.Index(spec, "(")
+		if open > 0 {
+			iface.methods[strings.TrimSpace(spec[:open])] = goSignatureKey(spec[open:])
+			continue
+		}
+
+		if builtin, ok := goBuiltinInterfaces[spec]; ok {
+			for _, method := range builtin {
+				open := strings.Index(method, "(")
+				iface.methods[method[:open]] = goSignatureKey(method[open:])
+			}
+			continue
+		}
+
+		embedded := findGoInterface(spec, iface.dir, interfaces)
+		if embedded == nil {
+			iface.complete = false
+			continue
+		}
+		resolveGoInterface(embedded, interfaces)
+		if !embedded.complete {
+			iface.complete = false
+		}
+		for name, signature := range embedded.methods {
+			iface.methods[name] = signature
+		}
+	}
+}
+
+// findGoInterface looks up an embedded interface name. Unqualified names refer
+// to the embedding package; pkg.Name is matched against a package directory
+// with the same base name.
+func findGoInterface(ref, dir string, interfaces map[string][]*goInterface) *goInterface {
+	pkg, name, qualified := strings.Cut(ref, ".")
+	if !qualified {
+		name = ref
+	}
+	for _, candidate := range interfaces[name] {
+		if !qualified && candidate.dir == dir {
+			return candidate
+		}
+		if qualified && filepath.Base(candidate.dir) == pkg {
+			return candidate
+		}
+	}
+	return nil
+}
+
+// goSatisfies reports whether methodSet contains every method of required
+// with an identical signature.
+func goSatisfies(methodSet, required map[string]string) bool {
+	for name, signature := range required {
+		if have, ok := methodSet[name]; !ok || have != signature {
+			return false
+		}
+	}
+	return true
+}
+
+// goSignatureKey normalizes a signature such as "(p []byte) (n int, err error)"
+// to its parameter and result types, "([]byte) (int, error)", so that
+// declarations differing only in parameter names compare equal.
+func goSignatureKey(signature string) string {
+	expr, err := parser.ParseExpr("func" + signature)
+	if err != nil {
+		return signature
+	}
+	fnType, ok := expr.(*ast.FuncType)
+	if !ok {
+		return signature
+	}
+	return "(" + goFieldTypes(fnType.Params) + ") (" + goFieldTypes(fnType.Results) + ")"
+}
+
+// goFieldTypes lists the type of every field, repeating grouped declarations.
+func goFieldTypes(fields *ast.FieldList) string {
+	if fields == nil {
+		return ""
+	}
+	var typeNames []string
+	for _, field := range fields.List {
+		typeName := types.ExprString(field.Type)
+		for n := max(len(field.Names), 1); n > 0; n-- {
+			typeNames = append(typeNames, typeName)
+		}
+	}
+	return strings.Join(typeNames, ", ")
+}
+
+// hasImplements reports whether implements already links class to iface.
+func hasImplements(implements []model.ImplementsEntity, class, iface string) bool {
+	for _, impl := range implements {
+		if impl.ClassName == class && impl.InterfaceName == iface {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,263 @@
+// internal/driver/incremental.go
+
+package driver
+
+import (
+	"bytes"
+	"io/ioutil"
+	"slices"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// LineRange is a span of lines, numbered from 1 like entity lines, with End
+// included.
+type LineRange struct {
+	Start int
+	End   int
+}
+
+// Overlaps reports whether r shares a line with the span from start to end.
+func (r LineRange) Overlaps(start, end int) bool {
+	return r.Start <= end && start <= r.End
+}
+
+// fileTree is the source and syntax tree of a file's previous parse.
+type fileTree struct {
+	src  []byte
+	tree *sitter.Tree
+}
+
+// ParseIncremental is Parse for files parsed again and again, as the monitor
+// does. It keeps the source and syntax tree of each file, and on the next
+// parse edits the old tree to match the new source so that Tree-sitter only
+// reparses the edited region. Entities are still extracted from the whole
+// tree, since IDs, call resolution and exports depend on the entire file.
+//
+// The returned ranges are the runs of lines of the new source changed since
+// the previous parse, one per hunk of a line diff, so that entities between
+// two edits count as unchanged. They are empty when the contents are the
+// same, and nil on the first parse of a file, when every line is new.
+func (t *TreeSitterDriver) ParseIncremental(path string) (ParsedFile, []LineRange, error) {
+	if err := t.checkExtension(path); err != nil {
+		return ParsedFile{}, nil, err
+	}
+
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		return ParsedFile{}, nil, err
+	}
+
+	// Trees are not safe for concurrent use, so a parse takes the file's
+	// tree and a concurrent parse of the same file starts from scratch
+	t.treesMu.Lock()
+	prev, ok := t.trees[path]
+	delete(t.trees, path)
+	t.treesMu.Unlock()
+
+	var oldTree *sitter.Tree
+	var edited []LineRange
+	if ok {
+		edited = []LineRange{}
+		for _, edit := range sourceEdits(prev.src, src) {
+			edited = append(edited, editedLines(edit))
+			if prev.tree != nil {
+				prev.tree.Edit(edit)
+			}
+		}
+		oldTree = prev.tree
+	}
+
+	pf, tree, err := t.parseSource(path, src, oldTree)
+	if err != nil {
+		return pf, nil, err
+	}
+
+	t.treesMu.Lock()
+	t.trees[path] = fileTree{src: src, tree: tree}
+	t.treesMu.Unlock()
+	return pf, edited, nil
+}
+
+// Forget drops what ParseIncremental keeps of the file at path, as when the
+// file is removed. Its next parse starts from scratch.
+func (t *TreeSitterDriver) Forget(path string) {
+	t.treesMu.Lock()
+	delete(t.trees, path)
+	t.treesMu.Unlock()
+}
+
+// maxLineEdits bounds the lines sourceEdits inserts and deletes while
+// matching the old and new lines. Past it the changed region is edited as a
+// whole, which costs a larger reparse instead of a quadratic diff.
+const maxLineEdits = 256
+
+// lineHunk is a run of lines, as half-open indexes, that differs between the
+// old and new source.
+type lineHunk struct {
+	oldStart, oldEnd int
+	newStart, newEnd int
+}
+
+// sourceEdits describes the change from old to new as one edit per run of
+// changed lines, in source order. Each edit is expressed against the source
+// as the edits before it left it, which is the order tree.Edit takes them in.
+// It returns nil when the sources are the same.
+func sourceEdits(old, new []byte) []sitter.EditInput {
+	oldLines, newLines := splitLines(old), splitLines(new)
+	oldOffsets, newOffsets := lineOffsets(oldLines), lineOffsets(newLines)
+
+	var edits []sitter.EditInput
+	for _, h := range lineHunks(oldLines, newLines) {
+		start := newOffsets[h.newStart]
+		removed := old[oldOffsets[h.oldStart]:oldOffsets[h.oldEnd]]
+		startPoint := pointAt(new, start)
+		edits = append(edits, sitter.EditInput{
+			StartIndex:  uint32(start),
+			OldEndIndex: uint32(start + len(removed)),
+			NewEndIndex: uint32(newOffsets[h.newEnd]),
+			StartPoint:  startPoint,
+			OldEndPoint: pointAfter(startPoint, removed),
+			NewEndPoint: pointAt(new, newOffsets[h.newEnd]),
+		})
+	}
+	return edits
+}
+
+// splitLines splits src after each newline. The last line has no newline
+// when src does not end in one.
+func splitLines(src []byte) [][]byte {
+	lines := bytes.SplitAfter(src, []byte{'\n'})
+	if len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// lineOffsets returns the byte offset of the start of each line, followed by
+// the length of the source.
+func lineOffsets(lines [][]byte) []int {
+	offsets := make([]int, len(lines)+1)
+	for i, line := range lines {
+		offsets[i+1] = offsets[i] + len(line)
+	}
+	return offsets
+}
+
+// lineHunks returns the runs of lines that differ between old and new, in
+// order, from a shortest edit script over the lines.
+func lineHunks(old, new [][]byte) []lineHunk {
+	prefix := 0
+	for prefix < len(old) && prefix < len(new) && bytes.Equal(old[prefix], new[prefix]) {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(old)-prefix && suffix < len(new)-prefix &&
+		bytes.Equal(old[len(old)-1-suffix], new[len(new)-1-suffix]) {
+		suffix++
+	}
+	old, new = old[prefix:len(old)-suffix], new[prefix:len(new)-suffix]
+	if len(old) == 0 && len(new) == 0 {
+		return nil
+	}
+
+	matches, ok := matchLines(old, new)
+	if !ok {
+		return []lineHunk{{prefix, prefix + len(old), prefix, prefix + len(new)}}
+	}
+
+	var hunks []lineHunk
+	i, j := 0, 0
+	for _, m := range append(matches, [2]int{len(old), len(new)}) {
+		if m[0] > i || m[1] > j {
+			hunks = append(hunks, lineHunk{prefix + i, prefix + m[0], prefix + j, prefix + m[1]})
+		}
+		i, j = m[0]+1, m[1]+1
+	}
+	return hunks
+}
+
+// matchLines pairs the lines old and new keep in common, in order, using
+// Myers' algorithm. It reports false when more than maxLineEdits lines are
+// inserted and deleted.
+func matchLines(old, new [][]byte) ([][2]int, bool) {
+	n, m := len(old), len(new)
+	limit := min(n+m, maxLineEdits)
+	offset := limit + 1
+
+	// v[offset+k] is the furthest x reached on diagonal k = x - y; trace
+	// holds v as it was before each round, for walking the path back
+	v := make([]int, 2*limit+3)
+	var trace [][]int
+	for d := 0; d <= limit; d++ {
+		trace = append(trace, slices.Clone(v))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && bytes.Equal(old[x], new[y]) {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrackLines(trace, offset, n, m), true
+			}
+		}
+	}
+	return nil, false
+}
+
+// backtrackLines walks the path matchLines found from its end and returns
+// the diagonal moves on it, which are the matched lines.
+func backtrackLines(trace [][]int, offset, x, y int) [][2]int {
+	var matches [][2]int
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			matches = append(matches, [2]int{x, y})
+		}
+		x, y = prevX, prevY
+	}
+	slices.Reverse(matches)
+	return matches
+}
+
+// pointAt returns the row and byte column of offset in src.
+func pointAt(src []byte, offset int) sitter.Point {
+	return pointAfter(sitter.Point{}, src[:offset])
+}
+
+// pointAfter returns the point reached by writing text at start.
+func pointAfter(start sitter.Point, text []byte) sitter.Point {
+	rows := bytes.Count(text, []byte{'\n'})
+	if rows == 0 {
+		return sitter.Point{Row: start.Row, Column: start.Column + uint32(len(text))}
+	}
+	column := len(text) - (bytes.LastIndexByte(text, '\n') + 1)
+	return sitter.Point{Row: start.Row + uint32(rows), Column: uint32(column)}
+}
+
+// editedLines returns the lines of the new source an edit covers. An edit
+// ending at the start of a line, as when whole lines are inserted, leaves
+// that line untouched.
+func editedLines(edit sitter.EditInput) LineRange {
+	end := int(edit.NewEndPoint.Row) + 1
+	if edit.NewEndPoint.Column == 0 && edit.NewEndIndex > edit.StartIndex {
+		end--
+	}
+	return LineRange{Start: int(edit.StartPoint.Row) + 1, End: end}
+}
@@ -0,0 +1,184 @@
+// internal/driver/incremental_test.go
+
+package driver
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+func TestSourceEdits(t *testing.T) {
+	tests := []struct {
+		name   string
+		old    string
+		new    string
+		edited []LineRange
+	}{
+		{"same", "a\nb\n", "a\nb\n", nil},
+		{"both empty", "", "", nil},
+		{"changed line", "a\nb\nc\n", "a\nB\nc\n", []LineRange{{2, 2}}},
+		{"inserted lines", "a\nc\n", "a\nb1\nb2\nc\n", []LineRange{{2, 3}}},
+		{"deleted line", "a\nb\nc\n", "a\nc\n", []LineRange{{2, 2}}},
+		{"appended without newline", "a\n", "a\nb", []LineRange{{2, 2}}},
+		{"newline added at end", "a", "a\n", []LineRange{{1, 1}}},
+		{"new file", "", "a\nb\n", []LineRange{{1, 2}}},
+		{"emptied file", "a\nb\n", "", []LineRange{{1, 1}}},
+		{"two hunks", "a\nb\nc\nd\ne\n", "A\nb\nc\nd\nE\n", []LineRange{{1, 1}, {5, 5}}},
+		{"edits around an unchanged block",
+			"func a() {}\n\nfunc b() {}\n\nfunc c() {}\n",
+			"func a() { x() }\n\nfunc b() {}\n\nfunc c() { y() }\n",
+			[]LineRange{{1, 1}, {5, 5}}},
+		{"moved line", "a\nb\nc\n", "b\nc\na\n", []LineRange{{1, 1}, {3, 3}}},
+		{"repeated lines", "x\nx\nx\n", "x\ny\nx\nx\n", []LineRange{{2, 2}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			edits := sourceEdits([]byte(tt.old), []byte(tt.new))
+
+			var edited []LineRange
+			for _, edit := range edits {
+				edited = append(edited, editedLines(edit))
+			}
+			if !reflect.DeepEqual(edited, tt.edited) {
+				t.Errorf("edited lines = %v, want %v", edited, tt.edited)
+			}
+
+			checkEdits(t, tt.old, tt.new, edits)
+		})
+	}
+}
+
+func TestSourceEditsBeyondLimit(t *testing.T) {
+	var old, new strings.Builder
+	old.WriteString("keep\n")
+	new.WriteString("keep\n")
+	for i := 0; i < maxLineEdits; i++ {
+		old.WriteString("old\n")
+		new.WriteString("new\n")
+	}
+	old.WriteString("tail\n")
+	new.WriteString("tail\n")
+
+	edits := sourceEdits([]byte(old.String()), []byte(new.String()))
+	if len(edits) != 1 {
+		t.Fatalf("got %d edits, want the changed region as one", len(edits))
+	}
+	if got, want := editedLines(edits[0]), (LineRange{2, maxLineEdits + 1}); got != want {
+		t.Errorf("edited lines = %v, want %v", got, want)
+	}
+	checkEdits(t, old.String(), new.String(), edits)
+}
+
+// checkEdits applies edits to old in order and checks that each is expressed
+// against the source the edits before it left, and that they produce new.
+func checkEdits(t *testing.T, old, new string, edits []sitter.EditInput) {
+	t.Helper()
+	src := []byte(old)
+	for i, edit := range edits {
+		if got := pointAt(src, int(edit.StartIndex)); got != edit.StartPoint {
+			t.Errorf("edit %d: start point = %v, want %v", i, edit.StartPoint, got)
+		}
+		if got := pointAt(src, int(edit.OldEndIndex)); got != edit.OldEndPoint {
+			t.Errorf("edit %d: old end point = %v, want %v", i, edit.OldEndPoint, got)
+		}
+		next := []byte(new[edit.StartIndex:edit.NewEndIndex])
+		src = append(append(append([]byte{}, src[:edit.StartIndex]...), next...), src[edit.OldEndIndex:]...)
+		if got := pointAt(src, int(edit.NewEndIndex)); got != edit.NewEndPoint {
+			t.Errorf("edit %d: new end point = %v, want %v", i, edit.NewEndPoint, got)
+		}
+	}
+	if string(src) != new {
+		t.Errorf("applying the edits gives %q, want %q", src, new)
+	}
+}
+
+func TestEditedLines(t *testing.T) {
+	tests := []struct {
+		name string
+		edit sitter.EditInput
+		want LineRange
+	}{
+		{"within a line",
+			sitter.EditInput{StartIndex: 4, NewEndIndex: 6, StartPoint: sitter.Point{Row: 2, Column: 4}, NewEndPoint: sitter.Point{Row: 2, Column: 6}},
+			LineRange{3, 3}},
+		{"whole lines inserted",
+			sitter.EditInput{StartIndex: 10, NewEndIndex: 20, StartPoint: sitter.Point{Row: 1}, NewEndPoint: sitter.Point{Row: 3}},
+			LineRange{2, 3}},
+		{"deletion",
+			sitter.EditInput{StartIndex: 10, OldEndIndex: 20, NewEndIndex: 10, StartPoint: sitter.Point{Row: 1}, NewEndPoint: sitter.Point{Row: 1}},
+			LineRange{2, 2}},
+		{"ending mid line",
+			sitter.EditInput{StartIndex: 10, NewEndIndex: 20, StartPoint: sitter.Point{Row: 1}, NewEndPoint: sitter.Point{Row: 2, Column: 3}},
+			LineRange{2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := editedLines(tt.edit); got != tt.want {
+				t.Errorf("editedLines = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseIncremental(t *testing.T) {
+	d, err := NewTreeSitterDriver()
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "widget.ts")
+
+	write := func(src string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	names := func(pf ParsedFile) []string {
+		var names []string
+		for _, fn := range pf.Funcs {
+			names = append(names, fn.Name)
+		}
+		return names
+	}
+
+	write("function a() {}\n\nfunction b() {}\n\nfunction c() {}\n")
+	if _, edited, err := d.ParseIncremental(path); err != nil || edited != nil {
+		t.Fatalf("first parse: edited = %v, err = %v; want nil, nil", edited, err)
+	}
+
+	write("function a() { b() }\n\nfunction b() {}\n\nfunction d() {}\n")
+	pf, edited, err := d.ParseIncremental(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []LineRange{{1, 1}, {5, 5}}; !reflect.DeepEqual(edited, want) {
+		t.Errorf("edited = %v, want %v", edited, want)
+	}
+	if got, want := names(pf), []string{"a", "b", "d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("functions = %v, want %v", got, want)
+	}
+
+	// The edited tree must parse the same as the source parsed from scratch
+	fresh, err := d.Parse(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(pf, fresh) {
+		t.Errorf("incremental parse differs from a fresh parse:\n%+v\n%+v", pf, fresh)
+	}
+
+	_, edited, err = d.ParseIncremental(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if edited == nil || len(edited) != 0 {
+		t.Errorf("unchanged file: edited = %v, want none", edited)
+	}
+}
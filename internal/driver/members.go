@@ -0,0 +1,146 @@
+// internal/driver/members.go
+
+package driver
+
+import (
+	"goParse/internal/model"
+	"slices"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// extractClassMembers adds the methods, constructor, accessors and fields
+// declared in the body of a TS/JS class to pf, along with the properties
+// TypeScript constructors declare through their parameters. Method names are
+// listed on class.
+func (t *TreeSitterDriver) extractClassMembers(pf *ParsedFile, class *model.ClassEntity, classNode *sitter.Node, src []byte) {
+	body := classNode.ChildByFieldName("body")
+	if body == nil {
+		return
+	}
+
+	for i := 0; i < int(body.NamedChildCount()); i++ {
+		node := body.NamedChild(i)
+
+		var kind string
+		switch node.Type() {
+		case "method_definition", "method_signature", "abstract_method_signature":
+			kind = "method"
+		case "public_field_definition", "field_definition":
+			kind = "property"
+		default:
+			continue // Static blocks, index signatures, decorators and comments
+		}
+
+		// JS fields name their property "property", everything else "name"
+		nameNode := node.ChildByFieldName("name")
+		if nameNode == nil {
+			nameNode = node.ChildByFieldName("property")
+		}
+		if nameNode == nil || (nameNode.Type() != "property_identifier" && nameNode.Type() != "private_property_identifier") {
+			continue // Computed and string-literal names
+		}
+
+		member := model.MemberEntity{
+			Name:       string(src[nameNode.StartByte():nameNode.EndByte()]),
+			Kind:       kind,
+			ClassName:  class.Name,
+			FilePath:   pf.FilePath,
+			StartLine:  int(node.StartPoint().Row) + 1,
+			EndLine:    int(node.EndPoint().Row) + 1,
+			Visibility: "public",
+		}
+		if nameNode.Type() == "private_property_identifier" {
+			member.Visibility = "private" // #name
+		}
+		t.applyMemberModifiers(&member, node, src)
+
+		if kind == "property" {
+			member.Type = typeAnnotation(node.ChildByFieldName("type"), src)
+		} else if member.Name == "constructor" {
+			member.Kind = "constructor"
+		}
+
+		pf.Members = append(pf.Members, member)
+		t.addMemberDecorators(pf, member, node, src)
+		if member.Kind != "property" && !slices.Contains(class.Methods, member.Name) {
+			class.Methods = append(class.Methods, member.Name) // Once for all overloads
+		}
+
+		if member.Kind == "constructor" {
+			t.extractParameterProperties(pf, class, node, src)
+		}
+	}
+}
+
+// applyMemberModifiers sets the visibility, accessor kind and static,
+// abstract and readonly flags from the keywords preceding a member's name.
+func (t *TreeSitterDriver) applyMemberModifiers(member *model.MemberEntity, node *sitter.Node, src []byte) {
+	for i := 0; i < int(node.ChildCount()); i++ {
+		child := node.Child(i)
+		switch child.Type() {
+		case "accessibility_modifier":
+			member.Visibility = string(src[child.StartByte():child.EndByte()])
+		case "static":
+			member.IsStatic = true
+		case "abstract":
+			member.IsAbstract = true
+		case "readonly":
+			member.IsReadonly = true
+		case "get":
+			member.Kind = "getter"
+		case "set":
+			member.Kind = "setter"
+		}
+	}
+}
+
+// extractParameterProperties adds the properties declared by constructor
+// parameters carrying an accessibility modifier or readonly, as in
+// constructor(private readonly repo: Repo).
+func (t *TreeSitterDriver) extractParameterProperties(pf *ParsedFile, class *model.ClassEntity, constructor *sitter.Node, src []byte) {
+	params := constructor.ChildByFieldName("parameters")
+	if params == nil {
+		return
+	}
+
+	for i := 0; i < int(params.NamedChildCount()); i++ {
+		param := params.NamedChild(i)
+		if param.Type() != "required_parameter" && param.Type() != "optional_parameter" {
+			continue
+		}
+		pattern := param.ChildByFieldName("pattern")
+		if pattern == nil || pattern.Type() != "identifier" {
+			continue
+		}
+
+		member := model.MemberEntity{
+			Name:      string(src[pattern.StartByte():pattern.EndByte()]),
+			Kind:      "property",
+			ClassName: class.Name,
+			FilePath:  pf.FilePath,
+			StartLine: int(param.StartPoint().Row) + 1,
+			EndLine:   int(param.EndPoint().Row) + 1,
+			Type:      typeAnnotation(param.ChildByFieldName("type"), src),
+		}
+		t.applyMemberModifiers(&member, param, src)
+		if member.Visibility == "" && !member.IsReadonly {
+			continue // A plain parameter
+		}
+		if member.Visibility == "" {
+			member.Visibility = "public"
+		}
+		pf.Members = append(pf.Members, member)
+	}
+}
+
+// typeAnnotation returns the type written in a type_annotation node, without
+// its leading colon.
+func typeAnnotation(annotation *sitter.Node, src []byte) string {
+	if annotation == nil {
+		return ""
+	}
+	text := string(src[annotation.StartByte():annotation.EndByte()])
+	return strings.TrimSpace(strings.TrimPrefix(text, ":"))
+}
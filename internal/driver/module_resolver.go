@@ -0,0 +1,669 @@
+// internal/driver/module_resolver.go
+
+package driver
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// resolveExtensions are probed, in order, when a specifier names no existing file.
+var resolveExtensions = []string{".ts", ".tsx", ".d.ts", ".js", ".jsx", ".mjs", ".cjs"}
+
+// esmSourceExtensions maps emitted extensions to the TypeScript sources that produce them,
+// so `import './util.js'` in a .ts project resolves to util.ts.
+var esmSourceExtensions = map[string][]string{
+	".js":  {".ts", ".tsx"},
+	".jsx": {".tsx"},
+	".mjs": {".mts"},
+	".cjs": {".cts"},
+}
+
+// pythonSourceRoots are the project directories absolute Python imports are
+// resolved from, in order.
+var pythonSourceRoots = []string{".", "src"}
+
+// exportConditions are the package.json "exports" conditions tried, in order.
+var exportConditions = []string{"types", "import", "module", "default", "require", "node"}
+
+// ModuleResolution is the outcome of resolving a single import specifier.
+type ModuleResolution struct {
+	File    string // Project file the specifier resolves to
+	Package string // External package name when the specifier is not a project file
+}
+
+// tsConfig holds the compiler options relevant to module resolution.
+type tsConfig struct {
+	baseURL string              // Absolute directory non-relative specifiers resolve from
+	paths   map[string][]string // Path alias patterns -> target patterns
+	pathDir string              // Directory paths targets are relative to
+}
+
+// ModuleResolver maps import specifiers to files following Node/TypeScript rules:
+// extension probing, index files, package.json main/exports and tsconfig.json
+// baseUrl/paths aliases. It is safe for concurrent use.
+type ModuleResolver struct {
+	root    string
+	absRoot string
+
+	mu      sync.Mutex
+	configs map[string]*tsConfig // Directory -> nearest tsconfig (nil if none)
+	exists  map[string]bool      // Stat cache: path -> is a regular file
+}
+
+// NewModuleResolver creates a resolver for the project rooted at root.
+func NewModuleResolver(root string) *ModuleResolver {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		absRoot = root
+	}
+	return &ModuleResolver{
+		root:    root,
+		absRoot: absRoot,
+		configs: make(map[string]*tsConfig),
+		exists:  make(map[string]bool),
+	}
+}
+
+// ResolveImports fills ResolvedFile or Package on every import of pf, and
+// ResolvedFile on its re-exports and CSS Modules class usages.
+func (r *ModuleResolver) ResolveImports(pf *ParsedFile) {
+	for i := range pf.Imports {
+		imp := &pf.Imports[i]
+		if imp.IsComputed {
+			continue // The module is only known at runtime
+		}
+		res := r.Resolve(pf.FilePath, imp.Module)
+
+		// `from pkg import mod` names a submodule rather than a member of pkg
+		if filepath.Ext(pf.FilePath) == ".py" && !imp.IsNamespace && len(imp.ImportedNames) == 1 {
+			name := imp.ImportedNames[0]
+			if original, ok := imp.Aliases[name]; ok {
+				name = original
+			}
+			module := imp.Module + "." + name
+			if strings.HasSuffix(imp.Module, ".") {
+				module = imp.Module + name
+			}
+			if sub := r.Resolve(pf.FilePath, module); sub.File != "" {
+				res = sub
+			}
+		}
+		imp.ResolvedFile = res.File
+		imp.Package = res.Package
+	}
+
+	for i := range pf.Exports {
+		if export := &pf.Exports[i]; export.Module != "" {
+			export.ResolvedFile = r.Resolve(pf.FilePath, export.Module).File
+		}
+	}
+
+	for i := range pf.ClassUsages {
+		if usage := &pf.ClassUsages[i]; usage.Module != "" {
+			usage.ResolvedFile = r.Resolve(pf.FilePath, usage.Module).File
+		}
+	}
+}
+
+// Resolve maps specifier, as imported from fromFile, to a project file or an
+// external package name.
+func (r *ModuleResolver) Resolve(fromFile, specifier string) ModuleResolution {
+	if specifier == "" {
+		return ModuleResolution{}
+	}
+	fromDir := filepath.Dir(fromFile)
+
+	switch filepath.Ext(fromFile) {
+	case ".py":
+		return r.resolvePython(fromDir, specifier)
+	case ".go":
+		// Go imports name whole packages, identified by their import path
+		return ModuleResolution{Package: specifier}
+	case ".scss":
+		return r.resolveSass(fromDir, specifier)
+	}
+
+	// Relative and absolute paths are resolved against the filesystem only
+	if isRelativeSpecifier(specifier) || filepath.IsAbs(specifier) {
+		target := specifier
+		if !filepath.IsAbs(specifier) {
+			target = filepath.Join(fromDir, filepath.FromSlash(specifier))
+		}
+		return ModuleResolution{File: r.resolvePath(target)}
+	}
+
+	// tsconfig.json paths aliases, then baseUrl
+	if cfg := r.configFor(fromDir); cfg != nil {
+		if file := r.resolveAlias(cfg, specifier); file != "" {
+			return ModuleResolution{File: file}
+		}
+		if cfg.baseURL != "" {
+			if file := r.resolvePath(filepath.Join(cfg.baseURL, filepath.FromSlash(specifier))); file != "" {
+				return ModuleResolution{File: file}
+			}
+		}
+	}
+
+	// node_modules lookup; symlinked workspace packages inside the project
+	// resolve to their sources, everything else is an external package.
+	pkgName, subpath := splitPackageSpecifier(specifier)
+	if file := r.resolveWorkspacePackage(fromDir, pkgName, subpath); file != "" {
+		return ModuleResolution{File: file}
+	}
+	return ModuleResolution{Package: pkgName}
+}
+
+// resolvePath probes target as a file, with extensions, as a package directory
+// and as a directory with an index file.
+func (r *ModuleResolver) resolvePath(target string) string {
+	if file := r.resolveFile(target); file != "" {
+		return file
+	}
+	return r.resolveDirectory(target)
+}
+
+// resolveFile probes target exactly, with source extensions and with
+// TypeScript sources replacing emitted .js extensions.
+func (r *ModuleResolver) resolveFile(target string) string {
+	if r.isFile(target) {
+		return target
+	}
+	for _, ext := range resolveExtensions {
+		if r.isFile(target + ext) {
+			return target + ext
+		}
+	}
+	ext := filepath.Ext(target)
+	for _, srcExt := range esmSourceExtensions[ext] {
+		candidate := strings.TrimSuffix(target, ext) + srcExt
+		if r.isFile(candidate) {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// resolveDirectory resolves a directory through package.json, then index files.
+func (r *ModuleResolver) resolveDirectory(dir string) string {
+	if file := r.resolvePackageEntry(dir, "."); file != "" {
+		return file
+	}
+	for _, ext := range resolveExtensions {
+		index := filepath.Join(dir, "index"+ext)
+		if r.isFile(index) {
+			return index
+		}
+	}
+	return ""
+}
+
+// packageJSON is the subset of package.json used for resolution.
+type packageJSON struct {
+	Main    string          `json:"main"`
+	Module  string          `json:"module"`
+	Types   string          `json:"types"`
+	Typings string          `json:"typings"`
+	Exports json.RawMessage `json:"exports"`
+}
+
+// resolvePackageEntry resolves subpath ("." or "./x") of the package in dir
+// using its "exports" map, falling back to types/module/main for ".".
+func (r *ModuleResolver) resolvePackageEntry(dir, subpath string) string {
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return ""
+	}
+	var pkg packageJSON
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return ""
+	}
+
+	for _, target := range exportTargets(pkg.Exports, subpath) {
+		if file := r.resolveFile(filepath.Join(dir, filepath.FromSlash(target))); file != "" {
+			return file
+		}
+	}
+
+	if subpath != "." {
+		return r.resolvePath(filepath.Join(dir, filepath.FromSlash(subpath)))
+	}
+	for _, entry := range []string{pkg.Types, pkg.Typings, pkg.Module, pkg.Main} {
+		if entry == "" {
+			continue
+		}
+		if file := r.resolvePath(filepath.Join(dir, filepath.FromSlash(entry))); file != "" {
+			return file
+		}
+	}
+	return ""
+}
+
+// exportTargets lists candidate targets for subpath from a package.json
+// "exports" value, which may be a string, a subpath map or a condition map.
+func exportTargets(raw json.RawMessage, subpath string) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	var str string
+	if json.Unmarshal(raw, &str) == nil {
+		if subpath == "." {
+			return []string{str}
+		}
+		return nil
+	}
+
+	var obj map[string]json.RawMessage
+	if json.Unmarshal(raw, &obj) != nil {
+		// A fallback array applies to "." like a condition map
+		if subpath == "." {
+			return conditionTargets(raw)
+		}
+		return nil
+	}
+
+	// A subpath map has keys starting with "."; otherwise it's conditions for "."
+	isSubpathMap := false
+	for key := range obj {
+		if strings.HasPrefix(key, ".") {
+			isSubpathMap = true
+			break
+		}
+	}
+	if !isSubpathMap {
+		if subpath != "." {
+			return nil
+		}
+		return conditionTargets(raw)
+	}
+
+	if entry, ok := obj[subpath]; ok {
+		return conditionTargets(entry)
+	}
+	// Wildcard subpaths: "./*": "./src/*.ts". As in Node, the pattern with the
+	// longest prefix wins, then the longest pattern; remaining ties go to the
+	// first key in sort order, since map order is random.
+	bestKey, bestStar := "", ""
+	for key := range obj {
+		prefix, suffix, ok := strings.Cut(key, "*")
+		if !ok || len(subpath) < len(prefix)+len(suffix) ||
+			!strings.HasPrefix(subpath, prefix) || !strings.HasSuffix(subpath, suffix) {
+			continue
+		}
+		bestPrefix, _, _ := strings.Cut(bestKey, "*")
+		if bestKey == "" || len(prefix) > len(bestPrefix) ||
+			(len(prefix) == len(bestPrefix) && len(key) > len(bestKey)) ||
+			(len(prefix) == len(bestPrefix) && len(key) == len(bestKey) && key < bestKey) {
+			bestKey, bestStar = key, subpath[len(prefix):len(subpath)-len(suffix)]
+		}
+	}
+	if bestKey == "" {
+		return nil
+	}
+
+	var targets []string
+	for _, target := range conditionTargets(obj[bestKey]) {
+		targets = append(targets, strings.ReplaceAll(target, "*", bestStar))
+	}
+	return targets
+}
+
+// conditionTargets flattens a string, array or condition object into targets.
+func conditionTargets(raw json.RawMessage) []string {
+	var str string
+	if json.Unmarshal(raw, &str) == nil {
+		return []string{str}
+	}
+
+	var arr []json.RawMessage
+	if json.Unmarshal(raw, &arr) == nil {
+		var targets []string
+		for _, item := range arr {
+			targets = append(targets, conditionTargets(item)...)
+		}
+		return targets
+	}
+
+	var obj map[string]json.RawMessage
+	if json.Unmarshal(raw, &obj) != nil {
+		return nil
+	}
+	var targets []string
+	for _, cond := range exportConditions {
+		if entry, ok := obj[cond]; ok {
+			targets = append(targets, conditionTargets(entry)...)
+		}
+	}
+	return targets
+}
+
+// resolveWorkspacePackage looks pkgName up in node_modules directories above
+// fromDir. Only packages that are symlinks back into the project (monorepo
+// workspaces) resolve to files; installed dependencies stay external.
+func (r *ModuleResolver) resolveWorkspacePackage(fromDir, pkgName, subpath string) string {
+	dir := fromDir
+	for {
+		pkgDir := filepath.Join(dir, "node_modules", filepath.FromSlash(pkgName))
+		if info, err := os.Lstat(pkgDir); err == nil {
+			if info.Mode()&os.ModeSymlink == 0 {
+				return ""
+			}
+			realDir, err := filepath.EvalSymlinks(pkgDir)
+			if err != nil {
+				return ""
+			}
+			projectDir, ok := r.projectPath(realDir)
+			if !ok {
+				return ""
+			}
+			return r.resolvePackageEntry(projectDir, subpath)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// projectPath rewrites an absolute path inside the project into the same form
+// as the root the resolver was created with.
+func (r *ModuleResolver) projectPath(absPath string) (string, bool) {
+	rel, err := filepath.Rel(r.absRoot, absPath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	for _, part := range strings.Split(rel, string(filepath.Separator)) {
+		if part == "node_modules" {
+			return "", false
+		}
+	}
+	return filepath.Join(r.root, rel), true
+}
+
+// resolveAlias matches specifier against tsconfig paths patterns.
+func (r *ModuleResolver) resolveAlias(cfg *tsConfig, specifier string) string {
+	// Exact patterns win over wildcard patterns; longer prefixes win among wildcards
+	bestPattern, bestStar, bestLen := "", "", -1
+	for pattern := range cfg.paths {
+		prefix, suffix, hasStar := strings.Cut(pattern, "*")
+		if !hasStar {
+			if pattern == specifier {
+				bestPattern, bestStar = pattern, ""
+				break
+			}
+			continue
+		}
+		if len(prefix) > bestLen && len(specifier) >= len(prefix)+len(suffix) &&
+			strings.HasPrefix(specifier, prefix) && strings.HasSuffix(specifier, suffix) {
+			bestPattern, bestLen = pattern, len(prefix)
+			bestStar = specifier[len(prefix) : len(specifier)-len(suffix)]
+		}
+	}
+	if bestPattern == "" {
+		return ""
+	}
+
+	for _, target := range cfg.paths[bestPattern] {
+		target = strings.ReplaceAll(target, "*", bestStar)
+		if file := r.resolvePath(filepath.Join(cfg.pathDir, filepath.FromSlash(target))); file != "" {
+			return file
+		}
+	}
+	return ""
+}
+
+// configFor returns the nearest tsconfig.json/jsconfig.json at or above dir,
+// stopping at the project root.
+func (r *ModuleResolver) configFor(dir string) *tsConfig {
+	r.mu.Lock()
+	cfg, ok := r.configs[dir]
+	r.mu.Unlock()
+	if ok {
+		return cfg
+	}
+
+	for _, name := range []string{"tsconfig.json", "jsconfig.json"} {
+		if path := filepath.Join(dir, name); r.isFile(path) {
+			cfg = loadTSConfig(path, 0)
+			break
+		}
+	}
+	if cfg == nil {
+		parent := filepath.Dir(dir)
+		if rel, err := filepath.Rel(r.root, dir); err == nil && rel != "." && !strings.HasPrefix(rel, "..") && parent != dir {
+			cfg = r.configFor(parent)
+		}
+	}
+
+	r.mu.Lock()
+	r.configs[dir] = cfg
+	r.mu.Unlock()
+	return cfg
+}
+
+// tsConfigFile is the subset of tsconfig.json used for resolution.
+type tsConfigFile struct {
+	Extends         string `json:"extends"`
+	CompilerOptions struct {
+		BaseURL *string             `json:"baseUrl"`
+		Paths   map[string][]string `json:"paths"`
+	} `json:"compilerOptions"`
+}
+
+// loadTSConfig reads a tsconfig file, following relative "extends" chains.
+func loadTSConfig(path string, depth int) *tsConfig {
+	data, err := os.ReadFile(path)
+	if err != nil || depth > 8 {
+		return nil
+	}
+	var file tsConfigFile
+	if err := json.Unmarshal(stripJSONC(data), &file); err != nil {
+		return nil
+	}
+
+	dir := filepath.Dir(path)
+	cfg := &tsConfig{pathDir: dir}
+	if file.Extends != "" && isRelativeSpecifier(file.Extends) {
+		parentPath := filepath.Join(dir, filepath.FromSlash(file.Extends))
+		if filepath.Ext(parentPath) != ".json" {
+			parentPath += ".json"
+		}
+		if parent := loadTSConfig(parentPath, depth+1); parent != nil {
+			*cfg = *parent
+		}
+	}
+
+	if file.CompilerOptions.BaseURL != nil {
+		cfg.baseURL = filepath.Join(dir, filepath.FromSlash(*file.CompilerOptions.BaseURL))
+		cfg.pathDir = cfg.baseURL
+	}
+	if file.CompilerOptions.Paths != nil {
+		cfg.paths = file.CompilerOptions.Paths
+		if file.CompilerOptions.BaseURL == nil && cfg.baseURL == "" {
+			cfg.pathDir = dir
+		}
+	}
+	if cfg.baseURL == "" && cfg.paths == nil {
+		return nil
+	}
+	return cfg
+}
+
+// stripJSONC removes comments and trailing commas so tsconfig files parse as JSON.
+func stripJSONC(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	inString := false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		if inString {
+			out = append(out, c)
+			if c == '\\' && i+1 < len(data) {
+				i++
+				out = append(out, data[i])
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+
+		switch {
+		case c == '"':
+			inString = true
+			out = append(out, c)
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			out = append(out, '\n')
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			i += 2
+			for i+1 < len(data) && !(data[i] == '*' && data[i+1] == '/') {
+				i++
+			}
+			i++
+		case c == ']' || c == '}':
+			// Drop a trailing comma before the closing bracket
+			j := len(out) - 1
+			for j >= 0 && (out[j] == ' ' || out[j] == '\t' || out[j] == '\n' || out[j] == '\r') {
+				j--
+			}
+			if j >= 0 && out[j] == ',' {
+				out = append(out[:j], out[j+1:]...)
+			}
+			out = append(out, c)
+		default:
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// isFile reports whether path is an existing regular file, caching the result.
+func (r *ModuleResolver) isFile(path string) bool {
+	r.mu.Lock()
+	exists, ok := r.exists[path]
+	r.mu.Unlock()
+	if ok {
+		return exists
+	}
+
+	info, err := os.Stat(path)
+	exists = err == nil && info.Mode().IsRegular()
+
+	r.mu.Lock()
+	r.exists[path] = exists
+	r.mu.Unlock()
+	return exists
+}
+
+// resolvePython resolves a Python module path: leading dots climb from the
+// importing package, absolute modules are looked up under pythonSourceRoots
+// and anything else is an external package named by its top-level segment.
+func (r *ModuleResolver) resolvePython(fromDir, module string) ModuleResolution {
+	dotted := strings.TrimLeft(module, ".")
+	rel := filepath.FromSlash(strings.ReplaceAll(dotted, ".", "/"))
+
+	if level := len(module) - len(dotted); level > 0 {
+		dir := fromDir
+		for i := 1; i < level; i++ {
+			dir = filepath.Dir(dir)
+		}
+		return ModuleResolution{File: r.resolvePythonModule(filepath.Join(dir, rel), rel == "")}
+	}
+
+	for _, srcRoot := range pythonSourceRoots {
+		if file := r.resolvePythonModule(filepath.Join(r.root, srcRoot, rel), false); file != "" {
+			return ModuleResolution{File: file}
+		}
+	}
+
+	pkgName, _, _ := strings.Cut(dotted, ".")
+	return ModuleResolution{Package: pkgName}
+}
+
+// resolvePythonModule probes target as a module file, then as a package with
+// an __init__.py. pkgOnly skips the module probe for bare `from . import x`.
+func (r *ModuleResolver) resolvePythonModule(target string, pkgOnly bool) string {
+	if !pkgOnly && r.isFile(target+".py") {
+		return target + ".py"
+	}
+	if init := filepath.Join(target, "__init__.py"); r.isFile(init) {
+		return init
+	}
+	return ""
+}
+
+// resolveSass resolves the URL of a Sass @use, @forward or @import. Built-in
+// sass: modules and ~ paths into node_modules are external packages; other
+// URLs are looked up relative to the importing file, then the project root.
+func (r *ModuleResolver) resolveSass(fromDir, url string) ModuleResolution {
+	if strings.HasPrefix(url, "sass:") {
+		return ModuleResolution{Package: url}
+	}
+	if pkg, ok := strings.CutPrefix(url, "~"); ok {
+		name, _ := splitPackageSpecifier(pkg)
+		return ModuleResolution{Package: name}
+	}
+	if strings.Contains(url, "://") {
+		return ModuleResolution{} // Plain CSS imports from a URL
+	}
+
+	target := filepath.FromSlash(url)
+	if filepath.IsAbs(target) {
+		return ModuleResolution{File: r.resolveSassFile(target)}
+	}
+	for _, dir := range []string{fromDir, r.root} {
+		if file := r.resolveSassFile(filepath.Join(dir, target)); file != "" {
+			return ModuleResolution{File: file}
+		}
+	}
+	name, _ := splitPackageSpecifier(url)
+	return ModuleResolution{Package: name}
+}
+
+// resolveSassFile probes target as Sass does: as written, as a partial
+// prefixed with _, with the .scss or .css extension, and as a directory
+// with an _index file.
+func (r *ModuleResolver) resolveSassFile(target string) string {
+	dir, base := filepath.Split(target)
+	var candidates []string
+	if ext := filepath.Ext(base); ext == ".scss" || ext == ".css" {
+		candidates = append(candidates, target, filepath.Join(dir, "_"+base))
+	} else {
+		for _, ext := range []string{".scss", ".css"} {
+			candidates = append(candidates, target+ext, filepath.Join(dir, "_"+base+ext))
+		}
+		candidates = append(candidates, filepath.Join(target, "_index.scss"), filepath.Join(target, "index.scss"))
+	}
+	for _, candidate := range candidates {
+		if r.isFile(candidate) {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// isRelativeSpecifier reports whether specifier is relative to the importing file.
+func isRelativeSpecifier(specifier string) bool {
+	return specifier == "." || specifier == ".." ||
+		strings.HasPrefix(specifier, "./") || strings.HasPrefix(specifier, "../")
+}
+
+// splitPackageSpecifier splits "@scope/pkg/sub/path" into ("@scope/pkg", "./sub/path").
+func splitPackageSpecifier(specifier string) (string, string) {
+	parts := strings.Split(specifier, "/")
+	n := 1
+	if strings.HasPrefix(specifier, "@") && len(parts) > 1 {
+		n = 2
+	}
+	if len(parts) <= n {
+		return specifier, "."
+	}
+	return strings.Join(parts[:n], "/"), "./" + strings.Join(parts[n:], "/")
+}
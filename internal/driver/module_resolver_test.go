@@ -0,0 +1,188 @@
+// internal/driver/module_resolver_test.go
+
+package driver
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeProject creates files under a temporary directory and returns it.
+func writeProject(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestModuleResolverResolve(t *testing.T) {
+	root := writeProject(t, map[string]string{
+		"tsconfig.json": `{
+			// Aliases for the app sources
+			"compilerOptions": {
+				"baseUrl": ".",
+				"paths": {
+					"@app/*": ["src/*"],
+					"@app/special/*": ["special/*"], /* longer prefix */
+				},
+			},
+		}`,
+		"src/app.ts":            "",
+		"src/util.ts":           "",
+		"src/esm.ts":            "",
+		"src/widgets/index.tsx": "",
+		"special/thing.ts":      "",
+		"lib/shared.js":         "",
+		"packages/ui/package.json": `{"exports": {
+			".": {"types": "./src/index.ts", "default": "./dist/index.js"},
+			"./hooks/*": "./src/hooks/*.ts"
+		}}`,
+		"packages/ui/src/index.ts":         "",
+		"packages/ui/src/hooks/useAuth.ts": "",
+		"node_modules/react/package.json":  `{"main": "index.js"}`,
+		"node_modules/react/index.js":      "",
+
+		"pkg/__init__.py":  "",
+		"pkg/models.py":    "",
+		"pkg/views.py":     "",
+		"src/tools/cli.py": "",
+
+		"styles/_vars.scss":       "",
+		"styles/main.scss":        "",
+		"styles/base/_index.scss": "",
+	})
+	if err := os.Symlink(filepath.Join(root, "packages/ui"), filepath.Join(root, "node_modules/ui")); err != nil {
+		t.Fatal(err)
+	}
+	r := NewModuleResolver(root)
+	file := func(name string) ModuleResolution {
+		return ModuleResolution{File: filepath.Join(root, filepath.FromSlash(name))}
+	}
+
+	tests := []struct {
+		name      string
+		from      string
+		specifier string
+		want      ModuleResolution
+	}{
+		{"relative with extension probing", "src/app.ts", "./util", file("src/util.ts")},
+		{"emitted .js extension", "src/app.ts", "./esm.js", file("src/esm.ts")},
+		{"directory index", "src/app.ts", "./widgets", file("src/widgets/index.tsx")},
+		{"parent directory", "src/widgets/index.tsx", "../util", file("src/util.ts")},
+		{"missing relative file", "src/app.ts", "./missing", ModuleResolution{}},
+		{"paths alias", "src/app.ts", "@app/util", file("src/util.ts")},
+		{"longest alias prefix", "src/app.ts", "@app/special/thing", file("special/thing.ts")},
+		{"baseUrl", "src/app.ts", "lib/shared", file("lib/shared.js")},
+		{"workspace package exports", "src/app.ts", "ui", file("packages/ui/src/index.ts")},
+		{"workspace package wildcard export", "src/app.ts", "ui/hooks/useAuth", file("packages/ui/src/hooks/useAuth.ts")},
+		{"installed package", "src/app.ts", "react", ModuleResolution{Package: "react"}},
+		{"scoped package subpath", "src/app.ts", "@scope/pkg/sub", ModuleResolution{Package: "@scope/pkg"}},
+		{"empty specifier", "src/app.ts", "", ModuleResolution{}},
+
+		{"python absolute module", "pkg/views.py", "pkg.models", file("pkg/models.py")},
+		{"python package", "pkg/views.py", "pkg", file("pkg/__init__.py")},
+		{"python relative module", "pkg/views.py", ".models", file("pkg/models.py")},
+		{"python bare relative import", "pkg/views.py", ".", file("pkg/__init__.py")},
+		{"python src root", "pkg/views.py", "tools.cli", file("src/tools/cli.py")},
+		{"python external package", "pkg/views.py", "requests.adapters", ModuleResolution{Package: "requests"}},
+
+		{"go import path", "main.go", "github.com/acme/tool/pkg", ModuleResolution{Package: "github.com/acme/tool/pkg"}},
+
+		{"sass partial", "styles/main.scss", "vars", file("styles/_vars.scss")},
+		{"sass index", "styles/main.scss", "base", file("styles/base/_index.scss")},
+		{"sass from the project root", "styles/main.scss", "styles/vars", file("styles/_vars.scss")},
+		{"sass built-in module", "styles/main.scss", "sass:math", ModuleResolution{Package: "sass:math"}},
+		{"sass node_modules path", "styles/main.scss", "~bootstrap/scss/grid", ModuleResolution{Package: "bootstrap"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from := filepath.Join(root, filepath.FromSlash(tt.from))
+			if got := r.Resolve(from, tt.specifier); got != tt.want {
+				t.Errorf("Resolve(%q, %q) = %+v, want %+v", tt.from, tt.specifier, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExportTargets(t *testing.T) {
+	tests := []struct {
+		name    string
+		exports string
+		subpath string
+		want    []string
+	}{
+		{"none", ``, ".", nil},
+		{"string", `"./index.js"`, ".", []string{"./index.js"}},
+		{"string for a subpath", `"./index.js"`, "./sub", nil},
+		{"conditions in priority order", `{"default": "./d.js", "import": "./i.mjs", "types": "./t.d.ts"}`, ".",
+			[]string{"./t.d.ts", "./i.mjs", "./d.js"}},
+		{"conditions for a subpath", `{"import": "./i.mjs"}`, "./sub", nil},
+		{"nested conditions", `{"import": {"types": "./i.d.ts", "default": "./i.js"}}`, ".",
+			[]string{"./i.d.ts", "./i.js"}},
+		{"array", `[{"import": "./a.mjs"}, "./b.js"]`, ".", []string{"./a.mjs", "./b.js"}},
+		{"exact subpath", `{".": "./index.js", "./feature": "./feature.js"}`, "./feature", []string{"./feature.js"}},
+		{"missing subpath", `{".": "./index.js"}`, "./feature", nil},
+		{"wildcard", `{"./*": "./src/*.ts"}`, "./utils/date", []string{"./src/utils/date.ts"}},
+		{"wildcard with suffix", `{"./*.js": "./src/*.ts"}`, "./util.js", []string{"./src/util.ts"}},
+		{"wildcard suffix mismatch", `{"./*.js": "./src/*.ts"}`, "./util.css", nil},
+		{"exact beats wildcard", `{"./*": "./src/*.ts", "./feature": "./feature.js"}`, "./feature", []string{"./feature.js"}},
+		{"longest prefix wins", `{"./*": "./src/*.ts", "./utils/*": "./lib/utils/*.js", "./u*": "./u/*.js"}`, "./utils/date",
+			[]string{"./lib/utils/date.js"}},
+		{"longest pattern breaks prefix ties", `{"./a/*": "./one/*.js", "./a/*.js": "./two/*.js"}`, "./a/b.js",
+			[]string{"./two/b.js"}},
+		{"prefix and suffix may not overlap", `{"./ab*ba": "./x/*.js"}`, "./aba", nil},
+		{"wildcard with conditions", `{"./*": {"types": "./types/*.d.ts", "default": "./dist/*.js"}}`, "./x",
+			[]string{"./types/x.d.ts", "./dist/x.js"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Repeat to catch results depending on map iteration order
+			for i := 0; i < 20; i++ {
+				if got := exportTargets(json.RawMessage(tt.exports), tt.subpath); !reflect.DeepEqual(got, tt.want) {
+					t.Fatalf("exportTargets(%s, %q) = %q, want %q", tt.exports, tt.subpath, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestStripJSONC(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain", `{"a": 1}`, `{"a": 1}`},
+		{"line comment", "{\"a\": 1 // one\n}", "{\"a\": 1 \n}"},
+		{"block comment", `{/* a */"a": /* one */1}`, `{"a": 1}`},
+		{"multi-line block comment", "{\n/*\n * a\n */\n\"a\": 1}", "{\n\n\"a\": 1}"},
+		{"comment markers in strings", `{"url": "http://x/*y*/"}`, `{"url": "http://x/*y*/"}`},
+		{"escaped quote", `{"a": "say \"//\""}`, `{"a": "say \"//\""}`},
+		{"trailing comma in object", `{"a": 1,}`, `{"a": 1}`},
+		{"trailing comma in array", "[1, 2,\n\t]", "[1, 2\n\t]"},
+		{"trailing comma before comment", "{\"a\": 1, // last\n}", "{\"a\": 1 \n}"},
+		{"comma in string", `{"a": ",}"}`, `{"a": ",}"}`},
+		{"unterminated block comment", `{"a": 1} /* open`, `{"a": 1} `},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(stripJSONC([]byte(tt.in))); got != tt.want {
+				t.Errorf("stripJSONC(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
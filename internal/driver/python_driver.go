@@ -0,0 +1,633 @@
+// internal/driver/python_driver.go
+
+package driver
+
+import (
+	"goParse/internal/model"
+	"regexp"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// pyConstantName matches module-level names written as constants (UPPER_SNAKE_CASE).
+var pyConstantName = regexp.MustCompile(`^_*[A-Z][A-Z0-9_]*$`)
+
+// parsePython extracts all entities and relationships from Python files
+func (t *TreeSitterDriver) parsePython(pf *ParsedFile, src []byte, root *sitter.Node, lang *sitter.Language) {
+	// Extract imports
+	t.extractPyImports(pf, src, root, lang)
+
+	// Module-level names listed in __all__, when the module declares it
+	all := pyAllNames(root, src)
+
+	// Extract classes first so functions can be attached to them as methods
+	t.extractPyClasses(pf, src, root, lang, all)
+
+	// Extract functions and methods
+	t.extractPyFunctions(pf, src, root, lang, all)
+
+	// Extract module-level constants and variables
+	t.extractPyModuleAssignments(pf, src, root)
+
+	// Extract function calls
+	t.extractPyFunctionCalls(pf, src, root, lang)
+}
+
+// Python extraction methods
+
+func (t *TreeSitterDriver) extractPyImports(pf *ParsedFile, src []byte, root *sitter.Node, lang *sitter.Language) {
+	qs := t.query(lang, pyImportQuery)
+	for match := range t.matches(qs, root) {
+		for _, capture := range match.Captures {
+			stmtNode := capture.Node
+			if stmtNode.Type() == "import_statement" {
+				t.runPyImport(pf, src, stmtNode)
+			} else {
+				t.runPyFromImport(pf, src, stmtNode)
+			}
+		}
+	}
+}
+
+// runPyImport handles `import a.b, c as d`; each module binds one namespace name.
+func (t *TreeSitterDriver) runPyImport(pf *ParsedFile, src []byte, stmtNode *sitter.Node) {
+	for i := 0; i < int(stmtNode.ChildCount()); i++ {
+		if stmtNode.FieldNameForChild(i) != "name" {
+			continue
+		}
+		child := stmtNode.Child(i)
+
+		var module, local string
+		switch child.Type() {
+		case "dotted_name":
+			module = string(src[child.StartByte():child.EndByte()])
+			// `import a.b` binds the top-level package `a`
+			local, _, _ = strings.Cut(module, ".")
+		case "aliased_import":
+			nameNode := child.ChildByFieldName("name")
+			aliasNode := child.ChildByFieldName("alias")
+			if nameNode == nil || aliasNode == nil {
+				continue
+			}
+			module = string(src[nameNode.StartByte():nameNode.EndByte()])
+			local = string(src[aliasNode.StartByte():aliasNode.EndByte()])
+		default:
+			continue
+		}
+
+		pf.Imports = append(pf.Imports, model.ImportEntity{
+			Module:        module,
+			FilePath:      pf.FilePath,
+			ImportedNames: []string{local},
+			IsNamespace:   true,
+		})
+	}
+}
+
+// runPyFromImport handles `from .mod import a, b as c` and `from mod import *`.
+func (t *TreeSitterDriver) runPyFromImport(pf *ParsedFile, src []byte, stmtNode *sitter.Node) {
+	moduleNode := stmtNode.ChildByFieldName("module_name")
+	if moduleNode == nil {
+		return
+	}
+
+	imp := model.ImportEntity{
+		Module:   string(src[moduleNode.StartByte():moduleNode.EndByte()]),
+		FilePath: pf.FilePath,
+	}
+
+	for i := 0; i < int(stmtNode.ChildCount()); i++ {
+		child := stmtNode.Child(i)
+		if child.Type() == "wildcard_import" {
+			imp.ImportedNames = append(imp.ImportedNames, "*")
+			imp.IsNamespace = true
+			continue
+		}
+		if stmtNode.FieldNameForChild(i) != "name" {
+			continue
+		}
+
+		switch child.Type() {
+		case "dotted_name":
+			imp.ImportedNames = append(imp.ImportedNames, string(src[child.StartByte():child.EndByte()]))
+		case "aliased_import":
+			nameNode := child.ChildByFieldName("name")
+			aliasNode := child.ChildByFieldName("alias")
+			if nameNode == nil || aliasNode == nil {
+				continue
+			}
+			alias := string(src[aliasNode.StartByte():aliasNode.EndByte()])
+			if imp.Aliases == nil {
+				imp.Aliases = make(map[string]string)
+			}
+			imp.Aliases[alias] = string(src[nameNode.StartByte():nameNode.EndByte()])
+			imp.ImportedNames = append(imp.ImportedNames, alias)
+		}
+	}
+
+	pf.Imports = append(pf.Imports, imp)
+}
+
+func (t *TreeSitterDriver) extractPyClasses(pf *ParsedFile, src []byte, root *sitter.Node, lang *sitter.Language, all map[string]bool) {
+	qs := t.query(lang, pyClassQuery)
+	for match := range t.matches(qs, root) {
+		var nameNode, defNode *sitter.Node
+		for _, capture := range match.Captures {
+			capName := qs.CaptureNameForId(capture.Index)
+			if capName == "class.name" {
+				nameNode = capture.Node
+			} else if capName == "class.def" {
+				defNode = capture.Node
+			}
+		}
+		if nameNode == nil || defNode == nil {
+			continue
+		}
+
+		className := string(src[nameNode.StartByte():nameNode.EndByte()])
+		outer := t.pyDecoratedNode(defNode)
+
+		class := model.ClassEntity{
+			Name:          className,
+			QualifiedName: t.qualifiedName(defNode, className, src),
+			FilePath:      pf.FilePath,
+			StartLine:     int(outer.StartPoint().Row) + 1,
+			EndLine:       int(outer.EndPoint().Row) + 1,
+			IsExport:      t.pyIsExport(defNode, className, all),
+			Doc:           pyDocstring(defNode, src),
+		}
+
+		// Base classes become EXTENDS; an ABC base or ABCMeta metaclass marks it abstract
+		if bases := defNode.ChildByFieldName("superclasses"); bases != nil {
+			for i := 0; i < int(bases.NamedChildCount()); i++ {
+				base := bases.NamedChild(i)
+				switch base.Type() {
+				case "identifier", "attribute":
+					baseName := string(src[base.StartByte():base.EndByte()])
+					if baseName == "ABC" || baseName == "abc.ABC" {
+						class.IsAbstract = true
+					}
+					if baseName == "object" {
+						continue
+					}
+					pf.Extends = append(pf.Extends, model.ExtendsEntity{
+						ChildName:  className,
+						ParentName: baseName,
+						FilePath:   pf.FilePath,
+					})
+				case "keyword_argument":
+					if value := base.ChildByFieldName("value"); value != nil {
+						if strings.HasSuffix(string(src[value.StartByte():value.EndByte()]), "ABCMeta") {
+							class.IsAbstract = true
+						}
+					}
+				}
+			}
+		}
+
+		pf.Classes = append(pf.Classes, class)
+		t.extractPyClassMembers(pf, className, defNode, src)
+		t.addPyDecoratorReferences(pf, src, defNode, className)
+	}
+}
+
+// extractPyClassMembers adds the members of a class body to pf: class
+// attributes, methods with the kind and flags their decorators give them,
+// and the attributes __init__ assigns on self.
+func (t *TreeSitterDriver) extractPyClassMembers(pf *ParsedFile, className string, classDef *sitter.Node, src []byte) {
+	body := classDef.ChildByFieldName("body")
+	if body == nil {
+		return
+	}
+
+	seen := make(map[string]bool)
+	addAttribute := func(assignment *sitter.Node, name string) {
+		if seen[name] {
+			return
+		}
+		seen[name] = true
+		member := model.MemberEntity{
+			Name:       name,
+			Kind:       "property",
+			ClassName:  className,
+			FilePath:   pf.FilePath,
+			StartLine:  int(assignment.StartPoint().Row) + 1,
+			EndLine:    int(assignment.EndPoint().Row) + 1,
+			Visibility: pyVisibility(name),
+		}
+		if typeNode := assignment.ChildByFieldName("type"); typeNode != nil {
+			member.Type = string(src[typeNode.StartByte():typeNode.EndByte()])
+		}
+		pf.Members = append(pf.Members, member)
+	}
+
+	var initBody *sitter.Node
+	for i := 0; i < int(body.NamedChildCount()); i++ {
+		stmt := body.NamedChild(i)
+		switch stmt.Type() {
+		case "expression_statement":
+			assignment := stmt.NamedChild(0)
+			if assignment == nil || assignment.Type() != "assignment" {
+				continue
+			}
+			if left := assignment.ChildByFieldName("left"); left != nil && left.Type() == "identifier" {
+				addAttribute(assignment, string(src[left.StartByte():left.EndByte()]))
+			}
+
+		case "function_definition", "decorated_definition":
+			def := stmt
+			if stmt.Type() == "decorated_definition" {
+				def = stmt.ChildByFieldName("definition")
+			}
+			if def == nil || def.Type() != "function_definition" {
+				continue // Nested classes
+			}
+			nameNode := def.ChildByFieldName("name")
+			if nameNode == nil {
+				continue
+			}
+
+			member := model.MemberEntity{
+				Name:      string(src[nameNode.StartByte():nameNode.EndByte()]),
+				Kind:      "method",
+				ClassName: className,
+				FilePath:  pf.FilePath,
+				StartLine: int(stmt.StartPoint().Row) + 1,
+				EndLine:   int(stmt.EndPoint().Row) + 1,
+			}
+			member.Visibility = pyVisibility(member.Name)
+			for j := 0; stmt != def && j < int(stmt.NamedChildCount()); j++ {
+				decorator := stmt.NamedChild(j)
+				if decorator.Type() != "decorator" || decorator.NamedChildCount() == 0 {
+					continue
+				}
+				expr := decorator.NamedChild(0)
+				if expr.Type() == "call" {
+					if fn := expr.ChildByFieldName("function"); fn != nil {
+						expr = fn
+					}
+				}
+				switch name := string(src[expr.StartByte():expr.EndByte()]); {
+				case name == "property" || name == "functools.cached_property" || name == "cached_property":
+					member.Kind = "getter"
+				case strings.HasSuffix(name, ".setter"):
+					member.Kind = "setter"
+				case name == "staticmethod" || name == "classmethod":
+					member.IsStatic = true
+				case name == "abstractmethod" || name == "abc.abstractmethod":
+					member.IsAbstract = true
+				}
+			}
+			if member.Name == "__init__" {
+				member.Kind = "constructor"
+				initBody = def.ChildByFieldName("body")
+			}
+			pf.Members = append(pf.Members, member)
+		}
+	}
+
+	// Instance attributes, found in __init__ but not in functions nested in it
+	var walk func(node *sitter.Node)
+	walk = func(node *sitter.Node) {
+		if node.Type() == "function_definition" || node.Type() == "class_definition" || node.Type() == "lambda" {
+			return
+		}
+		if node.Type() == "assignment" {
+			left := node.ChildByFieldName("left")
+			if left != nil && left.Type() == "attribute" {
+				object := left.ChildByFieldName("object")
+				attribute := left.ChildByFieldName("attribute")
+				if object != nil && attribute != nil && string(src[object.StartByte():object.EndByte()]) == "self" {
+					addAttribute(node, string(src[attribute.StartByte():attribute.EndByte()]))
+				}
+			}
+		}
+		for i := 0; i < int(node.NamedChildCount()); i++ {
+			walk(node.NamedChild(i))
+		}
+	}
+	if initBody != nil {
+		walk(initBody)
+	}
+}
+
+// pyVisibility maps Python naming conventions to a visibility: __name is
+// private, _name protected, and dunder and other names public.
+func pyVisibility(name string) string {
+	switch {
+	case strings.HasPrefix(name, "__") && strings.HasSuffix(name, "__"):
+		return "public"
+	case strings.HasPrefix(name, "__"):
+		return "private"
+	case strings.HasPrefix(name, "_"):
+		return "protected"
+	default:
+		return "public"
+	}
+}
+
+func (t *TreeSitterDriver) extractPyFunctions(pf *ParsedFile, src []byte, root *sitter.Node, lang *sitter.Language, all map[string]bool) {
+	qs := t.query(lang, pyFunctionQuery)
+	for match := range t.matches(qs, root) {
+		var nameNode, defNode *sitter.Node
+		for _, capture := range match.Captures {
+			capName := qs.CaptureNameForId(capture.Index)
+			if capName == "func.name" {
+				nameNode = capture.Node
+			} else if capName == "func.def" {
+				defNode = capture.Node
+			}
+		}
+		if nameNode == nil || defNode == nil {
+			continue
+		}
+
+		fnName := string(src[nameNode.StartByte():nameNode.EndByte()])
+		outer := t.pyDecoratedNode(defNode)
+
+		pf.Funcs = append(pf.Funcs, model.FunctionEntity{
+			Name:           fnName,
+			QualifiedName:  t.qualifiedName(defNode, fnName, src),
+			FilePath:       pf.FilePath,
+			StartLine:      int(outer.StartPoint().Row) + 1,
+			EndLine:        int(outer.EndPoint().Row) + 1,
+			Signature:      t.extractFunctionSignature(defNode, src),
+			Parameters:     t.pyParameters(defNode.ChildByFieldName("parameters"), src),
+			ReturnType:     nodeText(defNode.ChildByFieldName("return_type"), src),
+			TypeParameters: t.pyTypeParameters(defNode.ChildByFieldName("type_parameters"), src),
+			IsAsync:        defNode.ChildCount() > 0 && defNode.Child(0).Type() == "async",
+			IsGenerator:    pyYields(defNode.ChildByFieldName("body")),
+			IsExport:       t.pyIsExport(defNode, fnName, all),
+			Doc:            pyDocstring(defNode, src),
+		})
+
+		// Functions defined directly in a class body are its methods
+		if className := t.pyEnclosingClass(defNode, src); className != "" {
+			for i := range pf.Classes {
+				class := &pf.Classes[i]
+				if class.Name == className && class.StartLine <= int(defNode.StartPoint().Row)+1 && class.EndLine >= int(defNode.EndPoint().Row)+1 {
+					class.Methods = append(class.Methods, fnName)
+					break
+				}
+			}
+		}
+
+		t.addPyDecoratorReferences(pf, src, defNode, fnName)
+	}
+}
+
+// pyIsExport reports whether the class or function def is part of its
+// module's public interface: it must be defined at module level, and be
+// listed in __all__ when the module declares it, or else not start with an
+// underscore. Methods and nested functions are never exported.
+func (t *TreeSitterDriver) pyIsExport(def *sitter.Node, name string, all map[string]bool) bool {
+	if parent := t.pyDecoratedNode(def).Parent(); parent == nil || parent.Type() != "module" {
+		return false
+	}
+	if all != nil {
+		return all[name]
+	}
+	return !strings.HasPrefix(name, "_")
+}
+
+// pyAllNames returns the names a module lists in __all__, assigned or
+// extended at module level with lists or tuples of string literals, or nil
+// when it has no __all__.
+func pyAllNames(root *sitter.Node, src []byte) map[string]bool {
+	var all map[string]bool
+	for i := 0; i < int(root.NamedChildCount()); i++ {
+		stmt := root.NamedChild(i)
+		if stmt.Type() != "expression_statement" || stmt.NamedChildCount() == 0 {
+			continue
+		}
+		assign := stmt.NamedChild(0)
+		if assign.Type() != "assignment" && assign.Type() != "augmented_assignment" {
+			continue
+		}
+		left, right := assign.ChildByFieldName("left"), assign.ChildByFieldName("right")
+		if left == nil || right == nil || nodeText(left, src) != "__all__" {
+			continue
+		}
+		if right.Type() != "list" && right.Type() != "tuple" {
+			continue
+		}
+
+		if all == nil || assign.Type() == "assignment" {
+			all = make(map[string]bool)
+		}
+		for j := 0; j < int(right.NamedChildCount()); j++ {
+			item := right.NamedChild(j)
+			if item.Type() != "string" {
+				continue
+			}
+			for k := 0; k < int(item.NamedChildCount()); k++ {
+				if content := item.NamedChild(k); content.Type() == "string_content" {
+					all[nodeText(content, src)] = true
+				}
+			}
+		}
+	}
+	return all
+}
+
+// pyParameters reads a parameters node. *args and **kwargs keep their stars
+// in their names and are marked rest; the bare / and * separators are skipped.
+func (t *TreeSitterDriver) pyParameters(params *sitter.Node, src []byte) []model.Parameter {
+	if params == nil {
+		return nil
+	}
+
+	var out []model.Parameter
+	for i := 0; i < int(params.NamedChildCount()); i++ {
+		node := params.NamedChild(i)
+
+		var param model.Parameter
+		nameNode := node
+		switch node.Type() {
+		case "identifier", "list_splat_pattern", "dictionary_splat_pattern":
+		case "typed_parameter":
+			// The name is the first named child; typed *args wrap a splat pattern
+			nameNode = node.NamedChild(0)
+			param.Type = nodeText(node.ChildByFieldName("type"), src)
+		case "default_parameter", "typed_default_parameter":
+			nameNode = node.ChildByFieldName("name")
+			param.Type = nodeText(node.ChildByFieldName("type"), src)
+			param.DefaultValue = nodeText(node.ChildByFieldName("value"), src)
+			param.IsOptional = true
+		default:
+			continue // Separators and comments
+		}
+		if nameNode == nil {
+			continue
+		}
+
+		param.Name = nodeText(nameNode, src)
+		param.IsRest = nameNode.Type() == "list_splat_pattern" || nameNode.Type() == "dictionary_splat_pattern"
+		out = append(out, param)
+	}
+	return out
+}
+
+// pyTypeParameters reads the PEP 695 type parameters of def[T: Base, *Ts].
+func (t *TreeSitterDriver) pyTypeParameters(typeParams *sitter.Node, src []byte) []model.TypeParameter {
+	if typeParams == nil {
+		return nil
+	}
+
+	var out []model.TypeParameter
+	for i := 0; i < int(typeParams.NamedChildCount()); i++ {
+		node := typeParams.NamedChild(i)
+		if node.Type() != "type" || node.NamedChildCount() == 0 {
+			continue
+		}
+		inner := node.NamedChild(0)
+		if inner.Type() == "constrained_type" && inner.NamedChildCount() == 2 {
+			out = append(out, model.TypeParameter{
+				Name:       nodeText(inner.NamedChild(0), src),
+				Constraint: nodeText(inner.NamedChild(1), src),
+			})
+			continue
+		}
+		out = append(out, model.TypeParameter{Name: nodeText(inner, src)})
+	}
+	return out
+}
+
+// pyYields reports whether a function body yields, which makes the function
+// a generator. Yields inside nested functions, lambdas and classes belong to
+// them instead.
+func pyYields(node *sitter.Node) bool {
+	if node == nil {
+		return false
+	}
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		child := node.NamedChild(i)
+		switch child.Type() {
+		case "yield":
+			return true
+		case "function_definition", "lambda", "class_definition":
+			continue
+		}
+		if pyYields(child) {
+			return true
+		}
+	}
+	return false
+}
+
+// pyDecoratedNode returns the decorated_definition wrapping def, or def itself,
+// so entity line ranges include their decorators.
+func (t *TreeSitterDriver) pyDecoratedNode(def *sitter.Node) *sitter.Node {
+	if parent := def.Parent(); parent != nil && parent.Type() == "decorated_definition" {
+		return parent
+	}
+	return def
+}
+
+// pyEnclosingClass returns the class whose body directly contains def.
+func (t *TreeSitterDriver) pyEnclosingClass(def *sitter.Node, src []byte) string {
+	node := t.pyDecoratedNode(def)
+	block := node.Parent()
+	if block == nil || block.Type() != "block" {
+		return ""
+	}
+	class := block.Parent()
+	if class == nil || class.Type() != "class_definition" {
+		return ""
+	}
+	if nameNode := class.ChildByFieldName("name"); nameNode != nil {
+		return string(src[nameNode.StartByte():nameNode.EndByte()])
+	}
+	return ""
+}
+
+// addPyDecoratorReferences records each decorator applied to def as a
+// "decorator" reference from the decorated entity.
+func (t *TreeSitterDriver) addPyDecoratorReferences(pf *ParsedFile, src []byte, def *sitter.Node, entityName string) {
+	outer := t.pyDecoratedNode(def)
+	if outer == def {
+		return
+	}
+
+	for i := 0; i < int(outer.NamedChildCount()); i++ {
+		decorator := outer.NamedChild(i)
+		if decorator.Type() != "decorator" || decorator.NamedChildCount() == 0 {
+			continue
+		}
+
+		// @name, @pkg.name and @name(args) all reference the callee expression
+		expr := decorator.NamedChild(0)
+		if expr.Type() == "call" {
+			if fn := expr.ChildByFieldName("function"); fn != nil {
+				expr = fn
+			}
+		}
+
+		pf.References = append(pf.References, model.ReferenceEntity{
+			SourceFile:   pf.FilePath,
+			SourceEntity: entityName,
+			TargetEntity: string(src[expr.StartByte():expr.EndByte()]),
+			RefType:      "decorator",
+			Line:         int(decorator.StartPoint().Row) + 1,
+		})
+	}
+}
+
+// extractPyModuleAssignments records top-level assignments: UPPER_CASE names
+// as constants, everything else as variables.
+func (t *TreeSitterDriver) extractPyModuleAssignments(pf *ParsedFile, src []byte, root *sitter.Node) {
+	for i := 0; i < int(root.NamedChildCount()); i++ {
+		stmt := root.NamedChild(i)
+		if stmt.Type() != "expression_statement" || stmt.NamedChildCount() == 0 {
+			continue
+		}
+		assign := stmt.NamedChild(0)
+		if assign.Type() != "assignment" {
+			continue
+		}
+
+		left := assign.ChildByFieldName("left")
+		if left == nil || left.Type() != "identifier" {
+			continue
+		}
+		name := string(src[left.StartByte():left.EndByte()])
+
+		var value string
+		right := assign.ChildByFieldName("right")
+		if right != nil {
+			if right.Type() == "lambda" {
+				continue // Lambdas are functions, not data
+			}
+			value = string(src[right.StartByte():right.EndByte()])
+		}
+
+		if pyConstantName.MatchString(name) {
+			pf.Constants = append(pf.Constants, model.ConstantEntity{
+				Name:     name,
+				FilePath: pf.FilePath,
+				Value:    value,
+			})
+			continue
+		}
+
+		varType := "variable"
+		if typeNode := assign.ChildByFieldName("type"); typeNode != nil {
+			varType = string(src[typeNode.StartByte():typeNode.EndByte()])
+		}
+		pf.Variables = append(pf.Variables, model.VariableEntity{
+			Name:      name,
+			FilePath:  pf.FilePath,
+			Type:      varType,
+			StartLine: int(stmt.StartPoint().Row) + 1,
+		})
+	}
+}
+
+func (t *TreeSitterDriver) extractPyFunctionCalls(pf *ParsedFile, src []byte, root *sitter.Node, lang *sitter.Language) {
+	// Direct function calls
+	t.runFunctionCallQuery(pf, src, root, t.query(lang, pyCallQuery))
+
+	// Method and module attribute calls
+	t.runMethodCallQuery(pf, src, root, t.query(lang, pyMethodCallQuery))
+}
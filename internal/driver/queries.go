@@ -0,0 +1,137 @@
+// internal/driver/queries.go
+
+package driver
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// TypeScript and JavaScript queries
+const (
+	tsFunctionQuery           = `(function_declaration name: (identifier) @func.name) @func.def`
+	tsGeneratorFunctionQuery  = `(generator_function_declaration name: (identifier) @func.name) @func.def`
+	tsMethodQuery             = `(method_definition name: (property_identifier) @func.name) @func.def`
+	tsMethodSignatureQuery    = `(method_signature name: (property_identifier) @func.name) @func.def`
+	tsArrowFunctionQuery      = `(variable_declarator name: (identifier) @func.name value: (arrow_function)) @func.def`
+	tsFunctionExpressionQuery = `(variable_declarator name: (identifier) @func.name value: [(function_expression) (generator_function)]) @func.def`
+	tsImportQuery             = `(import_statement source: (string) @imp.module) @imp.stmt`
+	tsRequireQuery            = `(call_expression function: (identifier) @req arguments: (arguments (string) @imp.module))`
+	tsDynamicImportQuery      = `(call_expression function: (import)) @imp.dynamic`
+	tsVariableQuery           = `(variable_declarator name: (identifier) @var.name) @var.decl`
+	tsTypeAliasQuery          = `(type_alias_declaration name: (type_identifier) @type.name)`
+	tsInterfaceQuery          = `(interface_declaration) @interface`
+	tsClassQuery              = `[(class_declaration) (abstract_class_declaration)] @class`
+	jsClassQuery              = `(class_declaration) @class`
+	tsCallQuery               = `(call_expression function: (identifier) @call.name) @call.expr`
+	tsMethodCallQuery         = `(call_expression function: (member_expression object: (_) @call.object property: (property_identifier) @call.name)) @call.expr`
+	jsxElementQuery           = `(jsx_element (jsx_opening_element (identifier) @tag.name)) @jsx.element`
+	jsxSelfClosingQuery       = `(jsx_self_closing_element) @jsx.element`
+)
+
+// tsTypeUsageQueries find the types named in each context of a TypeScript file.
+var tsTypeUsageQueries = []struct {
+	query   string
+	context string
+}{
+	{`(type_annotation (type_identifier) @type)`, "annotation"},
+	{`(type_arguments (type_identifier) @type)`, "type_argument"},
+	{`(_ return_type: (type_annotation (type_identifier) @type))`, "return_type"},
+	{`[(required_parameter type: (type_annotation (type_identifier) @type)) (optional_parameter type: (type_annotation (type_identifier) @type))]`, "parameter"},
+	{`(property_signature type: (type_annotation (type_identifier) @type))`, "property"},
+	{`(type_parameter [(constraint (type_identifier) @type) (default_type (type_identifier) @type)])`, "type_parameter"},
+}
+
+// CSS queries
+const (
+	cssClassQuery    = `(class_selector (class_name) @class.name) @selector`
+	cssIDQuery       = `(id_selector (id_name) @id.name) @selector`
+	cssVariableQuery = `(declaration (property_name) @prop.name (plain_value) @prop.value)`
+)
+
+// Python queries
+const (
+	pyImportQuery     = `[(import_statement) (import_from_statement)] @imp.stmt`
+	pyClassQuery      = `(class_definition name: (identifier) @class.name) @class.def`
+	pyFunctionQuery   = `(function_definition name: (identifier) @func.name) @func.def`
+	pyCallQuery       = `(call function: (identifier) @call.name) @call.expr`
+	pyMethodCallQuery = `(call function: (attribute object: (_) @call.object attribute: (identifier) @call.name)) @call.expr`
+)
+
+// Go queries
+const (
+	goImportQuery   = `(import_spec path: (interpreted_string_literal) @import.path) @import.spec`
+	goTypeQuery     = `[(type_spec name: (type_identifier) @type.name) (type_alias name: (type_identifier) @type.name)] @type.def`
+	goFunctionQuery = `[(function_declaration name: (identifier) @func.name) (method_declaration name: (field_identifier) @func.name)] @func.def`
+	goCallQuery     = `[
+		(call_expression function: [(identifier) (selector_expression)] @call.function)
+		(type_conversion_expression type: (generic_type type: [(type_identifier) (qualified_type)] @call.function))
+	] @call.expr`
+)
+
+// scriptQueries are run on both TypeScript and JavaScript files.
+var scriptQueries = []string{
+	tsFunctionQuery, tsGeneratorFunctionQuery, tsMethodQuery, tsArrowFunctionQuery, tsFunctionExpressionQuery,
+	tsImportQuery, tsRequireQuery, tsDynamicImportQuery, tsVariableQuery, tsCallQuery, tsMethodCallQuery,
+}
+
+// typeScriptQueries returns the queries run on TypeScript files.
+func typeScriptQueries() []string {
+	queries := slices.Concat(scriptQueries, []string{tsMethodSignatureQuery, tsTypeAliasQuery, tsInterfaceQuery, tsClassQuery})
+	for _, q := range tsTypeUsageQueries {
+		queries = append(queries, q.query)
+	}
+	return queries
+}
+
+// queryKey identifies a query compiled for a grammar.
+type queryKey struct {
+	lang   *sitter.Language
+	source string
+}
+
+// compileQueries compiles each query for its grammar, failing on the first
+// query the grammar rejects.
+func compileQueries(grammars map[*sitter.Language][]string) (map[queryKey]*sitter.Query, error) {
+	queries := make(map[queryKey]*sitter.Query)
+	for lang, sources := range grammars {
+		for _, source := range sources {
+			qs, err := sitter.NewQuery([]byte(source), lang)
+			if err != nil {
+				return nil, fmt.Errorf("failed to compile query %s: %w", source, err)
+			}
+			queries[queryKey{lang, source}] = qs
+		}
+	}
+	return queries, nil
+}
+
+// query returns the query compiled from source for lang, or nil when the
+// query is not run on that grammar, as for TypeScript-only queries on
+// JavaScript files.
+func (t *TreeSitterDriver) query(lang *sitter.Language, source string) *sitter.Query {
+	return t.queries[queryKey{lang, source}]
+}
+
+// matches runs qs on node and yields each match, using a query cursor from
+// the driver's pool for the length of the loop. A nil qs yields nothing.
+func (t *TreeSitterDriver) matches(qs *sitter.Query, node *sitter.Node) iter.Seq[*sitter.QueryMatch] {
+	return func(yield func(*sitter.QueryMatch) bool) {
+		if qs == nil {
+			return
+		}
+		qc := t.cursors.Get().(*sitter.QueryCursor)
+		defer t.cursors.Put(qc)
+
+		qc.Exec(qs, node)
+		for {
+			match, ok := qc.NextMatch()
+			if !ok || !yield(match) {
+				return
+			}
+		}
+	}
+}
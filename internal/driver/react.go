@@ -0,0 +1,283 @@
+// internal/driver/react.go
+
+package driver
+
+import (
+	"goParse/internal/model"
+	"strings"
+	"unicode"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// componentNode is a component found in the syntax tree with the node
+// spanning its body.
+type componentNode struct {
+	component model.ComponentEntity
+	node      *sitter.Node
+}
+
+// extractComponents identifies the React components of a JSX file: functions
+// named in PascalCase that render JSX, such functions wrapped in memo or
+// forwardRef, and classes extending Component or PureComponent. It then
+// records the custom components each one renders and the hooks it calls.
+func (t *TreeSitterDriver) extractComponents(pf *ParsedFile, src []byte, root *sitter.Node) {
+	var components []componentNode
+	var walk func(node *sitter.Node)
+	walk = func(node *sitter.Node) {
+		if component, ok := reactComponent(node, src); ok {
+			component.FilePath = pf.FilePath
+			components = append(components, componentNode{component: component, node: node})
+		}
+		for i := 0; i < int(node.NamedChildCount()); i++ {
+			walk(node.NamedChild(i))
+		}
+	}
+	walk(root)
+
+	for _, c := range components {
+		pf.Components = append(pf.Components, c.component)
+	}
+	if len(components) == 0 {
+		return
+	}
+
+	// Renders and hook calls belong to the innermost component around them
+	rendered := make(map[[2]string]bool)
+	used := make(map[[2]string]bool)
+	walk = func(node *sitter.Node) {
+		switch node.Type() {
+		case "jsx_opening_element", "jsx_self_closing_element":
+			// <Button /> or, through a namespace import, <UI.Button />
+			tag := node.ChildByFieldName("name")
+			if tag == nil || (tag.Type() != "identifier" && tag.Type() != "member_expression") {
+				break
+			}
+			if name := nodeText(tag, src); !isComponentName(name[strings.LastIndex(name, ".")+1:]) {
+				break
+			}
+			if owner := innermostComponent(components, node); owner != nil {
+				key := [2]string{owner.component.Name, nodeText(tag, src)}
+				if !rendered[key] {
+					rendered[key] = true
+					pf.Renders = append(pf.Renders, model.RenderEntity{
+						Component: key[0],
+						TagName:   key[1],
+						FilePath:  pf.FilePath,
+						Line:      int(tag.StartPoint().Row) + 1,
+					})
+				}
+			}
+		case "call_expression":
+			callee := node.ChildByFieldName("function")
+			if callee == nil || (callee.Type() != "identifier" && callee.Type() != "member_expression") {
+				break
+			}
+			name := nodeText(callee, src)
+			name = name[strings.LastIndex(name, ".")+1:] // React.useState
+			if !isHookName(name) {
+				break
+			}
+			if owner := innermostComponent(components, node); owner != nil {
+				key := [2]string{owner.component.Name, name}
+				if !used[key] {
+					used[key] = true
+					pf.HookUsages = append(pf.HookUsages, model.HookUsageEntity{
+						Component: key[0],
+						Hook:      name,
+						FilePath:  pf.FilePath,
+						Line:      int(node.StartPoint().Row) + 1,
+					})
+				}
+			}
+		}
+		for i := 0; i < int(node.NamedChildCount()); i++ {
+			walk(node.NamedChild(i))
+		}
+	}
+	walk(root)
+}
+
+// reactComponent reports whether node declares a component and reads its
+// name, kind, lines and props type.
+func reactComponent(node *sitter.Node, src []byte) (model.ComponentEntity, bool) {
+	component := model.ComponentEntity{
+		StartLine: int(node.StartPoint().Row) + 1,
+		EndLine:   int(node.EndPoint().Row) + 1,
+	}
+
+	switch node.Type() {
+	case "function_declaration":
+		component.Name = nodeText(node.ChildByFieldName("name"), src)
+		if !isComponentName(component.Name) || !containsJSX(node.ChildByFieldName("body")) {
+			return component, false
+		}
+		component.Kind = "function"
+		component.PropsType = propsParameterType(node, src)
+		return component, true
+
+	case "class_declaration":
+		component.Name = nodeText(node.ChildByFieldName("name"), src)
+		base, typeArgs := classBase(node)
+		if !isComponentName(component.Name) || base == nil {
+			return component, false
+		}
+		if name := nodeText(base, src); name != "Component" && name != "PureComponent" &&
+			name != "React.Component" && name != "React.PureComponent" {
+			return component, false
+		}
+		component.Kind = "class"
+		component.PropsType = typeArgument(typeArgs, 0, src)
+		return component, true
+
+	case "variable_declarator":
+		// const Card = (props) => ..., optionally wrapped: memo(forwardRef(...))
+		component.Name = nodeText(node.ChildByFieldName("name"), src)
+		if !isComponentName(component.Name) {
+			return component, false
+		}
+		component.PropsType = typeArgument(genericTypeArguments(node.ChildByFieldName("type")), 0, src) // React.FC<Props>
+
+		value := node.ChildByFieldName("value")
+		wrapped := false
+		for value != nil && value.Type() == "call_expression" {
+			callee := nodeText(value.ChildByFieldName("function"), src)
+			typeArgs := value.ChildByFieldName("type_arguments")
+			switch callee[strings.LastIndex(callee, ".")+1:] {
+			case "memo":
+				if component.PropsType == "" {
+					component.PropsType = typeArgument(typeArgs, 0, src)
+				}
+			case "forwardRef":
+				if component.PropsType == "" {
+					component.PropsType = typeArgument(typeArgs, 1, src) // forwardRef<Ref, Props>
+				}
+			default:
+				return component, false
+			}
+			args := value.ChildByFieldName("arguments")
+			if args == nil || args.NamedChildCount() == 0 {
+				return component, false
+			}
+			value = args.NamedChild(0)
+			wrapped = true
+		}
+		if value == nil {
+			return component, false
+		}
+		switch value.Type() {
+		case "arrow_function", "function", "function_expression":
+			if !containsJSX(value) {
+				return component, false
+			}
+			if component.PropsType == "" {
+				component.PropsType = propsParameterType(value, src)
+			}
+		case "identifier":
+			// memo(Button) wraps a component declared elsewhere
+			if !wrapped {
+				return component, false
+			}
+		default:
+			return component, false
+		}
+		component.Kind = "function"
+		return component, true
+	}
+	return component, false
+}
+
+// classBase returns the class a class declaration extends and the type
+// arguments passed to it, as in extends React.Component<Props, State>.
+func classBase(class *sitter.Node) (*sitter.Node, *sitter.Node) {
+	for i := 0; i < int(class.NamedChildCount()); i++ {
+		heritage := class.NamedChild(i)
+		if heritage.Type() != "class_heritage" || heritage.NamedChildCount() == 0 {
+			continue
+		}
+		clause := heritage.NamedChild(0)
+		if clause.Type() == "extends_clause" {
+			return clause.ChildByFieldName("value"), clause.ChildByFieldName("type_arguments")
+		}
+		return clause, nil // JavaScript has no extends_clause
+	}
+	return nil, nil
+}
+
+// genericTypeArguments returns the type arguments of a generic type
+// annotation such as React.FC<Props>.
+func genericTypeArguments(annotation *sitter.Node) *sitter.Node {
+	if annotation == nil || annotation.NamedChildCount() == 0 {
+		return nil
+	}
+	if generic := annotation.NamedChild(0); generic.Type() == "generic_type" {
+		return generic.ChildByFieldName("type_arguments")
+	}
+	return nil
+}
+
+// typeArgument returns the type argument at index, or "".
+func typeArgument(typeArgs *sitter.Node, index int, src []byte) string {
+	if typeArgs == nil || int(typeArgs.NamedChildCount()) <= index {
+		return ""
+	}
+	return nodeText(typeArgs.NamedChild(index), src)
+}
+
+// propsParameterType returns the declared type of a function's first parameter.
+func propsParameterType(fn *sitter.Node, src []byte) string {
+	params := fn.ChildByFieldName("parameters")
+	if params == nil {
+		return ""
+	}
+	for i := 0; i < int(params.NamedChildCount()); i++ {
+		if param, ok := tsParameter(params.NamedChild(i), src); ok {
+			return param.Type
+		}
+	}
+	return ""
+}
+
+// containsJSX reports whether any JSX element occurs within node.
+func containsJSX(node *sitter.Node) bool {
+	if node == nil {
+		return false
+	}
+	switch node.Type() {
+	case "jsx_element", "jsx_self_closing_element", "jsx_fragment":
+		return true
+	}
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		if containsJSX(node.NamedChild(i)) {
+			return true
+		}
+	}
+	return false
+}
+
+// innermostComponent returns the innermost component whose node contains node.
+func innermostComponent(components []componentNode, node *sitter.Node) *componentNode {
+	var best *componentNode
+	for i := range components {
+		c := &components[i]
+		if node.StartByte() < c.node.StartByte() || node.EndByte() > c.node.EndByte() {
+			continue
+		}
+		if best == nil || c.node.EndByte()-c.node.StartByte() < best.node.EndByte()-best.node.StartByte() {
+			best = c
+		}
+	}
+	return best
+}
+
+// isComponentName reports whether name is written in PascalCase, which JSX
+// reserves for components; lowercase tags are HTML elements.
+func isComponentName(name string) bool {
+	return name != "" && unicode.IsUpper(rune(name[0]))
+}
+
+// isHookName reports whether name follows the hook naming rule: use, or use
+// followed by an uppercase letter as in useState.
+func isHookName(name string) bool {
+	return name == "use" || (len(name) > 3 && strings.HasPrefix(name, "use") && unicode.IsUpper(rune(name[3])))
+}
@@ -0,0 +1,497 @@
+// internal/driver/resolver.go
+
+package driver
+
+import (
+	"goParse/internal/model"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// importBinding points a local name in one file at a symbol in another file.
+type importBinding struct {
+	file string // Path of the defining module (as stored in ParsedFile.FilePath)
+	name string // Exported name in the defining module
+}
+
+// symbolTable indexes the definitions of every parsed file by name.
+type symbolTable struct {
+	funcs   map[string]map[string]model.FunctionEntity // Top-level functions of a file
+	methods map[string][]model.FunctionEntity          // Every function of a file, including class methods
+	classes map[string]map[string]model.ClassEntity
+	files   map[string]*ParsedFile // For following imports and re-exports
+}
+
+// exportTarget is the declaration an exported name resolves to.
+type exportTarget struct {
+	kind string // "function", "class", "interface", "type", "variable" or "module"
+	name string
+	file string // Path of the declaring file (as stored in ParsedFile.FilePath)
+	id   string // Set for functions and classes
+}
+
+// ResolveProjectCalls runs a project-wide pass over all parsed files. Calls that
+// the per-file pass could not resolve are followed through the caller's imports
+// to the defining module, filling ResolvedTarget, TargetFile and TargetID. It returns the
+// number of calls resolved across files.
+func ResolveProjectCalls(files []ParsedFile) int {
+	symbols := buildSymbolTable(files)
+
+	resolved := 0
+	for i := range files {
+		pf := &files[i]
+		bindings := buildImportBindings(pf, symbols)
+		if len(bindings) == 0 {
+			continue
+		}
+
+		for j := range pf.FunctionCalls {
+			call := &pf.FunctionCalls[j]
+			if call.ResolvedTarget != "" {
+				continue
+			}
+			if symbols.resolveCall(call, bindings) {
+				resolved++
+			}
+		}
+	}
+
+	return resolved
+}
+
+// ResolveProjectExports runs a project-wide pass over all parsed files,
+// following each export through re-exports and barrel files to the
+// declaration it names. It returns the number of exports resolved to a
+// declaration in another file.
+func ResolveProjectExports(files []ParsedFile) int {
+	symbols := buildSymbolTable(files)
+
+	resolved := 0
+	for i := range files {
+		resolved += resolveExports(&files[i], symbols)
+	}
+	return resolved
+}
+
+// ResolveProjectComponents runs a project-wide pass over all parsed files,
+// following the components each component renders and the custom hooks it
+// calls through imports to the files declaring them. It returns the number of
+// renders and hook usages resolved to another file.
+func ResolveProjectComponents(files []ParsedFile) int {
+	symbols := buildSymbolTable(files)
+
+	resolved := 0
+	for i := range files {
+		resolved += resolveComponents(&files[i], symbols)
+	}
+	return resolved
+}
+
+// buildSymbolTable collects the functions and classes defined by each file.
+func buildSymbolTable(files []ParsedFile) *symbolTable {
+	st := &symbolTable{
+		funcs:   make(map[string]map[string]model.FunctionEntity, len(files)),
+		methods: make(map[string][]model.FunctionEntity, len(files)),
+		classes: make(map[string]map[string]model.ClassEntity, len(files)),
+		files:   make(map[string]*ParsedFile, len(files)),
+	}
+
+	for i := range files {
+		pf := &files[i]
+		key := filepath.Clean(pf.FilePath)
+		st.files[key] = pf
+
+		// Only top-level functions can be imported; methods, Go receivers
+		// included, and nested functions are reached through st.methods
+		funcs := make(map[string]model.FunctionEntity, len(pf.Funcs))
+		for _, fn := range pf.Funcs {
+			if fn.Receiver != "" || fn.QualifiedName != fn.Name {
+				continue
+			}
+			if _, exists := funcs[fn.QualifiedName]; !exists {
+				funcs[fn.QualifiedName] = fn
+			}
+		}
+		st.funcs[key] = funcs
+		st.methods[key] = pf.Funcs
+
+		classes := make(map[string]model.ClassEntity, len(pf.Classes))
+		for _, class := range pf.Classes {
+			classes[class.Name] = class
+		}
+		st.classes[key] = classes
+	}
+
+	return st
+}
+
+// buildImportBindings maps every name imported by pf to the module defining it.
+func buildImportBindings(pf *ParsedFile, st *symbolTable) map[string]importBinding {
+	bindings := make(map[string]importBinding)
+
+	for _, imp := range pf.Imports {
+		target := st.moduleFile(pf.FilePath, imp.Module, imp.ResolvedFile)
+		if target == "" {
+			continue
+		}
+
+		for _, local := range imp.ImportedNames {
+			exported := local
+			if original, ok := imp.Aliases[local]; ok {
+				exported = original
+			}
+			bindings[local] = importBinding{file: target, name: exported}
+
+			// Names imported through a barrel bind to the module declaring them
+			if decl, ok := st.imported(imp, local, target, map[string]bool{}); ok && decl.kind != "module" {
+				bindings[local] = importBinding{file: filepath.Clean(decl.file), name: decl.name}
+			}
+		}
+	}
+
+	return bindings
+}
+
+// moduleFile returns the key of the parsed file an import or re-export names:
+// its resolved file when that was parsed, else the relative specifier probed
+// by resolveModule.
+func (st *symbolTable) moduleFile(fromFile, module, resolvedFile string) string {
+	if resolvedFile != "" {
+		if key := filepath.Clean(resolvedFile); st.files[key] != nil {
+			return key
+		}
+	}
+	return st.resolveModule(fromFile, module)
+}
+
+// resolveModule maps a relative import specifier to a parsed file, probing
+// extensions and index files. It is the fallback for imports that were not
+// run through a ModuleResolver.
+func (st *symbolTable) resolveModule(fromFile, module string) string {
+	if !strings.HasPrefix(module, "./") && !strings.HasPrefix(module, "../") {
+		return ""
+	}
+
+	base := filepath.Join(filepath.Dir(fromFile), filepath.FromSlash(module))
+
+	candidates := []string{base}
+	for _, ext := range resolveExtensions {
+		candidates = append(candidates, base+ext)
+	}
+	for _, ext := range resolveExtensions {
+		candidates = append(candidates, filepath.Join(base, "index"+ext))
+	}
+
+	for _, candidate := range candidates {
+		if _, ok := st.funcs[candidate]; ok {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// resolveCall fills the call target from the caller's import bindings.
+func (st *symbolTable) resolveCall(call *model.FunctionCallEntity, bindings map[string]importBinding) bool {
+	// Direct call of an imported function: helper()
+	if call.CallContext == "" {
+		b, ok := bindings[call.CalledFunc]
+		if !ok {
+			return false
+		}
+		if fn, ok := st.funcs[b.file][b.name]; ok {
+			call.ResolvedTarget = fn.Name
+			call.TargetFile = fn.FilePath
+			call.TargetID = fn.ID
+			return true
+		}
+		return false
+	}
+
+	// Method call on an imported binding: Class.method() or namespace.fn()
+	b, ok := bindings[call.CallContext]
+	if !ok {
+		return false
+	}
+	methodName := call.CalledFunc[strings.LastIndex(call.CalledFunc, ".")+1:]
+
+	// Methods are the functions declared within the class body's line range
+	if class, ok := st.classes[b.file][b.name]; ok {
+		for _, fn := range st.methods[b.file] {
+			if fn.Name == methodName && fn.StartLine >= class.StartLine && fn.EndLine <= class.EndLine {
+				call.ResolvedTarget = fn.Name
+				call.TargetFile = fn.FilePath
+				call.TargetID = fn.ID
+				return true
+			}
+		}
+		return false
+	}
+
+	// Namespace import: import * as utils from './utils'
+	if fn, ok := st.funcs[b.file][methodName]; ok {
+		call.ResolvedTarget = fn.Name
+		call.TargetFile = fn.FilePath
+		call.TargetID = fn.ID
+		return true
+	}
+
+	// Namespace import of a barrel re-exporting the function
+	if decl, ok := st.exported(b.file, methodName, map[string]bool{}); ok && decl.kind == "function" {
+		call.ResolvedTarget = decl.name
+		call.TargetFile = decl.file
+		call.TargetID = decl.id
+		return true
+	}
+	return false
+}
+
+// resolveExports follows each export of pf to the declaration it names,
+// filling TargetKind, TargetName, TargetFile and TargetID, and marks the
+// exported declarations of pf with IsExport. It returns the number of exports
+// resolved to a declaration in another file.
+func resolveExports(pf *ParsedFile, st *symbolTable) int {
+	resolved := 0
+	for i := range pf.Exports {
+		export := &pf.Exports[i]
+
+		var decl exportTarget
+		var ok bool
+		if export.Name == "*" {
+			// export * from './a' re-exports a module without naming a binding
+			if module := st.moduleFile(pf.FilePath, export.Module, export.ResolvedFile); module != "" {
+				decl, ok = exportTarget{kind: "module", file: st.files[module].FilePath}, true
+			}
+		} else {
+			decl, ok = st.exportTarget(pf, *export, map[string]bool{})
+		}
+		if !ok {
+			continue
+		}
+
+		export.TargetKind = decl.kind
+		export.TargetName = decl.name
+		export.TargetFile = decl.file
+		export.TargetID = decl.id
+		if decl.file != pf.FilePath {
+			resolved++
+			continue
+		}
+		markExported(pf, decl)
+	}
+	return resolved
+}
+
+// resolveComponents fills TargetFile and TargetID of the renders and hook
+// usages of pf: rendered tags name a component of pf or one it imports, and
+// custom hooks a function. Tags and hooks from libraries stay unresolved. It
+// returns the number resolved to another file.
+func resolveComponents(pf *ParsedFile, st *symbolTable) int {
+	resolved := 0
+	for i := range pf.Renders {
+		render := &pf.Renders[i]
+		target, ok := st.component(pf, render.TagName)
+		if !ok {
+			continue
+		}
+		render.TargetFile, render.TargetID = target.FilePath, target.ID
+		if target.FilePath != pf.FilePath {
+			resolved++
+		}
+	}
+	for i := range pf.HookUsages {
+		usage := &pf.HookUsages[i]
+		decl, ok := st.binding(pf, usage.Hook, map[string]bool{})
+		if !ok || decl.kind != "function" {
+			continue
+		}
+		usage.TargetFile, usage.TargetID = decl.file, decl.id
+		if decl.file != pf.FilePath {
+			resolved++
+		}
+	}
+	return resolved
+}
+
+// component resolves a JSX tag of pf to the component it names, declared in
+// pf or imported from another parsed file, possibly through a namespace
+// import as in <UI.Button />.
+func (st *symbolTable) component(pf *ParsedFile, tag string) (model.ComponentEntity, bool) {
+	for _, c := range pf.Components {
+		if c.Name == tag {
+			return c, true
+		}
+	}
+
+	namespace, name, qualified := strings.Cut(tag, ".")
+	decl, ok := st.binding(pf, namespace, map[string]bool{})
+	if ok && qualified {
+		if decl.kind != "module" || strings.Contains(name, ".") {
+			return model.ComponentEntity{}, false // Members of objects, not modules
+		}
+		decl, ok = st.exported(filepath.Clean(decl.file), name, map[string]bool{})
+	}
+	if !ok || decl.kind == "module" || st.files[filepath.Clean(decl.file)] == nil {
+		return model.ComponentEntity{}, false
+	}
+	for _, c := range st.files[filepath.Clean(decl.file)].Components {
+		if (decl.id != "" && c.ID == decl.id) || c.Name == decl.name {
+			return c, true
+		}
+	}
+	return model.ComponentEntity{}, false
+}
+
+// markExported sets IsExport on the declaration of pf an export names.
+func markExported(pf *ParsedFile, decl exportTarget) {
+	switch decl.kind {
+	case "function":
+		for i := range pf.Funcs {
+			if pf.Funcs[i].ID == decl.id {
+				pf.Funcs[i].IsExport = true
+			}
+		}
+	case "class":
+		for i := range pf.Classes {
+			if pf.Classes[i].ID == decl.id {
+				pf.Classes[i].IsExport = true
+			}
+		}
+	case "interface":
+		for i := range pf.Interfaces {
+			if pf.Interfaces[i].Name == decl.name {
+				pf.Interfaces[i].IsExport = true
+			}
+		}
+	case "type":
+		for i := range pf.Types {
+			if pf.Types[i].Name == decl.name {
+				pf.Types[i].IsExport = true
+			}
+		}
+	}
+}
+
+// exported resolves a name exported by a parsed file, following re-exports,
+// exported imports and export * barrels. seen breaks re-export cycles.
+func (st *symbolTable) exported(file, name string, seen map[string]bool) (exportTarget, bool) {
+	pf := st.files[file]
+	if pf == nil || seen[file+"#"+name] {
+		return exportTarget{}, false
+	}
+	seen[file+"#"+name] = true
+
+	for _, export := range pf.Exports {
+		if export.Name == name {
+			return st.exportTarget(pf, export, seen)
+		}
+	}
+
+	// export * re-exports every name of a module except its default
+	if name == "default" {
+		return exportTarget{}, false
+	}
+	for _, export := range pf.Exports {
+		if export.Name != "*" {
+			continue
+		}
+		if module := st.moduleFile(pf.FilePath, export.Module, export.ResolvedFile); module != "" {
+			if decl, ok := st.exported(module, name, seen); ok {
+				return decl, true
+			}
+		}
+	}
+	return exportTarget{}, false
+}
+
+// exportTarget resolves a single export of pf: a re-export is followed into
+// its module, anything else names a binding of pf itself.
+func (st *symbolTable) exportTarget(pf *ParsedFile, export model.ExportEntity, seen map[string]bool) (exportTarget, bool) {
+	if export.Module == "" {
+		return st.binding(pf, export.LocalName, seen)
+	}
+
+	module := st.moduleFile(pf.FilePath, export.Module, export.ResolvedFile)
+	if module == "" {
+		return exportTarget{}, false
+	}
+	if export.LocalName == "*" {
+		return exportTarget{kind: "module", file: st.files[module].FilePath}, true
+	}
+	return st.exported(module, export.LocalName, seen)
+}
+
+// binding resolves a top-level name of pf: a declaration of the file, or an
+// import followed to the declaration it names.
+func (st *symbolTable) binding(pf *ParsedFile, name string, seen map[string]bool) (exportTarget, bool) {
+	if name == "" {
+		return exportTarget{}, false
+	}
+	if decl, ok := declaration(pf, name); ok {
+		return decl, true
+	}
+
+	for _, imp := range pf.Imports {
+		if !slices.Contains(imp.ImportedNames, name) {
+			continue
+		}
+		module := st.moduleFile(pf.FilePath, imp.Module, imp.ResolvedFile)
+		if module == "" {
+			return exportTarget{}, false
+		}
+		return st.imported(imp, name, module, seen)
+	}
+	return exportTarget{}, false
+}
+
+// imported resolves a name bound by an import from module. Namespace imports
+// bind the whole module, and default imports bind its default export under
+// their own name.
+func (st *symbolTable) imported(imp model.ImportEntity, local, module string, seen map[string]bool) (exportTarget, bool) {
+	if imp.IsNamespace {
+		return exportTarget{kind: "module", file: st.files[module].FilePath}, true
+	}
+
+	exported := local
+	if original, ok := imp.Aliases[local]; ok {
+		exported = original
+	}
+	if decl, ok := st.exported(module, exported, seen); ok {
+		return decl, true
+	}
+	// The default binding is written first: import React, { useState } from 'react'
+	if imp.IsDefault && imp.ImportedNames[0] == local {
+		return st.exported(module, "default", seen)
+	}
+	return exportTarget{}, false
+}
+
+// declaration finds the top-level declaration of name in pf.
+func declaration(pf *ParsedFile, name string) (exportTarget, bool) {
+	for _, fn := range pf.Funcs {
+		if fn.QualifiedName == name && fn.Receiver == "" {
+			return exportTarget{kind: "function", name: fn.Name, file: fn.FilePath, id: fn.ID}, true
+		}
+	}
+	for _, class := range pf.Classes {
+		if class.QualifiedName == name {
+			return exportTarget{kind: "class", name: class.Name, file: class.FilePath, id: class.ID}, true
+		}
+	}
+	for _, iface := range pf.Interfaces {
+		if iface.Name == name {
+			return exportTarget{kind: "interface", name: iface.Name, file: iface.FilePath}, true
+		}
+	}
+	for _, typ := range pf.Types {
+		if typ.Name == name {
+			return exportTarget{kind: "type", name: typ.Name, file: typ.FilePath}, true
+		}
+	}
+	for _, variable := range pf.Variables {
+		if variable.Name == name {
+			return exportTarget{kind: "variable", name: variable.Name, file: variable.FilePath}, true
+		}
+	}
+	return exportTarget{}, false
+}
@@ -0,0 +1,180 @@
+// internal/driver/resolver_test.go
+
+package driver
+
+import (
+	"testing"
+
+	"goParse/internal/model"
+)
+
+// resolverProject is a small TypeScript project as the per-file pass leaves
+// it: math.ts declares, index.ts is a barrel over it, app.ts imports both and
+// cycle_a.ts and cycle_b.ts re-export each other.
+func resolverProject() []ParsedFile {
+	fn := func(file, name string, start, end int) model.FunctionEntity {
+		return model.FunctionEntity{ID: file + "#" + name, Name: name, QualifiedName: name, FilePath: file, StartLine: start, EndLine: end}
+	}
+	sum := fn("src/math.ts", "sum", 12, 14)
+	sum.ID, sum.QualifiedName = "src/math.ts#Calc.sum", "Calc.sum"
+
+	return []ParsedFile{
+		{
+			FilePath: "src/math.ts",
+			Funcs:    []model.FunctionEntity{fn("src/math.ts", "add", 1, 3), sum},
+			Classes: []model.ClassEntity{
+				{ID: "src/math.ts#Calc", Name: "Calc", QualifiedName: "Calc", FilePath: "src/math.ts", StartLine: 10, EndLine: 20},
+			},
+			Interfaces: []model.InterfaceEntity{{Name: "Shape", FilePath: "src/math.ts"}},
+			Types:      []model.TypeEntity{{Name: "Id", FilePath: "src/math.ts"}},
+			Variables:  []model.VariableEntity{{Name: "PI", FilePath: "src/math.ts"}},
+			Exports: []model.ExportEntity{
+				{Name: "add", LocalName: "add", FilePath: "src/math.ts"},
+				{Name: "Calc", LocalName: "Calc", FilePath: "src/math.ts"},
+				{Name: "Shape", LocalName: "Shape", FilePath: "src/math.ts"},
+				{Name: "Identifier", LocalName: "Id", FilePath: "src/math.ts"},
+				{Name: "PI", LocalName: "PI", FilePath: "src/math.ts"},
+				{Name: "default", LocalName: "add", FilePath: "src/math.ts"},
+			},
+		},
+		{
+			FilePath: "src/geo.ts",
+			Funcs:    []model.FunctionEntity{fn("src/geo.ts", "area", 1, 5)},
+			Exports:  []model.ExportEntity{{Name: "area", LocalName: "area", FilePath: "src/geo.ts"}},
+		},
+		{
+			FilePath: "src/index.ts",
+			Exports: []model.ExportEntity{
+				{Name: "*", LocalName: "*", FilePath: "src/index.ts", Module: "./math"},
+				{Name: "plus", LocalName: "add", FilePath: "src/index.ts", Module: "./math"},
+				{Name: "geo", LocalName: "*", FilePath: "src/index.ts", Module: "./geo"},
+				{Name: "missing", LocalName: "nope", FilePath: "src/index.ts", Module: "./math"},
+				{Name: "ghost", LocalName: "ghost", FilePath: "src/index.ts", Module: "./cycle_a"},
+				{Name: "external", LocalName: "external", FilePath: "src/index.ts", Module: "lodash"},
+			},
+		},
+		{
+			FilePath: "src/cycle_a.ts",
+			Exports:  []model.ExportEntity{{Name: "*", LocalName: "*", FilePath: "src/cycle_a.ts", Module: "./cycle_b"}},
+		},
+		{
+			FilePath: "src/cycle_b.ts",
+			Exports:  []model.ExportEntity{{Name: "*", LocalName: "*", FilePath: "src/cycle_b.ts", Module: "./cycle_a"}},
+		},
+		{
+			FilePath: "src/app.ts",
+			Funcs:    []model.FunctionEntity{fn("src/app.ts", "main", 1, 30)},
+			Imports: []model.ImportEntity{
+				{Module: "./math", ImportedNames: []string{"add", "Calc"}},
+				{Module: "./math", ImportedNames: []string{"sum2"}, Aliases: map[string]string{"sum2": "add"}},
+				{Module: "./math", ImportedNames: []string{"mathDefault"}, IsDefault: true},
+				{Module: "./math", ImportedNames: []string{"m"}, IsNamespace: true},
+				{Module: "./index", ImportedNames: []string{"plus", "ghost"}},
+				{Module: "./index", ImportedNames: []string{"lib"}, IsNamespace: true},
+				{Module: "./", ImportedNames: []string{"barrel"}, IsNamespace: true},
+				{Module: "react", ImportedNames: []string{"useState"}},
+			},
+		},
+	}
+}
+
+func TestResolveCall(t *testing.T) {
+	files := resolverProject()
+	st := buildSymbolTable(files)
+	bindings := buildImportBindings(&files[len(files)-1], st)
+
+	tests := []struct {
+		name       string
+		calledFunc string
+		context    string
+		wantID     string // "" when the call stays unresolved
+	}{
+		{"imported function", "add", "", "src/math.ts#add"},
+		{"aliased import", "sum2", "", "src/math.ts#add"},
+		{"default import", "mathDefault", "", "src/math.ts#add"},
+		{"renamed re-export of a barrel", "plus", "", "src/math.ts#add"},
+		{"re-export cycle", "ghost", "", ""},
+		{"package import", "useState", "", ""},
+		{"not imported", "helper", "", ""},
+		{"method of an imported class", "Calc.sum", "Calc", "src/math.ts#Calc.sum"},
+		{"missing method", "Calc.product", "Calc", ""},
+		{"namespace import", "m.add", "m", "src/math.ts#add"},
+		{"namespace import of a barrel", "lib.add", "lib", "src/math.ts#add"},
+		{"namespace import of a directory index", "barrel.add", "barrel", "src/math.ts#add"},
+		{"namespace member that is not a function", "m.PI", "m", ""},
+		{"local object", "obj.add", "obj", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			call := model.FunctionCallEntity{CallerFile: "src/app.ts", CalledFunc: tt.calledFunc, CallContext: tt.context}
+			ok := st.resolveCall(&call, bindings)
+			if ok != (tt.wantID != "") || call.TargetID != tt.wantID {
+				t.Fatalf("resolveCall(%q) = %v with target %q, want target %q", tt.calledFunc, ok, call.TargetID, tt.wantID)
+			}
+			if ok && (call.TargetFile != "src/math.ts" || call.ResolvedTarget == "") {
+				t.Errorf("resolved to %q in %q, want a function of src/math.ts", call.ResolvedTarget, call.TargetFile)
+			}
+		})
+	}
+}
+
+func TestResolveProjectExports(t *testing.T) {
+	files := resolverProject()
+	if got, want := ResolveProjectExports(files), 5; got != want {
+		t.Errorf("ResolveProjectExports resolved %d exports to other files, want %d", got, want)
+	}
+
+	exports := make(map[string]model.ExportEntity)
+	for _, pf := range files {
+		for _, export := range pf.Exports {
+			exports[pf.FilePath+" "+export.Name] = export
+		}
+	}
+
+	tests := []struct {
+		export string // File and exported name
+		kind   string // "" when the export stays unresolved
+		name   string
+		file   string
+		id     string
+	}{
+		{"src/math.ts add", "function", "add", "src/math.ts", "src/math.ts#add"},
+		{"src/math.ts Calc", "class", "Calc", "src/math.ts", "src/math.ts#Calc"},
+		{"src/math.ts Shape", "interface", "Shape", "src/math.ts", ""},
+		{"src/math.ts Identifier", "type", "Id", "src/math.ts", ""},
+		{"src/math.ts PI", "variable", "PI", "src/math.ts", ""},
+		{"src/math.ts default", "function", "add", "src/math.ts", "src/math.ts#add"},
+		{"src/index.ts *", "module", "", "src/math.ts", ""},
+		{"src/index.ts plus", "function", "add", "src/math.ts", "src/math.ts#add"},
+		{"src/index.ts geo", "module", "", "src/geo.ts", ""},
+		{"src/index.ts missing", "", "", "", ""},
+		{"src/index.ts ghost", "", "", "", ""},
+		{"src/index.ts external", "", "", "", ""},
+		{"src/cycle_a.ts *", "module", "", "src/cycle_b.ts", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.export, func(t *testing.T) {
+			export, ok := exports[tt.export]
+			if !ok {
+				t.Fatalf("no export %s", tt.export)
+			}
+			if export.TargetKind != tt.kind || export.TargetName != tt.name ||
+				export.TargetFile != tt.file || export.TargetID != tt.id {
+				t.Errorf("target = %s %q in %q (%q), want %s %q in %q (%q)",
+					export.TargetKind, export.TargetName, export.TargetFile, export.TargetID,
+					tt.kind, tt.name, tt.file, tt.id)
+			}
+		})
+	}
+
+	// Declarations named by an export of their own file are marked exported
+	math := files[0]
+	if !math.Funcs[0].IsExport || math.Funcs[1].IsExport {
+		t.Errorf("IsExport of add, Calc.sum = %v, %v; want true, false", math.Funcs[0].IsExport, math.Funcs[1].IsExport)
+	}
+	if !math.Classes[0].IsExport || !math.Interfaces[0].IsExport || !math.Types[0].IsExport {
+		t.Errorf("Calc, Shape and Id should be marked exported")
+	}
+}
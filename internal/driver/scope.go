@@ -0,0 +1,55 @@
+// internal/driver/scope.go
+
+package driver
+
+import (
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// scopeNodeTypes are the declarations whose names prefix the qualified names
+// of the entities declared inside them.
+var scopeNodeTypes = map[string]bool{
+	// TypeScript / JavaScript
+	"class_declaration":              true,
+	"abstract_class_declaration":     true,
+	"class":                          true,
+	"interface_declaration":          true,
+	"function_declaration":           true,
+	"generator_function_declaration": true,
+	"function_expression":            true,
+	"method_definition":              true,
+	"variable_declarator":            true,
+	"internal_module":                true,
+	"module":                         true,
+	// Python
+	"class_definition":    true,
+	"function_definition": true,
+}
+
+// qualifiedName prefixes name with the names of the scopes enclosing def,
+// outermost first, e.g. "Widget.render" for a method or "App.handleClick"
+// for a function declared inside a component. Anonymous scopes are skipped.
+func (t *TreeSitterDriver) qualifiedName(def *sitter.Node, name string, src []byte) string {
+	parts := []string{name}
+	for current := def.Parent(); current != nil; current = current.Parent() {
+		if !scopeNodeTypes[current.Type()] {
+			continue
+		}
+		nameNode := current.ChildByFieldName("name")
+		if nameNode == nil {
+			continue
+		}
+		// Destructuring patterns declare several names and scope none of them
+		if current.Type() == "variable_declarator" && nameNode.Type() != "identifier" {
+			continue
+		}
+		parts = append(parts, string(src[nameNode.StartByte():nameNode.EndByte()]))
+	}
+
+	for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
+		parts[i], parts[j] = parts[j], parts[i]
+	}
+	return strings.Join(parts, ".")
+}
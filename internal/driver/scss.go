@@ -0,0 +1,535 @@
+// internal/driver/scss.go
+
+package driver
+
+import (
+	"bytes"
+	"goParse/internal/model"
+	"slices"
+	"strings"
+)
+
+// go-tree-sitter ships no SCSS grammar, and the CSS one turns nesting, $variables
+// and most at-rules into ERROR nodes, so SCSS files are read by the small
+// statement scanner below. It only needs statement boundaries: selectors,
+// at-rule preludes and declarations are split on the top-level {, ; and }
+// that follow them, skipping strings, comments, parentheses and #{}
+// interpolation.
+
+// scssStatement is a selector, at-rule or declaration with the character
+// ending it: '{' opens a block, ';' ends a statement and '}' closes the
+// enclosing block, or 0 at the end of the file.
+type scssStatement struct {
+	text string
+	line int
+	end  byte
+}
+
+// scssScanner reads the statements of an SCSS file in order.
+type scssScanner struct {
+	src  []byte
+	pos  int
+	line int
+}
+
+// next returns the next statement, with comments removed and whitespace
+// trimmed.
+func (s *scssScanner) next() scssStatement {
+	var text strings.Builder
+	stmt := scssStatement{line: -1}
+	parens, interpolations := 0, 0
+
+	for s.pos < len(s.src) {
+		c := s.src[s.pos]
+		switch {
+		case c == '\n':
+			s.line++
+		case c == '/' && s.peek(1) == '*':
+			end := bytes.Index(s.src[s.pos+2:], []byte("*/"))
+			if end < 0 {
+				end = len(s.src) - s.pos - 2
+			}
+			s.advance(end + 4)
+			text.WriteByte(' ')
+			continue
+		case c == '/' && s.peek(1) == '/' && parens == 0:
+			// Line comments; inside parentheses // belongs to a url()
+			for s.pos < len(s.src) && s.src[s.pos] != '\n' {
+				s.pos++
+			}
+			continue
+		case c == '"' || c == '\'':
+			start := s.pos
+			s.pos++
+			for s.pos < len(s.src) && s.src[s.pos] != c && s.src[s.pos] != '\n' {
+				if s.src[s.pos] == '\\' {
+					s.pos++
+				}
+				s.pos++
+			}
+			s.pos = min(s.pos+1, len(s.src))
+			if stmt.line < 0 {
+				stmt.line = s.line
+			}
+			text.Write(s.src[start:s.pos])
+			continue
+		case c == '#' && s.peek(1) == '{':
+			interpolations++
+			text.WriteString("#{")
+			if stmt.line < 0 {
+				stmt.line = s.line
+			}
+			s.pos += 2
+			continue
+		case c == '(' || c == '[':
+			parens++
+		case c == ')' || c == ']':
+			parens = max(parens-1, 0)
+		case c == '}' && interpolations > 0:
+			interpolations--
+		case (c == '{' || c == ';' || c == '}') && parens == 0:
+			s.pos++
+			stmt.text = strings.TrimSpace(text.String())
+			stmt.end = c
+			return stmt
+		}
+		if stmt.line < 0 && c != ' ' && c != '\t' && c != '\n' && c != '\r' {
+			stmt.line = s.line
+		}
+		text.WriteByte(c)
+		s.pos++
+	}
+	stmt.text = strings.TrimSpace(text.String())
+	return stmt
+}
+
+// peek returns the byte offset bytes ahead, or 0 past the end.
+func (s *scssScanner) peek(offset int) byte {
+	if s.pos+offset < len(s.src) {
+		return s.src[s.pos+offset]
+	}
+	return 0
+}
+
+// advance skips n bytes, counting the lines they span.
+func (s *scssScanner) advance(n int) {
+	end := min(s.pos+n, len(s.src))
+	s.line += bytes.Count(s.src[s.pos:end], []byte("\n"))
+	s.pos = end
+}
+
+// scssScope is what the statements of a block belong to: the flattened
+// selectors of the enclosing style rules, and the rules an @include or
+// @extend in the block is recorded on.
+type scssScope struct {
+	selectors []string
+	owners    []string
+	root      bool
+}
+
+// parseSCSS extracts the rules, variables, mixins, imports, includes and
+// extends of an SCSS file. Nested selectors are flattened to full
+// selectors, with & replaced by the parent, before their classes, IDs and
+// placeholders are recorded as CSS rules.
+func (t *TreeSitterDriver) parseSCSS(pf *ParsedFile, src []byte) {
+	s := &scssScanner{src: src, line: 1}
+	t.scssBlock(pf, s, scssScope{root: true})
+
+	// Mixins and selectors declared in this file resolve right away
+	declared := make(map[[2]string]bool)
+	for _, rule := range pf.CSSRules {
+		declared[[2]string{rule.RuleType, rule.Selector}] = true
+	}
+	for i := range pf.StyleReferences {
+		ref := &pf.StyleReferences[i]
+		if ref.Namespace == "" && declaresStyle(declared, *ref) {
+			ref.TargetFile = pf.FilePath
+		}
+	}
+}
+
+// scssBlock reads statements up to the end of the current block.
+func (t *TreeSitterDriver) scssBlock(pf *ParsedFile, s *scssScanner, scope scssScope) {
+	for {
+		stmt := s.next()
+		if stmt.end == '{' {
+			t.scssNested(pf, s, scope, stmt)
+			continue
+		}
+		if stmt.text != "" {
+			t.scssStatement(pf, scope, stmt)
+		}
+		if stmt.end != ';' {
+			return // '}' or the end of the file
+		}
+	}
+}
+
+// scssNested handles a statement opening a block: a style rule, an at-rule
+// with a body or a nested property such as font: { family: ... }.
+func (t *TreeSitterDriver) scssNested(pf *ParsedFile, s *scssScanner, scope scssScope, stmt scssStatement) {
+	body := scope
+	body.root = false
+
+	if name, rest, ok := scssAtRule(stmt.text); ok {
+		switch name {
+		case "mixin", "function":
+			// The body of a mixin belongs to the mixin; & is unknown until it is included
+			mixin, params := scssCallee(rest)
+			pf.CSSRules = append(pf.CSSRules, model.CSSRuleEntity{
+				Selector: mixin,
+				RuleType: name,
+				FilePath: pf.FilePath,
+				Line:     stmt.line,
+				Value:    params,
+			})
+			body = scssScope{owners: []string{mixin}}
+		case "include":
+			t.scssStatement(pf, scope, stmt) // The block is the mixin's @content
+		case "at-root":
+			body.selectors = nil
+			if rest != "" {
+				stmt.text = rest
+				t.scssRule(pf, s, scssScope{owners: scope.owners}, stmt)
+				return
+			}
+		}
+		t.scssBlock(pf, s, body)
+		return
+	}
+
+	if strings.HasSuffix(stmt.text, ":") {
+		t.scssBlock(pf, s, body) // Nested properties
+		return
+	}
+	t.scssRule(pf, s, scope, stmt)
+}
+
+// scssRule records the classes, IDs and placeholders of a style rule's
+// flattened selectors, each with the full selector it appears in, then reads
+// its body.
+func (t *TreeSitterDriver) scssRule(pf *ParsedFile, s *scssScanner, scope scssScope, stmt scssStatement) {
+	body := scssScope{selectors: flattenSelectors(scope.selectors, stmt.text)}
+	for _, selector := range body.selectors {
+		names := selectorNames(selector)
+		for _, name := range names {
+			pf.CSSRules = append(pf.CSSRules, model.CSSRuleEntity{
+				Selector:     name,
+				FullSelector: selector,
+				RuleType:     selectorRuleType(name),
+				FilePath:     pf.FilePath,
+				Line:         stmt.line,
+			})
+		}
+		// @include and @extend apply to the rule's subject, its rightmost name
+		// outside pseudo-class arguments such as :not(.hidden)
+		if subject := selectorNames(selectorOutsideParens(selector)); len(subject) > 0 && !slices.Contains(body.owners, subject[len(subject)-1]) {
+			body.owners = append(body.owners, subject[len(subject)-1])
+		}
+	}
+	t.scssBlock(pf, s, body)
+}
+
+// scssStatement handles a statement without a block: an at-rule such as
+// @use, @import, @include or @extend, or a declaration.
+func (t *TreeSitterDriver) scssStatement(pf *ParsedFile, scope scssScope, stmt scssStatement) {
+	name, rest, ok := scssAtRule(stmt.text)
+	if !ok {
+		// $variables of the file scope and CSS custom properties anywhere
+		property, value, found := strings.Cut(stmt.text, ":")
+		property = strings.TrimSpace(property)
+		if !found || !(strings.HasPrefix(property, "--") || strings.HasPrefix(property, "$") && scope.root) {
+			return
+		}
+		value = strings.TrimSpace(value)
+		for _, flag := range []string{"!default", "!global"} {
+			value = strings.TrimSpace(strings.ReplaceAll(value, flag, ""))
+		}
+		pf.CSSRules = append(pf.CSSRules, model.CSSRuleEntity{
+			Selector:     property,
+			RuleType:     "variable",
+			FilePath:     pf.FilePath,
+			Line:         stmt.line,
+			PropertyName: property,
+			Value:        value,
+		})
+		return
+	}
+
+	switch name {
+	case "use":
+		// @use 'src/corners' binds the corners namespace, as * none
+		url, rest := scssURL(rest)
+		if url == "" {
+			return
+		}
+		imp := model.ImportEntity{Module: url, FilePath: pf.FilePath, IsNamespace: true}
+		namespace := scssNamespace(url)
+		if fields := strings.Fields(rest); len(fields) >= 2 && fields[0] == "as" {
+			namespace = fields[1]
+		}
+		if namespace != "*" {
+			imp.ImportedNames = []string{namespace}
+		}
+		pf.Imports = append(pf.Imports, imp)
+
+	case "forward", "import":
+		// @import takes a list: @import 'reset', 'theme';
+		for _, part := range splitTopLevel(rest, ',') {
+			if url, _ := scssURL(part); url != "" {
+				pf.Imports = append(pf.Imports, model.ImportEntity{Module: url, FilePath: pf.FilePath})
+			}
+			if name == "forward" {
+				break
+			}
+		}
+
+	case "include":
+		// @include m.button(...) names the button mixin of the m namespace
+		mixin, _ := scssCallee(rest)
+		namespace := ""
+		if dot := strings.LastIndex(mixin, "."); dot >= 0 {
+			namespace, mixin = mixin[:dot], mixin[dot+1:]
+		}
+		t.addStyleReferences(pf, scope, model.StyleReferenceEntity{
+			Kind:      "include",
+			Target:    mixin,
+			Namespace: namespace,
+			FilePath:  pf.FilePath,
+			Line:      stmt.line,
+		})
+
+	case "extend":
+		target := strings.TrimSpace(strings.ReplaceAll(rest, "!optional", ""))
+		for _, selector := range splitTopLevel(target, ',') {
+			t.addStyleReferences(pf, scope, model.StyleReferenceEntity{
+				Kind:     "extend",
+				Target:   strings.Join(strings.Fields(selector), " "),
+				FilePath: pf.FilePath,
+				Line:     stmt.line,
+			})
+		}
+	}
+}
+
+// addStyleReferences records ref once for each rule of the scope, or once
+// without a source outside any rule with a class, ID or placeholder.
+func (t *TreeSitterDriver) addStyleReferences(pf *ParsedFile, scope scssScope, ref model.StyleReferenceEntity) {
+	if ref.Target == "" {
+		return
+	}
+	if len(scope.owners) == 0 {
+		pf.StyleReferences = append(pf.StyleReferences, ref)
+		return
+	}
+	for _, owner := range scope.owners {
+		ref.Source = owner
+		pf.StyleReferences = append(pf.StyleReferences, ref)
+	}
+}
+
+// scssAtRule splits "@name rest" into its name and the text after it.
+func scssAtRule(text string) (string, string, bool) {
+	if !strings.HasPrefix(text, "@") {
+		return "", "", false
+	}
+	end := 1
+	for end < len(text) && (isIdentByte(text[end]) || text[end] == '-') {
+		end++
+	}
+	return text[1:end], strings.TrimSpace(text[end:]), true
+}
+
+// scssCallee splits "name(args) ..." into the name and its parenthesized
+// arguments, as written.
+func scssCallee(text string) (string, string) {
+	end := 0
+	for end < len(text) && (isIdentByte(text[end]) || text[end] == '-' || text[end] == '.') {
+		end++
+	}
+	name, rest := text[:end], strings.TrimSpace(text[end:])
+	if !strings.HasPrefix(rest, "(") {
+		return name, ""
+	}
+	depth := 0
+	for i, c := range rest {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return name, rest[:i+1]
+			}
+		}
+	}
+	return name, rest
+}
+
+// scssURL reads the quoted or url() module of an @use, @forward or @import,
+// returning the text after it.
+func scssURL(text string) (string, string) {
+	text = strings.TrimSpace(text)
+	if inner, ok := strings.CutPrefix(text, "url("); ok {
+		url, rest, _ := strings.Cut(inner, ")")
+		return strings.Trim(strings.TrimSpace(url), `"'`), rest
+	}
+	if text == "" || (text[0] != '"' && text[0] != '\'') {
+		return "", text
+	}
+	end := strings.IndexByte(text[1:], text[0])
+	if end < 0 {
+		return "", text
+	}
+	return text[1 : end+1], text[end+2:]
+}
+
+// scssNamespace returns the default namespace of an @use: the last segment of
+// its URL without a partial's underscore or the extension.
+func scssNamespace(url string) string {
+	name := url[strings.LastIndex(url, "/")+1:]
+	if i := strings.IndexByte(name, '.'); i >= 0 {
+		name = name[:i]
+	}
+	if strings.HasPrefix(url, "sass:") {
+		return strings.TrimPrefix(name, "sass:")
+	}
+	return strings.TrimPrefix(name, "_")
+}
+
+// flattenSelectors combines each selector of a nested rule with each of its
+// parents: & is replaced by the parent, and other selectors are its
+// descendants. Top-level selectors are returned as written.
+func flattenSelectors(parents []string, selector string) []string {
+	var flattened []string
+	for _, child := range splitTopLevel(selector, ',') {
+		child = strings.Join(strings.Fields(child), " ")
+		if child == "" {
+			continue
+		}
+		if len(parents) == 0 {
+			flattened = append(flattened, strings.ReplaceAll(child, "&", ""))
+			continue
+		}
+		for _, parent := range parents {
+			if strings.Contains(child, "&") {
+				flattened = append(flattened, strings.ReplaceAll(child, "&", parent))
+			} else {
+				flattened = append(flattened, parent+" "+child)
+			}
+		}
+	}
+	return flattened
+}
+
+// selectorNames returns the classes, IDs and placeholders of a selector in
+// order, e.g. .card, #main and %message. Attribute selectors, strings and
+// names built by #{} interpolation are skipped.
+func selectorNames(selector string) []string {
+	var names []string
+	for i := 0; i < len(selector); i++ {
+		switch c := selector[i]; c {
+		case '[':
+			if end := strings.IndexByte(selector[i:], ']'); end >= 0 {
+				i += end
+			}
+		case '"', '\'':
+			if end := strings.IndexByte(selector[i+1:], c); end >= 0 {
+				i += end + 1
+			}
+		case '.', '#', '%':
+			start := i + 1
+			end := start
+			if end < len(selector) && selector[end] == '-' {
+				end++
+			}
+			if end >= len(selector) || !(isIdentByte(selector[end]) && !isDigit(selector[end])) {
+				break
+			}
+			for end < len(selector) && (isIdentByte(selector[end]) || selector[end] == '-' || selector[end] == '\\') {
+				if selector[end] == '\\' {
+					end++ // Escaped characters, as in .md\:flex
+				}
+				end++
+			}
+			end = min(end, len(selector))
+			if !strings.HasPrefix(selector[end:], "#{") {
+				names = append(names, string(c)+selector[start:end])
+			}
+			i = end - 1
+		}
+	}
+	return names
+}
+
+// selectorOutsideParens returns selector without its parenthesized parts.
+func selectorOutsideParens(selector string) string {
+	var b strings.Builder
+	depth := 0
+	for i := 0; i < len(selector); i++ {
+		switch c := selector[i]; {
+		case c == '(':
+			depth++
+		case c == ')' && depth > 0:
+			depth--
+		case depth == 0:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// selectorRuleType returns the CSS rule type of a selector name.
+func selectorRuleType(name string) string {
+	switch name[0] {
+	case '#':
+		return "id"
+	case '%':
+		return "placeholder"
+	}
+	return "class"
+}
+
+// declaresStyle reports whether the rules in declared, keyed by rule type and
+// selector, include the target of ref: a mixin for @include, a class, ID or
+// placeholder for @extend.
+func declaresStyle(declared map[[2]string]bool, ref model.StyleReferenceEntity) bool {
+	if ref.Kind == "include" {
+		return declared[[2]string{"mixin", ref.Target}]
+	}
+	return ref.Target != "" && declared[[2]string{selectorRuleType(ref.Target), ref.Target}]
+}
+
+// splitTopLevel splits text on sep outside parentheses, brackets and strings.
+func splitTopLevel(text string, sep byte) []string {
+	var parts []string
+	depth, start := 0, 0
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '(' || c == '[':
+			depth++
+		case c == ')' || c == ']':
+			depth--
+		case c == sep && depth == 0:
+			parts = append(parts, strings.TrimSpace(text[start:i]))
+			start = i + 1
+		}
+	}
+	return append(parts, strings.TrimSpace(text[start:]))
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || isDigit(c) || c >= 0x80
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,83 @@
+// internal/driver/scss_resolver.go
+
+package driver
+
+import (
+	"goParse/internal/model"
+	"path/filepath"
+	"slices"
+)
+
+// ResolveProjectStyles runs a project-wide pass over the SCSS files, finding
+// the file that declares the mixin of each @include and the selector of each
+// @extend not declared in the including file. Namespaced mixins such as
+// m.button are looked up in the module the namespace was bound to by @use;
+// other mixins in the modules made visible by @import, @forward and
+// @use ... as *, and extended selectors in any module loaded upstream. It
+// returns the number of references resolved.
+func ResolveProjectStyles(files []ParsedFile) int {
+	byPath := make(map[string]*ParsedFile)
+	for i := range files {
+		if files[i].Language == "scss" {
+			byPath[filepath.Clean(files[i].FilePath)] = &files[i]
+		}
+	}
+
+	resolved := 0
+	for _, pf := range byPath {
+		for i := range pf.StyleReferences {
+			ref := &pf.StyleReferences[i]
+			if ref.TargetFile != "" {
+				continue
+			}
+			if file := styleDeclaration(byPath, pf, *ref); file != "" {
+				ref.TargetFile = file
+				resolved++
+			}
+		}
+	}
+	return resolved
+}
+
+// styleDeclaration returns the path of the file declaring the target of ref,
+// searching the modules visible from pf depth first, or "".
+func styleDeclaration(byPath map[string]*ParsedFile, pf *ParsedFile, ref model.StyleReferenceEntity) string {
+	seen := make(map[string]bool)
+	var search func(pf *ParsedFile) string
+	search = func(pf *ParsedFile) string {
+		key := filepath.Clean(pf.FilePath)
+		if seen[key] {
+			return ""
+		}
+		seen[key] = true
+
+		declared := make(map[[2]string]bool, len(pf.CSSRules))
+		for _, rule := range pf.CSSRules {
+			declared[[2]string{rule.RuleType, rule.Selector}] = true
+		}
+		if declaresStyle(declared, ref) {
+			return pf.FilePath
+		}
+		// Namespaced @use members are not visible to the files using this one,
+		// but @extend reaches the selectors of every module loaded upstream
+		for _, imp := range pf.Imports {
+			if imported := byPath[filepath.Clean(imp.ResolvedFile)]; imported != nil && (len(imp.ImportedNames) == 0 || ref.Kind == "extend") {
+				if file := search(imported); file != "" {
+					return file
+				}
+			}
+		}
+		return ""
+	}
+
+	if ref.Namespace == "" {
+		return search(pf)
+	}
+	seen[filepath.Clean(pf.FilePath)] = true
+	for _, imp := range pf.Imports {
+		if imported := byPath[filepath.Clean(imp.ResolvedFile)]; imported != nil && slices.Contains(imp.ImportedNames, ref.Namespace) {
+			return search(imported)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,93 @@
+// internal/driver/scss_test.go
+
+package driver
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSCSSScanner(t *testing.T) {
+	// Statements without text, such as closing braces, have no line
+	tests := []struct {
+		name string
+		src  string
+		want []scssStatement
+	}{
+		{"rule", ".a { color: red; }", []scssStatement{
+			{".a", 1, '{'}, {"color: red", 1, ';'}, {"", -1, '}'}, {"", -1, 0},
+		}},
+		{"last declaration without semicolon", ".a { a: b }", []scssStatement{
+			{".a", 1, '{'}, {"a: b", 1, '}'}, {"", -1, 0},
+		}},
+		{"comments", "// note { ;\n.a {\n  /* { } */ b: 1;\n}\n", []scssStatement{
+			{".a", 2, '{'}, {"b: 1", 3, ';'}, {"", -1, '}'}, {"", -1, 0},
+		}},
+		{"unterminated comment", "a: b; /* open", []scssStatement{
+			{"a: b", 1, ';'}, {"", -1, 0},
+		}},
+		{"strings", `.x { content: "a;b}"; quotes: 'it\'s'; }`, []scssStatement{
+			{".x", 1, '{'}, {`content: "a;b}"`, 1, ';'}, {`quotes: 'it\'s'`, 1, ';'}, {"", -1, '}'}, {"", -1, 0},
+		}},
+		{"parentheses", "$map: (key: 1; other: 2);\n$list: [a; b];", []scssStatement{
+			{"$map: (key: 1; other: 2)", 1, ';'}, {"$list: [a; b]", 2, ';'}, {"", -1, 0},
+		}},
+		{"interpolation", ".icon-#{$name} { a: #{$b}; }", []scssStatement{
+			{".icon-#{$name}", 1, '{'}, {"a: #{$b}", 1, ';'}, {"", -1, '}'}, {"", -1, 0},
+		}},
+		{"url", "a { background: url(http://x/y.png); }", []scssStatement{
+			{"a", 1, '{'}, {"background: url(http://x/y.png)", 1, ';'}, {"", -1, '}'}, {"", -1, 0},
+		}},
+		{"at-rules", "@use 'sass:math';\n@include mq(\"x\") { }\n@import \"a\"", []scssStatement{
+			{"@use 'sass:math'", 1, ';'}, {`@include mq("x")`, 2, '{'}, {"", -1, '}'}, {`@import "a"`, 3, 0},
+		}},
+		{"empty", "", []scssStatement{{"", -1, 0}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &scssScanner{src: []byte(tt.src), line: 1}
+			var got []scssStatement
+			for len(got) <= len(tt.want) {
+				stmt := s.next()
+				got = append(got, stmt)
+				if stmt.end == 0 {
+					break
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("statements = %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlattenSelectors(t *testing.T) {
+	tests := []struct {
+		name     string
+		parents  []string
+		selector string
+		want     []string
+	}{
+		{"top level", nil, ".card", []string{".card"}},
+		{"top level list", nil, ".a, .b", []string{".a", ".b"}},
+		{"top level &", nil, "&.active", []string{".active"}},
+		{"whitespace", nil, "  .a \n  >  .b ", []string{".a > .b"}},
+		{"descendant", []string{".card"}, ".title", []string{".card .title"}},
+		{"child combinator", []string{".card"}, "> .title", []string{".card > .title"}},
+		{"parent suffix", []string{".card"}, "&__title", []string{".card__title"}},
+		{"parent modifier", []string{".btn"}, "&:hover, &.is-active", []string{".btn:hover", ".btn.is-active"}},
+		{"parent after", []string{".card"}, ".dark &", []string{".dark .card"}},
+		{"every parent", []string{".a", ".b"}, ".c, &-d", []string{".a .c", ".b .c", ".a-d", ".b-d"}},
+		{"commas in parentheses", []string{".list"}, ":not(.a, .b)", []string{".list :not(.a, .b)"}},
+		{"empty parts", []string{".a"}, ".b, , .c", []string{".a .b", ".a .c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := flattenSelectors(tt.parents, tt.selector); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("flattenSelectors(%q, %q) = %q, want %q", tt.parents, tt.selector, got, tt.want)
+			}
+		})
+	}
+}
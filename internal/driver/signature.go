@@ -0,0 +1,124 @@
+// internal/driver/signature.go
+
+package driver
+
+import (
+	"goParse/internal/model"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// applyFunctionSignature fills in the parameters, return type, type parameters
+// and async, generator and export flags of a TS/JS function. def is the
+// declaration, or the variable_declarator of a function assigned to a variable.
+func (t *TreeSitterDriver) applyFunctionSignature(fn *model.FunctionEntity, def *sitter.Node, src []byte) {
+	fn.IsExport = isExported(def)
+
+	node := def
+	if def.Type() == "variable_declarator" {
+		if node = def.ChildByFieldName("value"); node == nil {
+			return
+		}
+	}
+
+	switch node.Type() {
+	case "generator_function_declaration", "generator_function":
+		fn.IsGenerator = true
+	}
+	for i := 0; i < int(node.ChildCount()); i++ {
+		switch node.Child(i).Type() {
+		case "async":
+			fn.IsAsync = true
+		case "*":
+			fn.IsGenerator = true // Generator methods
+		}
+	}
+
+	if params := node.ChildByFieldName("parameters"); params != nil {
+		for i := 0; i < int(params.NamedChildCount()); i++ {
+			if param, ok := tsParameter(params.NamedChild(i), src); ok {
+				fn.Parameters = append(fn.Parameters, param)
+			}
+		}
+	} else if param := node.ChildByFieldName("parameter"); param != nil {
+		fn.Parameters = append(fn.Parameters, model.Parameter{Name: nodeText(param, src)}) // x => ...
+	}
+
+	fn.ReturnType = typeAnnotation(node.ChildByFieldName("return_type"), src)
+
+	if typeParams := node.ChildByFieldName("type_parameters"); typeParams != nil {
+		for i := 0; i < int(typeParams.NamedChildCount()); i++ {
+			typeParam := typeParams.NamedChild(i)
+			if typeParam.Type() != "type_parameter" {
+				continue
+			}
+			fn.TypeParameters = append(fn.TypeParameters, model.TypeParameter{
+				Name:       nodeText(typeParam.ChildByFieldName("name"), src),
+				Constraint: trimKeyword(nodeText(typeParam.ChildByFieldName("constraint"), src), "extends"),
+				Default:    trimKeyword(nodeText(typeParam.ChildByFieldName("value"), src), "="),
+			})
+		}
+	}
+}
+
+// tsParameter reads one entry of formal_parameters. TypeScript wraps every
+// parameter in a required_parameter or optional_parameter; JavaScript lists
+// identifiers, assignment_pattern defaults and rest_pattern directly.
+// Parameters with a default are optional to callers. TypeScript's this
+// parameter is not a parameter and is skipped.
+func tsParameter(node *sitter.Node, src []byte) (model.Parameter, bool) {
+	var param model.Parameter
+	var pattern *sitter.Node
+	switch node.Type() {
+	case "required_parameter", "optional_parameter":
+		pattern = node.ChildByFieldName("pattern")
+		param.Type = typeAnnotation(node.ChildByFieldName("type"), src)
+		param.DefaultValue = nodeText(node.ChildByFieldName("value"), src)
+		param.IsOptional = node.Type() == "optional_parameter"
+	case "assignment_pattern":
+		pattern = node.ChildByFieldName("left")
+		param.DefaultValue = nodeText(node.ChildByFieldName("right"), src)
+	case "identifier", "rest_pattern", "object_pattern", "array_pattern":
+		pattern = node
+	default:
+		return param, false // Comments and decorators
+	}
+	if pattern == nil || pattern.Type() == "this" {
+		return param, false
+	}
+
+	param.Name = nodeText(pattern, src) // Destructuring patterns keep their text
+	if pattern.Type() == "rest_pattern" {
+		param.Name = strings.TrimPrefix(param.Name, "...")
+		param.IsRest = true
+	}
+	if param.DefaultValue != "" {
+		param.IsOptional = true
+	}
+	return param, true
+}
+
+// isExported reports whether a declaration, or the variable declaration
+// holding def, is the subject of an export statement.
+func isExported(def *sitter.Node) bool {
+	parent := def.Parent()
+	if parent != nil && (parent.Type() == "lexical_declaration" || parent.Type() == "variable_declaration") {
+		parent = parent.Parent()
+	}
+	return parent != nil && parent.Type() == "export_statement"
+}
+
+// nodeText returns the source text of node, or "" when it is nil.
+func nodeText(node *sitter.Node, src []byte) string {
+	if node == nil {
+		return ""
+	}
+	return string(src[node.StartByte():node.EndByte()])
+}
+
+// trimKeyword strips the leading keyword or operator of a clause such as
+// "extends Base" or "= string".
+func trimKeyword(text, keyword string) string {
+	return strings.TrimSpace(strings.TrimPrefix(text, keyword))
+}
@@ -0,0 +1,268 @@
+// internal/driver/styles.go
+
+package driver
+
+import (
+	"goParse/internal/model"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// classNameHelpers are the functions commonly used to join conditional class
+// names: clsx, classnames and their usual local names.
+var classNameHelpers = map[string]bool{
+	"clsx":       true,
+	"classnames": true,
+	"classNames": true,
+	"cx":         true,
+	"cn":         true,
+}
+
+// jsxElementKey identifies a JSX element within its file.
+type jsxElementKey struct {
+	tagName string
+	line    int
+}
+
+// classReference is a class named in a className expression, with the
+// stylesheet import of the CSS Modules object it was read from, if any.
+type classReference struct {
+	name   string
+	module string
+}
+
+// extractClassUsages records the CSS classes each JSX element names in its
+// className attribute: the words of string literals, the static words of
+// template strings, the arguments of clsx and classnames, and CSS Modules
+// members such as styles.header. The names are also kept on the element.
+func (t *TreeSitterDriver) extractClassUsages(pf *ParsedFile, src []byte, root *sitter.Node) {
+	modules := styleModules(pf.Imports)
+
+	elements := make(map[jsxElementKey][]int)
+	for i, jsx := range pf.JSXElements {
+		key := jsxElementKey{jsx.TagName, jsx.Line}
+		elements[key] = append(elements[key], i)
+	}
+
+	seen := make(map[classReference]map[jsxElementKey]bool)
+	var walk func(node *sitter.Node)
+	walk = func(node *sitter.Node) {
+		switch node.Type() {
+		case "jsx_opening_element", "jsx_self_closing_element":
+			tag := node.ChildByFieldName("name")
+			value := classNameValue(node, src)
+			if tag == nil || value == nil {
+				break
+			}
+			// Elements are keyed by the line of their tag, as extractJSXElements records them
+			line := int(tag.StartPoint().Row) + 1
+			key := jsxElementKey{nodeText(tag, src), line}
+
+			var names []string
+			for _, ref := range classReferences(value, src, modules) {
+				if seen[ref] == nil {
+					seen[ref] = make(map[jsxElementKey]bool)
+				}
+				if seen[ref][key] {
+					continue
+				}
+				seen[ref][key] = true
+				if !slices.Contains(names, ref.name) {
+					names = append(names, ref.name)
+				}
+				pf.ClassUsages = append(pf.ClassUsages, model.ClassUsageEntity{
+					ClassName: ref.name,
+					TagName:   key.tagName,
+					FilePath:  pf.FilePath,
+					Line:      line,
+					Component: componentAt(pf.Components, line),
+					Module:    ref.module,
+				})
+			}
+			for _, i := range elements[key] {
+				pf.JSXElements[i].ClassNames = append(pf.JSXElements[i].ClassNames, names...)
+			}
+		}
+		for i := 0; i < int(node.NamedChildCount()); i++ {
+			walk(node.NamedChild(i))
+		}
+	}
+	walk(root)
+}
+
+// styleModules maps the names bound by stylesheet imports, as in
+// import styles from './Header.module.css', to the imported module.
+func styleModules(imports []model.ImportEntity) map[string]string {
+	modules := make(map[string]string)
+	for _, imp := range imports {
+		module, _, _ := strings.Cut(imp.Module, "?")
+		switch filepath.Ext(module) {
+		case ".css", ".scss", ".sass", ".less":
+			for _, name := range imp.ImportedNames {
+				modules[name] = imp.Module
+			}
+		}
+	}
+	return modules
+}
+
+// classNameValue returns the value of an element's own className attribute.
+func classNameValue(element *sitter.Node, src []byte) *sitter.Node {
+	for i := 0; i < int(element.NamedChildCount()); i++ {
+		attr := element.NamedChild(i)
+		if attr.Type() != "jsx_attribute" || attr.NamedChildCount() < 2 {
+			continue
+		}
+		if nodeText(attr.NamedChild(0), src) == "className" {
+			return attr.NamedChild(1)
+		}
+	}
+	return nil
+}
+
+// classReferences returns the classes a className expression can produce.
+// Both branches of conditionals count, and values only known at runtime,
+// such as variables and template substitutions, name no class.
+func classReferences(node *sitter.Node, src []byte, modules map[string]string) []classReference {
+	var refs []classReference
+	add := func(text string) {
+		for _, name := range strings.Fields(text) {
+			refs = append(refs, classReference{name: name})
+		}
+	}
+
+	var collect func(node *sitter.Node)
+	collect = func(node *sitter.Node) {
+		if node == nil {
+			return
+		}
+		switch node.Type() {
+		case "string":
+			add(literalArgument(node, src))
+
+		case "template_string":
+			// Words touching a substitution, as in btn-${size}, are incomplete
+			for i := 0; i < int(node.NamedChildCount()); i++ {
+				part := node.NamedChild(i)
+				if part.Type() == "template_substitution" {
+					for j := 0; j < int(part.NamedChildCount()); j++ {
+						collect(part.NamedChild(j))
+					}
+					continue
+				}
+				text := nodeText(part, src)
+				words := strings.Fields(text)
+				if len(words) > 0 && i > 0 && !startsWithSpace(text) {
+					words = words[1:]
+				}
+				if len(words) > 0 && i+1 < int(node.NamedChildCount()) && !endsWithSpace(text) {
+					words = words[:len(words)-1]
+				}
+				add(strings.Join(words, " "))
+			}
+
+		case "jsx_expression", "parenthesized_expression", "array":
+			for i := 0; i < int(node.NamedChildCount()); i++ {
+				collect(node.NamedChild(i))
+			}
+
+		case "ternary_expression":
+			collect(node.ChildByFieldName("consequence"))
+			collect(node.ChildByFieldName("alternative"))
+
+		case "binary_expression":
+			// active && 'active' yields its right side; a || b and a + b either
+			switch nodeText(node.ChildByFieldName("operator"), src) {
+			case "&&":
+				collect(node.ChildByFieldName("right"))
+			case "||", "??", "+":
+				collect(node.ChildByFieldName("left"))
+				collect(node.ChildByFieldName("right"))
+			}
+
+		case "object":
+			// clsx({ active: isActive, [styles.open]: open })
+			for i := 0; i < int(node.NamedChildCount()); i++ {
+				pair := node.NamedChild(i)
+				switch pair.Type() {
+				case "pair":
+					key := pair.ChildByFieldName("key")
+					switch key.Type() {
+					case "property_identifier":
+						add(nodeText(key, src))
+					case "computed_property_name":
+						for j := 0; j < int(key.NamedChildCount()); j++ {
+							collect(key.NamedChild(j))
+						}
+					default:
+						collect(key)
+					}
+				case "shorthand_property_identifier":
+					add(nodeText(pair, src))
+				}
+			}
+
+		case "call_expression":
+			callee := node.ChildByFieldName("function")
+			args := node.ChildByFieldName("arguments")
+			if callee == nil || args == nil {
+				return
+			}
+			switch {
+			case callee.Type() == "identifier" && classNameHelpers[nodeText(callee, src)]:
+				for i := 0; i < int(args.NamedChildCount()); i++ {
+					collect(args.NamedChild(i))
+				}
+			case callee.Type() == "member_expression" && nodeText(callee.ChildByFieldName("property"), src) == "join":
+				collect(callee.ChildByFieldName("object")) // ['a', b && 'b'].join(' ')
+			}
+
+		case "member_expression":
+			// styles.header
+			object, property := node.ChildByFieldName("object"), node.ChildByFieldName("property")
+			if module, ok := modules[nodeText(object, src)]; ok && object.Type() == "identifier" {
+				refs = append(refs, classReference{name: nodeText(property, src), module: module})
+			}
+
+		case "subscript_expression":
+			// styles['nav-item']
+			object, index := node.ChildByFieldName("object"), node.ChildByFieldName("index")
+			if module, ok := modules[nodeText(object, src)]; ok && object.Type() == "identifier" && index != nil && index.Type() == "string" {
+				refs = append(refs, classReference{name: literalArgument(index, src), module: module})
+			}
+		}
+	}
+	collect(node)
+	return refs
+}
+
+// componentAt returns the name of the innermost component whose lines
+// contain line, or "" outside every component.
+func componentAt(components []model.ComponentEntity, line int) string {
+	var best *model.ComponentEntity
+	for i := range components {
+		c := &components[i]
+		if line < c.StartLine || line > c.EndLine {
+			continue
+		}
+		if best == nil || c.EndLine-c.StartLine < best.EndLine-best.StartLine {
+			best = c
+		}
+	}
+	if best == nil {
+		return ""
+	}
+	return best.Name
+}
+
+func startsWithSpace(text string) bool {
+	return text != "" && unicode.IsSpace(rune(text[0]))
+}
+
+func endsWithSpace(text string) bool {
+	return text != "" && unicode.IsSpace(rune(text[len(text)-1]))
+}
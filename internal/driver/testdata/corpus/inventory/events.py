@@ -0,0 +1,20 @@
+"""A minimal in-process event bus."""
+
+from collections import defaultdict
+from typing import Any, Callable, Dict, List
+
+Handler = Callable[[str, Dict[str, Any]], None]
+
+_handlers: Dict[str, List[Handler]] = defaultdict(list)
+
+
+def subscribe(topic: str, handler: Handler) -> None:
+    _handlers[topic].append(handler)
+
+
+def publish(topic: str, payload: Dict[str, Any]) -> int:
+    delivered = 0
+    for handler in _handlers.get(topic, []):
+        handler(topic, payload)
+        delivered += 1
+    return delivered
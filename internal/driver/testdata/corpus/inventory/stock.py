@@ -0,0 +1,83 @@
+"""Stock levels and reservations for the warehouse."""
+
+from __future__ import annotations
+
+import logging
+from dataclasses import dataclass, field
+from datetime import datetime, timedelta
+from typing import Dict, Iterable, Optional
+
+from .events import publish
+
+LOW_STOCK_THRESHOLD = 5
+RESERVATION_TTL = timedelta(minutes=15)
+
+log = logging.getLogger(__name__)
+
+
+class OutOfStock(Exception):
+    """Raised when a reservation asks for more than is available."""
+
+    def __init__(self, sku: str, requested: int, available: int):
+        super().__init__(f"{sku}: requested {requested}, available {available}")
+        self.sku = sku
+        self.requested = requested
+        self.available = available
+
+
+@dataclass
+class Reservation:
+    sku: str
+    quantity: int
+    expires_at: datetime
+
+    def expired(self, now: Optional[datetime] = None) -> bool:
+        return (now or datetime.utcnow()) >= self.expires_at
+
+
+@dataclass
+class Stock:
+    levels: Dict[str, int] = field(default_factory=dict)
+    reservations: Dict[str, Reservation] = field(default_factory=dict)
+
+    def available(self, sku: str) -> int:
+        """Return the quantity of sku that is neither sold nor reserved."""
+        reserved = sum(r.quantity for r in self.reservations.values() if r.sku == sku and not r.expired())
+        return self.levels.get(sku, 0) - reserved
+
+    def reserve(self, order_id: str, sku: str, quantity: int) -> Reservation:
+        available = self.available(sku)
+        if quantity > available:
+            raise OutOfStock(sku, quantity, available)
+        reservation = Reservation(sku, quantity, datetime.utcnow() + RESERVATION_TTL)
+        self.reservations[order_id] = reservation
+        if available - quantity <= LOW_STOCK_THRESHOLD:
+            publish("stock.low", {"sku": sku, "available": available - quantity})
+        return reservation
+
+    def commit(self, order_id: str) -> None:
+        reservation = self.reservations.pop(order_id)
+        self.levels[reservation.sku] -= reservation.quantity
+        log.info("committed %s x%d for %s", reservation.sku, reservation.quantity, order_id)
+
+    def release_expired(self) -> int:
+        expired = [order_id for order_id, r in self.reservations.items() if r.expired()]
+        for order_id in expired:
+            del self.reservations[order_id]
+        return len(expired)
+
+    @classmethod
+    def from_counts(cls, counts: Iterable[tuple[str, int]]) -> "Stock":
+        stock = cls()
+        for sku, count in counts:
+            stock.levels[sku] = stock.levels.get(sku, 0) + count
+        return stock
+
+
+def restock(stock: Stock, sku: str, quantity: int) -> int:
+    def clamp(value: int) -> int:
+        return max(0, value)
+
+    stock.levels[sku] = clamp(stock.levels.get(sku, 0) + quantity)
+    publish("stock.restocked", {"sku": sku, "level": stock.levels[sku]})
+    return stock.levels[sku]
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned when an order does not exist.
+var ErrNotFound = errors.New("order not found")
+
+const defaultPageSize = 50
+
+// Status is the lifecycle state of an order.
+type Status string
+
+// Order statuses.
+const (
+	StatusPending   Status = "pending"
+	StatusPaid      Status = "paid"
+	StatusCancelled Status = "cancelled"
+)
+
+// Order is a customer order.
+type Order struct {
+	ID         string
+	CustomerID string
+	Status     Status
+	TotalCents int64
+	CreatedAt  time.Time
+}
+
+// Store persists orders.
+type Store interface {
+	Get(ctx context.Context, id string) (Order, error)
+	Put(ctx context.Context, order Order) error
+	List(ctx context.Context, customerID string, limit int) ([]Order, error)
+}
+
+// MemoryStore is a Store kept in memory.
+type MemoryStore struct {
+	mu     sync.RWMutex
+	orders map[string]Order
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{orders: make(map[string]Order)}
+}
+
+// Get returns the order with the given ID.
+func (s *MemoryStore) Get(ctx context.Context, id string) (Order, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	order, ok := s.orders[id]
+	if !ok {
+		return Order{}, fmt.Errorf("get %s: %w", id, ErrNotFound)
+	}
+	return order, nil
+}
+
+// Put stores order, replacing any order with the same ID.
+func (s *MemoryStore) Put(ctx context.Context, order Order) error {
+	if order.ID == "" {
+		return errors.New("order has no ID")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.orders[order.ID] = order
+	return nil
+}
+
+// List returns a customer's orders, newest first.
+func (s *MemoryStore) List(ctx context.Context, customerID string, limit int) ([]Order, error) {
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	s.mu.RLock()
+	var orders []Order
+	for _, order := range s.orders {
+		if customerID == "" || order.CustomerID == customerID {
+			orders = append(orders, order)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(orders, func(i, j int) bool { return orders[i].CreatedAt.After(orders[j].CreatedAt) })
+	if len(orders) > limit {
+		orders = orders[:limit]
+	}
+	return orders, nil
+}
+
+// Cancel marks an open order cancelled.
+func Cancel(ctx context.Context, store Store, id string) (Order, error) {
+	order, err := store.Get(ctx, id)
+	if err != nil {
+		return Order{}, err
+	}
+	if order.Status == StatusCancelled {
+		return order, nil
+	}
+	order.Status = StatusCancelled
+	if err := store.Put(ctx, order); err != nil {
+		return Order{}, fmt.Errorf("cancel %s: %w", id, err)
+	}
+	return order, nil
+}
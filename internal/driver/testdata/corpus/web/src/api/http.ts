@@ -0,0 +1,41 @@
+export interface RequestOptions {
+  headers?: Record<string, string>;
+  timeoutMs?: number;
+}
+
+export class HttpError extends Error {
+  constructor(readonly status: number, message: string) {
+    super(message);
+  }
+}
+
+export class HttpClient {
+  constructor(private readonly baseUrl: string) {}
+
+  async get<T>(path: string, options: RequestOptions = {}): Promise<T> {
+    return this.request<T>('GET', path, undefined, options);
+  }
+
+  async post<T>(path: string, body: unknown, options: RequestOptions = {}): Promise<T> {
+    return this.request<T>('POST', path, body, options);
+  }
+
+  private async request<T>(method: string, path: string, body: unknown, options: RequestOptions): Promise<T> {
+    const controller = new AbortController();
+    const timer = setTimeout(() => controller.abort(), options.timeoutMs ?? 10000);
+    try {
+      const response = await fetch(this.baseUrl + path, {
+        method,
+        headers: { 'Content-Type': 'application/json', ...options.headers },
+        body: body === undefined ? undefined : JSON.stringify(body),
+        signal: controller.signal,
+      });
+      if (!response.ok) {
+        throw new HttpError(response.status, await response.text());
+      }
+      return (await response.json()) as T;
+    } finally {
+      clearTimeout(timer);
+    }
+  }
+}
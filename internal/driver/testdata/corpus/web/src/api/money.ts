@@ -0,0 +1,15 @@
+export interface Money {
+  currency: string;
+  cents: number;
+}
+
+export function formatMoney(money: Money, locale = 'en-US'): string {
+  return new Intl.NumberFormat(locale, { style: 'currency', currency: money.currency }).format(money.cents / 100);
+}
+
+export function addMoney(a: Money, b: Money): Money {
+  if (a.currency !== b.currency) {
+    throw new Error(`cannot add ${a.currency} to ${b.currency}`);
+  }
+  return { currency: a.currency, cents: a.cents + b.cents };
+}
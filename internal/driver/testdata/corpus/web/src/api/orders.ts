@@ -0,0 +1,101 @@
+import { HttpClient, RequestOptions } from './http';
+import type { Money } from './money';
+
+export type OrderStatus = 'pending' | 'paid' | 'shipped' | 'cancelled';
+
+export interface OrderLine {
+  sku: string;
+  quantity: number;
+  unitPrice: Money;
+}
+
+export interface Order {
+  id: string;
+  customerId: string;
+  status: OrderStatus;
+  lines: OrderLine[];
+  createdAt: Date;
+}
+
+export interface OrderFilter {
+  status?: OrderStatus;
+  customerId?: string;
+  limit?: number;
+}
+
+/**
+ * Computes the total of an order's lines.
+ * @param lines the lines to sum
+ * @returns the total in the currency of the first line
+ */
+export function orderTotal(lines: OrderLine[]): Money {
+  const currency = lines.length > 0 ? lines[0].unitPrice.currency : 'EUR';
+  const cents = lines.reduce((sum, line) => sum + line.quantity * line.unitPrice.cents, 0);
+  return { currency, cents };
+}
+
+export const isOpen = (order: Order): boolean =>
+  order.status === 'pending' || order.status === 'paid';
+
+export abstract class Repository<T extends { id: string }> {
+  protected cache = new Map<string, T>();
+
+  abstract fetch(id: string): Promise<T>;
+
+  async get(id: string): Promise<T> {
+    const cached = this.cache.get(id);
+    if (cached) {
+      return cached;
+    }
+    const item = await this.fetch(id);
+    this.cache.set(item.id, item);
+    return item;
+  }
+
+  clear(): void {
+    this.cache.clear();
+  }
+}
+
+export class OrderRepository extends Repository<Order> {
+  constructor(private readonly http: HttpClient, private readonly options: RequestOptions = {}) {
+    super();
+  }
+
+  async fetch(id: string): Promise<Order> {
+    const order = await this.http.get<Order>(`/orders/${id}`, this.options);
+    return normalize(order);
+  }
+
+  async list(filter: OrderFilter = {}): Promise<Order[]> {
+    const query = new URLSearchParams();
+    if (filter.status) {
+      query.set('status', filter.status);
+    }
+    if (filter.customerId) {
+      query.set('customer', filter.customerId);
+    }
+    query.set('limit', String(filter.limit ?? 50));
+    const orders = await this.http.get<Order[]>(`/orders?${query}`, this.options);
+    return orders.map(normalize);
+  }
+
+  async cancel(order: Order): Promise<Order> {
+    if (!isOpen(order)) {
+      throw new Error(`order ${order.id} is ${order.status}`);
+    }
+    const updated = await this.http.post<Order>(`/orders/${order.id}/cancel`, {}, this.options);
+    this.cache.set(updated.id, updated);
+    return normalize(updated);
+  }
+}
+
+function normalize(order: Order): Order {
+  return { ...order, createdAt: new Date(order.createdAt) };
+}
+
+export function* pages<T>(items: T[], size: number): Generator<T[]> {
+  for (let i = 0; i < items.length; i += size) {
+    yield items.slice(i, i + size);
+  }
+}
@@ -0,0 +1,57 @@
+const { EventEmitter } = require('events');
+const { addMoney } = require('./api/money');
+
+const EMPTY = Object.freeze({ currency: 'EUR', cents: 0 });
+
+class Cart extends EventEmitter {
+  constructor(currency = 'EUR') {
+    super();
+    this.currency = currency;
+    this.items = new Map();
+  }
+
+  add(sku, unitPrice, quantity = 1) {
+    const item = this.items.get(sku) || { sku, unitPrice, quantity: 0 };
+    item.quantity += quantity;
+    this.items.set(sku, item);
+    this.emit('change', this.summary());
+    return item;
+  }
+
+  remove(sku) {
+    if (this.items.delete(sku)) {
+      this.emit('change', this.summary());
+    }
+  }
+
+  total() {
+    let total = { ...EMPTY, currency: this.currency };
+    for (const item of this.items.values()) {
+      total = addMoney(total, { currency: item.unitPrice.currency, cents: item.unitPrice.cents * item.quantity });
+    }
+    return total;
+  }
+
+  summary() {
+    return { count: this.items.size, total: this.total() };
+  }
+}
+
+function restore(saved) {
+  const cart = new Cart(saved.currency);
+  for (const item of saved.items) {
+    cart.add(item.sku, item.unitPrice, item.quantity);
+  }
+  return cart;
+}
+
+const persist = function (cart, storage) {
+  storage.setItem('cart', JSON.stringify({ currency: cart.currency, items: [...cart.items.values()] }));
+};
+
+async function loadLazily() {
+  const { formatMoney } = await import('./api/money');
+  return formatMoney;
+}
+
+module.exports = { Cart, restore, persist, loadLazily };
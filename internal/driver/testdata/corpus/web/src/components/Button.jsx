@@ -0,0 +1,28 @@
+import React from 'react';
+import PropTypes from 'prop-types';
+import '../styles/button.scss';
+
+const variants = {
+  primary: 'btn btn--primary',
+  danger: 'btn btn--danger',
+  link: 'btn btn--link',
+};
+
+export function Button({ variant = 'primary', children, ...props }) {
+  return (
+    <button type="button" className={variants[variant]} {...props}>
+      {children}
+    </button>
+  );
+}
+
+Button.propTypes = {
+  variant: PropTypes.oneOf(Object.keys(variants)),
+  children: PropTypes.node,
+};
+
+export const IconButton = ({ icon, label, ...props }) => (
+  <Button variant="link" aria-label={label} {...props}>
+    <span className="btn__icon">{icon}</span>
+  </Button>
+);
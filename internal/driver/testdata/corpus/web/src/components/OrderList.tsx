@@ -0,0 +1,75 @@
+import React, { useEffect, useMemo, useState } from 'react';
+import { Order, OrderRepository, orderTotal } from '../api/orders';
+import { formatMoney } from '../api/money';
+import { Button } from './Button';
+import styles from '../styles/orders.module.css';
+
+interface OrderListProps {
+  repository: OrderRepository;
+  customerId?: string;
+  onSelect?: (order: Order) => void;
+}
+
+function useOrders(repository: OrderRepository, customerId?: string) {
+  const [orders, setOrders] = useState<Order[]>([]);
+  const [error, setError] = useState<string | null>(null);
+
+  useEffect(() => {
+    let cancelled = false;
+    repository
+      .list({ customerId })
+      .then((result) => {
+        if (!cancelled) {
+          setOrders(result);
+        }
+      })
+      .catch((err: Error) => setError(err.message));
+    return () => {
+      cancelled = true;
+    };
+  }, [repository, customerId]);
+
+  return { orders, error, setOrders };
+}
+
+export const OrderRow: React.FC<{ order: Order; onCancel: (order: Order) => void }> = ({ order, onCancel }) => (
+  <tr className={styles.row}>
+    <td>{order.id}</td>
+    <td className={`${styles.status} status-${order.status}`}>{order.status}</td>
+    <td>{formatMoney(orderTotal(order.lines))}</td>
+    <td>
+      <Button variant="danger" onClick={() => onCancel(order)} disabled={order.status === 'cancelled'}>
+        Cancel
+      </Button>
+    </td>
+  </tr>
+);
+
+export default function OrderList({ repository, customerId, onSelect }: OrderListProps) {
+  const { orders, error, setOrders } = useOrders(repository, customerId);
+  const total = useMemo(() => orders.reduce((sum, order) => sum + orderTotal(order.lines).cents, 0), [orders]);
+
+  const cancel = async (order: Order) => {
+    const updated = await repository.cancel(order);
+    setOrders(orders.map((o) => (o.id === updated.id ? updated : o)));
+  };
+
+  if (error) {
+    return <p className="error">{error}</p>;
+  }
+
+  return (
+    <section className="order-list">
+      <table className={styles.table}>
+        <tbody>
+          {orders.map((order) => (
+            <OrderRow key={order.id} order={order} onCancel={cancel} />
+          ))}
+        </tbody>
+      </table>
+      <footer className="order-list__footer" onClick={() => orders[0] && onSelect?.(orders[0])}>
+        {orders.length} orders, {total / 100} total
+      </footer>
+    </section>
+  );
+}
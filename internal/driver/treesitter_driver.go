@@ -6,66 +6,99 @@ import (
 	"fmt"
 	"goParse/internal/model"
 	"io/ioutil"
-	"log"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 
 	sitter "github.com/smacker/go-tree-sitter"
 	tsCSS "github.com/smacker/go-tree-sitter/css"
+	tsGo "github.com/smacker/go-tree-sitter/golang"
 	tsJS "github.com/smacker/go-tree-sitter/javascript"
+	tsPy "github.com/smacker/go-tree-sitter/python"
+	tsTSX "github.com/smacker/go-tree-sitter/typescript/tsx"
 	tsTS "github.com/smacker/go-tree-sitter/typescript/typescript"
 )
 
 // ParsedFile holds normalized entities extracted from a single source file.
-type ParsedFile struct {
-	FilePath string
-	Language string
-
-	// Entity collections
-	Funcs       []model.FunctionEntity
-	Imports     []model.ImportEntity
-	Variables   []model.VariableEntity
-	Types       []model.TypeEntity
-	Interfaces  []model.InterfaceEntity
-	Classes     []model.ClassEntity
-	Constants   []model.ConstantEntity
-	JSXElements []model.JSXElementEntity
-	CSSRules    []model.CSSRuleEntity
-
-	// Relationship collections
-	FunctionCalls []model.FunctionCallEntity
-	TypeUsages    []model.TypeUsageEntity
-	Extends       []model.ExtendsEntity
-	Implements    []model.ImplementsEntity
-	References    []model.ReferenceEntity
-}
+type ParsedFile = model.ParsedFile
 
-// TreeSitterDriver knows how to parse .ts/.tsx/.js/.jsx/.css files.
+// TreeSitterDriver knows how to parse .ts/.tsx/.js/.jsx/.css/.scss/.py/.go
+// files, and the files of any language added with Register. SCSS has no
+// grammar and is read by a scanner of its own. A driver is safe for
+// concurrent use: its queries are compiled once and shared, while parsers and
+// query cursors, which are not, are pooled so that each worker reuses its own.
 type TreeSitterDriver struct {
-	langs map[string]*sitter.Language
+	extractors map[string]LanguageExtractor // By extension
+	skipDirs   map[string]bool
+	queries    map[queryKey]*sitter.Query
+	parsers    sync.Pool // *sitter.Parser
+	cursors    sync.Pool // *sitter.QueryCursor
+
+	// Previous parses kept by ParseIncremental, by path
+	treesMu sync.Mutex
+	trees   map[string]fileTree
 }
 
-// NewTreeSitterDriver constructs a driver with grammars for needed file types.
-func NewTreeSitterDriver() *TreeSitterDriver {
-	return &TreeSitterDriver{
-		langs: map[string]*sitter.Language{
-			".ts":   tsTS.GetLanguage(),
-			".tsx":  tsTS.GetLanguage(),
-			".js":   tsJS.GetLanguage(),
-			".jsx":  tsJS.GetLanguage(),
-			".css":  tsCSS.GetLanguage(),
-			".scss": tsCSS.GetLanguage(),
-		},
+// NewTreeSitterDriver constructs a driver with the built-in and registered
+// language extractors and compiles the queries run on each built-in grammar.
+// It fails if a grammar rejects one of the queries or two extractors claim
+// the same extension.
+func NewTreeSitterDriver() (*TreeSitterDriver, error) {
+	ts, tsx, js := tsTS.GetLanguage(), tsTSX.GetLanguage(), tsJS.GetLanguage()
+	css, py, golang := tsCSS.GetLanguage(), tsPy.GetLanguage(), tsGo.GetLanguage()
+
+	queries, err := compileQueries(map[*sitter.Language][]string{
+		ts:     typeScriptQueries(),
+		tsx:    append(typeScriptQueries(), jsxElementQuery, jsxSelfClosingQuery),
+		js:     append(slices.Clone(scriptQueries), jsClassQuery, jsxElementQuery, jsxSelfClosingQuery),
+		css:    {cssClassQuery, cssIDQuery, cssVariableQuery},
+		py:     {pyImportQuery, pyClassQuery, pyFunctionQuery, pyCallQuery, pyMethodCallQuery},
+		golang: {goImportQuery, goTypeQuery, goFunctionQuery, goCallQuery},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	t := &TreeSitterDriver{
+		extractors: make(map[string]LanguageExtractor),
+		skipDirs:   make(map[string]bool),
+		queries:    queries,
+		parsers:    sync.Pool{New: func() any { return sitter.NewParser() }},
+		cursors:    sync.Pool{New: func() any { return sitter.NewQueryCursor() }},
+		trees:      make(map[string]fileTree),
+	}
+	for _, name := range commonSkipDirs {
+		t.skipDirs[name] = true
 	}
+
+	parseSCSS := func(pf *ParsedFile, src []byte, _ *sitter.Node, _ *sitter.Language) { t.parseSCSS(pf, src) }
+	extractors := []LanguageExtractor{
+		builtinExtractor{[]string{".ts"}, ts, scriptSkipDirs, t.parseTypeScript},
+		builtinExtractor{[]string{".tsx"}, tsx, scriptSkipDirs, t.parseTypeScript}, // TypeScript cannot parse JSX
+		builtinExtractor{[]string{".js", ".jsx"}, js, scriptSkipDirs, t.parseJavaScript},
+		builtinExtractor{[]string{".css"}, css, nil, t.parseCSS},
+		builtinExtractor{[]string{".scss"}, nil, nil, parseSCSS},
+		builtinExtractor{[]string{".py"}, py, pythonSkipDirs, t.parsePython},
+		builtinExtractor{[]string{".go"}, golang, goSkipDirs, t.parseGo},
+	}
+	registryMu.Lock()
+	extractors = append(extractors, registered...)
+	registryMu.Unlock()
+
+	for _, e := range extractors {
+		if err := t.register(e); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
 }
 
 // Parse reads the file at 'path', builds an AST with Tree-sitter, then extracts
 // comprehensive entities and relationships into a ParsedFile struct.
 func (t *TreeSitterDriver) Parse(path string) (ParsedFile, error) {
-	ext := filepath.Ext(path)
-	lang, ok := t.langs[ext]
-	if !ok {
-		return ParsedFile{}, fmt.Errorf("unsupported extension: %s", ext)
+	if err := t.checkExtension(path); err != nil {
+		return ParsedFile{}, err
 	}
 
 	src, err := ioutil.ReadFile(path)
@@ -73,32 +106,48 @@ func (t *TreeSitterDriver) Parse(path string) (ParsedFile, error) {
 		return ParsedFile{}, err
 	}
 
-	parser := sitter.NewParser()
-	parser.SetLanguage(lang)
-	tree := parser.Parse(nil, src)
-	if tree == nil {
-		return ParsedFile{}, fmt.Errorf("tree-sitter failed to parse file: %s", path)
-	}
-	root := tree.RootNode()
+	pf, _, err := t.parseSource(path, src, nil)
+	return pf, err
+}
 
-	pf := ParsedFile{
-		FilePath: path,
-		Language: ext[1:], // e.g. "ts", "tsx", "js", "jsx", "css", "scss"
+// checkExtension reports an error for files the driver cannot parse.
+func (t *TreeSitterDriver) checkExtension(path string) error {
+	if !t.Supports(path) {
+		return fmt.Errorf("unsupported extension: %s", filepath.Ext(path))
 	}
+	return nil
+}
 
-	switch ext {
-	case ".ts", ".tsx":
-		t.parseTypeScript(&pf, src, root, lang)
-	case ".js", ".jsx":
-		t.parseJavaScript(&pf, src, root, lang)
-	case ".css", ".scss":
-		t.parseCSS(&pf, src, root, lang)
+// parseSource extracts the entities of src, the contents of path, with the
+// extractor of its extension and returns them with the syntax tree, which is
+// nil for languages without a grammar such as SCSS. When oldTree is given, it
+// must already be edited to match src, and Tree-sitter reuses its unchanged
+// subtrees.
+func (t *TreeSitterDriver) parseSource(path string, src []byte, oldTree *sitter.Tree) (ParsedFile, *sitter.Tree, error) {
+	extractor := t.extractors[filepath.Ext(path)]
+
+	var tree *sitter.Tree
+	if grammar := extractor.Grammar(); grammar != nil {
+		parser := t.parsers.Get().(*sitter.Parser)
+		parser.SetLanguage(grammar)
+		tree = parser.Parse(oldTree, src)
+		t.parsers.Put(parser)
+		if tree == nil {
+			return ParsedFile{}, nil, fmt.Errorf("tree-sitter failed to parse file: %s", path)
+		}
 	}
 
-	// Post-processing: resolve function calls
+	pf := extractor.Extract(path, src, tree)
+
+	// Post-processing: identify entities, then resolve function calls, the
+	// exports of declarations in this file and the components they render
+	model.AssignIDs(&pf)
 	t.resolveFunctionCalls(&pf)
+	symbols := buildSymbolTable([]ParsedFile{pf})
+	resolveExports(&pf, symbols)
+	resolveComponents(&pf, symbols)
 
-	return pf, nil
+	return pf, tree, nil
 }
 
 // parseTypeScript extracts all entities and relationships from TypeScript/TSX files
@@ -130,9 +179,14 @@ func (t *TreeSitterDriver) parseTypeScript(pf *ParsedFile, src []byte, root *sit
 	// Extract inheritance relationships
 	t.extractTSInheritance(pf, src, root, lang)
 
-	// Extract JSX elements if in .tsx file
+	// Extract exports and re-exports
+	t.extractExports(pf, src, root)
+
+	// Extract JSX elements, React components and their CSS classes if in .tsx file
 	if strings.HasSuffix(pf.FilePath, ".tsx") {
 		t.extractJSXElements(pf, src, root, lang)
+		t.extractComponents(pf, src, root)
+		t.extractClassUsages(pf, src, root)
 	}
 }
 
@@ -153,13 +207,18 @@ func (t *TreeSitterDriver) parseJavaScript(pf *ParsedFile, src []byte, root *sit
 	// Extract function calls
 	t.extractJSFunctionCalls(pf, src, root, lang)
 
-	// Extract JSX elements if in .jsx file
+	// Extract exports and re-exports
+	t.extractExports(pf, src, root)
+
+	// Extract JSX elements, React components and their CSS classes if in .jsx file
 	if strings.HasSuffix(pf.FilePath, ".jsx") {
 		t.extractJSXElements(pf, src, root, lang)
+		t.extractComponents(pf, src, root)
+		t.extractClassUsages(pf, src, root)
 	}
 }
 
-// parseCSS extracts entities from CSS/SCSS files
+// parseCSS extracts entities from CSS files
 func (t *TreeSitterDriver) parseCSS(pf *ParsedFile, src []byte, root *sitter.Node, lang *sitter.Language) {
 	t.extractCSSRules(pf, src, root, lang)
 }
@@ -168,45 +227,26 @@ func (t *TreeSitterDriver) parseCSS(pf *ParsedFile, src []byte, root *sitter.Nod
 
 func (t *TreeSitterDriver) extractTSFunctions(pf *ParsedFile, src []byte, root *sitter.Node, lang *sitter.Language) {
 	// Function declarations
-	query1 := `(function_declaration name: (identifier) @func.name) @func.def`
-	if qs, err := sitter.NewQuery([]byte(query1), lang); err == nil {
-		t.runFunctionQuery(pf, src, root, qs)
-	}
+	t.runFunctionQuery(pf, src, root, t.query(lang, tsFunctionQuery))
+
+	// Generator function declarations
+	t.runFunctionQuery(pf, src, root, t.query(lang, tsGeneratorFunctionQuery))
 
 	// Method definitions
-	query2 := `(method_definition key: (property_identifier) @func.name) @func.def`
-	if qs, err := sitter.NewQuery([]byte(query2), lang); err == nil {
-		t.runFunctionQuery(pf, src, root, qs)
-	}
+	t.runFunctionQuery(pf, src, root, t.query(lang, tsMethodQuery))
 
 	// Method signatures
-	query3 := `(method_signature name: (property_identifier) @func.name) @func.def`
-	if qs, err := sitter.NewQuery([]byte(query3), lang); err == nil {
-		t.runFunctionQuery(pf, src, root, qs)
-	}
+	t.runFunctionQuery(pf, src, root, t.query(lang, tsMethodSignatureQuery))
 
 	// Arrow functions assigned to variables
-	query4 := `(variable_declarator name: (identifier) @func.name value: (arrow_function)) @func.def`
-	if qs, err := sitter.NewQuery([]byte(query4), lang); err == nil {
-		t.runFunctionQuery(pf, src, root, qs)
-	}
+	t.runFunctionQuery(pf, src, root, t.query(lang, tsArrowFunctionQuery))
 
 	// Function expressions assigned to variables
-	query5 := `(variable_declarator name: (identifier) @func.name value: (function_expression)) @func.def`
-	if qs, err := sitter.NewQuery([]byte(query5), lang); err == nil {
-		t.runFunctionQuery(pf, src, root, qs)
-	}
+	t.runFunctionQuery(pf, src, root, t.query(lang, tsFunctionExpressionQuery))
 }
 
 func (t *TreeSitterDriver) runFunctionQuery(pf *ParsedFile, src []byte, root *sitter.Node, qs *sitter.Query) {
-	qc := sitter.NewQueryCursor()
-	qc.Exec(qs, root)
-	for {
-		match, ok := qc.NextMatch()
-		if !ok {
-			break
-		}
-
+	for match := range t.matches(qs, root) {
 		var nameNode, defNode *sitter.Node
 		for _, capture := range match.Captures {
 			capName := qs.CaptureNameForId(capture.Index)
@@ -223,13 +263,17 @@ func (t *TreeSitterDriver) runFunctionQuery(pf *ParsedFile, src []byte, root *si
 			// Extract signature and other metadata
 			signature := t.extractFunctionSignature(defNode, src)
 
-			pf.Funcs = append(pf.Funcs, model.FunctionEntity{
-				Name:      fnName,
-				FilePath:  pf.FilePath,
-				StartLine: int(defNode.StartPoint().Row) + 1,
-				EndLine:   int(defNode.EndPoint().Row) + 1,
-				Signature: signature,
-			})
+			fn := model.FunctionEntity{
+				Name:          fnName,
+				QualifiedName: t.qualifiedName(defNode, fnName, src),
+				FilePath:      pf.FilePath,
+				StartLine:     int(defNode.StartPoint().Row) + 1,
+				EndLine:       int(defNode.EndPoint().Row) + 1,
+				Signature:     signature,
+				Doc:           jsDocComment(defNode, src),
+			}
+			t.applyFunctionSignature(&fn, defNode, src)
+			pf.Funcs = append(pf.Funcs, fn)
 		}
 	}
 }
@@ -247,27 +291,17 @@ func (t *TreeSitterDriver) extractFunctionSignature(node *sitter.Node, src []byt
 
 func (t *TreeSitterDriver) extractTSImports(pf *ParsedFile, src []byte, root *sitter.Node, lang *sitter.Language) {
 	// ES6 imports with named imports
-	query1 := `(import_statement source: (string) @imp.module) @imp.stmt`
-	if qs, err := sitter.NewQuery([]byte(query1), lang); err == nil {
-		t.runImportQuery(pf, src, root, qs, false)
-	}
+	t.runImportQuery(pf, src, root, t.query(lang, tsImportQuery), false)
 
 	// CommonJS requires
-	query2 := `(call_expression function: (identifier) @req arguments: (arguments (string) @imp.module))`
-	if qs, err := sitter.NewQuery([]byte(query2), lang); err == nil {
-		t.runImportQuery(pf, src, root, qs, true)
-	}
+	t.runImportQuery(pf, src, root, t.query(lang, tsRequireQuery), true)
+
+	// Dynamic imports, including React.lazy and Next.js dynamic() loaders
+	t.runDynamicImportQuery(pf, src, root, t.query(lang, tsDynamicImportQuery))
 }
 
 func (t *TreeSitterDriver) runImportQuery(pf *ParsedFile, src []byte, root *sitter.Node, qs *sitter.Query, isRequire bool) {
-	qc := sitter.NewQueryCursor()
-	qc.Exec(qs, root)
-	for {
-		match, ok := qc.NextMatch()
-		if !ok {
-			break
-		}
-
+	for match := range t.matches(qs, root) {
 		var moduleNode, stmtNode *sitter.Node
 		for _, capture := range match.Captures {
 			capName := qs.CaptureNameForId(capture.Index)
@@ -290,23 +324,85 @@ func (t *TreeSitterDriver) runImportQuery(pf *ParsedFile, src []byte, root *sitt
 			if len(raw) >= 2 && (raw[0] == '"' || raw[0] == '\'') {
 				module := raw[1 : len(raw)-1]
 
-				// Extract imported names
-				var importedNames []string
+				imp := model.ImportEntity{
+					Module:   module,
+					FilePath: pf.FilePath,
+				}
+
+				// Extract imported names and binding kinds
 				if stmtNode != nil {
-					importedNames = t.extractImportedNames(stmtNode, src)
+					imp.ImportedNames = t.extractImportedNames(stmtNode, src)
+					t.extractImportBindings(&imp, stmtNode, src)
 				}
 
-				pf.Imports = append(pf.Imports, model.ImportEntity{
-					Module:        module,
-					FilePath:      pf.FilePath,
-					ImportedNames: importedNames,
-				})
+				pf.Imports = append(pf.Imports, imp)
 			}
 		}
 	nextMatch:
 	}
 }
 
+// runDynamicImportQuery records import() calls as dynamic imports. A specifier
+// that is not a string literal, such as a template literal with substitutions,
+// is kept as written and marked computed so it is not resolved.
+func (t *TreeSitterDriver) runDynamicImportQuery(pf *ParsedFile, src []byte, root *sitter.Node, qs *sitter.Query) {
+	for match := range t.matches(qs, root) {
+		for _, capture := range match.Captures {
+			args := capture.Node.ChildByFieldName("arguments")
+			if args == nil || args.NamedChildCount() == 0 {
+				continue
+			}
+			arg := args.NamedChild(0)
+
+			imp := model.ImportEntity{
+				Module:    literalArgument(arg, src),
+				FilePath:  pf.FilePath,
+				IsDynamic: true,
+			}
+			// Only literals lose their quotes
+			imp.IsComputed = imp.Module == nodeText(arg, src)
+
+			// const Page = lazy(() => import('./Page')) binds the default export
+			if name := lazyBinding(capture.Node, src); name != "" {
+				imp.ImportedNames = []string{name}
+				imp.IsDefault = true
+			}
+
+			pf.Imports = append(pf.Imports, imp)
+		}
+	}
+}
+
+// lazyBinding returns the variable a React.lazy or Next.js dynamic loader
+// returning the import() call is assigned to, or "" for any other import().
+func lazyBinding(call *sitter.Node, src []byte) string {
+	arrow := call.Parent()
+	if arrow == nil || arrow.Type() != "arrow_function" {
+		return ""
+	}
+	args := arrow.Parent()
+	if args == nil || args.Type() != "arguments" {
+		return ""
+	}
+	loader := args.Parent()
+	if loader == nil || loader.Type() != "call_expression" {
+		return ""
+	}
+	callee := nodeText(loader.ChildByFieldName("function"), src)
+	if name := callee[strings.LastIndex(callee, ".")+1:]; name != "lazy" && name != "dynamic" {
+		return ""
+	}
+
+	declarator := loader.Parent()
+	if declarator == nil || declarator.Type() != "variable_declarator" {
+		return ""
+	}
+	if name := declarator.ChildByFieldName("name"); name != nil && name.Type() == "identifier" {
+		return nodeText(name, src)
+	}
+	return ""
+}
+
 func (t *TreeSitterDriver) extractImportedNames(importNode *sitter.Node, src []byte) []string {
 	var names []string
 
@@ -333,22 +429,47 @@ func (t *TreeSitterDriver) extractImportedNames(importNode *sitter.Node, src []b
 	return names
 }
 
-func (t *TreeSitterDriver) extractTSClasses(pf *ParsedFile, src []byte, root *sitter.Node, lang *sitter.Language) {
-	query := `(class_declaration) @class`
-	qs, err := sitter.NewQuery([]byte(query), lang)
-	if err != nil {
-		log.Printf("Failed to compile class query: %v", err)
-		return
-	}
+// extractImportBindings records default/namespace imports and aliased specifiers.
+func (t *TreeSitterDriver) extractImportBindings(imp *model.ImportEntity, importNode *sitter.Node, src []byte) {
+	var walk func(*sitter.Node)
+	walk = func(node *sitter.Node) {
+		if node == nil {
+			return
+		}
+
+		switch node.Type() {
+		case "import_clause":
+			for i := 0; i < int(node.NamedChildCount()); i++ {
+				if node.NamedChild(i).Type() == "identifier" {
+					imp.IsDefault = true
+				}
+			}
+		case "namespace_import":
+			imp.IsNamespace = true
+		case "import_specifier":
+			nameNode := node.ChildByFieldName("name")
+			aliasNode := node.ChildByFieldName("alias")
+			if nameNode != nil && aliasNode != nil {
+				if imp.Aliases == nil {
+					imp.Aliases = make(map[string]string)
+				}
+				alias := string(src[aliasNode.StartByte():aliasNode.EndByte()])
+				imp.Aliases[alias] = string(src[nameNode.StartByte():nameNode.EndByte()])
+			}
+			return
+		}
 
-	qc := sitter.NewQueryCursor()
-	qc.Exec(qs, root)
-	for {
-		match, ok := qc.NextMatch()
-		if !ok {
-			break
+		for i := 0; i < int(node.ChildCount()); i++ {
+			walk(node.Child(i))
 		}
+	}
+
+	walk(importNode)
+}
 
+func (t *TreeSitterDriver) extractTSClasses(pf *ParsedFile, src []byte, root *sitter.Node, lang *sitter.Language) {
+	qs := t.query(lang, tsClassQuery)
+	for match := range t.matches(qs, root) {
 		for _, capture := range match.Captures {
 			classNode := capture.Node
 
@@ -373,11 +494,16 @@ func (t *TreeSitterDriver) extractTSClasses(pf *ParsedFile, src []byte, root *si
 
 			if className != "" {
 				class := model.ClassEntity{
-					Name:      className,
-					FilePath:  pf.FilePath,
-					StartLine: int(classNode.StartPoint().Row) + 1,
-					EndLine:   int(classNode.EndPoint().Row) + 1,
+					Name:          className,
+					QualifiedName: t.qualifiedName(classNode, className, src),
+					FilePath:      pf.FilePath,
+					StartLine:     int(classNode.StartPoint().Row) + 1,
+					EndLine:       int(classNode.EndPoint().Row) + 1,
+					IsAbstract:    classNode.Type() == "abstract_class_declaration",
+					Doc:           jsDocComment(classNode, src),
 				}
+				t.extractClassMembers(pf, &class, classNode, src)
+				t.addClassDecorators(pf, class, classNode, src)
 				pf.Classes = append(pf.Classes, class)
 
 				// Add extends relationship
@@ -441,20 +567,8 @@ func (t *TreeSitterDriver) extractClassHeritage(heritageNode *sitter.Node, src [
 }
 
 func (t *TreeSitterDriver) extractTSInterfaces(pf *ParsedFile, src []byte, root *sitter.Node, lang *sitter.Language) {
-	query := `(interface_declaration) @interface`
-	qs, err := sitter.NewQuery([]byte(query), lang)
-	if err != nil {
-		return
-	}
-
-	qc := sitter.NewQueryCursor()
-	qc.Exec(qs, root)
-	for {
-		match, ok := qc.NextMatch()
-		if !ok {
-			break
-		}
-
+	qs := t.query(lang, tsInterfaceQuery)
+	for match := range t.matches(qs, root) {
 		for _, capture := range match.Captures {
 			interfaceNode := capture.Node
 
@@ -489,6 +603,7 @@ func (t *TreeSitterDriver) extractTSInterfaces(pf *ParsedFile, src []byte, root
 				pf.Interfaces = append(pf.Interfaces, model.InterfaceEntity{
 					Name:     interfaceName,
 					FilePath: pf.FilePath,
+					Doc:      jsDocComment(interfaceNode, src),
 				})
 
 				// Add extends relationships for interfaces
@@ -506,27 +621,14 @@ func (t *TreeSitterDriver) extractTSInterfaces(pf *ParsedFile, src []byte, root
 
 func (t *TreeSitterDriver) extractTSFunctionCalls(pf *ParsedFile, src []byte, root *sitter.Node, lang *sitter.Language) {
 	// Direct function calls
-	query1 := `(call_expression function: (identifier) @call.name) @call.expr`
-	if qs, err := sitter.NewQuery([]byte(query1), lang); err == nil {
-		t.runFunctionCallQuery(pf, src, root, qs)
-	}
+	t.runFunctionCallQuery(pf, src, root, t.query(lang, tsCallQuery))
 
 	// Method calls
-	query2 := `(call_expression function: (member_expression property: (property_identifier) @call.name object: (_) @call.object)) @call.expr`
-	if qs, err := sitter.NewQuery([]byte(query2), lang); err == nil {
-		t.runMethodCallQuery(pf, src, root, qs)
-	}
+	t.runMethodCallQuery(pf, src, root, t.query(lang, tsMethodCallQuery))
 }
 
 func (t *TreeSitterDriver) runFunctionCallQuery(pf *ParsedFile, src []byte, root *sitter.Node, qs *sitter.Query) {
-	qc := sitter.NewQueryCursor()
-	qc.Exec(qs, root)
-	for {
-		match, ok := qc.NextMatch()
-		if !ok {
-			break
-		}
-
+	for match := range t.matches(qs, root) {
 		var nameNode, exprNode *sitter.Node
 		for _, capture := range match.Captures {
 			capName := qs.CaptureNameForId(capture.Index)
@@ -554,14 +656,7 @@ func (t *TreeSitterDriver) runFunctionCallQuery(pf *ParsedFile, src []byte, root
 }
 
 func (t *TreeSitterDriver) runMethodCallQuery(pf *ParsedFile, src []byte, root *sitter.Node, qs *sitter.Query) {
-	qc := sitter.NewQueryCursor()
-	qc.Exec(qs, root)
-	for {
-		match, ok := qc.NextMatch()
-		if !ok {
-			break
-		}
-
+	for match := range t.matches(qs, root) {
 		var nameNode, objectNode, exprNode *sitter.Node
 		for _, capture := range match.Captures {
 			capName := qs.CaptureNameForId(capture.Index)
@@ -611,8 +706,9 @@ func (t *TreeSitterDriver) findContainingFunction(node *sitter.Node, src []byte)
 	current := node.Parent()
 	for current != nil {
 		nodeType := current.Type()
-		if nodeType == "function_declaration" || nodeType == "method_definition" ||
-			nodeType == "arrow_function" || nodeType == "function_expression" {
+		if nodeType == "function_declaration" || nodeType == "generator_function_declaration" || nodeType == "method_definition" ||
+			nodeType == "arrow_function" || nodeType == "function_expression" ||
+			nodeType == "function_definition" {
 			// Find the function name
 			for i := 0; i < int(current.ChildCount()); i++ {
 				child := current.Child(i)
@@ -638,43 +734,23 @@ func (t *TreeSitterDriver) findContainingFunction(node *sitter.Node, src []byte)
 
 func (t *TreeSitterDriver) extractTSTypeUsages(pf *ParsedFile, src []byte, root *sitter.Node, lang *sitter.Language) {
 	// Type annotations in various contexts
-	queries := []struct {
-		query   string
-		context string
-	}{
-		{`(type_annotation (type_identifier) @type)`, "annotation"},
-		{`(type_arguments (type_identifier) @type)`, "type_argument"},
-		{`(return_type (type_identifier) @type)`, "return_type"},
-		{`(parameter type: (type_annotation (type_identifier) @type))`, "parameter"},
-		{`(property_signature type: (type_annotation (type_identifier) @type))`, "property"},
-		{`(type_parameter_declaration (type_parameter (type_identifier) @type))`, "type_parameter"},
-	}
-
-	for _, q := range queries {
-		if qs, err := sitter.NewQuery([]byte(q.query), lang); err == nil {
-			qc := sitter.NewQueryCursor()
-			qc.Exec(qs, root)
-			for {
-				match, ok := qc.NextMatch()
-				if !ok {
-					break
-				}
-
-				for _, capture := range match.Captures {
-					if qs.CaptureNameForId(capture.Index) == "type" {
-						typeName := string(src[capture.Node.StartByte():capture.Node.EndByte()])
-
-						// Find the containing context (function, class, etc.)
-						containingEntity := t.findContainingEntity(capture.Node, src)
-
-						pf.TypeUsages = append(pf.TypeUsages, model.TypeUsageEntity{
-							UsingFile:     pf.FilePath,
-							UsingEntity:   containingEntity,
-							UsedType:      typeName,
-							UsageContext:  q.context,
-							UsageLocation: int(capture.Node.StartPoint().Row) + 1,
-						})
-					}
+	for _, q := range tsTypeUsageQueries {
+		qs := t.query(lang, q.query)
+		for match := range t.matches(qs, root) {
+			for _, capture := range match.Captures {
+				if qs.CaptureNameForId(capture.Index) == "type" {
+					typeName := string(src[capture.Node.StartByte():capture.Node.EndByte()])
+
+					// Find the containing context (function, class, etc.)
+					containingEntity := t.findContainingEntity(capture.Node, src)
+
+					pf.TypeUsages = append(pf.TypeUsages, model.TypeUsageEntity{
+						UsingFile:     pf.FilePath,
+						UsingEntity:   containingEntity,
+						UsedType:      typeName,
+						UsageContext:  q.context,
+						UsageLocation: int(capture.Node.StartPoint().Row) + 1,
+					})
 				}
 			}
 		}
@@ -730,25 +806,8 @@ func (t *TreeSitterDriver) extractTSInheritance(pf *ParsedFile, src []byte, root
 
 // JSX extraction
 func (t *TreeSitterDriver) extractJSXElements(pf *ParsedFile, src []byte, root *sitter.Node, lang *sitter.Language) {
-	query := `(jsx_element (jsx_opening_element (identifier) @tag.name)) @jsx.element`
-	qs, err := sitter.NewQuery([]byte(query), lang)
-	if err != nil {
-		// Try alternative query for jsx_self_closing_element
-		query2 := `(jsx_self_closing_element (identifier) @tag.name) @jsx.element`
-		qs, err = sitter.NewQuery([]byte(query2), lang)
-		if err != nil {
-			return
-		}
-	}
-
-	qc := sitter.NewQueryCursor()
-	qc.Exec(qs, root)
-	for {
-		match, ok := qc.NextMatch()
-		if !ok {
-			break
-		}
-
+	qs := t.query(lang, jsxElementQuery)
+	for match := range t.matches(qs, root) {
 		var tagNode, elementNode *sitter.Node
 		for _, capture := range match.Captures {
 			capName := qs.CaptureNameForId(capture.Index)
@@ -774,6 +833,7 @@ func (t *TreeSitterDriver) extractJSXElements(pf *ParsedFile, src []byte, root *
 				ContainingComponent: containingComponent,
 				Props:               props,
 				Line:                int(tagNode.StartPoint().Row) + 1,
+				IsCustomComponent:   isComponentName(tagName),
 			})
 		}
 	}
@@ -783,29 +843,19 @@ func (t *TreeSitterDriver) extractJSXElements(pf *ParsedFile, src []byte, root *
 }
 
 func (t *TreeSitterDriver) extractJSXSelfClosingElements(pf *ParsedFile, src []byte, root *sitter.Node, lang *sitter.Language) {
-	query := `(jsx_self_closing_element) @jsx.element`
-	qs, err := sitter.NewQuery([]byte(query), lang)
-	if err != nil {
-		return
-	}
-
-	qc := sitter.NewQueryCursor()
-	qc.Exec(qs, root)
-	for {
-		match, ok := qc.NextMatch()
-		if !ok {
-			break
-		}
-
+	qs := t.query(lang, jsxSelfClosingQuery)
+	for match := range t.matches(qs, root) {
 		for _, capture := range match.Captures {
 			elementNode := capture.Node
 
 			// Find the identifier child
 			var tagName string
+			var tagNode *sitter.Node
 			for i := 0; i < int(elementNode.ChildCount()); i++ {
 				child := elementNode.Child(i)
 				if child != nil && child.Type() == "identifier" {
 					tagName = string(src[child.StartByte():child.EndByte()])
+					tagNode = child
 					break
 				}
 			}
@@ -822,7 +872,8 @@ func (t *TreeSitterDriver) extractJSXSelfClosingElements(pf *ParsedFile, src []b
 					FilePath:            pf.FilePath,
 					ContainingComponent: containingComponent,
 					Props:               props,
-					Line:                int(elementNode.StartPoint().Row) + 1,
+					Line:                int(tagNode.StartPoint().Row) + 1, // The element may start with the whitespace before it
+					IsCustomComponent:   isComponentName(tagName),
 				})
 			}
 		}
@@ -861,41 +912,26 @@ func (t *TreeSitterDriver) extractJSXProps(elementNode *sitter.Node, src []byte)
 // CSS extraction
 func (t *TreeSitterDriver) extractCSSRules(pf *ParsedFile, src []byte, root *sitter.Node, lang *sitter.Language) {
 	// Extract CSS class selectors
-	query1 := `(class_selector (class_name) @class.name) @selector`
-	if qs, err := sitter.NewQuery([]byte(query1), lang); err == nil {
-		t.runCSSClassQuery(pf, src, root, qs)
-	}
+	t.runCSSClassQuery(pf, src, root, t.query(lang, cssClassQuery))
 
 	// Extract CSS ID selectors
-	query2 := `(id_selector (id_name) @id.name) @selector`
-	if qs, err := sitter.NewQuery([]byte(query2), lang); err == nil {
-		t.runCSSIDQuery(pf, src, root, qs)
-	}
+	t.runCSSIDQuery(pf, src, root, t.query(lang, cssIDQuery))
 
 	// Extract CSS variables (custom properties)
-	query3 := `(declaration (property_name) @prop.name (plain_value) @prop.value)`
-	if qs, err := sitter.NewQuery([]byte(query3), lang); err == nil {
-		t.runCSSVariableQuery(pf, src, root, qs)
-	}
+	t.runCSSVariableQuery(pf, src, root, t.query(lang, cssVariableQuery))
 }
 
 func (t *TreeSitterDriver) runCSSClassQuery(pf *ParsedFile, src []byte, root *sitter.Node, qs *sitter.Query) {
-	qc := sitter.NewQueryCursor()
-	qc.Exec(qs, root)
-	for {
-		match, ok := qc.NextMatch()
-		if !ok {
-			break
-		}
-
+	for match := range t.matches(qs, root) {
 		for _, capture := range match.Captures {
 			if qs.CaptureNameForId(capture.Index) == "class.name" {
 				className := string(src[capture.Node.StartByte():capture.Node.EndByte()])
 				pf.CSSRules = append(pf.CSSRules, model.CSSRuleEntity{
-					Selector: "." + className,
-					RuleType: "class",
-					FilePath: pf.FilePath,
-					Line:     int(capture.Node.StartPoint().Row) + 1,
+					Selector:     "." + className,
+					FullSelector: cssFullSelector(capture.Node, src),
+					RuleType:     "class",
+					FilePath:     pf.FilePath,
+					Line:         int(capture.Node.StartPoint().Row) + 1,
 				})
 			}
 		}
@@ -903,37 +939,38 @@ func (t *TreeSitterDriver) runCSSClassQuery(pf *ParsedFile, src []byte, root *si
 }
 
 func (t *TreeSitterDriver) runCSSIDQuery(pf *ParsedFile, src []byte, root *sitter.Node, qs *sitter.Query) {
-	qc := sitter.NewQueryCursor()
-	qc.Exec(qs, root)
-	for {
-		match, ok := qc.NextMatch()
-		if !ok {
-			break
-		}
-
+	for match := range t.matches(qs, root) {
 		for _, capture := range match.Captures {
 			if qs.CaptureNameForId(capture.Index) == "id.name" {
 				idName := string(src[capture.Node.StartByte():capture.Node.EndByte()])
 				pf.CSSRules = append(pf.CSSRules, model.CSSRuleEntity{
-					Selector: "#" + idName,
-					RuleType: "id",
-					FilePath: pf.FilePath,
-					Line:     int(capture.Node.StartPoint().Row) + 1,
+					Selector:     "#" + idName,
+					FullSelector: cssFullSelector(capture.Node, src),
+					RuleType:     "id",
+					FilePath:     pf.FilePath,
+					Line:         int(capture.Node.StartPoint().Row) + 1,
 				})
 			}
 		}
 	}
 }
 
-func (t *TreeSitterDriver) runCSSVariableQuery(pf *ParsedFile, src []byte, root *sitter.Node, qs *sitter.Query) {
-	qc := sitter.NewQueryCursor()
-	qc.Exec(qs, root)
-	for {
-		match, ok := qc.NextMatch()
-		if !ok {
-			break
-		}
+// cssFullSelector returns the complex selector containing a class or ID name,
+// one entry of its rule's comma-separated selector list, with runs of
+// whitespace collapsed.
+func cssFullSelector(name *sitter.Node, src []byte) string {
+	node := name
+	for parent := node.Parent(); parent != nil && parent.Type() != "selectors"; parent = parent.Parent() {
+		node = parent
+	}
+	if node.Parent() == nil {
+		return "" // Not part of a rule's selectors, as in @media or @keyframes
+	}
+	return collapseSpace(nodeText(node, src))
+}
 
+func (t *TreeSitterDriver) runCSSVariableQuery(pf *ParsedFile, src []byte, root *sitter.Node, qs *sitter.Query) {
+	for match := range t.matches(qs, root) {
 		var propName, propValue string
 		for _, capture := range match.Captures {
 			capName := qs.CaptureNameForId(capture.Index)
@@ -971,21 +1008,8 @@ func (t *TreeSitterDriver) extractJSVariables(pf *ParsedFile, src []byte, root *
 }
 
 func (t *TreeSitterDriver) extractJSClasses(pf *ParsedFile, src []byte, root *sitter.Node, lang *sitter.Language) {
-	query := `(class_declaration) @class`
-	qs, err := sitter.NewQuery([]byte(query), lang)
-	if err != nil {
-		log.Printf("Failed to compile JS class query: %v", err)
-		return
-	}
-
-	qc := sitter.NewQueryCursor()
-	qc.Exec(qs, root)
-	for {
-		match, ok := qc.NextMatch()
-		if !ok {
-			break
-		}
-
+	qs := t.query(lang, jsClassQuery)
+	for match := range t.matches(qs, root) {
 		for _, capture := range match.Captures {
 			classNode := capture.Node
 
@@ -1017,10 +1041,17 @@ func (t *TreeSitterDriver) extractJSClasses(pf *ParsedFile, src []byte, root *si
 			}
 
 			if className != "" {
-				pf.Classes = append(pf.Classes, model.ClassEntity{
-					Name:     className,
-					FilePath: pf.FilePath,
-				})
+				class := model.ClassEntity{
+					Name:          className,
+					QualifiedName: t.qualifiedName(classNode, className, src),
+					FilePath:      pf.FilePath,
+					StartLine:     int(classNode.StartPoint().Row) + 1,
+					EndLine:       int(classNode.EndPoint().Row) + 1,
+					Doc:           jsDocComment(classNode, src),
+				}
+				t.extractClassMembers(pf, &class, classNode, src)
+				t.addClassDecorators(pf, class, classNode, src)
+				pf.Classes = append(pf.Classes, class)
 
 				// Add extends relationship
 				if extendsClass != "" {
@@ -1040,20 +1071,8 @@ func (t *TreeSitterDriver) extractJSFunctionCalls(pf *ParsedFile, src []byte, ro
 }
 
 func (t *TreeSitterDriver) extractTSVariables(pf *ParsedFile, src []byte, root *sitter.Node, lang *sitter.Language) {
-	query := `(variable_declarator name: (identifier) @var.name) @var.decl`
-	qs, err := sitter.NewQuery([]byte(query), lang)
-	if err != nil {
-		return
-	}
-
-	qc := sitter.NewQueryCursor()
-	qc.Exec(qs, root)
-	for {
-		match, ok := qc.NextMatch()
-		if !ok {
-			break
-		}
-
+	qs := t.query(lang, tsVariableQuery)
+	for match := range t.matches(qs, root) {
 		var nameNode, declNode *sitter.Node
 		for _, capture := range match.Captures {
 			capName := qs.CaptureNameForId(capture.Index)
@@ -1081,35 +1100,25 @@ func (t *TreeSitterDriver) extractTSVariables(pf *ParsedFile, src []byte, root *
 
 			if valueIdx != -1 {
 				valueNode := declNode.Child(valueIdx)
-				if valueNode.Type() == "arrow_function" || valueNode.Type() == "function" || valueNode.Type() == "function_expression" {
+				if valueNode.Type() == "arrow_function" || valueNode.Type() == "function" || valueNode.Type() == "function_expression" || valueNode.Type() == "generator_function" {
 					continue // Skip function assignments
 				}
 			}
 
 			pf.Variables = append(pf.Variables, model.VariableEntity{
-				Name:     varName,
-				FilePath: pf.FilePath,
-				Type:     "variable",
+				Name:      varName,
+				FilePath:  pf.FilePath,
+				Type:      "variable",
+				StartLine: int(declNode.StartPoint().Row) + 1,
+				Doc:       jsDocComment(declNode, src),
 			})
 		}
 	}
 }
 
 func (t *TreeSitterDriver) extractTSTypes(pf *ParsedFile, src []byte, root *sitter.Node, lang *sitter.Language) {
-	query := `(type_alias_declaration name: (type_identifier) @type.name)`
-	qs, err := sitter.NewQuery([]byte(query), lang)
-	if err != nil {
-		return
-	}
-
-	qc := sitter.NewQueryCursor()
-	qc.Exec(qs, root)
-	for {
-		match, ok := qc.NextMatch()
-		if !ok {
-			break
-		}
-
+	qs := t.query(lang, tsTypeAliasQuery)
+	for match := range t.matches(qs, root) {
 		for _, capture := range match.Captures {
 			if qs.CaptureNameForId(capture.Index) == "type.name" {
 				typeName := string(src[capture.Node.StartByte():capture.Node.EndByte()])
@@ -1117,6 +1126,7 @@ func (t *TreeSitterDriver) extractTSTypes(pf *ParsedFile, src []byte, root *sitt
 					Name:     typeName,
 					FilePath: pf.FilePath,
 					Kind:     "type_alias",
+					Doc:      jsDocComment(capture.Node.Parent(), src),
 				})
 			}
 		}
@@ -1128,6 +1138,9 @@ func (t *TreeSitterDriver) resolveFunctionCalls(pf *ParsedFile) {
 	// Create a map of function names to their definitions
 	funcMap := make(map[string]model.FunctionEntity)
 	for _, fn := range pf.Funcs {
+		if fn.Receiver != "" {
+			continue // Go methods are only reachable through their receiver
+		}
 		funcMap[fn.Name] = fn
 	}
 
@@ -1139,6 +1152,7 @@ func (t *TreeSitterDriver) resolveFunctionCalls(pf *ParsedFile) {
 		if fn, exists := funcMap[call.CalledFunc]; exists {
 			call.ResolvedTarget = fn.Name
 			call.TargetFile = fn.FilePath
+			call.TargetID = fn.ID
 		}
 
 		// For method calls, try to resolve based on context
@@ -1151,9 +1165,10 @@ func (t *TreeSitterDriver) resolveFunctionCalls(pf *ParsedFile) {
 					if class.Name == call.CallContext {
 						// Method might belong to this class
 						for _, fn := range pf.Funcs {
-							if fn.Name == methodName {
+							if fn.Name == methodName && goMethodMatches(pf, fn, class.Name) {
 								call.ResolvedTarget = fn.Name
 								call.TargetFile = fn.FilePath
+								call.TargetID = fn.ID
 								break
 							}
 						}
@@ -1161,5 +1176,28 @@ func (t *TreeSitterDriver) resolveFunctionCalls(pf *ParsedFile) {
 				}
 			}
 		}
+
+		// Calls on the receiver (this/self/cls) target a method of the enclosing class
+		if call.ResolvedTarget == "" && isReceiverContext(call.CallContext) {
+			methodName := call.CalledFunc[strings.LastIndex(call.CalledFunc, ".")+1:]
+			for _, class := range pf.Classes {
+				if call.CallLocation < class.StartLine || call.CallLocation > class.EndLine {
+					continue
+				}
+				for _, fn := range pf.Funcs {
+					if fn.Name == methodName && fn.StartLine >= class.StartLine && fn.EndLine <= class.EndLine {
+						call.ResolvedTarget = fn.Name
+						call.TargetFile = fn.FilePath
+						call.TargetID = fn.ID
+						break
+					}
+				}
+			}
+		}
 	}
 }
+
+// isReceiverContext reports whether a call context names the current instance or class.
+func isReceiverContext(context string) bool {
+	return context == "this" || context == "self" || context == "cls"
+}
@@ -0,0 +1,140 @@
+// internal/driver/treesitter_driver_test.go
+
+package driver
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// corpus is the project the parse benchmarks walk. Point it at a large
+// checkout to measure real throughput:
+//
+//	go test -run '^$' -bench Parse ./internal/driver -args -corpus /path/to/project
+var corpus = flag.String("corpus", "testdata/corpus", "directory of source files parsed by the benchmarks")
+
+// corpusFiles returns the files under the corpus the driver can parse and
+// their total size in bytes.
+func corpusFiles(b *testing.B, t *TreeSitterDriver) ([]string, int64) {
+	b.Helper()
+	var files []string
+	var size int64
+	err := filepath.Walk(*corpus, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if path != *corpus && t.SkipDir(info.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if t.Supports(path) {
+			files = append(files, path)
+			size += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	if len(files) == 0 {
+		b.Fatalf("no parseable files under %s", *corpus)
+	}
+	return files, size
+}
+
+func newBenchmarkDriver(b *testing.B) *TreeSitterDriver {
+	b.Helper()
+	t, err := NewTreeSitterDriver()
+	if err != nil {
+		b.Fatal(err)
+	}
+	return t
+}
+
+// BenchmarkParse parses the whole corpus per iteration with one shared
+// driver, whose queries are compiled once.
+func BenchmarkParse(b *testing.B) {
+	t := newBenchmarkDriver(b)
+	files, size := corpusFiles(b, t)
+	b.SetBytes(size)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, path := range files {
+			if _, err := t.Parse(path); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+	b.ReportMetric(float64(len(files)*b.N)/b.Elapsed().Seconds(), "files/s")
+}
+
+// BenchmarkParseParallel parses the corpus from GOMAXPROCS goroutines
+// sharing one driver, so that they contend for its parser and query cursor
+// pools as the CLI's workers do. An iteration is one file.
+func BenchmarkParseParallel(b *testing.B) {
+	t := newBenchmarkDriver(b)
+	files, size := corpusFiles(b, t)
+	b.SetBytes(size / int64(len(files)))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			if _, err := t.Parse(files[i%len(files)]); err != nil {
+				b.Error(err)
+				return
+			}
+			i++
+		}
+	})
+	b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "files/s")
+}
+
+// BenchmarkParseRecompiled is the baseline BenchmarkParse improves on: before
+// each parse the queries of the file's grammar are compiled afresh, as
+// extraction did before they were compiled once per driver.
+func BenchmarkParseRecompiled(b *testing.B) {
+	t := newBenchmarkDriver(b)
+	files, size := corpusFiles(b, t)
+	compiled := t.queries
+	b.Cleanup(func() { t.queries = compiled })
+	b.SetBytes(size)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, path := range files {
+			lang := t.extractors[filepath.Ext(path)].Grammar()
+			queries := make(map[queryKey]*sitter.Query)
+			for key := range compiled {
+				if key.lang != lang {
+					continue
+				}
+				qs, err := sitter.NewQuery([]byte(key.source), lang)
+				if err != nil {
+					b.Fatal(err)
+				}
+				queries[key] = qs
+			}
+			t.queries = queries
+
+			_, err := t.Parse(path)
+			for _, qs := range queries {
+				qs.Close()
+			}
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+	b.ReportMetric(float64(len(files)*b.N)/b.Elapsed().Seconds(), "files/s")
+}
@@ -5,12 +5,15 @@ package embeddings
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"strings"
+	"sync/atomic"
 	"time"
 )
 
@@ -19,6 +22,7 @@ type EmbeddingProvider interface {
 	GenerateEmbedding(ctx context.Context, text string) ([]float32, error)
 	GenerateEmbeddings(ctx context.Context, texts []string) ([][]float32, error)
 	GetDimension() int
+	GetModel() string
 }
 
 // OpenAIProvider implements embedding generation using OpenAI API
@@ -30,6 +34,17 @@ type OpenAIProvider struct {
 	httpClient *http.Client
 }
 
+// init registers the OpenAI provider
+func init() {
+	RegisterProvider("openai", "OpenAI", func(model string, dimension int) (EmbeddingProvider, error) {
+		provider, err := NewOpenAIProvider(model, dimension)
+		if err != nil {
+			return nil, err
+		}
+		return provider, nil
+	})
+}
+
 // NewOpenAIProvider creates a new OpenAI embedding provider
 func NewOpenAIProvider(model string, dimension int) (*OpenAIProvider, error) {
 	apiKey := os.Getenv("OPENAI_API_KEY")
@@ -43,10 +58,11 @@ func NewOpenAIProvider(model string, dimension int) (*OpenAIProvider, error) {
 	}
 	endpoint = strings.TrimRight(endpoint, "/") + "/v1/embeddings"
 
-	// Validate model and dimension combinations
+	// Validate model and dimension combinations; the first dimension listed
+	// is the model's default
 	validModels := map[string][]int{
-		"text-embedding-3-small": {512, 1536},
-		"text-embedding-3-large": {256, 1024, 3072},
+		"text-embedding-3-small": {1536, 512},
+		"text-embedding-3-large": {3072, 1024, 256},
 		"text-embedding-ada-002": {1536},
 	}
 
@@ -91,6 +107,11 @@ func (p *OpenAIProvider) GetDimension() int {
 	return p.dimension
 }
 
+// GetModel returns the embedding model name
+func (p *OpenAIProvider) GetModel() string {
+	return p.model
+}
+
 // GenerateEmbedding generates an embedding for a single text
 func (p *OpenAIProvider) GenerateEmbedding(ctx context.Context, text string) ([]float32, error) {
 	embeddings, err := p.GenerateEmbeddings(ctx, []string{text})
@@ -171,6 +192,10 @@ type CodeEmbeddingGenerator struct {
 	oracleStore *OracleEmbeddingStore
 	batchSize   int
 	useOracle   bool
+
+	// Chunks reused from the store and chunks sent to the provider
+	cacheHits   atomic.Int64
+	cacheMisses atomic.Int64
 }
 
 // NewCodeEmbeddingGenerator creates a new code embedding generator
@@ -208,30 +233,105 @@ func (g *CodeEmbeddingGenerator) Close() error {
 	return nil
 }
 
-// ProcessFile generates embeddings for all chunks in a parsed file
+// ProcessFile replaces the stored chunks of a parsed file with its current
+// ones. A chunk whose content hash matches the stored chunk with the same ID
+// keeps its embedding, so only new or changed chunks reach the provider.
 func (g *CodeEmbeddingGenerator) ProcessFile(ctx context.Context, parsedFile ParsedFileData) error {
 	// Create chunks from parsed file
 	chunks := CreateCodeChunks(parsedFile)
-	if len(chunks) == 0 {
-		return nil
+
+	stored, err := g.chunkHashes(ctx, parsedFile.FilePath)
+	if err != nil {
+		return fmt.Errorf("failed to load stored chunks: %w", err)
+	}
+
+	// Refresh the details of unchanged chunks and collect the rest for embedding
+	var changed []CodeChunk
+	current := make(map[string]bool, len(chunks))
+	for _, chunk := range chunks {
+		chunk.ContentHash = g.contentHash(g.prepareTextForEmbedding(chunk))
+		current[chunk.ID] = true
+
+		if stored[chunk.ID] != chunk.ContentHash {
+			changed = append(changed, chunk)
+			continue
+		}
+		if err := g.updateChunkDetails(ctx, chunk); err != nil {
+			return fmt.Errorf("failed to update chunk %s: %w", chunk.ID, err)
+		}
+		g.cacheHits.Add(1)
 	}
 
-	// Process chunks in batches
-	for i := 0; i < len(chunks); i += g.batchSize {
+	// Process changed chunks in batches
+	for i := 0; i < len(changed); i += g.batchSize {
 		end := i + g.batchSize
-		if end > len(chunks) {
-			end = len(chunks)
+		if end > len(changed) {
+			end = len(changed)
 		}
 
-		batch := chunks[i:end]
+		batch := changed[i:end]
 		if err := g.processBatch(ctx, batch); err != nil {
 			return fmt.Errorf("failed to process batch %d-%d: %w", i, end, err)
 		}
+		g.cacheMisses.Add(int64(len(batch)))
+	}
+
+	// Drop chunks of entities that are no longer in the file
+	var stale []string
+	for id := range stored {
+		if !current[id] {
+			stale = append(stale, id)
+		}
+	}
+	if err := g.deleteChunks(ctx, stale); err != nil {
+		return fmt.Errorf("failed to delete stale chunks: %w", err)
 	}
 
 	return nil
 }
 
+// CacheStats returns how many chunks reused a stored embedding and how many
+// were sent to the provider since the generator was created
+func (g *CodeEmbeddingGenerator) CacheStats() (hits, misses int64) {
+	return g.cacheHits.Load(), g.cacheMisses.Load()
+}
+
+// contentHash identifies the embedding of text: the same text embedded by the
+// same model at the same dimension always yields the same vector
+func (g *CodeEmbeddingGenerator) contentHash(text string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%d\x00", g.provider.GetModel(), g.provider.GetDimension())
+	h.Write([]byte(text))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// chunkHashes returns the content hashes stored for a file, keyed by chunk ID
+func (g *CodeEmbeddingGenerator) chunkHashes(ctx context.Context, filePath string) (map[string]string, error) {
+	if g.useOracle {
+		return g.oracleStore.ChunkHashes(ctx, filePath)
+	}
+	return g.pgStore.ChunkHashes(ctx, filePath)
+}
+
+// updateChunkDetails refreshes a stored chunk without touching its embedding
+func (g *CodeEmbeddingGenerator) updateChunkDetails(ctx context.Context, chunk CodeChunk) error {
+	if g.useOracle {
+		return g.oracleStore.UpdateChunkDetails(ctx, chunk)
+	}
+	return g.pgStore.UpdateChunkDetails(ctx, chunk)
+}
+
+// deleteChunks removes stored chunks by ID
+func (g *CodeEmbeddingGenerator) deleteChunks(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if g.useOracle {
+		return g.oracleStore.DeleteChunks(ctx, ids)
+	}
+	return g.pgStore.DeleteChunks(ctx, ids)
+}
+
 // processBatch processes a batch of chunks
 func (g *CodeEmbeddingGenerator) processBatch(ctx context.Context, chunks []CodeChunk) error {
 	// Prepare texts for embedding
@@ -249,6 +349,9 @@ func (g *CodeEmbeddingGenerator) processBatch(ctx context.Context, chunks []Code
 	// Update chunks with embeddings
 	for i, chunk := range chunks {
 		if i < len(embeddings) {
+			if len(embeddings[i]) != g.provider.GetDimension() {
+				return fmt.Errorf("embedding for chunk %s has %d dimensions, expected %d", chunk.ID, len(embeddings[i]), g.provider.GetDimension())
+			}
 			chunk.Embedding = embeddings[i]
 			chunk.EmbeddingDim = len(embeddings[i])
 
@@ -304,6 +407,11 @@ func (g *CodeEmbeddingGenerator) prepareTextForEmbedding(chunk CodeChunk) string
 		}
 	}
 
+	// Add the doc comment, which describes intent in prose the code lacks
+	if doc, ok := chunk.Metadata["doc"].(string); ok && doc != "" {
+		parts = append(parts, fmt.Sprintf("Documentation: %s", doc))
+	}
+
 	// Add the main content
 	parts = append(parts, "\n--- Content ---\n")
 	parts = append(parts, chunk.Content)
@@ -341,6 +449,14 @@ func (g *CodeEmbeddingGenerator) HybridSearch(ctx context.Context, query string,
 	return g.oracleStore.HybridSearch(ctx, embedding, keywords, limit, filters)
 }
 
+// DeleteFile removes all stored chunks for a file
+func (g *CodeEmbeddingGenerator) DeleteFile(ctx context.Context, filePath string) error {
+	if g.useOracle {
+		return g.oracleStore.DeleteChunksForFile(ctx, filePath)
+	}
+	return g.pgStore.DeleteChunksForFile(ctx, filePath)
+}
+
 // GetStats returns statistics about the embeddings
 func (g *CodeEmbeddingGenerator) GetStats(ctx context.Context) (map[string]interface{}, error) {
 	if g.useOracle {
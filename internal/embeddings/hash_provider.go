@@ -0,0 +1,137 @@
+// internal/embeddings/hash_provider.go
+
+package embeddings
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// defaultHashDimension is used when no dimension is given
+const defaultHashDimension = 256
+
+// hashModel names the hashing scheme. Change it whenever the features change
+// so embeddings cached under the old scheme are recomputed.
+const hashModel = "feature-hash-v1"
+
+// HashProvider generates embeddings by feature hashing the identifiers in a
+// text. The same text always gives the same vector, and texts sharing
+// identifiers or their camelCase/snake_case parts land close together. It
+// needs no network access, which makes it suitable for tests and air-gapped
+// machines, but it captures no meaning beyond shared words.
+type HashProvider struct {
+	dimension int
+}
+
+// init registers the hashing provider
+func init() {
+	RegisterProvider("hash", "Deterministic hashing (offline)", func(model string, dimension int) (EmbeddingProvider, error) {
+		return NewHashProvider(dimension), nil
+	})
+}
+
+// NewHashProvider creates a hashing provider producing vectors of dimension
+// floats, or defaultHashDimension when dimension is zero
+func NewHashProvider(dimension int) *HashProvider {
+	if dimension <= 0 {
+		dimension = defaultHashDimension
+	}
+	return &HashProvider{dimension: dimension}
+}
+
+// GetDimension returns the embedding dimension
+func (p *HashProvider) GetDimension() int {
+	return p.dimension
+}
+
+// GetModel returns the name of the hashing scheme
+func (p *HashProvider) GetModel() string {
+	return hashModel
+}
+
+// GenerateEmbedding generates an embedding for a single text
+func (p *HashProvider) GenerateEmbedding(ctx context.Context, text string) ([]float32, error) {
+	vec := make([]float64, p.dimension)
+	for _, token := range hashTokens(text) {
+		p.add(vec, token, 1)
+		// Sub-words let getUserName match user_name and UserService
+		if parts := splitIdentifier(token); len(parts) > 1 {
+			for _, part := range parts {
+				p.add(vec, part, 0.5)
+			}
+		}
+	}
+
+	// L2-normalize so cosine distance only depends on direction
+	var norm float64
+	for _, v := range vec {
+		norm += v * v
+	}
+	norm = math.Sqrt(norm)
+
+	embedding := make([]float32, p.dimension)
+	for i, v := range vec {
+		if norm > 0 {
+			embedding[i] = float32(v / norm)
+		}
+	}
+	return embedding, nil
+}
+
+// GenerateEmbeddings generates embeddings for multiple texts
+func (p *HashProvider) GenerateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
+	embeddings := make([][]float32, len(texts))
+	for i, text := range texts {
+		embedding, err := p.GenerateEmbedding(ctx, text)
+		if err != nil {
+			return nil, err
+		}
+		embeddings[i] = embedding
+	}
+	return embeddings, nil
+}
+
+// add hashes feature into a bucket of vec with a hash-derived sign, so
+// colliding features tend to cancel out rather than pile up
+func (p *HashProvider) add(vec []float64, feature string, weight float64) {
+	h := fnv.New64a()
+	h.Write([]byte(strings.ToLower(feature)))
+	sum := h.Sum64()
+
+	if sum&(1<<63) != 0 {
+		weight = -weight
+	}
+	vec[sum%uint64(p.dimension)] += weight
+}
+
+// hashTokens splits text into identifier-like tokens
+func hashTokens(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	})
+}
+
+// splitIdentifier splits a camelCase, PascalCase or snake_case identifier
+// into its words, keeping acronyms together (parseHTTPRequest -> parse, HTTP, Request)
+func splitIdentifier(token string) []string {
+	var parts []string
+	for _, word := range strings.Split(token, "_") {
+		runes := []rune(word)
+		start := 0
+		for i := 1; i < len(runes); i++ {
+			lowerToUpper := unicode.IsLower(runes[i-1]) && unicode.IsUpper(runes[i])
+			acronymEnd := i+1 < len(runes) && unicode.IsUpper(runes[i-1]) && unicode.IsUpper(runes[i]) && unicode.IsLower(runes[i+1])
+			if lowerToUpper || acronymEnd {
+				parts = append(parts, string(runes[start:i]))
+				start = i
+			}
+		}
+		if start < len(runes) {
+			parts = append(parts, string(runes[start:]))
+		}
+	}
+	return parts
+}
@@ -0,0 +1,226 @@
+// internal/embeddings/local_provider.go
+
+package embeddings
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Wire formats a LocalProvider can speak
+const (
+	localAPIOllama = "ollama" // POST /api/embeddings, one prompt per request
+	localAPIOpenAI = "openai" // POST /v1/embeddings, batched input
+)
+
+// LocalProvider implements embedding generation against a self-hosted
+// server, either Ollama or any server exposing the OpenAI embeddings API.
+// Model names are passed through unchecked; the dimension is whatever the
+// model returns.
+type LocalProvider struct {
+	api        string
+	apiKey     string
+	model      string
+	dimension  int
+	endpoint   string
+	httpClient *http.Client
+}
+
+// init registers the local providers
+func init() {
+	RegisterProvider("ollama", "Ollama", func(model string, dimension int) (EmbeddingProvider, error) {
+		provider, err := NewOllamaProvider(model, dimension)
+		if err != nil {
+			return nil, err
+		}
+		return provider, nil
+	})
+	RegisterProvider("openai-compatible", "OpenAI-compatible server", func(model string, dimension int) (EmbeddingProvider, error) {
+		provider, err := NewOpenAICompatibleProvider(model, dimension)
+		if err != nil {
+			return nil, err
+		}
+		return provider, nil
+	})
+}
+
+// NewOllamaProvider creates a provider for an Ollama server at OLLAMA_HOST
+// (default http://localhost:11434). The model defaults to nomic-embed-text.
+// A zero dimension is detected by embedding a probe text.
+func NewOllamaProvider(model string, dimension int) (*LocalProvider, error) {
+	host := os.Getenv("OLLAMA_HOST")
+	if host == "" {
+		host = "http://localhost:11434"
+	}
+	if !strings.Contains(host, "://") {
+		host = "http://" + host // OLLAMA_HOST is commonly set as host:port
+	}
+
+	if model == "" {
+		model = "nomic-embed-text"
+	}
+
+	return newLocalProvider(localAPIOllama, strings.TrimRight(host, "/")+"/api/embeddings", "", model, dimension)
+}
+
+// NewOpenAICompatibleProvider creates a provider for a server exposing
+// /v1/embeddings at EMBEDDING_BASE_URL, such as vLLM, LM Studio or
+// llama.cpp. EMBEDDING_API_KEY is sent as a bearer token when set. A zero
+// dimension is detected by embedding a probe text.
+func NewOpenAICompatibleProvider(model string, dimension int) (*LocalProvider, error) {
+	baseURL := os.Getenv("EMBEDDING_BASE_URL")
+	if baseURL == "" {
+		return nil, fmt.Errorf("EMBEDDING_BASE_URL environment variable not set")
+	}
+	if model == "" {
+		return nil, fmt.Errorf("an embedding model is required for OpenAI-compatible servers")
+	}
+
+	endpoint := strings.TrimRight(baseURL, "/")
+	if !strings.HasSuffix(endpoint, "/v1") {
+		endpoint += "/v1"
+	}
+
+	return newLocalProvider(localAPIOpenAI, endpoint+"/embeddings", os.Getenv("EMBEDDING_API_KEY"), model, dimension)
+}
+
+// newLocalProvider builds a LocalProvider, probing the server for the
+// dimension when none is given
+func newLocalProvider(api, endpoint, apiKey, model string, dimension int) (*LocalProvider, error) {
+	p := &LocalProvider{
+		api:       api,
+		apiKey:    apiKey,
+		model:     model,
+		dimension: dimension,
+		endpoint:  endpoint,
+		httpClient: &http.Client{
+			Timeout: 120 * time.Second, // Local models on CPU can be slow
+		},
+	}
+
+	if p.dimension == 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), p.httpClient.Timeout)
+		defer cancel()
+
+		embedding, err := p.GenerateEmbedding(ctx, "dimension probe")
+		if err != nil {
+			return nil, fmt.Errorf("failed to detect embedding dimension of %s: %w", model, err)
+		}
+		if len(embedding) == 0 {
+			return nil, fmt.Errorf("model %s returned an empty embedding", model)
+		}
+		p.dimension = len(embedding)
+	}
+
+	return p, nil
+}
+
+// GetDimension returns the embedding dimension
+func (p *LocalProvider) GetDimension() int {
+	return p.dimension
+}
+
+// GetModel returns the embedding model name
+func (p *LocalProvider) GetModel() string {
+	return p.model
+}
+
+// GenerateEmbedding generates an embedding for a single text
+func (p *LocalProvider) GenerateEmbedding(ctx context.Context, text string) ([]float32, error) {
+	embeddings, err := p.GenerateEmbeddings(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	if len(embeddings) == 0 {
+		return nil, fmt.Errorf("no embedding returned")
+	}
+	return embeddings[0], nil
+}
+
+// GenerateEmbeddings generates embeddings for multiple texts
+func (p *LocalProvider) GenerateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
+	var embeddings [][]float32
+	if p.api == localAPIOllama {
+		// Ollama's /api/embeddings takes a single prompt
+		embeddings = make([][]float32, 0, len(texts))
+		for _, text := range texts {
+			var response struct {
+				Embedding []float32 `json:"embedding"`
+			}
+			if err := p.post(ctx, map[string]interface{}{"model": p.model, "prompt": text}, &response); err != nil {
+				return nil, err
+			}
+			embeddings = append(embeddings, response.Embedding)
+		}
+	} else {
+		var response struct {
+			Data []struct {
+				Index     int       `json:"index"`
+				Embedding []float32 `json:"embedding"`
+			} `json:"data"`
+		}
+		if err := p.post(ctx, map[string]interface{}{"model": p.model, "input": texts}, &response); err != nil {
+			return nil, err
+		}
+		if len(response.Data) != len(texts) {
+			return nil, fmt.Errorf("server returned %d embeddings for %d inputs", len(response.Data), len(texts))
+		}
+
+		// Servers may answer out of order; index says which input each belongs to
+		embeddings = make([][]float32, len(texts))
+		for i, data := range response.Data {
+			if data.Index >= 0 && data.Index < len(texts) {
+				i = data.Index
+			}
+			embeddings[i] = data.Embedding
+		}
+	}
+
+	for _, embedding := range embeddings {
+		if p.dimension > 0 && len(embedding) != p.dimension {
+			return nil, fmt.Errorf("model %s returned %d dimensions, expected %d", p.model, len(embedding), p.dimension)
+		}
+	}
+	return embeddings, nil
+}
+
+// post sends body as JSON to the provider's endpoint and decodes the reply into out
+func (p *LocalProvider) post(ctx context.Context, body interface{}, out interface{}) error {
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.endpoint, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("embedding server error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -27,6 +27,10 @@ func init() {
 
 // NewOracleEmbeddingStore creates a new Oracle embedding store
 func NewOracleEmbeddingStore(embeddingDim int) (*OracleEmbeddingStore, error) {
+	if embeddingDim <= 0 {
+		return nil, fmt.Errorf("invalid embedding dimension %d", embeddingDim)
+	}
+
 	user := os.Getenv("ORACLE_USER")
 	pass := os.Getenv("ORACLE_PASS")
 	dsn := os.Getenv("ORACLE_DSN")
@@ -77,7 +81,7 @@ func (s *OracleEmbeddingStore) initialize() error {
 	// Create embeddings table with vector column
 	createTableSQL := fmt.Sprintf(`
 		CREATE TABLE %s (
-			id VARCHAR2(255) PRIMARY KEY,
+			id VARCHAR2(2000) PRIMARY KEY,
 			chunk_type VARCHAR2(50) NOT NULL,
 			name VARCHAR2(500) NOT NULL,
 			file_path VARCHAR2(1000) NOT NULL,
@@ -87,6 +91,7 @@ func (s *OracleEmbeddingStore) initialize() error {
 			language VARCHAR2(20),
 			metadata CLOB CHECK (metadata IS JSON),
 			embedding VECTOR(%d, FLOAT32),
+			content_hash VARCHAR2(64),
 			created_at TIMESTAMP DEFAULT SYSTIMESTAMP,
 			updated_at TIMESTAMP DEFAULT SYSTIMESTAMP
 		)
@@ -110,6 +115,37 @@ func (s *OracleEmbeddingStore) initialize() error {
 		}
 	}
 
+	// Tables created before chunks were hashed lack the column
+	var hashColumns int
+	err = s.db.QueryRow(fmt.Sprintf(
+		"SELECT COUNT(*) FROM user_tab_columns WHERE table_name = '%s' AND column_name = 'CONTENT_HASH'",
+		strings.ToUpper(s.tableName),
+	)).Scan(&hashColumns)
+	if err != nil {
+		return fmt.Errorf("failed to check for content_hash column: %w", err)
+	}
+	if hashColumns == 0 {
+		if _, err := s.db.Exec(fmt.Sprintf("ALTER TABLE %s ADD (content_hash VARCHAR2(64))", s.tableName)); err != nil {
+			return fmt.Errorf("failed to add content_hash column: %w", err)
+		}
+	}
+
+	// Chunk IDs embed the file path and qualified name, which older tables
+	// were too narrow for
+	var idLength int
+	err = s.db.QueryRow(fmt.Sprintf(
+		"SELECT data_length FROM user_tab_columns WHERE table_name = '%s' AND column_name = 'ID'",
+		strings.ToUpper(s.tableName),
+	)).Scan(&idLength)
+	if err != nil {
+		return fmt.Errorf("failed to check id column: %w", err)
+	}
+	if idLength < 2000 {
+		if _, err := s.db.Exec(fmt.Sprintf("ALTER TABLE %s MODIFY (id VARCHAR2(2000))", s.tableName)); err != nil {
+			return fmt.Errorf("failed to widen id column: %w", err)
+		}
+	}
+
 	// Create indexes
 	indexes := []struct {
 		name   string
@@ -213,10 +249,11 @@ func (s *OracleEmbeddingStore) UpsertChunk(ctx context.Context, chunk CodeChunk)
 				language = :8,
 				metadata = :9,
 				embedding = TO_VECTOR(:10),
+				content_hash = :11,
 				updated_at = SYSTIMESTAMP
 		WHEN NOT MATCHED THEN
-			INSERT (id, chunk_type, name, file_path, content, start_line, end_line, language, metadata, embedding)
-			VALUES (:1, :2, :3, :4, :5, :6, :7, :8, :9, TO_VECTOR(:10))
+			INSERT (id, chunk_type, name, file_path, content, start_line, end_line, language, metadata, embedding, content_hash)
+			VALUES (:1, :2, :3, :4, :5, :6, :7, :8, :9, TO_VECTOR(:10), :11)
 	`, s.tableName)
 
 	_, err = s.db.ExecContext(ctx, query,
@@ -230,11 +267,74 @@ func (s *OracleEmbeddingStore) UpsertChunk(ctx context.Context, chunk CodeChunk)
 		chunk.Language,
 		string(metadataJSON),
 		vectorStr,
+		chunk.ContentHash,
 	)
 
 	return err
 }
 
+// UpdateChunkDetails rewrites everything about a stored chunk except its
+// embedding and content hash, for chunks whose embedded text is unchanged
+func (s *OracleEmbeddingStore) UpdateChunkDetails(ctx context.Context, chunk CodeChunk) error {
+	metadataJSON, err := json.Marshal(chunk.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %s SET
+			chunk_type = :2,
+			name = :3,
+			file_path = :4,
+			content = :5,
+			start_line = :6,
+			end_line = :7,
+			language = :8,
+			metadata = :9,
+			updated_at = SYSTIMESTAMP
+		WHERE id = :1
+	`, s.tableName)
+
+	_, err = s.db.ExecContext(ctx, query,
+		chunk.ID,
+		string(chunk.Type),
+		chunk.Name,
+		chunk.FilePath,
+		chunk.Content,
+		chunk.StartLine,
+		chunk.EndLine,
+		chunk.Language,
+		string(metadataJSON),
+	)
+
+	return err
+}
+
+// ChunkHashes returns the content hash of every chunk stored for a file,
+// keyed by chunk ID. Chunks stored without a hash, such as those written
+// before hashes were recorded, map to "" so they are re-embedded or deleted.
+func (s *OracleEmbeddingStore) ChunkHashes(ctx context.Context, filePath string) (map[string]string, error) {
+	query := fmt.Sprintf("SELECT id, content_hash FROM %s WHERE file_path = :1", s.tableName)
+
+	rows, err := s.db.QueryContext(ctx, query, filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query chunk hashes: %w", err)
+	}
+	defer rows.Close()
+
+	hashes := make(map[string]string)
+	for rows.Next() {
+		var id string
+		// Oracle stores '' as NULL, so the hash is scanned as nullable
+		var hash sql.NullString
+		if err := rows.Scan(&id, &hash); err != nil {
+			return nil, fmt.Errorf("failed to scan chunk hash: %w", err)
+		}
+		hashes[id] = hash.String
+	}
+	return hashes, rows.Err()
+}
+
 // SearchSimilar finds the most similar code chunks to the given embedding
 func (s *OracleEmbeddingStore) SearchSimilar(ctx context.Context, embedding []float32, limit int, filters map[string]interface{}) ([]CodeChunk, error) {
 	// Build filter conditions
@@ -368,6 +468,17 @@ func (s *OracleEmbeddingStore) DeleteChunksForFile(ctx context.Context, filePath
 	return err
 }
 
+// DeleteChunks removes the chunks with the given IDs
+func (s *OracleEmbeddingStore) DeleteChunks(ctx context.Context, ids []string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = :1", s.tableName)
+	for _, id := range ids {
+		if _, err := s.db.ExecContext(ctx, query, id); err != nil {
+			return fmt.Errorf("failed to delete chunk %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
 // GetStats returns statistics about the embeddings store
 func (s *OracleEmbeddingStore) GetStats(ctx context.Context) (map[string]interface{}, error) {
 	stats := make(map[string]interface{})
@@ -467,6 +578,7 @@ func (s *OracleEmbeddingStore) BatchUpsertChunks(ctx context.Context, chunks []C
 			TYPE t_langs IS TABLE OF VARCHAR2(20);
 			TYPE t_metas IS TABLE OF CLOB;
 			TYPE t_vecs IS TABLE OF VARCHAR2(32767);
+			TYPE t_hashes IS TABLE OF VARCHAR2(64);
 			
 			l_ids t_ids := :1;
 			l_types t_types := :2;
@@ -478,6 +590,7 @@ func (s *OracleEmbeddingStore) BatchUpsertChunks(ctx context.Context, chunks []C
 			l_langs t_langs := :8;
 			l_metas t_metas := :9;
 			l_vecs t_vecs := :10;
+			l_hashes t_hashes := :11;
 		BEGIN
 			FOR i IN 1..l_ids.COUNT LOOP
 				MERGE INTO %s t
@@ -494,10 +607,11 @@ func (s *OracleEmbeddingStore) BatchUpsertChunks(ctx context.Context, chunks []C
 						language = l_langs(i),
 						metadata = l_metas(i),
 						embedding = TO_VECTOR(l_vecs(i)),
+						content_hash = l_hashes(i),
 						updated_at = SYSTIMESTAMP
 				WHEN NOT MATCHED THEN
-					INSERT (id, chunk_type, name, file_path, content, start_line, end_line, language, metadata, embedding)
-					VALUES (l_ids(i), l_types(i), l_names(i), l_paths(i), l_contents(i), l_starts(i), l_ends(i), l_langs(i), l_metas(i), TO_VECTOR(l_vecs(i)));
+					INSERT (id, chunk_type, name, file_path, content, start_line, end_line, language, metadata, embedding, content_hash)
+					VALUES (l_ids(i), l_types(i), l_names(i), l_paths(i), l_contents(i), l_starts(i), l_ends(i), l_langs(i), l_metas(i), TO_VECTOR(l_vecs(i)), l_hashes(i));
 			END LOOP;
 		END;
 	`, s.tableName)
@@ -513,6 +627,7 @@ func (s *OracleEmbeddingStore) BatchUpsertChunks(ctx context.Context, chunks []C
 	langs := make([]string, len(chunks))
 	metas := make([]string, len(chunks))
 	vecs := make([]string, len(chunks))
+	hashes := make([]string, len(chunks))
 
 	for i, chunk := range chunks {
 		ids[i] = chunk.ID
@@ -528,6 +643,7 @@ func (s *OracleEmbeddingStore) BatchUpsertChunks(ctx context.Context, chunks []C
 		metas[i] = string(metaJSON)
 
 		vecs[i] = floatSliceToOracleVector(chunk.Embedding)
+		hashes[i] = chunk.ContentHash
 	}
 
 	_, err := s.db.ExecContext(ctx, plsql,
@@ -541,6 +657,7 @@ func (s *OracleEmbeddingStore) BatchUpsertChunks(ctx context.Context, chunks []C
 		langs,
 		metas,
 		vecs,
+		hashes,
 	)
 
 	return err
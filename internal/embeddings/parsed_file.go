@@ -0,0 +1,115 @@
+// internal/embeddings/parsed_file.go
+
+package embeddings
+
+import (
+	"strings"
+
+	"goParse/internal/model"
+)
+
+// NewParsedFileData converts a parsed file into the entities chunks are made
+// from. fileContent is the source of the file, from which function and class
+// bodies are cut. The codeparser and the monitor both embed files through it,
+// so a file gets the same chunks whichever of them processes it last.
+func NewParsedFileData(pf model.ParsedFile, fileContent []byte) ParsedFileData {
+	data := ParsedFileData{
+		FilePath:    pf.FilePath,
+		Language:    pf.Language,
+		FileContent: string(fileContent),
+	}
+
+	for _, fn := range pf.Funcs {
+		data.Functions = append(data.Functions, FunctionData{
+			ID:        fn.ID,
+			Name:      fn.Name,
+			Content:   extractContent(fileContent, fn.StartLine, fn.EndLine),
+			StartLine: fn.StartLine,
+			EndLine:   fn.EndLine,
+			Signature: fn.Signature,
+			IsAsync:   fn.IsAsync,
+			IsExport:  fn.IsExport,
+			Doc:       fn.Doc.Text(),
+		})
+	}
+
+	for _, class := range pf.Classes {
+		data.Classes = append(data.Classes, ClassData{
+			ID:         class.ID,
+			Name:       class.Name,
+			Content:    extractContent(fileContent, class.StartLine, class.EndLine),
+			StartLine:  class.StartLine,
+			EndLine:    class.EndLine,
+			IsExport:   class.IsExport,
+			IsAbstract: class.IsAbstract,
+			Methods:    class.Methods,
+			Doc:        class.Doc.Text(),
+		})
+	}
+
+	for _, iface := range pf.Interfaces {
+		data.Interfaces = append(data.Interfaces, InterfaceData{
+			Name:       iface.Name,
+			Content:    "",
+			IsExport:   iface.IsExport,
+			Properties: iface.Properties,
+			Doc:        iface.Doc.Text(),
+		})
+	}
+
+	for _, typ := range pf.Types {
+		data.Types = append(data.Types, TypeData{
+			Name:       typ.Name,
+			Definition: typ.Definition,
+			Kind:       typ.Kind,
+			IsExport:   typ.IsExport,
+			Doc:        typ.Doc.Text(),
+		})
+	}
+
+	for _, variable := range pf.Variables {
+		data.Variables = append(data.Variables, VariableData{
+			Name:      variable.Name,
+			Content:   extractContent(fileContent, variable.StartLine, variable.StartLine),
+			StartLine: variable.StartLine,
+			Type:      variable.Type,
+			Doc:       variable.Doc.Text(),
+		})
+	}
+
+	for _, jsx := range pf.JSXElements {
+		data.JSXElements = append(data.JSXElements, JSXData{
+			TagName:             jsx.TagName,
+			ContainingComponent: jsx.ContainingComponent,
+			Props:               jsx.Props,
+			Line:                jsx.Line,
+		})
+	}
+
+	for _, imp := range pf.Imports {
+		data.Imports = append(data.Imports, ImportData{
+			Module: imp.Module,
+		})
+	}
+
+	return data
+}
+
+// extractContent returns lines startLine to endLine of fileContent, numbered
+// from 1, or "" when the range is unknown.
+func extractContent(fileContent []byte, startLine, endLine int) string {
+	if startLine <= 0 || endLine <= 0 {
+		return ""
+	}
+
+	lines := strings.Split(string(fileContent), "\n")
+	if startLine > len(lines) {
+		return ""
+	}
+
+	if endLine > len(lines) {
+		endLine = len(lines)
+	}
+
+	return strings.Join(lines[startLine-1:endLine], "\n")
+}
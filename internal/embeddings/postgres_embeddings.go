@@ -24,6 +24,7 @@ const (
 	ChunkTypeClass     ChunkType = "class"
 	ChunkTypeInterface ChunkType = "interface"
 	ChunkTypeType      ChunkType = "type"
+	ChunkTypeVariable  ChunkType = "variable"
 	ChunkTypeJSX       ChunkType = "jsx_component"
 	ChunkTypeFile      ChunkType = "file"
 	ChunkTypeImports   ChunkType = "imports"
@@ -42,6 +43,7 @@ type CodeChunk struct {
 	Metadata     map[string]interface{} `json:"metadata"`
 	Embedding    []float32              `json:"-"`
 	EmbeddingDim int                    `json:"embedding_dim"`
+	ContentHash  string                 `json:"content_hash"` // Hash of the embedded text, model and dimension
 }
 
 // PostgresEmbeddingStore manages code embeddings in PostgreSQL
@@ -51,6 +53,9 @@ type PostgresEmbeddingStore struct {
 	embeddingDim int
 }
 
+// maxIndexedDim is the widest vector pgvector can build an IVFFlat index on
+const maxIndexedDim = 2000
+
 // init loads environment variables
 func init() {
 	_ = godotenv.Load()
@@ -58,6 +63,10 @@ func init() {
 
 // NewPostgresEmbeddingStore creates a new PostgreSQL embedding store
 func NewPostgresEmbeddingStore(embeddingDim int) (*PostgresEmbeddingStore, error) {
+	if embeddingDim <= 0 {
+		return nil, fmt.Errorf("invalid embedding dimension %d", embeddingDim)
+	}
+
 	host := os.Getenv("PG_HOST")
 	if host == "" {
 		host = "localhost"
@@ -127,7 +136,7 @@ func (s *PostgresEmbeddingStore) initialize() error {
 	// Create embeddings table
 	createTableSQL := fmt.Sprintf(`
 		CREATE TABLE IF NOT EXISTS %s (
-			id VARCHAR(255) PRIMARY KEY,
+			id VARCHAR(2000) PRIMARY KEY,
 			chunk_type VARCHAR(50) NOT NULL,
 			name VARCHAR(500) NOT NULL,
 			file_path VARCHAR(1000) NOT NULL,
@@ -137,6 +146,7 @@ func (s *PostgresEmbeddingStore) initialize() error {
 			language VARCHAR(20),
 			metadata JSONB,
 			embedding vector(%d),
+			content_hash VARCHAR(64),
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 		)
@@ -147,6 +157,36 @@ func (s *PostgresEmbeddingStore) initialize() error {
 		return fmt.Errorf("failed to create embeddings table: %w", err)
 	}
 
+	// Tables created before chunks were hashed lack the column
+	_, err = s.db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS content_hash VARCHAR(64)",
+		pq.QuoteIdentifier(s.tableName)))
+	if err != nil {
+		return fmt.Errorf("failed to add content_hash column: %w", err)
+	}
+
+	// Chunk IDs embed the file path and qualified name, which older tables
+	// were too narrow for
+	_, err = s.db.Exec(fmt.Sprintf("ALTER TABLE %s ALTER COLUMN id TYPE VARCHAR(2000)",
+		pq.QuoteIdentifier(s.tableName)))
+	if err != nil {
+		return fmt.Errorf("failed to widen id column: %w", err)
+	}
+
+	// An existing table keeps its vector size, so a provider with another
+	// dimension needs a table of its own
+	var tableDim int
+	err = s.db.QueryRow(
+		"SELECT atttypmod FROM pg_attribute WHERE attrelid = to_regclass($1) AND attname = 'embedding'",
+		pq.QuoteIdentifier(s.tableName),
+	).Scan(&tableDim)
+	if err != nil {
+		return fmt.Errorf("failed to check embedding dimension: %w", err)
+	}
+	if tableDim != s.embeddingDim {
+		return fmt.Errorf("table %s stores %d-dimensional embeddings but the provider produces %d; set PG_EMBEDDINGS_TABLE to use another table",
+			s.tableName, tableDim, s.embeddingDim)
+	}
+
 	// Create indexes
 	indexes := []string{
 		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_chunk_type ON %s(chunk_type)",
@@ -157,9 +197,15 @@ func (s *PostgresEmbeddingStore) initialize() error {
 			s.tableName, pq.QuoteIdentifier(s.tableName)),
 		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_language ON %s(language)",
 			s.tableName, pq.QuoteIdentifier(s.tableName)),
-		// Create IVFFlat index for similarity search
-		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_embedding ON %s USING ivfflat (embedding vector_cosine_ops) WITH (lists = 100)",
-			s.tableName, pq.QuoteIdentifier(s.tableName)),
+	}
+
+	// Create IVFFlat index for similarity search; pgvector cannot index
+	// vectors wider than maxIndexedDim, which are searched sequentially
+	if s.embeddingDim <= maxIndexedDim {
+		indexes = append(indexes, fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_embedding ON %s USING ivfflat (embedding vector_cosine_ops) WITH (lists = 100)",
+			s.tableName, pq.QuoteIdentifier(s.tableName)))
+	} else {
+		fmt.Printf("Warning: %d-dimensional embeddings are too wide for an IVFFlat index; similarity search will scan the table\n", s.embeddingDim)
 	}
 
 	for _, idx := range indexes {
@@ -186,8 +232,8 @@ func (s *PostgresEmbeddingStore) UpsertChunk(ctx context.Context, chunk CodeChun
 
 	query := fmt.Sprintf(`
 		INSERT INTO %s 
-		(id, chunk_type, name, file_path, content, start_line, end_line, language, metadata, embedding)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		(id, chunk_type, name, file_path, content, start_line, end_line, language, metadata, embedding, content_hash)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 		ON CONFLICT (id) 
 		DO UPDATE SET
 			chunk_type = EXCLUDED.chunk_type,
@@ -199,6 +245,7 @@ func (s *PostgresEmbeddingStore) UpsertChunk(ctx context.Context, chunk CodeChun
 			language = EXCLUDED.language,
 			metadata = EXCLUDED.metadata,
 			embedding = EXCLUDED.embedding,
+			content_hash = EXCLUDED.content_hash,
 			updated_at = CURRENT_TIMESTAMP
 	`, pq.QuoteIdentifier(s.tableName))
 
@@ -213,11 +260,73 @@ func (s *PostgresEmbeddingStore) UpsertChunk(ctx context.Context, chunk CodeChun
 		chunk.Language,
 		metadataJSON,
 		pgvector.NewVector(chunk.Embedding),
+		chunk.ContentHash,
+	)
+
+	return err
+}
+
+// UpdateChunkDetails rewrites everything about a stored chunk except its
+// embedding and content hash, for chunks whose embedded text is unchanged
+func (s *PostgresEmbeddingStore) UpdateChunkDetails(ctx context.Context, chunk CodeChunk) error {
+	metadataJSON, err := json.Marshal(chunk.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %s SET
+			chunk_type = $2,
+			name = $3,
+			file_path = $4,
+			content = $5,
+			start_line = $6,
+			end_line = $7,
+			language = $8,
+			metadata = $9,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`, pq.QuoteIdentifier(s.tableName))
+
+	_, err = s.db.ExecContext(ctx, query,
+		chunk.ID,
+		string(chunk.Type),
+		chunk.Name,
+		chunk.FilePath,
+		chunk.Content,
+		chunk.StartLine,
+		chunk.EndLine,
+		chunk.Language,
+		metadataJSON,
 	)
 
 	return err
 }
 
+// ChunkHashes returns the content hash of every chunk stored for a file,
+// keyed by chunk ID. Chunks stored without a hash, such as those written
+// before hashes were recorded, map to "" so they are re-embedded or deleted.
+func (s *PostgresEmbeddingStore) ChunkHashes(ctx context.Context, filePath string) (map[string]string, error) {
+	query := fmt.Sprintf("SELECT id, COALESCE(content_hash, '') FROM %s WHERE file_path = $1",
+		pq.QuoteIdentifier(s.tableName))
+
+	rows, err := s.db.QueryContext(ctx, query, filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query chunk hashes: %w", err)
+	}
+	defer rows.Close()
+
+	hashes := make(map[string]string)
+	for rows.Next() {
+		var id, hash string
+		if err := rows.Scan(&id, &hash); err != nil {
+			return nil, fmt.Errorf("failed to scan chunk hash: %w", err)
+		}
+		hashes[id] = hash
+	}
+	return hashes, rows.Err()
+}
+
 // SearchSimilar finds the most similar code chunks to the given embedding
 func (s *PostgresEmbeddingStore) SearchSimilar(ctx context.Context, embedding []float32, limit int, filters map[string]interface{}) ([]CodeChunk, error) {
 	// Build filter conditions
@@ -348,6 +457,16 @@ func (s *PostgresEmbeddingStore) DeleteChunksForFile(ctx context.Context, filePa
 	return err
 }
 
+// DeleteChunks removes the chunks with the given IDs
+func (s *PostgresEmbeddingStore) DeleteChunks(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = ANY($1)", pq.QuoteIdentifier(s.tableName))
+	_, err := s.db.ExecContext(ctx, query, pq.Array(ids))
+	return err
+}
+
 // GetStats returns statistics about the embeddings store
 func (s *PostgresEmbeddingStore) GetStats(ctx context.Context) (map[string]interface{}, error) {
 	stats := make(map[string]interface{})
@@ -420,7 +539,7 @@ func CreateCodeChunks(parsedFile ParsedFileData) []CodeChunk {
 	// Create chunks for functions
 	for _, fn := range parsedFile.Functions {
 		chunk := CodeChunk{
-			ID:        fmt.Sprintf("func_%s_%s", parsedFile.FilePath, fn.Name),
+			ID:        chunkID(ChunkTypeFunction, entityID(parsedFile.FilePath, fn.ID, fn.Name)),
 			Type:      ChunkTypeFunction,
 			Name:      fn.Name,
 			FilePath:  parsedFile.FilePath,
@@ -432,6 +551,7 @@ func CreateCodeChunks(parsedFile ParsedFileData) []CodeChunk {
 				"signature": fn.Signature,
 				"is_async":  fn.IsAsync,
 				"is_export": fn.IsExport,
+				"doc":       fn.Doc,
 			},
 		}
 		chunks = append(chunks, chunk)
@@ -440,7 +560,7 @@ func CreateCodeChunks(parsedFile ParsedFileData) []CodeChunk {
 	// Create chunks for classes
 	for _, class := range parsedFile.Classes {
 		chunk := CodeChunk{
-			ID:        fmt.Sprintf("class_%s_%s", parsedFile.FilePath, class.Name),
+			ID:        chunkID(ChunkTypeClass, entityID(parsedFile.FilePath, class.ID, class.Name)),
 			Type:      ChunkTypeClass,
 			Name:      class.Name,
 			FilePath:  parsedFile.FilePath,
@@ -452,6 +572,7 @@ func CreateCodeChunks(parsedFile ParsedFileData) []CodeChunk {
 				"is_export":   class.IsExport,
 				"is_abstract": class.IsAbstract,
 				"methods":     class.Methods,
+				"doc":         class.Doc,
 			},
 		}
 		chunks = append(chunks, chunk)
@@ -460,7 +581,7 @@ func CreateCodeChunks(parsedFile ParsedFileData) []CodeChunk {
 	// Create chunks for interfaces
 	for _, iface := range parsedFile.Interfaces {
 		chunk := CodeChunk{
-			ID:       fmt.Sprintf("interface_%s_%s", parsedFile.FilePath, iface.Name),
+			ID:       chunkID(ChunkTypeInterface, entityID(parsedFile.FilePath, "", iface.Name)),
 			Type:     ChunkTypeInterface,
 			Name:     iface.Name,
 			FilePath: parsedFile.FilePath,
@@ -469,6 +590,7 @@ func CreateCodeChunks(parsedFile ParsedFileData) []CodeChunk {
 			Metadata: map[string]interface{}{
 				"is_export":  iface.IsExport,
 				"properties": iface.Properties,
+				"doc":        iface.Doc,
 			},
 		}
 		chunks = append(chunks, chunk)
@@ -477,7 +599,7 @@ func CreateCodeChunks(parsedFile ParsedFileData) []CodeChunk {
 	// Create chunks for types
 	for _, typ := range parsedFile.Types {
 		chunk := CodeChunk{
-			ID:       fmt.Sprintf("type_%s_%s", parsedFile.FilePath, typ.Name),
+			ID:       chunkID(ChunkTypeType, entityID(parsedFile.FilePath, "", typ.Name)),
 			Type:     ChunkTypeType,
 			Name:     typ.Name,
 			FilePath: parsedFile.FilePath,
@@ -486,6 +608,30 @@ func CreateCodeChunks(parsedFile ParsedFileData) []CodeChunk {
 			Metadata: map[string]interface{}{
 				"kind":      typ.Kind,
 				"is_export": typ.IsExport,
+				"doc":       typ.Doc,
+			},
+		}
+		chunks = append(chunks, chunk)
+	}
+
+	// Create chunks for documented variables; undocumented ones say little
+	// beyond their name and would only crowd the search results
+	for _, variable := range parsedFile.Variables {
+		if variable.Doc == "" {
+			continue
+		}
+		chunk := CodeChunk{
+			ID:        chunkID(ChunkTypeVariable, entityID(parsedFile.FilePath, "", variable.Name)),
+			Type:      ChunkTypeVariable,
+			Name:      variable.Name,
+			FilePath:  parsedFile.FilePath,
+			Content:   variable.Content,
+			StartLine: variable.StartLine,
+			EndLine:   variable.StartLine,
+			Language:  parsedFile.Language,
+			Metadata: map[string]interface{}{
+				"type": variable.Type,
+				"doc":  variable.Doc,
 			},
 		}
 		chunks = append(chunks, chunk)
@@ -508,7 +654,7 @@ func CreateCodeChunks(parsedFile ParsedFileData) []CodeChunk {
 
 	for component, elements := range jsxByComponent {
 		chunk := CodeChunk{
-			ID:       fmt.Sprintf("jsx_%s_%s", parsedFile.FilePath, component),
+			ID:       chunkID(ChunkTypeJSX, entityID(parsedFile.FilePath, "", component)),
 			Type:     ChunkTypeJSX,
 			Name:     component + "_jsx",
 			FilePath: parsedFile.FilePath,
@@ -531,7 +677,7 @@ func CreateCodeChunks(parsedFile ParsedFileData) []CodeChunk {
 		}
 
 		chunk := CodeChunk{
-			ID:       fmt.Sprintf("imports_%s", parsedFile.FilePath),
+			ID:       chunkID(ChunkTypeImports, parsedFile.FilePath),
 			Type:     ChunkTypeImports,
 			Name:     "imports",
 			FilePath: parsedFile.FilePath,
@@ -547,7 +693,7 @@ func CreateCodeChunks(parsedFile ParsedFileData) []CodeChunk {
 	// If no specific chunks were created, create a file-level chunk
 	if len(chunks) == 0 && parsedFile.FileContent != "" {
 		chunk := CodeChunk{
-			ID:       fmt.Sprintf("file_%s", parsedFile.FilePath),
+			ID:       chunkID(ChunkTypeFile, parsedFile.FilePath),
 			Type:     ChunkTypeFile,
 			Name:     parsedFile.FilePath,
 			FilePath: parsedFile.FilePath,
@@ -563,6 +709,21 @@ func CreateCodeChunks(parsedFile ParsedFileData) []CodeChunk {
 	return chunks
 }
 
+// chunkID identifies the chunk of chunkType covering an entity or file, e.g.
+// "function:src/widget.ts#Widget.render"
+func chunkID(chunkType ChunkType, key string) string {
+	return string(chunkType) + ":" + key
+}
+
+// entityID returns id, or for entities the parser gave no ID, one built from
+// the file and name the same way the graph does
+func entityID(filePath, id, name string) string {
+	if id != "" {
+		return id
+	}
+	return filePath + "#" + name
+}
+
 // ParsedFileData represents the data from parsing a file (simplified)
 type ParsedFileData struct {
 	FilePath    string
@@ -572,12 +733,14 @@ type ParsedFileData struct {
 	Classes     []ClassData
 	Interfaces  []InterfaceData
 	Types       []TypeData
+	Variables   []VariableData
 	JSXElements []JSXData
 	Imports     []ImportData
 }
 
 // Simplified data structures for parsed entities
 type FunctionData struct {
+	ID        string // Entity ID assigned by the parser, e.g. src/widget.ts#Widget.render
 	Name      string
 	Content   string
 	StartLine int
@@ -585,9 +748,11 @@ type FunctionData struct {
 	Signature string
 	IsAsync   bool
 	IsExport  bool
+	Doc       string // Leading doc comment, rendered as text
 }
 
 type ClassData struct {
+	ID         string // Entity ID assigned by the parser
 	Name       string
 	Content    string
 	StartLine  int
@@ -595,6 +760,7 @@ type ClassData struct {
 	IsExport   bool
 	IsAbstract bool
 	Methods    []string
+	Doc        string
 }
 
 type InterfaceData struct {
@@ -602,6 +768,7 @@ type InterfaceData struct {
 	Content    string
 	IsExport   bool
 	Properties []string
+	Doc        string
 }
 
 type TypeData struct {
@@ -609,6 +776,15 @@ type TypeData struct {
 	Definition string
 	Kind       string
 	IsExport   bool
+	Doc        string
+}
+
+type VariableData struct {
+	Name      string
+	Content   string // The line declaring the variable
+	StartLine int
+	Type      string
+	Doc       string
 }
 
 type JSXData struct {
@@ -0,0 +1,72 @@
+// internal/embeddings/provider.go
+
+package embeddings
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ProviderFactory creates an embedding provider. An empty model or a zero
+// dimension selects the provider's default.
+type ProviderFactory func(model string, dimension int) (EmbeddingProvider, error)
+
+// providerInfo is a registered embedding provider
+type providerInfo struct {
+	description string
+	factory     ProviderFactory
+}
+
+var (
+	providersMu sync.RWMutex
+	providers   = make(map[string]providerInfo)
+)
+
+// RegisterProvider makes an embedding provider available under name. It panics
+// if the name is already taken, so conflicting registrations fail at startup.
+func RegisterProvider(name, description string, factory ProviderFactory) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+
+	if _, exists := providers[name]; exists {
+		panic(fmt.Sprintf("embedding provider %q registered twice", name))
+	}
+	providers[name] = providerInfo{description: description, factory: factory}
+}
+
+// NewProvider creates the embedding provider registered under name
+func NewProvider(name, model string, dimension int) (EmbeddingProvider, error) {
+	providersMu.RLock()
+	info, ok := providers[name]
+	providersMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown embedding provider %q (available: %s)", name, strings.Join(Providers(), ", "))
+	}
+	if dimension < 0 {
+		return nil, fmt.Errorf("invalid embedding dimension %d", dimension)
+	}
+	return info.factory(model, dimension)
+}
+
+// Providers returns the names of all registered embedding providers, sorted
+func Providers() []string {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ProviderDescription returns the human-readable description of a provider
+func ProviderDescription(name string) string {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	return providers[name].description
+}
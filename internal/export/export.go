@@ -0,0 +1,130 @@
+// internal/export/export.go
+
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"goParse/internal/model"
+)
+
+// SchemaVersion is the version of the output schema described in
+// Documentation/OutputSchema.md. It changes whenever a field is renamed,
+// removed or changes meaning; new fields do not change it.
+const SchemaVersion = 1
+
+// Format selects how parsed files are written.
+type Format string
+
+const (
+	FormatNDJSON Format = "ndjson" // One JSON record per line
+	FormatJSON   Format = "json"   // A single JSON document
+)
+
+// ParseFormat returns the Format named s.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(s); f {
+	case FormatNDJSON, FormatJSON:
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown output format %q (available: %s, %s)", s, FormatNDJSON, FormatJSON)
+}
+
+// fileRecord is one NDJSON line.
+type fileRecord struct {
+	SchemaVersion int              `json:"schemaVersion"`
+	File          model.ParsedFile `json:"file"`
+}
+
+// documentHeader opens a JSON document; its files array is streamed after it.
+type documentHeader struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Root          string `json:"root"`
+}
+
+// Writer streams parsed files to an io.Writer without holding them in memory.
+type Writer struct {
+	w      *bufio.Writer
+	format Format
+	count  int
+}
+
+// NewWriter returns a Writer for format. For FormatJSON the document header
+// is written immediately and root is recorded in it.
+func NewWriter(w io.Writer, format Format, root string) (*Writer, error) {
+	ew := &Writer{w: bufio.NewWriter(w), format: format}
+	if format != FormatJSON {
+		return ew, nil
+	}
+
+	header, err := encode(documentHeader{SchemaVersion: SchemaVersion, Root: root})
+	if err != nil {
+		return nil, err
+	}
+	// Reopen the header object and append the files array to it
+	header = bytes.TrimSuffix(header, []byte("}"))
+	if _, err := fmt.Fprintf(ew.w, "%s,\"files\":[", header); err != nil {
+		return nil, fmt.Errorf("failed to write document header: %w", err)
+	}
+	return ew, nil
+}
+
+// Write emits pf as the next record.
+func (ew *Writer) Write(pf model.ParsedFile) error {
+	var (
+		data []byte
+		err  error
+	)
+	if ew.format == FormatJSON {
+		data, err = encode(pf)
+		if err == nil && ew.count > 0 {
+			data = append([]byte{','}, data...)
+		}
+	} else {
+		data, err = encode(fileRecord{SchemaVersion: SchemaVersion, File: pf})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", pf.FilePath, err)
+	}
+
+	if _, err := ew.w.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write %s: %w", pf.FilePath, err)
+	}
+	ew.count++
+	return nil
+}
+
+// Close finishes the document and flushes buffered output. It does not close
+// the underlying io.Writer.
+func (ew *Writer) Close() error {
+	if ew.format == FormatJSON {
+		if _, err := ew.w.WriteString("]}\n"); err != nil {
+			return fmt.Errorf("failed to finish document: %w", err)
+		}
+	}
+	if err := ew.w.Flush(); err != nil {
+		return fmt.Errorf("failed to flush output: %w", err)
+	}
+	return nil
+}
+
+// Count returns the number of files written so far.
+func (ew *Writer) Count() int {
+	return ew.count
+}
+
+// encode marshals v without HTML escaping, so JSX tags and selectors stay
+// readable, and without a trailing newline.
+func encode(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
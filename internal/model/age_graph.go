@@ -7,6 +7,7 @@ import (
 	"database/sql"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/joho/godotenv"
@@ -18,11 +19,20 @@ import (
 type AGEClient struct {
 	db        *sql.DB
 	graphName string
+	tx        *sql.Tx // Set on copies bound to a ReplaceFileEntities transaction
 }
 
-// init loads environment variables from .env (if present).
+// init loads environment variables from .env (if present) and registers the backend.
 func init() {
 	_ = godotenv.Load()
+
+	RegisterBackend("age", "Apache AGE", func() (GraphClient, error) {
+		client, err := NewAGEClient()
+		if err != nil {
+			return nil, err
+		}
+		return client, nil
+	})
 }
 
 // NewAGEClient reads PG_HOST, PG_PORT, PG_USER, PG_PASS, PG_DB from env and connects.
@@ -127,51 +137,34 @@ func (c *AGEClient) Close(ctx context.Context) error {
 	return c.db.Close()
 }
 
-// executeCypher executes a Cypher query within AGE
+// executeCypher executes a Cypher query within AGE. Params are passed as
+// AGE's agtype parameter map, the third cypher() argument, and referenced in
+// the query as $name, so their values never become part of the query text.
 func (c *AGEClient) executeCypher(ctx context.Context, cypher string, params map[string]any) error {
-	// Convert params to AGE format
-	paramStr := ""
+	var args []any
+	paramArg := ""
 	if len(params) > 0 {
-		var paramPairs []string
-		for k, v := range params {
-			paramPairs = append(paramPairs, fmt.Sprintf("%s: %s", k, c.formatValue(v)))
+		encoded, err := encodeAgtype(params)
+		if err != nil {
+			return fmt.Errorf("failed to encode cypher parameters: %w", err)
 		}
-		paramStr = fmt.Sprintf("WITH {%s} AS params ", strings.Join(paramPairs, ", "))
+		args = append(args, encoded)
+		paramArg = ", $1"
 	}
 
 	// Build the AGE query
 	query := fmt.Sprintf(`
-		SELECT * FROM cypher('%s', $$
-			%s%s
-		$$) as (result agtype);
-	`, c.graphName, paramStr, cypher)
-
-	_, err := c.db.ExecContext(ctx, query)
-	return err
-}
+		SELECT * FROM cypher(%s, $$
+			%s
+		$$%s) as (result agtype);
+	`, pq.QuoteLiteral(c.graphName), cypher, paramArg)
 
-// formatValue formats a value for AGE Cypher queries
-func (c *AGEClient) formatValue(v any) string {
-	switch val := v.(type) {
-	case string:
-		// Escape single quotes and wrap in quotes
-		escaped := strings.ReplaceAll(val, "'", "''")
-		return fmt.Sprintf("'%s'", escaped)
-	case int, int32, int64:
-		return fmt.Sprintf("%d", val)
-	case bool:
-		return fmt.Sprintf("%t", val)
-	case []string:
-		var items []string
-		for _, item := range val {
-			items = append(items, c.formatValue(item))
-		}
-		return fmt.Sprintf("[%s]", strings.Join(items, ", "))
-	case nil:
-		return "null"
-	default:
-		return fmt.Sprintf("'%v'", val)
+	if c.tx != nil {
+		_, err := c.tx.ExecContext(ctx, query, args...)
+		return err
 	}
+	_, err := c.db.ExecContext(ctx, query, args...)
+	return err
 }
 
 // File Operations
@@ -179,9 +172,9 @@ func (c *AGEClient) formatValue(v any) string {
 // UpsertFile ensures a :File node exists with the given path and language
 func (c *AGEClient) UpsertFile(ctx context.Context, path, language string) error {
 	cypher := `
-		MERGE (f:File {path: params.path})
-		ON CREATE SET f.language = params.language, f.created = localdatetime()
-		ON MATCH SET f.language = params.language, f.updated = localdatetime()
+		MERGE (f:File {path: $path})
+		ON CREATE SET f.language = $language, f.created = localdatetime()
+		ON MATCH SET f.language = $language, f.updated = localdatetime()
 	`
 	params := map[string]any{
 		"path":     path,
@@ -195,54 +188,86 @@ func (c *AGEClient) UpsertFile(ctx context.Context, path, language string) error
 // UpsertFunction ensures a :Function node exists and creates BELONGS_TO→File
 func (c *AGEClient) UpsertFunction(ctx context.Context, fn FunctionEntity) error {
 	cypher := `
-		MERGE (func:Function {name: params.name, file: params.file})
+		MERGE (func:Function {id: $id})
 		ON CREATE SET 
-			func.startLine = params.startLine, 
-			func.endLine = params.endLine,
-			func.signature = params.signature,
-			func.isAsync = params.isAsync,
-			func.isExport = params.isExport,
+			func.name = $name,
+			func.qualifiedName = $qualifiedName,
+			func.file = $file,
+			func.startLine = $startLine, 
+			func.endLine = $endLine,
+			func.signature = $signature,
+			func.parameterNames = $parameterNames,
+			func.parameterTypes = $parameterTypes,
+			func.returnType = $returnType,
+			func.typeParameters = $typeParameters,
+			func.isAsync = $isAsync,
+			func.isGenerator = $isGenerator,
+			func.isExport = $isExport,
 			func.created = localdatetime()
 		ON MATCH SET 
-			func.startLine = params.startLine, 
-			func.endLine = params.endLine,
-			func.signature = params.signature,
-			func.isAsync = params.isAsync,
-			func.isExport = params.isExport,
+			func.name = $name,
+			func.qualifiedName = $qualifiedName,
+			func.file = $file,
+			func.startLine = $startLine, 
+			func.endLine = $endLine,
+			func.signature = $signature,
+			func.parameterNames = $parameterNames,
+			func.parameterTypes = $parameterTypes,
+			func.returnType = $returnType,
+			func.typeParameters = $typeParameters,
+			func.isAsync = $isAsync,
+			func.isGenerator = $isGenerator,
+			func.isExport = $isExport,
 			func.updated = localdatetime()
+		SET ` + docAssignments("func", "$") + `
 		WITH func
-		MATCH (f:File {path: params.file})
+		MATCH (f:File {path: $file})
 		MERGE (func)-[:BELONGS_TO]->(f)
 	`
-	params := map[string]any{
-		"name":      fn.Name,
-		"file":      fn.FilePath,
-		"startLine": fn.StartLine,
-		"endLine":   fn.EndLine,
-		"signature": fn.Signature,
-		"isAsync":   fn.IsAsync,
-		"isExport":  fn.IsExport,
-	}
+	params := withDoc(map[string]any{
+		"id":             fn.nodeID(),
+		"name":           fn.Name,
+		"qualifiedName":  fn.QualifiedName,
+		"file":           fn.FilePath,
+		"startLine":      fn.StartLine,
+		"endLine":        fn.EndLine,
+		"signature":      fn.Signature,
+		"parameterNames": fn.parameterNames(),
+		"parameterTypes": fn.parameterTypes(),
+		"returnType":     fn.ReturnType,
+		"typeParameters": fn.typeParameterNames(),
+		"isAsync":        fn.IsAsync,
+		"isGenerator":    fn.IsGenerator,
+		"isExport":       fn.IsExport,
+	}, fn.Doc)
 	return c.executeCypher(ctx, cypher, params)
 }
 
+// UpsertFunctionTypes creates ACCEPTS and RETURNS from a function to the
+// types named in its signature
+func (c *AGEClient) UpsertFunctionTypes(ctx context.Context, fn FunctionEntity) error {
+	return c.runStatements(ctx, functionTypeStatements([]FunctionEntity{fn}))
+}
+
 // Import Operations
 
-// UpsertImport ensures a :Import node exists and creates IMPORTS→File
+// UpsertImport ensures a :Import node exists and creates IMPORTS from the File
+// to the Import and to the resolved File or external Package
 func (c *AGEClient) UpsertImport(ctx context.Context, imp ImportEntity) error {
 	cypher := `
-		MERGE (i:Import {module: params.module})
+		MERGE (i:Import {module: $module})
 		ON CREATE SET 
 			i.created = localdatetime()
 		ON MATCH SET 
 			i.updated = localdatetime()
 		WITH i
-		MATCH (f:File {path: params.file})
+		MATCH (f:File {path: $file})
 		MERGE (f)-[r:IMPORTS]->(i)
 		ON CREATE SET 
-			r.importedNames = params.importedNames,
-			r.isDefault = params.isDefault,
-			r.isNamespace = params.isNamespace
+			r.importedNames = $importedNames,
+			r.isDefault = $isDefault,
+			r.isNamespace = $isNamespace,
+			r.isDynamic = $isDynamic
 	`
 	params := map[string]any{
 		"module":        imp.Module,
@@ -250,7 +275,34 @@ func (c *AGEClient) UpsertImport(ctx context.Context, imp ImportEntity) error {
 		"importedNames": imp.ImportedNames,
 		"isDefault":     imp.IsDefault,
 		"isNamespace":   imp.IsNamespace,
+		"isDynamic":     imp.IsDynamic,
+		"resolvedFile":  imp.ResolvedFile,
+		"package":       imp.Package,
+	}
+	if err := c.executeCypher(ctx, cypher, params); err != nil {
+		return err
+	}
+
+	// Link the importing file to the resolved File or external Package
+	var target string
+	switch {
+	case imp.ResolvedFile != "":
+		target = `MERGE (t:File {path: $resolvedFile})`
+	case imp.Package != "":
+		target = `MERGE (t:Package {name: $package})`
+	default:
+		return nil
 	}
+	cypher = target + `
+		WITH t
+		MATCH (f:File {path: $file})
+		MERGE (f)-[r:IMPORTS]->(t)
+		SET r.module = $module,
+			r.importedNames = $importedNames,
+			r.isDefault = $isDefault,
+			r.isNamespace = $isNamespace,
+			r.isDynamic = $isDynamic
+	`
 	return c.executeCypher(ctx, cypher, params)
 }
 
@@ -259,31 +311,32 @@ func (c *AGEClient) UpsertImport(ctx context.Context, imp ImportEntity) error {
 // UpsertVariable ensures a :Variable node exists and creates DEFINED_IN→File
 func (c *AGEClient) UpsertVariable(ctx context.Context, variable VariableEntity) error {
 	cypher := `
-		MERGE (v:Variable {name: params.name, file: params.file})
+		MERGE (v:Variable {name: $name, file: $file})
 		ON CREATE SET 
-			v.type = params.type,
-			v.isConst = params.isConst,
-			v.isLet = params.isLet,
-			v.startLine = params.startLine,
+			v.type = $type,
+			v.isConst = $isConst,
+			v.isLet = $isLet,
+			v.startLine = $startLine,
 			v.created = localdatetime()
 		ON MATCH SET 
-			v.type = params.type,
-			v.isConst = params.isConst,
-			v.isLet = params.isLet,
-			v.startLine = params.startLine,
+			v.type = $type,
+			v.isConst = $isConst,
+			v.isLet = $isLet,
+			v.startLine = $startLine,
 			v.updated = localdatetime()
+		SET ` + docAssignments("v", "$") + `
 		WITH v
-		MATCH (f:File {path: params.file})
+		MATCH (f:File {path: $file})
 		MERGE (v)-[:DEFINED_IN]->(f)
 	`
-	params := map[string]any{
+	params := withDoc(map[string]any{
 		"name":      variable.Name,
 		"file":      variable.FilePath,
 		"type":      variable.Type,
 		"isConst":   variable.IsConst,
 		"isLet":     variable.IsLet,
 		"startLine": variable.StartLine,
-	}
+	}, variable.Doc)
 	return c.executeCypher(ctx, cypher, params)
 }
 
@@ -292,28 +345,29 @@ func (c *AGEClient) UpsertVariable(ctx context.Context, variable VariableEntity)
 // UpsertType ensures a :Type node exists and creates BELONGS_TO→File
 func (c *AGEClient) UpsertType(ctx context.Context, typeEntity TypeEntity) error {
 	cypher := `
-		MERGE (t:Type {name: params.name, file: params.file})
+		MERGE (t:Type {name: $name, file: $file})
 		ON CREATE SET 
-			t.kind = params.kind,
-			t.definition = params.definition,
-			t.isExport = params.isExport,
+			t.kind = $kind,
+			t.definition = $definition,
+			t.isExport = $isExport,
 			t.created = localdatetime()
 		ON MATCH SET 
-			t.kind = params.kind,
-			t.definition = params.definition,
-			t.isExport = params.isExport,
+			t.kind = $kind,
+			t.definition = $definition,
+			t.isExport = $isExport,
 			t.updated = localdatetime()
+		SET ` + docAssignments("t", "$") + `
 		WITH t
-		MATCH (f:File {path: params.file})
+		MATCH (f:File {path: $file})
 		MERGE (t)-[:BELONGS_TO]->(f)
 	`
-	params := map[string]any{
+	params := withDoc(map[string]any{
 		"name":       typeEntity.Name,
 		"file":       typeEntity.FilePath,
 		"kind":       typeEntity.Kind,
 		"definition": typeEntity.Definition,
 		"isExport":   typeEntity.IsExport,
-	}
+	}, typeEntity.Doc)
 	return c.executeCypher(ctx, cypher, params)
 }
 
@@ -322,25 +376,26 @@ func (c *AGEClient) UpsertType(ctx context.Context, typeEntity TypeEntity) error
 // UpsertInterface ensures an :Interface node exists and creates BELONGS_TO→File
 func (c *AGEClient) UpsertInterface(ctx context.Context, iface InterfaceEntity) error {
 	cypher := `
-		MERGE (i:Interface {name: params.name, file: params.file})
+		MERGE (i:Interface {name: $name, file: $file})
 		ON CREATE SET 
-			i.isExport = params.isExport,
-			i.properties = params.properties,
+			i.isExport = $isExport,
+			i.properties = $properties,
 			i.created = localdatetime()
 		ON MATCH SET 
-			i.isExport = params.isExport,
-			i.properties = params.properties,
+			i.isExport = $isExport,
+			i.properties = $properties,
 			i.updated = localdatetime()
+		SET ` + docAssignments("i", "$") + `
 		WITH i
-		MATCH (f:File {path: params.file})
+		MATCH (f:File {path: $file})
 		MERGE (i)-[:BELONGS_TO]->(f)
 	`
-	params := map[string]any{
+	params := withDoc(map[string]any{
 		"name":       iface.Name,
 		"file":       iface.FilePath,
 		"isExport":   iface.IsExport,
 		"properties": iface.Properties,
-	}
+	}, iface.Doc)
 	return c.executeCypher(ctx, cypher, params)
 }
 
@@ -349,58 +404,114 @@ func (c *AGEClient) UpsertInterface(ctx context.Context, iface InterfaceEntity)
 // UpsertClass ensures a :Class node exists and creates BELONGS_TO→File
 func (c *AGEClient) UpsertClass(ctx context.Context, class ClassEntity) error {
 	cypher := `
-		MERGE (c:Class {name: params.name, file: params.file})
+		MERGE (c:Class {id: $id})
 		ON CREATE SET 
-			c.startLine = params.startLine,
-			c.endLine = params.endLine,
-			c.isExport = params.isExport,
-			c.isAbstract = params.isAbstract,
-			c.methods = params.methods,
+			c.name = $name,
+			c.qualifiedName = $qualifiedName,
+			c.file = $file,
+			c.startLine = $startLine,
+			c.endLine = $endLine,
+			c.isExport = $isExport,
+			c.isAbstract = $isAbstract,
+			c.methods = $methods,
 			c.created = localdatetime()
 		ON MATCH SET 
-			c.startLine = params.startLine,
-			c.endLine = params.endLine,
-			c.isExport = params.isExport,
-			c.isAbstract = params.isAbstract,
-			c.methods = params.methods,
+			c.name = $name,
+			c.qualifiedName = $qualifiedName,
+			c.file = $file,
+			c.startLine = $startLine,
+			c.endLine = $endLine,
+			c.isExport = $isExport,
+			c.isAbstract = $isAbstract,
+			c.methods = $methods,
 			c.updated = localdatetime()
+		SET ` + docAssignments("c", "$") + `
 		WITH c
-		MATCH (f:File {path: params.file})
+		MATCH (f:File {path: $file})
 		MERGE (c)-[:BELONGS_TO]->(f)
 	`
-	params := map[string]any{
-		"name":       class.Name,
-		"file":       class.FilePath,
-		"startLine":  class.StartLine,
-		"endLine":    class.EndLine,
-		"isExport":   class.IsExport,
-		"isAbstract": class.IsAbstract,
-		"methods":    class.Methods,
-	}
+	params := withDoc(map[string]any{
+		"id":            class.nodeID(),
+		"name":          class.Name,
+		"qualifiedName": class.QualifiedName,
+		"file":          class.FilePath,
+		"startLine":     class.StartLine,
+		"endLine":       class.EndLine,
+		"isExport":      class.IsExport,
+		"isAbstract":    class.IsAbstract,
+		"methods":       class.Methods,
+	}, class.Doc)
 	return c.executeCypher(ctx, cypher, params)
 }
 
+// Member Operations
+
+// UpsertMember ensures a :Method or :Property node exists, creates
+// Class→HAS_MEMBER and, for members with a body, IMPLEMENTED_BY→Function
+func (c *AGEClient) UpsertMember(ctx context.Context, member MemberEntity) error {
+	return c.runStatements(ctx, memberStatements([]MemberEntity{member}))
+}
+
+// Decorator Operations
+
+// UpsertDecorator ensures a :Decorator node exists and creates DECORATED_BY
+// from the class or member it decorates
+func (c *AGEClient) UpsertDecorator(ctx context.Context, decorator DecoratorEntity) error {
+	return c.runStatements(ctx, decoratorStatements([]DecoratorEntity{decorator}))
+}
+
+// Component Operations
+
+// UpsertComponent ensures a :Component node exists and creates DEFINED_IN→File
+// and IMPLEMENTED_BY→Function or Class
+func (c *AGEClient) UpsertComponent(ctx context.Context, component ComponentEntity) error {
+	return c.runStatements(ctx, componentStatements([]ComponentEntity{component}))
+}
+
+// UpsertRender creates RENDERS from a component to a component it renders
+func (c *AGEClient) UpsertRender(ctx context.Context, render RenderEntity) error {
+	return c.runStatements(ctx, []cypherStatement{renderStatement([]RenderEntity{render})})
+}
+
+// UpsertHookUsage creates USES_HOOK from a component to the hook it calls
+func (c *AGEClient) UpsertHookUsage(ctx context.Context, usage HookUsageEntity) error {
+	return c.runStatements(ctx, hookUsageStatements([]HookUsageEntity{usage}))
+}
+
+// UpsertClassUsage creates STYLED_BY from a JSX element and its component to
+// the CSS rules of a class it names
+func (c *AGEClient) UpsertClassUsage(ctx context.Context, usage ClassUsageEntity) error {
+	return c.runStatements(ctx, classUsageStatements([]ClassUsageEntity{usage}))
+}
+
+// UpsertStyleReference creates INCLUDES or EXTENDS between the CSS rules of an
+// SCSS @include or @extend
+func (c *AGEClient) UpsertStyleReference(ctx context.Context, ref StyleReferenceEntity) error {
+	return c.runStatements(ctx, styleReferenceStatements([]StyleReferenceEntity{ref}))
+}
+
 // Constant Operations
 
 // UpsertConstant ensures a :Constant node exists and creates DEFINED_IN→File
 func (c *AGEClient) UpsertConstant(ctx context.Context, constant ConstantEntity) error {
 	cypher := `
-		MERGE (c:Constant {name: params.name, file: params.file})
+		MERGE (c:Constant {name: $name, file: $file})
 		ON CREATE SET 
-			c.value = params.value,
+			c.value = $value,
 			c.created = localdatetime()
 		ON MATCH SET 
-			c.value = params.value,
+			c.value = $value,
 			c.updated = localdatetime()
+		SET ` + docAssignments("c", "$") + `
 		WITH c
-		MATCH (f:File {path: params.file})
+		MATCH (f:File {path: $file})
 		MERGE (c)-[:DEFINED_IN]->(f)
 	`
-	params := map[string]any{
+	params := withDoc(map[string]any{
 		"name":  constant.Name,
 		"file":  constant.FilePath,
 		"value": constant.Value,
-	}
+	}, constant.Doc)
 	return c.executeCypher(ctx, cypher, params)
 }
 
@@ -408,27 +519,26 @@ func (c *AGEClient) UpsertConstant(ctx context.Context, constant ConstantEntity)
 
 // UpsertJSXElement ensures a :JSXElement node exists and creates relationships
 func (c *AGEClient) UpsertJSXElement(ctx context.Context, jsx JSXElementEntity) error {
-	// Determine if it's a custom component
-	jsx.IsCustomComponent = len(jsx.TagName) > 0 && strings.ToUpper(jsx.TagName[:1]) == jsx.TagName[:1]
-
 	cypher := `
-		MERGE (jsx:JSXElement {tagName: params.tagName, file: params.file, line: params.line})
+		MERGE (jsx:JSXElement {tagName: $tagName, file: $file, line: $line})
 		ON CREATE SET 
-			jsx.containingComponent = params.containingComponent,
-			jsx.props = params.props,
-			jsx.isCustomComponent = params.isCustomComponent,
+			jsx.containingComponent = $containingComponent,
+			jsx.props = $props,
+			jsx.isCustomComponent = $isCustomComponent,
+			jsx.classNames = $classNames,
 			jsx.created = localdatetime()
 		ON MATCH SET 
-			jsx.containingComponent = params.containingComponent,
-			jsx.props = params.props,
-			jsx.isCustomComponent = params.isCustomComponent,
+			jsx.containingComponent = $containingComponent,
+			jsx.props = $props,
+			jsx.isCustomComponent = $isCustomComponent,
+			jsx.classNames = $classNames,
 			jsx.updated = localdatetime()
 		WITH jsx
-		MATCH (f:File {path: params.file})
+		MATCH (f:File {path: $file})
 		MERGE (jsx)-[:USED_IN]->(f)
 		WITH jsx, f
 		WHERE jsx.containingComponent IS NOT NULL AND jsx.containingComponent <> ''
-		OPTIONAL MATCH (func:Function {name: jsx.containingComponent, file: params.file})
+		OPTIONAL MATCH (func:Function {name: jsx.containingComponent, file: $file})
 		FOREACH (_ IN CASE WHEN func IS NOT NULL THEN [1] ELSE [] END |
 			MERGE (func)-[:RENDERS]->(jsx)
 		)
@@ -440,6 +550,7 @@ func (c *AGEClient) UpsertJSXElement(ctx context.Context, jsx JSXElementEntity)
 		"containingComponent": jsx.ContainingComponent,
 		"props":               jsx.Props,
 		"isCustomComponent":   jsx.IsCustomComponent,
+		"classNames":          nonNil(jsx.ClassNames),
 	}
 	return c.executeCypher(ctx, cypher, params)
 }
@@ -449,26 +560,29 @@ func (c *AGEClient) UpsertJSXElement(ctx context.Context, jsx JSXElementEntity)
 // UpsertCSSRule ensures a :CSSRule node exists and creates relationships
 func (c *AGEClient) UpsertCSSRule(ctx context.Context, css CSSRuleEntity) error {
 	cypher := `
-		MERGE (css:CSSRule {selector: params.selector, file: params.file})
+		MERGE (css:CSSRule {selector: $selector, file: $file})
 		ON CREATE SET 
-			css.ruleType = params.ruleType,
-			css.line = params.line,
-			css.propertyName = params.propertyName,
-			css.value = params.value,
+			css.fullSelector = $fullSelector,
+			css.ruleType = $ruleType,
+			css.line = $line,
+			css.propertyName = $propertyName,
+			css.value = $value,
 			css.created = localdatetime()
 		ON MATCH SET 
-			css.ruleType = params.ruleType,
-			css.line = params.line,
-			css.propertyName = params.propertyName,
-			css.value = params.value,
+			css.fullSelector = $fullSelector,
+			css.ruleType = $ruleType,
+			css.line = $line,
+			css.propertyName = $propertyName,
+			css.value = $value,
 			css.updated = localdatetime()
 		WITH css
-		MATCH (f:File {path: params.file})
+		MATCH (f:File {path: $file})
 		MERGE (css)-[:DEFINED_IN]->(f)
 	`
 	params := map[string]any{
 		"selector":     css.Selector,
 		"file":         css.FilePath,
+		"fullSelector": css.FullSelector,
 		"ruleType":     css.RuleType,
 		"line":         css.Line,
 		"propertyName": css.PropertyName,
@@ -484,23 +598,21 @@ func (c *AGEClient) UpsertFunctionCall(ctx context.Context, call FunctionCallEnt
 	// If we have a resolved target, create a direct function-to-function relationship
 	if call.ResolvedTarget != "" && call.TargetFile != "" {
 		cypher := `
-			MATCH (caller:Function {name: params.callerFunc, file: params.callerFile})
-			MATCH (target:Function {name: params.targetFunc, file: params.targetFile})
+			MATCH (caller:Function {id: $callerId})
+			MATCH (target:Function {id: $targetId})
 			MERGE (caller)-[r:CALLS]->(target)
 			ON CREATE SET 
-				r.callLocation = params.callLocation,
-				r.callContext = params.callContext,
+				r.callLocation = $callLocation,
+				r.callContext = $callContext,
 				r.created = localdatetime()
 			ON MATCH SET 
-				r.callLocation = params.callLocation,
-				r.callContext = params.callContext,
+				r.callLocation = $callLocation,
+				r.callContext = $callContext,
 				r.updated = localdatetime()
 		`
 		params := map[string]any{
-			"callerFunc":   call.CallerFunc,
-			"callerFile":   call.CallerFile,
-			"targetFunc":   call.ResolvedTarget,
-			"targetFile":   call.TargetFile,
+			"callerId":     call.callerNodeID(),
+			"targetId":     call.targetNodeID(),
 			"callLocation": call.CallLocation,
 			"callContext":  call.CallContext,
 		}
@@ -508,21 +620,21 @@ func (c *AGEClient) UpsertFunctionCall(ctx context.Context, call FunctionCallEnt
 	} else {
 		// Create an unresolved call relationship
 		cypher := `
-			MATCH (f:File {path: params.callerFile})
+			MATCH (f:File {path: $callerFile})
 			MERGE (call:UnresolvedCall {
-				calledFunc: params.calledFunc, 
-				callerFile: params.callerFile,
-				callerFunc: params.callerFunc,
-				line: params.callLocation
+				calledFunc: $calledFunc, 
+				callerFile: $callerFile,
+				callerFunc: $callerFunc,
+				line: $callLocation
 			})
 			ON CREATE SET 
-				call.callContext = params.callContext,
+				call.callContext = $callContext,
 				call.created = localdatetime()
 			WITH f, call
 			MERGE (f)-[:CONTAINS_CALL]->(call)
 			WITH call
-			WHERE params.callerFunc IS NOT NULL AND params.callerFunc <> ''
-			OPTIONAL MATCH (caller:Function {name: params.callerFunc, file: params.callerFile})
+			WHERE $callerFunc IS NOT NULL AND $callerFunc <> ''
+			OPTIONAL MATCH (caller:Function {id: $callerId})
 			FOREACH (_ IN CASE WHEN caller IS NOT NULL THEN [1] ELSE [] END |
 				MERGE (caller)-[:MAKES_CALL]->(call)
 			)
@@ -530,6 +642,7 @@ func (c *AGEClient) UpsertFunctionCall(ctx context.Context, call FunctionCallEnt
 		params := map[string]any{
 			"callerFile":   call.CallerFile,
 			"callerFunc":   call.CallerFunc,
+			"callerId":     call.callerNodeID(),
 			"calledFunc":   call.CalledFunc,
 			"callLocation": call.CallLocation,
 			"callContext":  call.CallContext,
@@ -542,19 +655,19 @@ func (c *AGEClient) UpsertFunctionCall(ctx context.Context, call FunctionCallEnt
 func (c *AGEClient) UpsertTypeUsage(ctx context.Context, usage TypeUsageEntity) error {
 	// Try to match the type first
 	cypher := `
-		MATCH (t:Type {name: params.usedType})
+		MATCH (t:Type {name: $usedType})
 		WITH t
-		MATCH (f:File {path: params.usingFile})
+		MATCH (f:File {path: $usingFile})
 		MERGE (f)-[r:USES_TYPE]->(t)
 		ON CREATE SET 
-			r.context = params.context,
-			r.location = params.location,
-			r.usingEntity = params.usingEntity,
+			r.context = $context,
+			r.location = $location,
+			r.usingEntity = $usingEntity,
 			r.created = localdatetime()
 		ON MATCH SET 
-			r.context = params.context,
-			r.location = params.location,
-			r.usingEntity = params.usingEntity,
+			r.context = $context,
+			r.location = $location,
+			r.usingEntity = $usingEntity,
 			r.updated = localdatetime()
 	`
 	params := map[string]any{
@@ -573,32 +686,33 @@ func (c *AGEClient) UpsertTypeUsage(ctx context.Context, usage TypeUsageEntity)
 
 	// If no type was matched, also check interfaces
 	cypher2 := `
-		MATCH (i:Interface {name: params.usedType})
+		MATCH (i:Interface {name: $usedType})
 		WITH i
-		MATCH (f:File {path: params.usingFile})
+		MATCH (f:File {path: $usingFile})
 		MERGE (f)-[r:USES_TYPE]->(i)
 		ON CREATE SET 
-			r.context = params.context,
-			r.location = params.location,
-			r.usingEntity = params.usingEntity,
+			r.context = $context,
+			r.location = $location,
+			r.usingEntity = $usingEntity,
 			r.created = localdatetime()
 		ON MATCH SET 
-			r.context = params.context,
-			r.location = params.location,
-			r.usingEntity = params.usingEntity,
+			r.context = $context,
+			r.location = $location,
+			r.usingEntity = $usingEntity,
 			r.updated = localdatetime()
 	`
 	return c.executeCypher(ctx, cypher2, params)
 }
 
-// UpsertExtends creates an EXTENDS relationship
+// UpsertExtends creates an EXTENDS relationship and OVERRIDES between the
+// methods of the two classes
 func (c *AGEClient) UpsertExtends(ctx context.Context, extends ExtendsEntity) error {
 	cypher := `
-		MATCH (child {name: params.childName, file: params.file})
+		MATCH (child {name: $childName, file: $file})
 		WHERE child:Class OR child:Interface
 		WITH child
-		OPTIONAL MATCH (parent:Class {name: params.parentName})
-		OPTIONAL MATCH (parentInterface:Interface {name: params.parentName})
+		OPTIONAL MATCH (parent:Class {name: $parentName})
+		OPTIONAL MATCH (parentInterface:Interface {name: $parentName})
 		WITH child, COALESCE(parent, parentInterface) AS parentNode
 		WHERE parentNode IS NOT NULL
 		MERGE (child)-[r:EXTENDS]->(parentNode)
@@ -610,14 +724,17 @@ func (c *AGEClient) UpsertExtends(ctx context.Context, extends ExtendsEntity) er
 		"parentName": extends.ParentName,
 		"file":       extends.FilePath,
 	}
-	return c.executeCypher(ctx, cypher, params)
+	if err := c.executeCypher(ctx, cypher, params); err != nil {
+		return err
+	}
+	return c.runStatements(ctx, []cypherStatement{overridesStatement([]ExtendsEntity{extends})})
 }
 
 // UpsertImplements creates an IMPLEMENTS relationship
 func (c *AGEClient) UpsertImplements(ctx context.Context, implements ImplementsEntity) error {
 	cypher := `
-		MATCH (class:Class {name: params.className, file: params.file})
-		MATCH (interface:Interface {name: params.interfaceName})
+		MATCH (class:Class {name: $className, file: $file})
+		MATCH (interface:Interface {name: $interfaceName})
 		MERGE (class)-[r:IMPLEMENTS]->(interface)
 		ON CREATE SET r.created = localdatetime()
 		ON MATCH SET r.updated = localdatetime()
@@ -633,18 +750,18 @@ func (c *AGEClient) UpsertImplements(ctx context.Context, implements ImplementsE
 // UpsertReference creates a generic REFERENCES relationship
 func (c *AGEClient) UpsertReference(ctx context.Context, ref ReferenceEntity) error {
 	cypher := `
-		MATCH (f:File {path: params.sourceFile})
+		MATCH (f:File {path: $sourceFile})
 		MERGE (ref:Reference {
-			sourceFile: params.sourceFile,
-			sourceEntity: params.sourceEntity,
-			targetEntity: params.targetEntity,
-			refType: params.refType
+			sourceFile: $sourceFile,
+			sourceEntity: $sourceEntity,
+			targetEntity: $targetEntity,
+			refType: $refType
 		})
 		ON CREATE SET 
-			ref.line = params.line,
+			ref.line = $line,
 			ref.created = localdatetime()
 		ON MATCH SET 
-			ref.line = params.line,
+			ref.line = $line,
 			ref.updated = localdatetime()
 		WITH f, ref
 		MERGE (f)-[:CONTAINS]->(ref)
@@ -659,6 +776,114 @@ func (c *AGEClient) UpsertReference(ctx context.Context, ref ReferenceEntity) er
 	return c.executeCypher(ctx, cypher, params)
 }
 
+// UpsertExport creates an EXPORTS relationship from a file to the node it
+// exports
+func (c *AGEClient) UpsertExport(ctx context.Context, export ExportEntity) error {
+	return c.runStatements(ctx, exportStatements([]ExportEntity{export}))
+}
+
+// File Lifecycle Operations
+
+// DeleteFile removes a :File node and every node and relationship owned by it
+// in a single transaction.
+func (c *AGEClient) DeleteFile(ctx context.Context, path string) error {
+	return c.inTransaction(ctx, func(bound *AGEClient) error {
+		// A file with no entities keeps none of its owned nodes
+		if err := bound.runStatements(ctx, deleteFileStatements([]ParsedFile{{FilePath: path}})); err != nil {
+			return err
+		}
+		params := map[string]any{"path": path}
+		if err := bound.executeCypher(ctx, `MATCH (f:File {path: $path}) DETACH DELETE f`, params); err != nil {
+			return fmt.Errorf("failed to delete file node: %w", err)
+		}
+		return bound.deleteOrphanImports(ctx)
+	})
+}
+
+// ReplaceFileEntities swaps the graph contents of pf.FilePath for pf in a
+// single transaction. Nodes the new parse still defines are updated in place
+// so relationships from other files keep pointing at them.
+func (c *AGEClient) ReplaceFileEntities(ctx context.Context, pf ParsedFile) error {
+	files := []ParsedFile{pf}
+	return c.inTransaction(ctx, func(bound *AGEClient) error {
+		if err := bound.runStatements(ctx, slices.Concat(deleteFileStatements(files), parsedFileStatements(files))); err != nil {
+			return err
+		}
+		return bound.deleteOrphanImports(ctx)
+	})
+}
+
+// WriteParsedFiles replaces the graph contents of files with the UNWIND
+// statements shared with the Neo4j backend, each chunk of opts.BatchSize rows
+// in its own transaction and up to opts.Concurrency chunks at once.
+func (c *AGEClient) WriteParsedFiles(ctx context.Context, files []ParsedFile, opts BatchOptions) error {
+	opts = opts.withDefaults()
+
+	for _, stmt := range slices.Concat(deleteFileStatements(files), parsedFileStatements(files)) {
+		err := forEachChunk(stmt, opts, func(chunk cypherStatement) error {
+			return c.inTransaction(ctx, func(bound *AGEClient) error {
+				return bound.runStatements(ctx, []cypherStatement{chunk})
+			})
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return c.inTransaction(ctx, func(bound *AGEClient) error {
+		return bound.deleteOrphanImports(ctx)
+	})
+}
+
+// runStatements runs shared statements in order, passing each one's rows as
+// the $rows parameter. AGE spells datetime() as localdatetime().
+func (c *AGEClient) runStatements(ctx context.Context, stmts []cypherStatement) error {
+	for _, stmt := range stmts {
+		if len(stmt.rows) == 0 {
+			continue
+		}
+		cypher := strings.ReplaceAll(stmt.cypher, "datetime()", "localdatetime()")
+		if err := c.executeCypher(ctx, cypher, map[string]any{"rows": stmt.rows}); err != nil {
+			return fmt.Errorf("failed to %s: %w", stmt.action, err)
+		}
+	}
+	return nil
+}
+
+// inTransaction runs fn with a copy of the client bound to a new transaction,
+// committing if fn succeeds.
+func (c *AGEClient) inTransaction(ctx context.Context, fn func(bound *AGEClient) error) error {
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// AGE must be loaded on whichever pooled connection the transaction got
+	if _, err := tx.ExecContext(ctx, "LOAD 'age'"); err != nil {
+		return fmt.Errorf("failed to load AGE: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, "SET LOCAL search_path = ag_catalog, \"$user\", public"); err != nil {
+		return fmt.Errorf("failed to set search path: %w", err)
+	}
+
+	if err := fn(&AGEClient{db: c.db, graphName: c.graphName, tx: tx}); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// deleteOrphanImports removes :Import and :Package nodes no file imports anymore.
+func (c *AGEClient) deleteOrphanImports(ctx context.Context) error {
+	for _, label := range []string{"Import", "Package"} {
+		cypher := fmt.Sprintf(`MATCH (n:%s) WHERE NOT EXISTS((n)<-[:IMPORTS]-()) DELETE n`, label)
+		if err := c.executeCypher(ctx, cypher, nil); err != nil {
+			return fmt.Errorf("failed to delete orphan imports: %w", err)
+		}
+	}
+	return nil
+}
+
 // Utility Operations
 
 // CreateIndexes creates recommended indexes for better query performance
@@ -674,14 +899,20 @@ func (c *AGEClient) CreateIndexes(ctx context.Context) error {
 	}
 
 	// Create indexes for each label
-	labels := []string{"File", "Function", "Import", "Type", "Class", "Interface", "JSXElement", "CSSRule", "UnresolvedCall"}
+	labels := []string{"File", "Function", "Import", "Package", "Type", "Class", "Method", "Property", "Interface", "Decorator", "Component", "Hook", "JSXElement", "CSSRule", "UnresolvedCall"}
 	properties := map[string][]string{
 		"File":           {"path"},
-		"Function":       {"name", "file"},
+		"Function":       {"id", "name", "file"},
 		"Import":         {"module"},
+		"Package":        {"name"},
 		"Type":           {"name"},
-		"Class":          {"name"},
+		"Class":          {"id", "name"},
+		"Method":         {"id", "name"},
+		"Property":       {"id"},
 		"Interface":      {"name"},
+		"Decorator":      {"id", "name"},
+		"Component":      {"id", "name"},
+		"Hook":           {"name"},
 		"JSXElement":     {"tagName"},
 		"CSSRule":        {"selector"},
 		"UnresolvedCall": {"calledFunc"},
@@ -0,0 +1,128 @@
+// internal/model/agtype.go
+
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// encodeAgtype renders v as agtype text, the input format of AGE's cypher()
+// parameter map. Strings are JSON-escaped, so any source text can be passed
+// without touching the query. Nil slices encode as empty lists; nil maps and
+// pointers as null. Values with no agtype equivalent are an error.
+func encodeAgtype(v any) (string, error) {
+	var b strings.Builder
+	if err := writeAgtype(&b, reflect.ValueOf(v)); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// writeAgtype appends the agtype encoding of v to b.
+func writeAgtype(b *strings.Builder, v reflect.Value) error {
+	if !v.IsValid() {
+		b.WriteString("null")
+		return nil
+	}
+
+	if t, ok := v.Interface().(time.Time); ok {
+		writeAgtypeString(b, t.Format(time.RFC3339Nano))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		writeAgtypeString(b, v.String())
+	case reflect.Bool:
+		b.WriteString(strconv.FormatBool(v.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		b.WriteString(strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v.Uint() > math.MaxInt64 {
+			return fmt.Errorf("agtype integer out of range: %d", v.Uint())
+		}
+		b.WriteString(strconv.FormatUint(v.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		if err := writeAgtypeFloat(b, v.Float()); err != nil {
+			return err
+		}
+	case reflect.Slice, reflect.Array:
+		b.WriteByte('[')
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			if err := writeAgtype(b, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		b.WriteByte(']')
+	case reflect.Map:
+		if v.IsNil() {
+			b.WriteString("null")
+			return nil
+		}
+		if v.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("unsupported agtype map key type %s", v.Type().Key())
+		}
+		keys := make([]string, 0, v.Len())
+		for _, key := range v.MapKeys() {
+			keys = append(keys, key.String())
+		}
+		sort.Strings(keys)
+
+		b.WriteByte('{')
+		for i, key := range keys {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			writeAgtypeString(b, key)
+			b.WriteString(": ")
+			if err := writeAgtype(b, v.MapIndex(reflect.ValueOf(key).Convert(v.Type().Key()))); err != nil {
+				return err
+			}
+		}
+		b.WriteByte('}')
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			b.WriteString("null")
+			return nil
+		}
+		return writeAgtype(b, v.Elem())
+	default:
+		return fmt.Errorf("unsupported agtype parameter type %s", v.Type())
+	}
+	return nil
+}
+
+// writeAgtypeString appends s as a double-quoted, JSON-escaped string.
+func writeAgtypeString(b *strings.Builder, s string) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s) // Encoding a string cannot fail
+	b.Write(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+}
+
+// writeAgtypeFloat appends f so that agtype reads it back as a float rather
+// than an integer. NaN and infinities have no portable agtype literal and are
+// an error.
+func writeAgtypeFloat(b *strings.Builder, f float64) error {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return fmt.Errorf("agtype float out of range: %v", f)
+	}
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".eE") {
+		s += ".0"
+	}
+	b.WriteString(s)
+	return nil
+}
@@ -0,0 +1,136 @@
+// internal/model/agtype_test.go
+
+package model
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+type agtypeKey string
+
+func TestEncodeAgtype(t *testing.T) {
+	var nilMap map[string]any
+	var nilSlice []string
+	var nilPointer *int
+	seven := 7
+
+	tests := []struct {
+		name string
+		in   any
+		want string
+	}{
+		{"nil", nil, `null`},
+		{"nil map", nilMap, `null`},
+		{"nil slice", nilSlice, `[]`},
+		{"nil pointer", nilPointer, `null`},
+		{"pointer", &seven, `7`},
+		{"bool", true, `true`},
+		{"int", -42, `-42`},
+		{"uint", uint8(255), `255`},
+		{"max int64", uint64(math.MaxInt64), `9223372036854775807`},
+		{"whole float", 3.0, `3.0`},
+		{"fraction", 0.25, `0.25`},
+		{"exponent", 1e21, `1e+21`},
+		{"negative float", float32(-1.5), `-1.5`},
+		{"time", time.Date(2024, 5, 1, 12, 30, 0, 5, time.UTC), `"2024-05-01T12:30:00.000000005Z"`},
+
+		{"plain string", "render", `"render"`},
+		{"dollar quotes", "SELECT $$ injected $$", `"SELECT $$ injected $$"`},
+		{"single quote", "it's", `"it's"`},
+		{"double quote", `say "hi"`, `"say \"hi\""`},
+		{"backslash", `C:\path\n`, `"C:\\path\\n"`},
+		{"newlines and tabs", "a\nb\r\tc", `"a\nb\r\tc"`},
+		{"control characters", "\x00\x01\x1f\x7f", `"\u0000\u0001\u001f` + "\x7f" + `"`},
+		{"html", "<a href='x'>&</a>", `"<a href='x'>&</a>"`},
+		{"line separators", "a\u2028b\u2029c", `"a\u2028b\u2029c"`},
+		{"non-BMP", "emoji 😀 𝄞", `"emoji 😀 𝄞"`},
+		{"invalid UTF-8", "bad \xff byte", "\"bad \ufffd byte\""},
+
+		{"list", []any{1, "two", 3.5, nil, false}, `[1, "two", 3.5, null, false]`},
+		{"array", [2]int{1, 2}, `[1, 2]`},
+		{"nested", map[string]any{
+			"rows": []map[string]any{
+				{"name": "a", "lines": []int{1, 2}},
+				{"name": "b", "meta": map[string]any{"deep": []any{[]string{"x"}}}},
+			},
+		}, `{"rows": [{"lines": [1, 2], "name": "a"}, {"meta": {"deep": [["x"]]}, "name": "b"}]}`},
+		{"sorted keys", map[string]int{"b": 2, "a": 1, "c": 3, "A": 0, "aa": 4}, `{"A": 0, "a": 1, "aa": 4, "b": 2, "c": 3}`},
+		{"named key type", map[agtypeKey]string{"z": "last", "m": "mid"}, `{"m": "mid", "z": "last"}`},
+		{"escaped key", map[string]int{`k"$$'`: 1}, `{"k\"$$'": 1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := encodeAgtype(tt.in)
+			if err != nil {
+				t.Fatalf("encodeAgtype(%#v) error: %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("encodeAgtype(%#v)\n got: %s\nwant: %s", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeAgtypeErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		in   any
+	}{
+		{"NaN", math.NaN()},
+		{"positive infinity", math.Inf(1)},
+		{"negative infinity", math.Inf(-1)},
+		{"NaN in list", []float64{1, math.NaN()}},
+		{"infinity in map", map[string]any{"score": math.Inf(1)}},
+		{"uint out of range", uint64(math.MaxInt64) + 1},
+		{"int map key", map[int]string{1: "one"}},
+		{"channel", make(chan int)},
+		{"function", func() {}},
+		{"struct", struct{ A int }{1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := encodeAgtype(tt.in); err == nil {
+				t.Errorf("encodeAgtype(%v) = %s, want error", tt.name, got)
+			}
+		})
+	}
+}
+
+// TestEncodeAgtypeStringsRoundTrip checks that every string comes back
+// unchanged from its encoding, so no source text can end the string early.
+func TestEncodeAgtypeStringsRoundTrip(t *testing.T) {
+	inputs := []string{
+		"",
+		"$$",
+		"$$); DROP TABLE files; --",
+		`'); SELECT 1; --`,
+		`"}, "injected": true, "x": {"`,
+		`\"`,
+		`\\\`,
+		"\x00\x1b[31m\x7f",
+		"multi\nline\r\nsource\ttext",
+		"\u2028\u2029",
+		"😀𝄞\U0010FFFF",
+		strings.Repeat(`"\$`, 100),
+	}
+
+	for _, in := range inputs {
+		encoded, err := encodeAgtype(map[string]any{"source": in})
+		if err != nil {
+			t.Fatalf("encodeAgtype(%q) error: %v", in, err)
+		}
+		var decoded map[string]string
+		if err := json.Unmarshal([]byte(encoded), &decoded); err != nil {
+			t.Fatalf("encoding of %q is not valid: %s: %v", in, encoded, err)
+		}
+		if len(decoded) != 1 || decoded["source"] != in {
+			t.Errorf("encoding of %q decoded as %q", in, decoded)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+// internal/model/backend.go
+
+package model
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// GraphClient is the interface every graph backend implements.
+type GraphClient interface {
+	Close(ctx context.Context) error
+	CreateIndexes(ctx context.Context) error
+	UpsertFile(ctx context.Context, path, language string) error
+	UpsertFunction(ctx context.Context, fn FunctionEntity) error
+	UpsertImport(ctx context.Context, imp ImportEntity) error
+	UpsertVariable(ctx context.Context, variable VariableEntity) error
+	UpsertType(ctx context.Context, typeEntity TypeEntity) error
+	UpsertInterface(ctx context.Context, iface InterfaceEntity) error
+	UpsertClass(ctx context.Context, class ClassEntity) error
+	UpsertMember(ctx context.Context, member MemberEntity) error
+	UpsertDecorator(ctx context.Context, decorator DecoratorEntity) error
+	UpsertComponent(ctx context.Context, component ComponentEntity) error
+	UpsertConstant(ctx context.Context, constant ConstantEntity) error
+	UpsertJSXElement(ctx context.Context, jsx JSXElementEntity) error
+	UpsertCSSRule(ctx context.Context, css CSSRuleEntity) error
+	UpsertFunctionCall(ctx context.Context, call FunctionCallEntity) error
+	UpsertFunctionTypes(ctx context.Context, fn FunctionEntity) error
+	UpsertTypeUsage(ctx context.Context, usage TypeUsageEntity) error
+	UpsertExtends(ctx context.Context, extends ExtendsEntity) error
+	UpsertImplements(ctx context.Context, implements ImplementsEntity) error
+	UpsertReference(ctx context.Context, ref ReferenceEntity) error
+	UpsertExport(ctx context.Context, export ExportEntity) error
+	UpsertRender(ctx context.Context, render RenderEntity) error
+	UpsertHookUsage(ctx context.Context, usage HookUsageEntity) error
+	UpsertClassUsage(ctx context.Context, usage ClassUsageEntity) error
+	UpsertStyleReference(ctx context.Context, ref StyleReferenceEntity) error
+	DeleteFile(ctx context.Context, path string) error
+	ReplaceFileEntities(ctx context.Context, pf ParsedFile) error
+}
+
+// BatchOptions controls how a BatchWriter splits its work.
+type BatchOptions struct {
+	BatchSize   int // Rows per statement
+	Concurrency int // Transactions run in parallel
+}
+
+// DefaultBatchOptions are used for any BatchOptions field left at zero.
+var DefaultBatchOptions = BatchOptions{BatchSize: 500, Concurrency: 4}
+
+// withDefaults fills zero fields from DefaultBatchOptions.
+func (o BatchOptions) withDefaults() BatchOptions {
+	if o.BatchSize <= 0 {
+		o.BatchSize = DefaultBatchOptions.BatchSize
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = DefaultBatchOptions.Concurrency
+	}
+	return o
+}
+
+// BatchWriter is implemented by backends that can write many parsed files
+// with set-based statements instead of one upsert per entity.
+type BatchWriter interface {
+	// WriteParsedFiles replaces the graph contents of every file in files,
+	// writing all entities before any relationship so cross-file edges resolve.
+	WriteParsedFiles(ctx context.Context, files []ParsedFile, opts BatchOptions) error
+}
+
+// BackendFactory connects a graph backend, reading its settings from the environment.
+type BackendFactory func() (GraphClient, error)
+
+// backendInfo is a registered backend.
+type backendInfo struct {
+	description string
+	factory     BackendFactory
+}
+
+var (
+	backendsMu sync.RWMutex
+	backends   = make(map[string]backendInfo)
+)
+
+// RegisterBackend makes a graph backend available under name. It panics if
+// the name is already taken, so conflicting registrations fail at startup.
+func RegisterBackend(name, description string, factory BackendFactory) {
+	backendsMu.Lock()
+	defer backendsMu.Unlock()
+
+	if _, exists := backends[name]; exists {
+		panic(fmt.Sprintf("graph backend %q registered twice", name))
+	}
+	backends[name] = backendInfo{description: description, factory: factory}
+}
+
+// NewGraphClient connects the backend registered under name.
+func NewGraphClient(name string) (GraphClient, error) {
+	backendsMu.RLock()
+	info, ok := backends[name]
+	backendsMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown graph backend %q (available: %s)", name, strings.Join(Backends(), ", "))
+	}
+	return info.factory()
+}
+
+// Backends returns the names of all registered backends, sorted.
+func Backends() []string {
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BackendDescription returns the human-readable description of a backend.
+func BackendDescription(name string) string {
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+	return backends[name].description
+}
@@ -0,0 +1,78 @@
+// internal/model/batch.go
+
+package model
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// WriteParsedFiles replaces the graph contents of files using the same UNWIND
+// statements as the single upserts. Each statement's rows are split into
+// chunks of opts.BatchSize, and up to opts.Concurrency chunks are written in
+// parallel transactions. A statement finishes before the next one starts, so
+// every node exists before the relationships that point at it are written.
+func (c *Neo4jClient) WriteParsedFiles(ctx context.Context, files []ParsedFile, opts BatchOptions) error {
+	opts = opts.withDefaults()
+
+	for _, stmt := range slices.Concat(deleteFileStatements(files), parsedFileStatements(files)) {
+		err := forEachChunk(stmt, opts, func(chunk cypherStatement) error {
+			return c.runStatements(ctx, chunk)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return c.write(ctx, func(tx neo4j.ManagedTransaction) error {
+		return deleteOrphanImports(ctx, tx)
+	})
+}
+
+// forEachChunk calls write for chunks of stmt holding at most opts.BatchSize
+// rows, running up to opts.Concurrency calls at once. It stops starting new
+// chunks after the first failure and returns that error once the running
+// chunks have finished.
+func forEachChunk(stmt cypherStatement, opts BatchOptions, write func(chunk cypherStatement) error) error {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	sem := make(chan struct{}, opts.Concurrency)
+
+	for start := 0; start < len(stmt.rows); start += opts.BatchSize {
+		mu.Lock()
+		failed := firstErr != nil
+		mu.Unlock()
+		if failed {
+			break
+		}
+
+		chunk := stmt
+		chunk.rows = stmt.rows[start:min(start+opts.BatchSize, len(stmt.rows))]
+
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if err := write(chunk); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("batch of %d rows: %w", len(chunk.rows), err)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+	return firstErr
+}
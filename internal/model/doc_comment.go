@@ -0,0 +1,96 @@
+// internal/model/doc_comment.go
+
+package model
+
+import (
+	"maps"
+	"strings"
+)
+
+// docPropertyNames are the graph properties a DocComment is stored in, in the
+// order docAssignments sets them.
+var docPropertyNames = []string{
+	"doc", "docParams", "docReturns", "docThrows", "docExamples", "docSee", "deprecated", "deprecationNote",
+}
+
+// properties flattens d into graph node properties: the description as doc,
+// each @param as "name description", and the other tags as strings or lists.
+// A nil comment clears them.
+func (d *DocComment) properties() map[string]any {
+	if d == nil {
+		d = &DocComment{}
+	}
+	params := make([]string, 0, len(d.Params))
+	for _, param := range d.Params {
+		params = append(params, strings.TrimSpace(param.Name+" "+param.Description))
+	}
+	return map[string]any{
+		"doc":             d.Description,
+		"docParams":       params,
+		"docReturns":      d.Returns,
+		"docThrows":       nonNil(d.Throws),
+		"docExamples":     nonNil(d.Examples),
+		"docSee":          nonNil(d.See),
+		"deprecated":      d.Deprecated,
+		"deprecationNote": d.DeprecationNote,
+	}
+}
+
+// nonNil returns an empty list for nil, so list properties are always lists.
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+// docAssignments returns the Cypher SET items storing a DocComment's
+// properties on alias, read from source: docAssignments("fn", "row.") gives
+// "fn.doc = row.doc, fn.docParams = row.docParams, ...".
+func docAssignments(alias, source string) string {
+	items := make([]string, 0, len(docPropertyNames))
+	for _, name := range docPropertyNames {
+		items = append(items, alias+"."+name+" = "+source+name)
+	}
+	return strings.Join(items, ", ")
+}
+
+// Text renders d as it would be written in a JSDoc block, description first
+// and then one line per tag, for embedding alongside the code it documents.
+// It returns "" for a nil comment.
+func (d *DocComment) Text() string {
+	if d == nil {
+		return ""
+	}
+
+	var lines []string
+	if d.Description != "" {
+		lines = append(lines, d.Description)
+	}
+	for _, param := range d.Params {
+		lines = append(lines, strings.TrimSpace("@param "+param.Name+" "+param.Description))
+	}
+	if d.Returns != "" {
+		lines = append(lines, "@returns "+d.Returns)
+	}
+	for _, throws := range d.Throws {
+		lines = append(lines, "@throws "+throws)
+	}
+	if d.Deprecated {
+		lines = append(lines, strings.TrimSpace("@deprecated "+d.DeprecationNote))
+	}
+	for _, see := range d.See {
+		lines = append(lines, "@see "+see)
+	}
+	for _, example := range d.Examples {
+		lines = append(lines, "@example\n"+example)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// withDoc copies props and adds the properties of doc.
+func withDoc(props map[string]any, doc *DocComment) map[string]any {
+	out := maps.Clone(props)
+	maps.Copy(out, doc.properties())
+	return out
+}
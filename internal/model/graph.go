@@ -6,6 +6,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
@@ -17,142 +18,385 @@ import (
 
 // FunctionEntity represents a :Function node in Neo4j.
 type FunctionEntity struct {
-	Name      string
-	FilePath  string
-	StartLine int
-	EndLine   int
-	Signature string // Function signature with parameters
-	IsAsync   bool
-	IsExport  bool
+	ID             string          `json:"id"` // Unique key within the project, see EntityID
+	Name           string          `json:"name"`
+	QualifiedName  string          `json:"qualifiedName"` // Name prefixed by its enclosing scopes, e.g. Widget.render
+	FilePath       string          `json:"filePath"`
+	StartLine      int             `json:"startLine"`
+	EndLine        int             `json:"endLine"`
+	Signature      string          `json:"signature"` // Function signature with parameters
+	Parameters     []Parameter     `json:"parameters,omitempty"`
+	ReturnType     string          `json:"returnType"` // Declared return type, if any
+	TypeParameters []TypeParameter `json:"typeParameters,omitempty"`
+	IsAsync        bool            `json:"isAsync"`
+	IsGenerator    bool            `json:"isGenerator"`
+	IsExport       bool            `json:"isExport"`
+	Receiver       string          `json:"receiver"` // Receiver type name for Go methods
+	Doc            *DocComment     `json:"doc,omitempty"`
+}
+
+// Parameter is a parameter of a FunctionEntity.
+type Parameter struct {
+	Name         string `json:"name"`         // Destructuring patterns are kept as written
+	Type         string `json:"type"`         // Declared type, if any
+	DefaultValue string `json:"defaultValue"` // Default value as written, if any
+	IsOptional   bool   `json:"isOptional"`   // Marked optional or given a default value
+	IsRest       bool   `json:"isRest"`       // ...rest, *args, **kwargs or a Go variadic parameter
+}
+
+// TypeParameter is a generic type parameter of a FunctionEntity.
+type TypeParameter struct {
+	Name       string `json:"name"`
+	Constraint string `json:"constraint"` // Bound the type must satisfy, if any
+	Default    string `json:"default"`    // Default type, if any
 }
 
 // ImportEntity represents a :Import node in Neo4j.
 type ImportEntity struct {
-	Module        string
-	FilePath      string
-	ImportedNames []string // Names of imported items
-	IsDefault     bool
-	IsNamespace   bool
+	Module        string            `json:"module"`
+	FilePath      string            `json:"filePath"`
+	ImportedNames []string          `json:"importedNames,omitempty"` // Names of imported items
+	Aliases       map[string]string `json:"aliases,omitempty"`       // Local alias -> exported name (import { a as b })
+	IsDefault     bool              `json:"isDefault"`
+	IsNamespace   bool              `json:"isNamespace"`
+	IsDynamic     bool              `json:"isDynamic"`    // Loaded with import(), e.g. by React.lazy
+	IsComputed    bool              `json:"isComputed"`   // Dynamic import whose specifier is an expression, kept as written in Module
+	ResolvedFile  string            `json:"resolvedFile"` // Project file the module resolves to, if any
+	Package       string            `json:"package"`      // External package name when the module is not a project file
 }
 
 // VariableEntity represents a :Variable node in Neo4j.
 type VariableEntity struct {
-	Name      string
-	FilePath  string
-	Type      string
-	IsConst   bool
-	IsLet     bool
-	StartLine int
+	Name      string      `json:"name"`
+	FilePath  string      `json:"filePath"`
+	Type      string      `json:"type"`
+	IsConst   bool        `json:"isConst"`
+	IsLet     bool        `json:"isLet"`
+	StartLine int         `json:"startLine"`
+	Doc       *DocComment `json:"doc,omitempty"`
 }
 
 // TypeEntity represents a :Type node in Neo4j (for type aliases, structs, etc.).
 type TypeEntity struct {
-	Name       string
-	FilePath   string
-	Kind       string // "type_alias", "enum", etc.
-	Definition string // The full type definition
-	IsExport   bool
+	Name       string      `json:"name"`
+	FilePath   string      `json:"filePath"`
+	Kind       string      `json:"kind"`       // "type_alias", "enum", etc.
+	Definition string      `json:"definition"` // The full type definition
+	IsExport   bool        `json:"isExport"`
+	Doc        *DocComment `json:"doc,omitempty"`
 }
 
 // InterfaceEntity represents an :Interface node in Neo4j.
 type InterfaceEntity struct {
-	Name       string
-	FilePath   string
-	IsExport   bool
-	Properties []string // List of property names
+	Name       string      `json:"name"`
+	FilePath   string      `json:"filePath"`
+	IsExport   bool        `json:"isExport"`
+	Properties []string    `json:"properties,omitempty"` // List of property names
+	Doc        *DocComment `json:"doc,omitempty"`
 }
 
 // ClassEntity represents a :Class node in Neo4j.
 type ClassEntity struct {
-	Name       string
-	FilePath   string
-	StartLine  int
-	EndLine    int
-	IsExport   bool
-	IsAbstract bool
-	Methods    []string // List of method names
+	ID            string      `json:"id"` // Unique key within the project, see EntityID
+	Name          string      `json:"name"`
+	QualifiedName string      `json:"qualifiedName"` // Name prefixed by its enclosing scopes
+	FilePath      string      `json:"filePath"`
+	StartLine     int         `json:"startLine"`
+	EndLine       int         `json:"endLine"`
+	IsExport      bool        `json:"isExport"`
+	IsAbstract    bool        `json:"isAbstract"`
+	Methods       []string    `json:"methods,omitempty"` // List of method names
+	Doc           *DocComment `json:"doc,omitempty"`
+}
+
+// MemberEntity represents a :Method or :Property node declared in a class.
+type MemberEntity struct {
+	ID         string `json:"id"` // Unique key within the project; methods share the ID of their Function
+	Name       string `json:"name"`
+	Kind       string `json:"kind"`    // "method", "constructor", "getter", "setter" or "property"
+	ClassID    string `json:"classId"` // ID of the declaring class
+	ClassName  string `json:"className"`
+	FilePath   string `json:"filePath"`
+	StartLine  int    `json:"startLine"`
+	EndLine    int    `json:"endLine"`
+	Type       string `json:"type"`       // Declared type of a property
+	Visibility string `json:"visibility"` // "public", "private" or "protected"
+	IsStatic   bool   `json:"isStatic"`
+	IsAbstract bool   `json:"isAbstract"`
+	IsReadonly bool   `json:"isReadonly"`
+}
+
+// label returns the node label of m: :Property for properties, :Method for
+// methods, constructors and accessors.
+func (m MemberEntity) label() string {
+	if m.Kind == "property" {
+		return "Property"
+	}
+	return "Method"
+}
+
+// DecoratorEntity represents a :Decorator node: one decorator applied to a
+// class, a class member or a parameter of a method, linked from the decorated
+// :Class, :Method or :Property by DECORATED_BY. Parameter decorators hang off
+// the method declaring the parameter.
+type DecoratorEntity struct {
+	ID         string   `json:"id"`                  // Target ID followed by @Name, or (parameter)@Name for parameters
+	Name       string   `json:"name"`                // Decorator as written without @ and arguments, e.g. Get or ng.Input
+	Arguments  []string `json:"arguments,omitempty"` // String literals unquoted, other arguments as written
+	TargetKind string   `json:"targetKind"`          // "class", "method", "property" or "parameter"
+	TargetID   string   `json:"targetId"`            // ID of the decorated class or member
+	TargetName string   `json:"targetName"`          // Name of the decorated class or member
+	TargetLine int      `json:"targetLine"`          // Start line of the decorated declaration
+	ClassName  string   `json:"className"`
+	Parameter  string   `json:"parameter,omitempty"` // Name of a decorated parameter
+	FilePath   string   `json:"filePath"`
+	Line       int      `json:"line"`
+}
+
+// targetLabel returns the node label of the entity d decorates.
+func (d DecoratorEntity) targetLabel() string {
+	switch d.TargetKind {
+	case "class":
+		return "Class"
+	case "property":
+		return "Property"
+	default:
+		return "Method"
+	}
 }
 
 // ConstantEntity represents a :Constant node in Neo4j.
 type ConstantEntity struct {
-	Name     string
-	FilePath string
-	Value    string // String representation of the value
+	Name     string      `json:"name"`
+	FilePath string      `json:"filePath"`
+	Value    string      `json:"value"` // String representation of the value
+	Doc      *DocComment `json:"doc,omitempty"`
 }
 
 // JSXElementEntity represents a :JSXElement node in Neo4j.
 type JSXElementEntity struct {
-	TagName             string
-	FilePath            string
-	ContainingComponent string   // The component/function containing this JSX
-	Props               []string // List of prop names
-	Line                int
-	IsCustomComponent   bool // true if TagName starts with uppercase
+	TagName             string   `json:"tagName"`
+	FilePath            string   `json:"filePath"`
+	ContainingComponent string   `json:"containingComponent"` // The component/function containing this JSX
+	Props               []string `json:"props,omitempty"`     // List of prop names
+	Line                int      `json:"line"`
+	IsCustomComponent   bool     `json:"isCustomComponent"`    // true if TagName starts with uppercase
+	ClassNames          []string `json:"classNames,omitempty"` // Classes named in its className, without the dot
+}
+
+// ComponentEntity represents a :Component node in Neo4j: a React function or
+// class component, linked by IMPLEMENTED_BY to the :Function or :Class that
+// declares it. Components wrapped in memo or forwardRef have no declaration of
+// their own and are keyed by file and name.
+type ComponentEntity struct {
+	ID        string `json:"id"` // ID of the implementing function or class, or file#Name
+	Name      string `json:"name"`
+	Kind      string `json:"kind"` // "function" or "class"
+	FilePath  string `json:"filePath"`
+	StartLine int    `json:"startLine"`
+	EndLine   int    `json:"endLine"`
+	PropsType string `json:"propsType"` // Declared props type, e.g. ButtonProps
+}
+
+// implementationLabel returns the label of the node declaring c.
+func (c ComponentEntity) implementationLabel() string {
+	if c.Kind == "class" {
+		return "Class"
+	}
+	return "Function"
+}
+
+// RenderEntity represents a RENDERS relationship from a :Component to a
+// custom component used as a tag in its JSX.
+type RenderEntity struct {
+	ComponentID string `json:"componentId"`
+	Component   string `json:"component"`
+	TagName     string `json:"tagName"`
+	FilePath    string `json:"filePath"`
+	Line        int    `json:"line"`       // First use of the tag in the component
+	TargetFile  string `json:"targetFile"` // File declaring the rendered component, once resolved
+	TargetID    string `json:"targetId"`   // ID of the rendered component, once resolved
+}
+
+// HookUsageEntity represents a USES_HOOK relationship from a :Component to
+// the hook it calls: the :Function of a custom hook declared in the project,
+// or a :Hook node for useState and other library hooks.
+type HookUsageEntity struct {
+	ComponentID string `json:"componentId"`
+	Component   string `json:"component"`
+	Hook        string `json:"hook"` // Hook as called, e.g. useState for React.useState
+	FilePath    string `json:"filePath"`
+	Line        int    `json:"line"`       // First call of the hook in the component
+	TargetFile  string `json:"targetFile"` // File declaring a custom hook
+	TargetID    string `json:"targetId"`   // ID of the custom hook function
+}
+
+// ClassUsageEntity represents a STYLED_BY relationship from a :JSXElement,
+// and from the :Component rendering it, to the :CSSRule of a class named in
+// its className. Classes read from a CSS Modules object, as in styles.header,
+// match only the rules of the imported stylesheet; plain class names match
+// the rules of every stylesheet that is not a CSS Module.
+type ClassUsageEntity struct {
+	ClassName    string `json:"className"` // Without the leading dot
+	TagName      string `json:"tagName"`
+	FilePath     string `json:"filePath"`
+	Line         int    `json:"line"` // Line of the JSX element
+	ComponentID  string `json:"componentId"`
+	Component    string `json:"component"`    // Innermost component around the element, if any
+	Module       string `json:"module"`       // Stylesheet import of a CSS Modules class
+	ResolvedFile string `json:"resolvedFile"` // Project file the stylesheet import resolves to
 }
 
 // CSSRuleEntity represents a :CSSRule node in Neo4j.
 type CSSRuleEntity struct {
-	Selector     string
-	RuleType     string // "class", "id", "element", "attribute", "pseudo", "variable"
-	FilePath     string
-	Line         int
-	PropertyName string // For CSS variables
-	Value        string // For CSS variables
+	Selector     string `json:"selector"`
+	FullSelector string `json:"fullSelector"` // The complex selector naming Selector, SCSS nesting flattened, e.g. ".card .card__title:hover"
+	RuleType     string `json:"ruleType"`     // "class", "id", "element", "attribute", "pseudo", "variable", or for SCSS "placeholder", "mixin", "function"
+	FilePath     string `json:"filePath"`
+	Line         int    `json:"line"`
+	PropertyName string `json:"propertyName"` // For CSS and SCSS variables
+	Value        string `json:"value"`        // For variables; the parameters of SCSS mixins and functions
+}
+
+// StyleReferenceEntity represents an SCSS @include of a mixin, stored as an
+// INCLUDES relationship, or an @extend of a selector, stored as EXTENDS,
+// from the :CSSRule of the rule or mixin containing it to the :CSSRule of
+// its target.
+type StyleReferenceEntity struct {
+	Kind       string `json:"kind"`      // "include" or "extend"
+	Source     string `json:"source"`    // Class, ID or placeholder the rule applies to, or the enclosing mixin; empty outside both
+	Target     string `json:"target"`    // Mixin name, or extended selector such as %message
+	Namespace  string `json:"namespace"` // @use namespace of an included mixin, e.g. m for m.button
+	FilePath   string `json:"filePath"`
+	Line       int    `json:"line"`
+	TargetFile string `json:"targetFile"` // File declaring the target, once resolved
+}
+
+// DocComment is the documentation comment preceding a declaration, such as
+// a JSDoc block, a Python docstring or a Go doc comment.
+type DocComment struct {
+	Description     string     `json:"description"`
+	Params          []DocParam `json:"params,omitempty"`          // @param
+	Returns         string     `json:"returns,omitempty"`         // @returns
+	Throws          []string   `json:"throws,omitempty"`          // @throws
+	Examples        []string   `json:"examples,omitempty"`        // @example
+	See             []string   `json:"see,omitempty"`             // @see
+	Deprecated      bool       `json:"deprecated"`                // @deprecated
+	DeprecationNote string     `json:"deprecationNote,omitempty"` // Text following @deprecated
+}
+
+// DocParam documents a parameter in a DocComment.
+type DocParam struct {
+	Name        string `json:"name"`
+	Type        string `json:"type,omitempty"` // JSDoc {type}, if given
+	Description string `json:"description"`
 }
 
 // Relationship Types
 
 // FunctionCallEntity represents a CALLS relationship.
 type FunctionCallEntity struct {
-	CallerFile     string
-	CallerFunc     string // The function making the call
-	CalledFunc     string // The function being called
-	CallLocation   int    // Line number
-	CallContext    string // For method calls, the object/class context
-	ResolvedTarget string // The resolved function name if found
-	TargetFile     string // The file containing the target function
+	CallerFile     string `json:"callerFile"`
+	CallerFunc     string `json:"callerFunc"`     // The function making the call
+	CalledFunc     string `json:"calledFunc"`     // The function being called
+	CallLocation   int    `json:"callLocation"`   // Line number
+	CallContext    string `json:"callContext"`    // For method calls, the object/class context
+	ResolvedTarget string `json:"resolvedTarget"` // The resolved function name if found
+	TargetFile     string `json:"targetFile"`     // The file containing the target function
+	CallerID       string `json:"callerId"`       // ID of the calling function, if any
+	TargetID       string `json:"targetId"`       // ID of the resolved function, if any
 }
 
 // TypeUsageEntity represents a USES_TYPE relationship.
 type TypeUsageEntity struct {
-	UsingFile     string
-	UsingEntity   string // The entity using the type (e.g., "function:getName")
-	UsedType      string
-	UsageContext  string // "parameter", "return_type", "variable", "property", etc.
-	UsageLocation int    // Line number
+	UsingFile     string `json:"usingFile"`
+	UsingEntity   string `json:"usingEntity"` // The entity using the type (e.g., "function:getName")
+	UsedType      string `json:"usedType"`
+	UsageContext  string `json:"usageContext"`  // "parameter", "return_type", "variable", "property", etc.
+	UsageLocation int    `json:"usageLocation"` // Line number
 }
 
 // ExtendsEntity represents an EXTENDS relationship.
 type ExtendsEntity struct {
-	ChildName  string
-	ParentName string
-	FilePath   string
+	ChildName  string `json:"childName"`
+	ParentName string `json:"parentName"`
+	FilePath   string `json:"filePath"`
 }
 
 // ImplementsEntity represents an IMPLEMENTS relationship.
 type ImplementsEntity struct {
-	ClassName     string
-	InterfaceName string
-	FilePath      string
+	ClassName     string `json:"className"`
+	InterfaceName string `json:"interfaceName"`
+	FilePath      string `json:"filePath"`
 }
 
 // ReferenceEntity represents a generic REFERENCES relationship.
 type ReferenceEntity struct {
-	SourceFile   string
-	SourceEntity string
-	TargetEntity string
-	RefType      string // "uses", "instantiates", "exports", etc.
-	Line         int
+	SourceFile   string `json:"sourceFile"`
+	SourceEntity string `json:"sourceEntity"`
+	TargetEntity string `json:"targetEntity"`
+	RefType      string `json:"refType"` // "uses", "instantiates", "exports", etc.
+	Line         int    `json:"line"`
+}
+
+// ExportEntity represents one name in the public surface of a TS/JS module,
+// written as an EXPORTS relationship from its :File to the exported
+// declaration. Re-exports and exported imports are followed to the file that
+// declares the name, so barrels link straight to the original definition.
+type ExportEntity struct {
+	Name         string `json:"name"`      // Exported name: "default" for default exports, "*" for export * from
+	LocalName    string `json:"localName"` // Name in this file, or in Module for re-exports; "*" for the whole module
+	FilePath     string `json:"filePath"`
+	Line         int    `json:"line"`
+	Module       string `json:"module"`       // Module re-exported from, if any
+	ResolvedFile string `json:"resolvedFile"` // Project file Module resolves to, if any
+	TargetKind   string `json:"targetKind"`   // "function", "class", "interface", "type", "variable" or "module" once resolved
+	TargetName   string `json:"targetName"`   // Name of the exported declaration
+	TargetFile   string `json:"targetFile"`   // File declaring it, or the re-exported module
+	TargetID     string `json:"targetId"`     // ID of an exported function or class
+}
+
+// target returns the label and key properties of the node e exports, or ""
+// when e resolves to nothing in the project. Unresolved re-exports point at
+// the module they re-export from.
+func (e ExportEntity) target() (string, map[string]any) {
+	switch e.TargetKind {
+	case "function":
+		return "Function", map[string]any{"id": e.TargetID}
+	case "class":
+		return "Class", map[string]any{"id": e.TargetID}
+	case "interface":
+		return "Interface", map[string]any{"name": e.TargetName, "file": e.TargetFile}
+	case "type":
+		return "Type", map[string]any{"name": e.TargetName, "file": e.TargetFile}
+	case "variable":
+		return "Variable", map[string]any{"name": e.TargetName, "file": e.TargetFile}
+	case "module":
+		return "File", map[string]any{"path": e.TargetFile}
+	}
+	if e.ResolvedFile != "" {
+		return "File", map[string]any{"path": e.ResolvedFile}
+	}
+	return "", nil
 }
 
 // Neo4jClient wraps a Bolt driver connected to Aura.
 type Neo4jClient struct {
 	driver neo4j.DriverWithContext
+	tx     neo4j.ManagedTransaction // Set on copies bound to a ReplaceFileEntities transaction
 }
 
-// init loads environment variables from .env (if present).
+// init loads environment variables from .env (if present) and registers the backend.
 func init() {
 	_ = godotenv.Load()
+
+	RegisterBackend("neo4j", "Neo4j", func() (GraphClient, error) {
+		client, err := NewNeo4jClient()
+		if err != nil {
+			return nil, err
+		}
+		return client, nil
+	})
 }
 
 // NewNeo4jClient reads NEO4J_URI, NEO4J_USER, NEO4J_PASS from env and connects.
@@ -182,609 +426,1542 @@ func (c *Neo4jClient) Close(ctx context.Context) error {
 	return c.driver.Close(ctx)
 }
 
-// File Operations
+// write runs fn in a write transaction, joining the bound transaction if any.
+func (c *Neo4jClient) write(ctx context.Context, fn func(tx neo4j.ManagedTransaction) error) error {
+	if c.tx != nil {
+		return fn(c.tx)
+	}
 
-// UpsertFile ensures a :File node exists with the given path and language.
-func (c *Neo4jClient) UpsertFile(ctx context.Context, path, language string) error {
 	session := c.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
 	defer session.Close(ctx)
 
 	_, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
-		cypher := `
-        MERGE (f:File {path: $path})
-        ON CREATE SET f.language = $language, f.created = datetime()
-        ON MATCH SET f.language = $language, f.updated = datetime()
-        `
-		params := map[string]any{
-			"path":     path,
-			"language": language,
-		}
-		_, err := tx.Run(ctx, cypher, params)
-		return nil, err
+		return nil, fn(tx)
 	})
 	return err
 }
 
+// Statements
+//
+// Every write is an UNWIND over $rows so the same cypher serves single
+// upserts (one row) and batched writes (many rows). Rows are deduplicated on
+// the MERGE key so that chunks of one statement never race to create the same
+// node or relationship.
+
+// cypherStatement is a cypher statement applied to each row of rows.
+type cypherStatement struct {
+	action string // Describes the statement in errors, e.g. "upsert functions"
+	cypher string
+	rows   []map[string]any
+}
+
+// run executes s in tx. Statements without rows are skipped.
+func (s cypherStatement) run(ctx context.Context, tx neo4j.ManagedTransaction) error {
+	if len(s.rows) == 0 {
+		return nil
+	}
+	if _, err := tx.Run(ctx, s.cypher, map[string]any{"rows": s.rows}); err != nil {
+		return fmt.Errorf("failed to %s: %w", s.action, err)
+	}
+	return nil
+}
+
+// runStatements runs stmts in order in a single write transaction.
+func (c *Neo4jClient) runStatements(ctx context.Context, stmts ...cypherStatement) error {
+	return c.write(ctx, func(tx neo4j.ManagedTransaction) error {
+		for _, stmt := range stmts {
+			if err := stmt.run(ctx, tx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// distinctRows drops rows whose key columns repeat an earlier row, keeping the
+// position of the first occurrence and the values of the last, as a sequence
+// of single upserts would.
+func distinctRows(rows []map[string]any, keys ...string) []map[string]any {
+	seen := make(map[string]int, len(rows))
+	out := make([]map[string]any, 0, len(rows))
+	parts := make([]string, len(keys))
+	for _, row := range rows {
+		for i, key := range keys {
+			parts[i] = fmt.Sprint(row[key])
+		}
+		id := strings.Join(parts, "\x00")
+		if i, ok := seen[id]; ok {
+			out[i] = row
+			continue
+		}
+		seen[id] = len(out)
+		out = append(out, row)
+	}
+	return out
+}
+
+// File Operations
+
+// UpsertFile ensures a :File node exists with the given path and language.
+func (c *Neo4jClient) UpsertFile(ctx context.Context, path, language string) error {
+	return c.runStatements(ctx, fileStatement([]ParsedFile{{FilePath: path, Language: language}}))
+}
+
+// fileStatement upserts a :File node per parsed file.
+func fileStatement(files []ParsedFile) cypherStatement {
+	rows := make([]map[string]any, 0, len(files))
+	for _, pf := range files {
+		rows = append(rows, map[string]any{
+			"path":     pf.FilePath,
+			"language": pf.Language,
+		})
+	}
+	return cypherStatement{
+		action: "upsert files",
+		cypher: `
+        UNWIND $rows AS row
+        MERGE (f:File {path: row.path})
+        ON CREATE SET f.language = row.language, f.created = datetime()
+        ON MATCH SET f.language = row.language, f.updated = datetime()
+        `,
+		rows: distinctRows(rows, "path"),
+	}
+}
+
 // Function Operations
 
 // UpsertFunction ensures a :Function node exists and creates BELONGS_TO→File.
 func (c *Neo4jClient) UpsertFunction(ctx context.Context, fn FunctionEntity) error {
-	session := c.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
-	defer session.Close(ctx)
+	return c.runStatements(ctx, functionStatement([]FunctionEntity{fn}))
+}
 
-	_, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
-		cypher := `
-        MERGE (func:Function {name: $name, file: $file})
+// functionStatement upserts :Function nodes.
+func functionStatement(fns []FunctionEntity) cypherStatement {
+	rows := make([]map[string]any, 0, len(fns))
+	for _, fn := range fns {
+		rows = append(rows, withDoc(map[string]any{
+			"id":             fn.nodeID(),
+			"name":           fn.Name,
+			"qualifiedName":  fn.QualifiedName,
+			"file":           fn.FilePath,
+			"startLine":      fn.StartLine,
+			"endLine":        fn.EndLine,
+			"signature":      fn.Signature,
+			"parameterNames": fn.parameterNames(),
+			"parameterTypes": fn.parameterTypes(),
+			"returnType":     fn.ReturnType,
+			"typeParameters": fn.typeParameterNames(),
+			"isAsync":        fn.IsAsync,
+			"isGenerator":    fn.IsGenerator,
+			"isExport":       fn.IsExport,
+		}, fn.Doc))
+	}
+	return cypherStatement{
+		action: "upsert functions",
+		cypher: `
+        UNWIND $rows AS row
+        MERGE (func:Function {id: row.id})
         ON CREATE SET 
-            func.startLine = $startLine, 
-            func.endLine = $endLine,
-            func.signature = $signature,
-            func.isAsync = $isAsync,
-            func.isExport = $isExport,
+            func.name = row.name,
+            func.qualifiedName = row.qualifiedName,
+            func.file = row.file,
+            func.startLine = row.startLine, 
+            func.endLine = row.endLine,
+            func.signature = row.signature,
+            func.parameterNames = row.parameterNames,
+            func.parameterTypes = row.parameterTypes,
+            func.returnType = row.returnType,
+            func.typeParameters = row.typeParameters,
+            func.isAsync = row.isAsync,
+            func.isGenerator = row.isGenerator,
+            func.isExport = row.isExport,
             func.created = datetime()
         ON MATCH SET 
-            func.startLine = $startLine, 
-            func.endLine = $endLine,
-            func.signature = $signature,
-            func.isAsync = $isAsync,
-            func.isExport = $isExport,
+            func.name = row.name,
+            func.qualifiedName = row.qualifiedName,
+            func.file = row.file,
+            func.startLine = row.startLine, 
+            func.endLine = row.endLine,
+            func.signature = row.signature,
+            func.parameterNames = row.parameterNames,
+            func.parameterTypes = row.parameterTypes,
+            func.returnType = row.returnType,
+            func.typeParameters = row.typeParameters,
+            func.isAsync = row.isAsync,
+            func.isGenerator = row.isGenerator,
+            func.isExport = row.isExport,
             func.updated = datetime()
-        WITH func
-        MATCH (f:File {path: $file})
+        SET ` + docAssignments("func", "row.") + `
+        WITH func, row
+        MATCH (f:File {path: row.file})
         MERGE (func)-[:BELONGS_TO]->(f)
-        `
-		params := map[string]any{
-			"name":      fn.Name,
-			"file":      fn.FilePath,
-			"startLine": fn.StartLine,
-			"endLine":   fn.EndLine,
-			"signature": fn.Signature,
-			"isAsync":   fn.IsAsync,
-			"isExport":  fn.IsExport,
-		}
-		_, err := tx.Run(ctx, cypher, params)
-		return nil, err
-	})
-	return err
+        `,
+		rows: distinctRows(rows, "id"),
+	}
 }
 
 // Import Operations
 
-// UpsertImport ensures a :Import node exists and creates IMPORTS→File.
+// UpsertImport ensures a :Import node exists, creates File→IMPORTS→Import and
+// File→IMPORTS→File/Package for the resolved module.
 func (c *Neo4jClient) UpsertImport(ctx context.Context, imp ImportEntity) error {
-	session := c.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
-	defer session.Close(ctx)
+	return c.runStatements(ctx, importStatements([]ImportEntity{imp})...)
+}
 
-	_, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
-		cypher := `
-        MERGE (i:Import {module: $module})
-        ON CREATE SET 
-            i.created = datetime()
-        ON MATCH SET 
-            i.updated = datetime()
-        WITH i
-        MATCH (f:File {path: $file})
-        MERGE (f)-[r:IMPORTS]->(i)
-        ON CREATE SET 
-            r.importedNames = $importedNames,
-            r.isDefault = $isDefault,
-            r.isNamespace = $isNamespace
-        `
-		params := map[string]any{
+// importStatements upserts :Import nodes and the IMPORTS relationships to
+// them and to the resolved :File or :Package. Shared target nodes are merged
+// by their own statements first so the relationship statements only match.
+func importStatements(imps []ImportEntity) []cypherStatement {
+	var rows, fileRows, packageRows []map[string]any
+	for _, imp := range imps {
+		row := map[string]any{
 			"module":        imp.Module,
 			"file":          imp.FilePath,
 			"importedNames": imp.ImportedNames,
 			"isDefault":     imp.IsDefault,
 			"isNamespace":   imp.IsNamespace,
+			"isDynamic":     imp.IsDynamic,
+			"resolvedFile":  imp.ResolvedFile,
+			"package":       imp.Package,
 		}
-		_, err := tx.Run(ctx, cypher, params)
-		return nil, err
-	})
-	return err
+		rows = append(rows, row)
+
+		// Link the importing file to the resolved File or external Package
+		switch {
+		case imp.ResolvedFile != "":
+			fileRows = append(fileRows, row)
+		case imp.Package != "":
+			packageRows = append(packageRows, row)
+		}
+	}
+
+	const linkTarget = `
+        WITH t, row
+        MATCH (f:File {path: row.file})
+        MERGE (f)-[r:IMPORTS]->(t)
+        SET r.module = row.module,
+            r.importedNames = row.importedNames,
+            r.isDefault = row.isDefault,
+            r.isNamespace = row.isNamespace,
+            r.isDynamic = row.isDynamic
+        `
+
+	return []cypherStatement{
+		{
+			action: "upsert imports",
+			cypher: `
+            UNWIND $rows AS row
+            MERGE (i:Import {module: row.module})
+            ON CREATE SET 
+                i.created = datetime()
+            ON MATCH SET 
+                i.updated = datetime()
+            `,
+			rows: distinctRows(rows, "module"),
+		},
+		{
+			action: "link imports",
+			cypher: `
+            UNWIND $rows AS row
+            MATCH (i:Import {module: row.module})
+            MATCH (f:File {path: row.file})
+            MERGE (f)-[r:IMPORTS]->(i)
+            ON CREATE SET 
+                r.importedNames = row.importedNames,
+                r.isDefault = row.isDefault,
+                r.isNamespace = row.isNamespace,
+                r.isDynamic = row.isDynamic
+            `,
+			rows: distinctRows(rows, "file", "module"),
+		},
+		{
+			action: "upsert imported files",
+			cypher: `
+            UNWIND $rows AS row
+            MERGE (t:File {path: row.resolvedFile})
+            `,
+			rows: distinctRows(fileRows, "resolvedFile"),
+		},
+		{
+			action: "link imported files",
+			cypher: `
+            UNWIND $rows AS row
+            MATCH (t:File {path: row.resolvedFile})` + linkTarget,
+			rows: distinctRows(fileRows, "file", "resolvedFile"),
+		},
+		{
+			action: "upsert packages",
+			cypher: `
+            UNWIND $rows AS row
+            MERGE (t:Package {name: row.package})
+            ON CREATE SET t.created = datetime()
+            `,
+			rows: distinctRows(packageRows, "package"),
+		},
+		{
+			action: "link packages",
+			cypher: `
+            UNWIND $rows AS row
+            MATCH (t:Package {name: row.package})` + linkTarget,
+			rows: distinctRows(packageRows, "file", "package"),
+		},
+	}
 }
 
 // Variable Operations
 
 // UpsertVariable ensures a :Variable node exists and creates DEFINED_IN→File.
 func (c *Neo4jClient) UpsertVariable(ctx context.Context, variable VariableEntity) error {
-	session := c.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
-	defer session.Close(ctx)
+	return c.runStatements(ctx, variableStatement([]VariableEntity{variable}))
+}
 
-	_, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
-		cypher := `
-        MERGE (v:Variable {name: $name, file: $file})
-        ON CREATE SET 
-            v.type = $type,
-            v.isConst = $isConst,
-            v.isLet = $isLet,
-            v.startLine = $startLine,
-            v.created = datetime()
-        ON MATCH SET 
-            v.type = $type,
-            v.isConst = $isConst,
-            v.isLet = $isLet,
-            v.startLine = $startLine,
-            v.updated = datetime()
-        WITH v
-        MATCH (f:File {path: $file})
-        MERGE (v)-[:DEFINED_IN]->(f)
-        `
-		params := map[string]any{
+// variableStatement upserts :Variable nodes.
+func variableStatement(variables []VariableEntity) cypherStatement {
+	rows := make([]map[string]any, 0, len(variables))
+	for _, variable := range variables {
+		rows = append(rows, withDoc(map[string]any{
 			"name":      variable.Name,
 			"file":      variable.FilePath,
 			"type":      variable.Type,
 			"isConst":   variable.IsConst,
 			"isLet":     variable.IsLet,
 			"startLine": variable.StartLine,
-		}
-		_, err := tx.Run(ctx, cypher, params)
-		return nil, err
-	})
-	return err
+		}, variable.Doc))
+	}
+	return cypherStatement{
+		action: "upsert variables",
+		cypher: `
+        UNWIND $rows AS row
+        MERGE (v:Variable {name: row.name, file: row.file})
+        ON CREATE SET 
+            v.type = row.type,
+            v.isConst = row.isConst,
+            v.isLet = row.isLet,
+            v.startLine = row.startLine,
+            v.created = datetime()
+        ON MATCH SET 
+            v.type = row.type,
+            v.isConst = row.isConst,
+            v.isLet = row.isLet,
+            v.startLine = row.startLine,
+            v.updated = datetime()
+        SET ` + docAssignments("v", "row.") + `
+        WITH v, row
+        MATCH (f:File {path: row.file})
+        MERGE (v)-[:DEFINED_IN]->(f)
+        `,
+		rows: distinctRows(rows, "name", "file"),
+	}
 }
 
 // Type Operations
 
 // UpsertType ensures a :Type node exists and creates BELONGS_TO→File.
 func (c *Neo4jClient) UpsertType(ctx context.Context, typeEntity TypeEntity) error {
-	session := c.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
-	defer session.Close(ctx)
+	return c.runStatements(ctx, typeStatement([]TypeEntity{typeEntity}))
+}
 
-	_, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
-		cypher := `
-        MERGE (t:Type {name: $name, file: $file})
-        ON CREATE SET 
-            t.kind = $kind,
-            t.definition = $definition,
-            t.isExport = $isExport,
-            t.created = datetime()
-        ON MATCH SET 
-            t.kind = $kind,
-            t.definition = $definition,
-            t.isExport = $isExport,
-            t.updated = datetime()
-        WITH t
-        MATCH (f:File {path: $file})
-        MERGE (t)-[:BELONGS_TO]->(f)
-        `
-		params := map[string]any{
+// typeStatement upserts :Type nodes.
+func typeStatement(typeEntities []TypeEntity) cypherStatement {
+	rows := make([]map[string]any, 0, len(typeEntities))
+	for _, typeEntity := range typeEntities {
+		rows = append(rows, withDoc(map[string]any{
 			"name":       typeEntity.Name,
 			"file":       typeEntity.FilePath,
 			"kind":       typeEntity.Kind,
 			"definition": typeEntity.Definition,
 			"isExport":   typeEntity.IsExport,
-		}
-		_, err := tx.Run(ctx, cypher, params)
-		return nil, err
-	})
-	return err
+		}, typeEntity.Doc))
+	}
+	return cypherStatement{
+		action: "upsert types",
+		cypher: `
+        UNWIND $rows AS row
+        MERGE (t:Type {name: row.name, file: row.file})
+        ON CREATE SET 
+            t.kind = row.kind,
+            t.definition = row.definition,
+            t.isExport = row.isExport,
+            t.created = datetime()
+        ON MATCH SET 
+            t.kind = row.kind,
+            t.definition = row.definition,
+            t.isExport = row.isExport,
+            t.updated = datetime()
+        SET ` + docAssignments("t", "row.") + `
+        WITH t, row
+        MATCH (f:File {path: row.file})
+        MERGE (t)-[:BELONGS_TO]->(f)
+        `,
+		rows: distinctRows(rows, "name", "file"),
+	}
 }
 
 // Interface Operations
 
 // UpsertInterface ensures an :Interface node exists and creates BELONGS_TO→File.
 func (c *Neo4jClient) UpsertInterface(ctx context.Context, iface InterfaceEntity) error {
-	session := c.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
-	defer session.Close(ctx)
+	return c.runStatements(ctx, interfaceStatement([]InterfaceEntity{iface}))
+}
 
-	_, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
-		cypher := `
-        MERGE (i:Interface {name: $name, file: $file})
+// interfaceStatement upserts :Interface nodes.
+func interfaceStatement(ifaces []InterfaceEntity) cypherStatement {
+	rows := make([]map[string]any, 0, len(ifaces))
+	for _, iface := range ifaces {
+		rows = append(rows, withDoc(map[string]any{
+			"name":       iface.Name,
+			"file":       iface.FilePath,
+			"isExport":   iface.IsExport,
+			"properties": iface.Properties,
+		}, iface.Doc))
+	}
+	return cypherStatement{
+		action: "upsert interfaces",
+		cypher: `
+        UNWIND $rows AS row
+        MERGE (i:Interface {name: row.name, file: row.file})
         ON CREATE SET 
-            i.isExport = $isExport,
-            i.properties = $properties,
+            i.isExport = row.isExport,
+            i.properties = row.properties,
             i.created = datetime()
         ON MATCH SET 
-            i.isExport = $isExport,
-            i.properties = $properties,
+            i.isExport = row.isExport,
+            i.properties = row.properties,
             i.updated = datetime()
-        WITH i
-        MATCH (f:File {path: $file})
+        SET ` + docAssignments("i", "row.") + `
+        WITH i, row
+        MATCH (f:File {path: row.file})
         MERGE (i)-[:BELONGS_TO]->(f)
-        `
-		params := map[string]any{
-			"name":       iface.Name,
-			"file":       iface.FilePath,
-			"isExport":   iface.IsExport,
-			"properties": iface.Properties,
-		}
-		_, err := tx.Run(ctx, cypher, params)
-		return nil, err
-	})
-	return err
+        `,
+		rows: distinctRows(rows, "name", "file"),
+	}
 }
 
 // Class Operations
 
 // UpsertClass ensures a :Class node exists and creates BELONGS_TO→File.
 func (c *Neo4jClient) UpsertClass(ctx context.Context, class ClassEntity) error {
-	session := c.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
-	defer session.Close(ctx)
+	return c.runStatements(ctx, classStatement([]ClassEntity{class}))
+}
 
-	_, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
-		cypher := `
-        MERGE (c:Class {name: $name, file: $file})
+// classStatement upserts :Class nodes.
+func classStatement(classes []ClassEntity) cypherStatement {
+	rows := make([]map[string]any, 0, len(classes))
+	for _, class := range classes {
+		rows = append(rows, withDoc(map[string]any{
+			"id":            class.nodeID(),
+			"name":          class.Name,
+			"qualifiedName": class.QualifiedName,
+			"file":          class.FilePath,
+			"startLine":     class.StartLine,
+			"endLine":       class.EndLine,
+			"isExport":      class.IsExport,
+			"isAbstract":    class.IsAbstract,
+			"methods":       class.Methods,
+		}, class.Doc))
+	}
+	return cypherStatement{
+		action: "upsert classes",
+		cypher: `
+        UNWIND $rows AS row
+        MERGE (c:Class {id: row.id})
         ON CREATE SET 
-            c.startLine = $startLine,
-            c.endLine = $endLine,
-            c.isExport = $isExport,
-            c.isAbstract = $isAbstract,
-            c.methods = $methods,
+            c.name = row.name,
+            c.qualifiedName = row.qualifiedName,
+            c.file = row.file,
+            c.startLine = row.startLine,
+            c.endLine = row.endLine,
+            c.isExport = row.isExport,
+            c.isAbstract = row.isAbstract,
+            c.methods = row.methods,
             c.created = datetime()
         ON MATCH SET 
-            c.startLine = $startLine,
-            c.endLine = $endLine,
-            c.isExport = $isExport,
-            c.isAbstract = $isAbstract,
-            c.methods = $methods,
+            c.name = row.name,
+            c.qualifiedName = row.qualifiedName,
+            c.file = row.file,
+            c.startLine = row.startLine,
+            c.endLine = row.endLine,
+            c.isExport = row.isExport,
+            c.isAbstract = row.isAbstract,
+            c.methods = row.methods,
             c.updated = datetime()
-        WITH c
-        MATCH (f:File {path: $file})
+        SET ` + docAssignments("c", "row.") + `
+        WITH c, row
+        MATCH (f:File {path: row.file})
         MERGE (c)-[:BELONGS_TO]->(f)
-        `
-		params := map[string]any{
-			"name":       class.Name,
-			"file":       class.FilePath,
-			"startLine":  class.StartLine,
-			"endLine":    class.EndLine,
-			"isExport":   class.IsExport,
-			"isAbstract": class.IsAbstract,
-			"methods":    class.Methods,
-		}
-		_, err := tx.Run(ctx, cypher, params)
-		return nil, err
-	})
-	return err
+        `,
+		rows: distinctRows(rows, "id"),
+	}
+}
+
+// Member Operations
+
+// UpsertMember ensures a :Method or :Property node exists, creates
+// Class→HAS_MEMBER and, for members with a body, IMPLEMENTED_BY→Function.
+func (c *Neo4jClient) UpsertMember(ctx context.Context, member MemberEntity) error {
+	return c.runStatements(ctx, memberStatements([]MemberEntity{member})...)
+}
+
+// memberStatements upsert :Method and :Property nodes, one statement per label.
+func memberStatements(members []MemberEntity) []cypherStatement {
+	rows := map[string][]map[string]any{}
+	for _, m := range members {
+		label := m.label()
+		rows[label] = append(rows[label], map[string]any{
+			"id":         m.nodeID(),
+			"name":       m.Name,
+			"kind":       m.Kind,
+			"classId":    m.ClassID,
+			"className":  m.ClassName,
+			"file":       m.FilePath,
+			"startLine":  m.StartLine,
+			"endLine":    m.EndLine,
+			"type":       m.Type,
+			"visibility": m.Visibility,
+			"isStatic":   m.IsStatic,
+			"isAbstract": m.IsAbstract,
+			"isReadonly": m.IsReadonly,
+		})
+	}
+
+	var stmts []cypherStatement
+	for _, label := range []string{"Method", "Property"} {
+		stmts = append(stmts, cypherStatement{
+			action: "upsert " + strings.ToLower(label) + " members",
+			cypher: fmt.Sprintf(`
+        UNWIND $rows AS row
+        MERGE (m:%s {id: row.id})
+        ON CREATE SET 
+            m.name = row.name,
+            m.kind = row.kind,
+            m.className = row.className,
+            m.file = row.file,
+            m.startLine = row.startLine,
+            m.endLine = row.endLine,
+            m.type = row.type,
+            m.visibility = row.visibility,
+            m.isStatic = row.isStatic,
+            m.isAbstract = row.isAbstract,
+            m.isReadonly = row.isReadonly,
+            m.created = datetime()
+        ON MATCH SET 
+            m.name = row.name,
+            m.kind = row.kind,
+            m.className = row.className,
+            m.file = row.file,
+            m.startLine = row.startLine,
+            m.endLine = row.endLine,
+            m.type = row.type,
+            m.visibility = row.visibility,
+            m.isStatic = row.isStatic,
+            m.isAbstract = row.isAbstract,
+            m.isReadonly = row.isReadonly,
+            m.updated = datetime()
+        WITH m, row
+        MATCH (c:Class {id: row.classId})
+        MERGE (c)-[:HAS_MEMBER]->(m)
+        WITH m, row
+        OPTIONAL MATCH (fn:Function {id: row.id})
+        FOREACH (_ IN CASE WHEN fn IS NOT NULL THEN [1] ELSE [] END |
+            MERGE (m)-[:IMPLEMENTED_BY]->(fn)
+        )
+        `, label),
+			rows: distinctRows(rows[label], "id"),
+		})
+	}
+	return stmts
+}
+
+// Decorator Operations
+
+// UpsertDecorator ensures a :Decorator node exists and creates DECORATED_BY
+// from the class or member it decorates.
+func (c *Neo4jClient) UpsertDecorator(ctx context.Context, decorator DecoratorEntity) error {
+	return c.runStatements(ctx, decoratorStatements([]DecoratorEntity{decorator})...)
+}
+
+// decoratorStatements upsert :Decorator nodes, one statement per target label.
+func decoratorStatements(decorators []DecoratorEntity) []cypherStatement {
+	rows := map[string][]map[string]any{}
+	for _, d := range decorators {
+		label := d.targetLabel()
+		rows[label] = append(rows[label], map[string]any{
+			"id":         d.ID,
+			"name":       d.Name,
+			"arguments":  nonNil(d.Arguments),
+			"targetKind": d.TargetKind,
+			"targetId":   d.TargetID,
+			"parameter":  d.Parameter,
+			"file":       d.FilePath,
+			"line":       d.Line,
+		})
+	}
+
+	var stmts []cypherStatement
+	for _, label := range []string{"Class", "Method", "Property"} {
+		stmts = append(stmts, cypherStatement{
+			action: "upsert " + strings.ToLower(label) + " decorators",
+			cypher: fmt.Sprintf(`
+        UNWIND $rows AS row
+        MATCH (t:%s {id: row.targetId})
+        MERGE (d:Decorator {id: row.id})
+        ON CREATE SET 
+            d.name = row.name,
+            d.arguments = row.arguments,
+            d.targetKind = row.targetKind,
+            d.parameter = row.parameter,
+            d.file = row.file,
+            d.line = row.line,
+            d.created = datetime()
+        ON MATCH SET 
+            d.name = row.name,
+            d.arguments = row.arguments,
+            d.targetKind = row.targetKind,
+            d.parameter = row.parameter,
+            d.file = row.file,
+            d.line = row.line,
+            d.updated = datetime()
+        MERGE (t)-[:DECORATED_BY]->(d)
+        `, label),
+			rows: distinctRows(rows[label], "id"),
+		})
+	}
+	return stmts
 }
 
 // Constant Operations
 
 // UpsertConstant ensures a :Constant node exists and creates DEFINED_IN→File.
 func (c *Neo4jClient) UpsertConstant(ctx context.Context, constant ConstantEntity) error {
-	session := c.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
-	defer session.Close(ctx)
+	return c.runStatements(ctx, constantStatement([]ConstantEntity{constant}))
+}
 
-	_, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
-		cypher := `
-        MERGE (c:Constant {name: $name, file: $file})
+// constantStatement upserts :Constant nodes.
+func constantStatement(constants []ConstantEntity) cypherStatement {
+	rows := make([]map[string]any, 0, len(constants))
+	for _, constant := range constants {
+		rows = append(rows, withDoc(map[string]any{
+			"name":  constant.Name,
+			"file":  constant.FilePath,
+			"value": constant.Value,
+		}, constant.Doc))
+	}
+	return cypherStatement{
+		action: "upsert constants",
+		cypher: `
+        UNWIND $rows AS row
+        MERGE (c:Constant {name: row.name, file: row.file})
         ON CREATE SET 
-            c.value = $value,
+            c.value = row.value,
             c.created = datetime()
         ON MATCH SET 
-            c.value = $value,
+            c.value = row.value,
             c.updated = datetime()
-        WITH c
-        MATCH (f:File {path: $file})
+        SET ` + docAssignments("c", "row.") + `
+        WITH c, row
+        MATCH (f:File {path: row.file})
         MERGE (c)-[:DEFINED_IN]->(f)
-        `
-		params := map[string]any{
-			"name":  constant.Name,
-			"file":  constant.FilePath,
-			"value": constant.Value,
-		}
-		_, err := tx.Run(ctx, cypher, params)
-		return nil, err
-	})
-	return err
+        `,
+		rows: distinctRows(rows, "name", "file"),
+	}
 }
 
 // JSX Operations
 
 // UpsertJSXElement ensures a :JSXElement node exists and creates relationships.
 func (c *Neo4jClient) UpsertJSXElement(ctx context.Context, jsx JSXElementEntity) error {
-	session := c.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
-	defer session.Close(ctx)
-
-	_, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
-		// Determine if it's a custom component
-		jsx.IsCustomComponent = len(jsx.TagName) > 0 && strings.ToUpper(jsx.TagName[:1]) == jsx.TagName[:1]
+	return c.runStatements(ctx, jsxStatement([]JSXElementEntity{jsx}))
+}
 
-		cypher := `
-        MERGE (jsx:JSXElement {tagName: $tagName, file: $file, line: $line})
+// jsxStatement upserts :JSXElement nodes and RENDERS from their component.
+func jsxStatement(elements []JSXElementEntity) cypherStatement {
+	rows := make([]map[string]any, 0, len(elements))
+	for _, jsx := range elements {
+		rows = append(rows, map[string]any{
+			"tagName":             jsx.TagName,
+			"file":                jsx.FilePath,
+			"line":                jsx.Line,
+			"containingComponent": jsx.ContainingComponent,
+			"props":               jsx.Props,
+			"isCustomComponent":   jsx.IsCustomComponent,
+			"classNames":          nonNil(jsx.ClassNames),
+		})
+	}
+	return cypherStatement{
+		action: "upsert JSX elements",
+		cypher: `
+        UNWIND $rows AS row
+        MERGE (jsx:JSXElement {tagName: row.tagName, file: row.file, line: row.line})
         ON CREATE SET 
-            jsx.containingComponent = $containingComponent,
-            jsx.props = $props,
-            jsx.isCustomComponent = $isCustomComponent,
+            jsx.containingComponent = row.containingComponent,
+            jsx.props = row.props,
+            jsx.isCustomComponent = row.isCustomComponent,
+            jsx.classNames = row.classNames,
             jsx.created = datetime()
         ON MATCH SET 
-            jsx.containingComponent = $containingComponent,
-            jsx.props = $props,
-            jsx.isCustomComponent = $isCustomComponent,
+            jsx.containingComponent = row.containingComponent,
+            jsx.props = row.props,
+            jsx.isCustomComponent = row.isCustomComponent,
+            jsx.classNames = row.classNames,
             jsx.updated = datetime()
-        WITH jsx
-        MATCH (f:File {path: $file})
+        WITH jsx, row
+        MATCH (f:File {path: row.file})
         MERGE (jsx)-[:USED_IN]->(f)
-        WITH jsx, f
+        WITH jsx, row
         WHERE jsx.containingComponent IS NOT NULL AND jsx.containingComponent <> ''
-        OPTIONAL MATCH (func:Function {name: jsx.containingComponent, file: $file})
+        OPTIONAL MATCH (func:Function {name: jsx.containingComponent, file: row.file})
         FOREACH (_ IN CASE WHEN func IS NOT NULL THEN [1] ELSE [] END |
             MERGE (func)-[:RENDERS]->(jsx)
         )
-        `
-		params := map[string]any{
-			"tagName":             jsx.TagName,
-			"file":                jsx.FilePath,
-			"line":                jsx.Line,
-			"containingComponent": jsx.ContainingComponent,
-			"props":               jsx.Props,
-			"isCustomComponent":   jsx.IsCustomComponent,
+        `,
+		rows: distinctRows(rows, "tagName", "file", "line"),
+	}
+}
+
+// Component Operations
+
+// UpsertComponent ensures a :Component node exists and creates DEFINED_IN→File
+// and IMPLEMENTED_BY→Function or Class.
+func (c *Neo4jClient) UpsertComponent(ctx context.Context, component ComponentEntity) error {
+	return c.runStatements(ctx, componentStatements([]ComponentEntity{component})...)
+}
+
+// componentStatements upsert :Component nodes, then link them to the function
+// or class implementing them, one statement per label.
+func componentStatements(components []ComponentEntity) []cypherStatement {
+	rows := make([]map[string]any, 0, len(components))
+	implementations := map[string][]map[string]any{}
+	for _, component := range components {
+		rows = append(rows, map[string]any{
+			"id":        component.ID,
+			"name":      component.Name,
+			"kind":      component.Kind,
+			"file":      component.FilePath,
+			"startLine": component.StartLine,
+			"endLine":   component.EndLine,
+			"propsType": component.PropsType,
+		})
+		label := component.implementationLabel()
+		implementations[label] = append(implementations[label], map[string]any{"id": component.ID})
+	}
+
+	stmts := []cypherStatement{{
+		action: "upsert components",
+		cypher: `
+        UNWIND $rows AS row
+        MERGE (c:Component {id: row.id})
+        ON CREATE SET 
+            c.name = row.name,
+            c.kind = row.kind,
+            c.file = row.file,
+            c.startLine = row.startLine,
+            c.endLine = row.endLine,
+            c.propsType = row.propsType,
+            c.created = datetime()
+        ON MATCH SET 
+            c.name = row.name,
+            c.kind = row.kind,
+            c.file = row.file,
+            c.startLine = row.startLine,
+            c.endLine = row.endLine,
+            c.propsType = row.propsType,
+            c.updated = datetime()
+        WITH c, row
+        MATCH (f:File {path: row.file})
+        MERGE (c)-[:DEFINED_IN]->(f)
+        `,
+		rows: distinctRows(rows, "id"),
+	}}
+	for _, label := range []string{"Function", "Class"} {
+		stmts = append(stmts, cypherStatement{
+			action: "link " + strings.ToLower(label) + " components",
+			cypher: fmt.Sprintf(`
+        UNWIND $rows AS row
+        MATCH (c:Component {id: row.id})
+        MATCH (impl:%s {id: row.id})
+        MERGE (c)-[:IMPLEMENTED_BY]->(impl)
+        `, label),
+			rows: distinctRows(implementations[label], "id"),
+		})
+	}
+	return stmts
+}
+
+// UpsertRender creates RENDERS from a component to a component it renders.
+func (c *Neo4jClient) UpsertRender(ctx context.Context, render RenderEntity) error {
+	return c.runStatements(ctx, renderStatement([]RenderEntity{render}))
+}
+
+// renderStatement creates RENDERS between components. Tags that resolve to no
+// component in the project are left to their :JSXElement.
+func renderStatement(renders []RenderEntity) cypherStatement {
+	rows := make([]map[string]any, 0, len(renders))
+	for _, render := range renders {
+		if render.TargetID == "" {
+			continue
 		}
-		_, err := tx.Run(ctx, cypher, params)
-		return nil, err
-	})
-	return err
+		rows = append(rows, map[string]any{
+			"componentId": render.ComponentID,
+			"targetId":    render.TargetID,
+			"tagName":     render.TagName,
+			"line":        render.Line,
+		})
+	}
+	return cypherStatement{
+		action: "upsert renders",
+		cypher: `
+        UNWIND $rows AS row
+        MATCH (c:Component {id: row.componentId})
+        MATCH (t:Component {id: row.targetId})
+        MERGE (c)-[r:RENDERS]->(t)
+        ON CREATE SET 
+            r.tagName = row.tagName,
+            r.line = row.line,
+            r.created = datetime()
+        ON MATCH SET 
+            r.tagName = row.tagName,
+            r.line = row.line,
+            r.updated = datetime()
+        `,
+		rows: distinctRows(rows, "componentId", "targetId"),
+	}
+}
+
+// UpsertHookUsage creates USES_HOOK from a component to the hook it calls.
+func (c *Neo4jClient) UpsertHookUsage(ctx context.Context, usage HookUsageEntity) error {
+	return c.runStatements(ctx, hookUsageStatements([]HookUsageEntity{usage})...)
+}
+
+// hookUsageStatements create USES_HOOK to the :Function of custom hooks
+// declared in the project and to shared :Hook nodes for all others.
+func hookUsageStatements(usages []HookUsageEntity) []cypherStatement {
+	var custom, library []map[string]any
+	for _, usage := range usages {
+		row := map[string]any{
+			"componentId": usage.ComponentID,
+			"hook":        usage.Hook,
+			"targetId":    usage.TargetID,
+			"line":        usage.Line,
+		}
+		if usage.TargetID != "" {
+			custom = append(custom, row)
+		} else {
+			library = append(library, row)
+		}
+	}
+	return []cypherStatement{
+		{
+			action: "upsert custom hook usages",
+			cypher: `
+        UNWIND $rows AS row
+        MATCH (c:Component {id: row.componentId})
+        MATCH (h:Function {id: row.targetId})
+        MERGE (c)-[r:USES_HOOK]->(h)
+        ON CREATE SET 
+            r.line = row.line,
+            r.created = datetime()
+        ON MATCH SET 
+            r.line = row.line,
+            r.updated = datetime()
+        `,
+			rows: distinctRows(custom, "componentId", "targetId"),
+		},
+		{
+			action: "upsert hook usages",
+			cypher: `
+        UNWIND $rows AS row
+        MATCH (c:Component {id: row.componentId})
+        MERGE (h:Hook {name: row.hook})
+        ON CREATE SET h.created = datetime()
+        MERGE (c)-[r:USES_HOOK]->(h)
+        ON CREATE SET 
+            r.line = row.line,
+            r.created = datetime()
+        ON MATCH SET 
+            r.line = row.line,
+            r.updated = datetime()
+        `,
+			rows: distinctRows(library, "componentId", "hook"),
+		},
+	}
 }
 
 // CSS Operations
 
 // UpsertCSSRule ensures a :CSSRule node exists and creates relationships.
 func (c *Neo4jClient) UpsertCSSRule(ctx context.Context, css CSSRuleEntity) error {
-	session := c.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
-	defer session.Close(ctx)
+	return c.runStatements(ctx, cssStatement([]CSSRuleEntity{css}))
+}
 
-	_, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
-		cypher := `
-        MERGE (css:CSSRule {selector: $selector, file: $file})
-        ON CREATE SET 
-            css.ruleType = $ruleType,
-            css.line = $line,
-            css.propertyName = $propertyName,
-            css.value = $value,
-            css.created = datetime()
-        ON MATCH SET 
-            css.ruleType = $ruleType,
-            css.line = $line,
-            css.propertyName = $propertyName,
-            css.value = $value,
-            css.updated = datetime()
-        WITH css
-        MATCH (f:File {path: $file})
-        MERGE (css)-[:DEFINED_IN]->(f)
-        `
-		params := map[string]any{
+// cssStatement upserts :CSSRule nodes.
+func cssStatement(rules []CSSRuleEntity) cypherStatement {
+	rows := make([]map[string]any, 0, len(rules))
+	for _, css := range rules {
+		rows = append(rows, map[string]any{
 			"selector":     css.Selector,
 			"file":         css.FilePath,
+			"fullSelector": css.FullSelector,
 			"ruleType":     css.RuleType,
 			"line":         css.Line,
 			"propertyName": css.PropertyName,
 			"value":        css.Value,
+		})
+	}
+	return cypherStatement{
+		action: "upsert CSS rules",
+		cypher: `
+        UNWIND $rows AS row
+        MERGE (css:CSSRule {selector: row.selector, file: row.file})
+        ON CREATE SET 
+            css.fullSelector = row.fullSelector,
+            css.ruleType = row.ruleType,
+            css.line = row.line,
+            css.propertyName = row.propertyName,
+            css.value = row.value,
+            css.created = datetime()
+        ON MATCH SET 
+            css.fullSelector = row.fullSelector,
+            css.ruleType = row.ruleType,
+            css.line = row.line,
+            css.propertyName = row.propertyName,
+            css.value = row.value,
+            css.updated = datetime()
+        WITH css, row
+        MATCH (f:File {path: row.file})
+        MERGE (css)-[:DEFINED_IN]->(f)
+        `,
+		rows: distinctRows(rows, "selector", "file"),
+	}
+}
+
+// UpsertClassUsage creates STYLED_BY from a JSX element and its component to
+// the :CSSRule nodes of a class it names.
+func (c *Neo4jClient) UpsertClassUsage(ctx context.Context, usage ClassUsageEntity) error {
+	return c.runStatements(ctx, classUsageStatements([]ClassUsageEntity{usage})...)
+}
+
+// classUsageStatements create STYLED_BY to the rules of each class. CSS
+// Modules classes match the rules of the stylesheet they were imported from
+// and are skipped when it was not resolved; other classes match the rules of
+// every stylesheet that is not a CSS Module.
+func classUsageStatements(usages []ClassUsageEntity) []cypherStatement {
+	rows := make([]map[string]any, 0, len(usages))
+	for _, usage := range usages {
+		if usage.Module != "" && usage.ResolvedFile == "" {
+			continue
 		}
-		_, err := tx.Run(ctx, cypher, params)
-		return nil, err
-	})
-	return err
+		rows = append(rows, map[string]any{
+			"tagName":     usage.TagName,
+			"file":        usage.FilePath,
+			"line":        usage.Line,
+			"componentId": usage.ComponentID,
+			"selector":    "." + usage.ClassName,
+			"styleFile":   usage.ResolvedFile,
+		})
+	}
+	var components []map[string]any
+	for _, row := range rows {
+		if row["componentId"] != "" {
+			components = append(components, row)
+		}
+	}
+
+	const rules = `
+        MATCH (css:CSSRule {selector: row.selector})
+        WHERE (row.styleFile = '' AND NOT css.file CONTAINS '.module.') OR css.file = row.styleFile`
+	return []cypherStatement{
+		{
+			action: "upsert JSX element class usages",
+			cypher: `
+        UNWIND $rows AS row
+        MATCH (jsx:JSXElement {tagName: row.tagName, file: row.file, line: row.line})` + rules + `
+        MERGE (jsx)-[r:STYLED_BY]->(css)
+        ON CREATE SET 
+            r.line = row.line,
+            r.created = datetime()
+        ON MATCH SET 
+            r.line = row.line,
+            r.updated = datetime()
+        `,
+			rows: distinctRows(rows, "tagName", "file", "line", "selector", "styleFile"),
+		},
+		{
+			action: "upsert component class usages",
+			cypher: `
+        UNWIND $rows AS row
+        MATCH (c:Component {id: row.componentId})` + rules + `
+        MERGE (c)-[r:STYLED_BY]->(css)
+        ON CREATE SET 
+            r.line = row.line,
+            r.created = datetime()
+        ON MATCH SET 
+            r.line = row.line,
+            r.updated = datetime()
+        `,
+			rows: distinctRows(components, "componentId", "selector", "styleFile"),
+		},
+	}
+}
+
+// UpsertStyleReference creates INCLUDES or EXTENDS between the :CSSRule nodes
+// of an SCSS @include or @extend.
+func (c *Neo4jClient) UpsertStyleReference(ctx context.Context, ref StyleReferenceEntity) error {
+	return c.runStatements(ctx, styleReferenceStatements([]StyleReferenceEntity{ref})...)
+}
+
+// styleReferenceStatements create INCLUDES from rules to the mixins they
+// include and EXTENDS to the selectors they extend. References outside any
+// rule, and those whose target was not found, are skipped.
+func styleReferenceStatements(refs []StyleReferenceEntity) []cypherStatement {
+	var includes, extends []map[string]any
+	for _, ref := range refs {
+		if ref.Source == "" || ref.TargetFile == "" {
+			continue
+		}
+		row := map[string]any{
+			"source":     ref.Source,
+			"file":       ref.FilePath,
+			"target":     ref.Target,
+			"targetFile": ref.TargetFile,
+			"line":       ref.Line,
+		}
+		if ref.Kind == "include" {
+			includes = append(includes, row)
+		} else {
+			extends = append(extends, row)
+		}
+	}
+
+	statement := func(action, relType string, rows []map[string]any) cypherStatement {
+		return cypherStatement{
+			action: action,
+			cypher: `
+        UNWIND $rows AS row
+        MATCH (s:CSSRule {selector: row.source, file: row.file})
+        MATCH (t:CSSRule {selector: row.target, file: row.targetFile})
+        MERGE (s)-[r:` + relType + `]->(t)
+        ON CREATE SET 
+            r.line = row.line,
+            r.created = datetime()
+        ON MATCH SET 
+            r.line = row.line,
+            r.updated = datetime()
+        `,
+			rows: distinctRows(rows, "source", "file", "target", "targetFile"),
+		}
+	}
+	return []cypherStatement{
+		statement("upsert SCSS includes", "INCLUDES", includes),
+		statement("upsert SCSS extends", "EXTENDS", extends),
+	}
 }
 
 // Relationship Operations
 
 // UpsertFunctionCall creates a CALLS relationship between functions.
 func (c *Neo4jClient) UpsertFunctionCall(ctx context.Context, call FunctionCallEntity) error {
-	session := c.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
-	defer session.Close(ctx)
+	return c.runStatements(ctx, callStatements([]FunctionCallEntity{call})...)
+}
 
-	_, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+// callStatements creates CALLS between functions for resolved calls and
+// :UnresolvedCall nodes for the rest.
+func callStatements(calls []FunctionCallEntity) []cypherStatement {
+	var resolved, unresolved []map[string]any
+	for _, call := range calls {
 		// If we have a resolved target, create a direct function-to-function relationship
 		if call.ResolvedTarget != "" && call.TargetFile != "" {
-			cypher := `
-            MATCH (caller:Function {name: $callerFunc, file: $callerFile})
-            MATCH (target:Function {name: $targetFunc, file: $targetFile})
+			resolved = append(resolved, map[string]any{
+				"callerId":     call.callerNodeID(),
+				"targetId":     call.targetNodeID(),
+				"callLocation": call.CallLocation,
+				"callContext":  call.CallContext,
+			})
+		} else {
+			unresolved = append(unresolved, map[string]any{
+				"callerFile":   call.CallerFile,
+				"callerFunc":   call.CallerFunc,
+				"callerId":     call.callerNodeID(),
+				"calledFunc":   call.CalledFunc,
+				"callLocation": call.CallLocation,
+				"callContext":  call.CallContext,
+			})
+		}
+	}
+
+	return []cypherStatement{
+		{
+			action: "upsert calls",
+			cypher: `
+            UNWIND $rows AS row
+            MATCH (caller:Function {id: row.callerId})
+            MATCH (target:Function {id: row.targetId})
             MERGE (caller)-[r:CALLS]->(target)
             ON CREATE SET 
-                r.callLocation = $callLocation,
-                r.callContext = $callContext,
+                r.callLocation = row.callLocation,
+                r.callContext = row.callContext,
                 r.created = datetime()
             ON MATCH SET 
-                r.callLocation = $callLocation,
-                r.callContext = $callContext,
+                r.callLocation = row.callLocation,
+                r.callContext = row.callContext,
                 r.updated = datetime()
-            `
-			params := map[string]any{
-				"callerFunc":   call.CallerFunc,
-				"callerFile":   call.CallerFile,
-				"targetFunc":   call.ResolvedTarget,
-				"targetFile":   call.TargetFile,
-				"callLocation": call.CallLocation,
-				"callContext":  call.CallContext,
-			}
-			_, err := tx.Run(ctx, cypher, params)
-			return nil, err
-		} else {
+            `,
+			rows: distinctRows(resolved, "callerId", "targetId"),
+		},
+		{
 			// Create an unresolved call relationship
-			cypher := `
-            MATCH (f:File {path: $callerFile})
+			action: "upsert unresolved calls",
+			cypher: `
+            UNWIND $rows AS row
+            MATCH (f:File {path: row.callerFile})
             MERGE (call:UnresolvedCall {
-                calledFunc: $calledFunc, 
-                callerFile: $callerFile,
-                callerFunc: $callerFunc,
-                line: $callLocation
+                calledFunc: row.calledFunc, 
+                callerFile: row.callerFile,
+                callerFunc: row.callerFunc,
+                line: row.callLocation
             })
             ON CREATE SET 
-                call.callContext = $callContext,
+                call.callContext = row.callContext,
                 call.created = datetime()
-            WITH f, call
+            WITH f, call, row
             MERGE (f)-[:CONTAINS_CALL]->(call)
-            WITH call
-            WHERE $callerFunc IS NOT NULL AND $callerFunc <> ''
-            OPTIONAL MATCH (caller:Function {name: $callerFunc, file: $callerFile})
+            WITH call, row
+            WHERE row.callerFunc IS NOT NULL AND row.callerFunc <> ''
+            OPTIONAL MATCH (caller:Function {id: row.callerId})
             FOREACH (_ IN CASE WHEN caller IS NOT NULL THEN [1] ELSE [] END |
                 MERGE (caller)-[:MAKES_CALL]->(call)
             )
-            `
-			params := map[string]any{
-				"callerFile":   call.CallerFile,
-				"callerFunc":   call.CallerFunc,
-				"calledFunc":   call.CalledFunc,
-				"callLocation": call.CallLocation,
-				"callContext":  call.CallContext,
-			}
-			_, err := tx.Run(ctx, cypher, params)
-			return nil, err
-		}
-	})
-	return err
+            `,
+			rows: distinctRows(unresolved, "calledFunc", "callerFile", "callerFunc", "callLocation"),
+		},
+	}
 }
 
-// UpsertTypeUsage creates a USES_TYPE relationship.
-func (c *Neo4jClient) UpsertTypeUsage(ctx context.Context, usage TypeUsageEntity) error {
-	session := c.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
-	defer session.Close(ctx)
+// UpsertFunctionTypes creates ACCEPTS and RETURNS from a function to the
+// :Type, :Interface and :Class nodes named in its parameter and return types.
+func (c *Neo4jClient) UpsertFunctionTypes(ctx context.Context, fn FunctionEntity) error {
+	return c.runStatements(ctx, functionTypeStatements([]FunctionEntity{fn})...)
+}
 
-	_, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
-		// Try to match the type first
-		cypher := `
-        MATCH (t:Type {name: $usedType})
-        WITH t
-        MATCH (f:File {path: $usingFile})
-        MERGE (f)-[r:USES_TYPE]->(t)
-        ON CREATE SET 
-            r.context = $context,
-            r.location = $location,
-            r.usingEntity = $usingEntity,
-            r.created = datetime()
-        ON MATCH SET 
-            r.context = $context,
-            r.location = $location,
-            r.usingEntity = $usingEntity,
-            r.updated = datetime()
-        RETURN count(r) as created
-        `
-		params := map[string]any{
-			"usingFile":   usage.UsingFile,
-			"usingEntity": usage.UsingEntity,
-			"usedType":    usage.UsedType,
-			"context":     usage.UsageContext,
-			"location":    usage.UsageLocation,
-		}
-		result, err := tx.Run(ctx, cypher, params)
-		if err != nil {
-			return nil, err
+// functionTypeStatements create ACCEPTS, listing the parameters declared with
+// each type, and RETURNS. Types are matched by name across the project.
+func functionTypeStatements(fns []FunctionEntity) []cypherStatement {
+	var accepts, returns []map[string]any
+	for _, fn := range fns {
+		for _, t := range fn.acceptedTypes() {
+			accepts = append(accepts, map[string]any{
+				"id":         fn.nodeID(),
+				"typeName":   t.name,
+				"parameters": t.parameters,
+			})
 		}
-
-		// Check if any rows were created/matched
-		var created int64
-		if result.Next(ctx) {
-			record := result.Record()
-			if count, ok := record.Get("created"); ok {
-				created = count.(int64)
-			}
+		for _, name := range fn.returnedTypes() {
+			returns = append(returns, map[string]any{
+				"id":       fn.nodeID(),
+				"typeName": name,
+			})
 		}
+	}
 
-		// If no type was matched, also check interfaces
-		if created == 0 {
-			cypher2 := `
-            MATCH (i:Interface {name: $usedType})
-            WITH i
-            MATCH (f:File {path: $usingFile})
-            MERGE (f)-[r:USES_TYPE]->(i)
+	return []cypherStatement{
+		{
+			action: "upsert accepted types",
+			cypher: `
+            UNWIND $rows AS row
+            MATCH (fn:Function {id: row.id})
+            MATCH (t {name: row.typeName})
+            WHERE t:Type OR t:Interface OR t:Class
+            MERGE (fn)-[r:ACCEPTS]->(t)
             ON CREATE SET 
-                r.context = $context,
-                r.location = $location,
-                r.usingEntity = $usingEntity,
+                r.parameters = row.parameters,
                 r.created = datetime()
             ON MATCH SET 
-                r.context = $context,
-                r.location = $location,
-                r.usingEntity = $usingEntity,
+                r.parameters = row.parameters,
                 r.updated = datetime()
-            `
-			_, err2 := tx.Run(ctx, cypher2, params)
-			if err2 != nil {
-				return nil, err2
-			}
-		}
+            `,
+			rows: distinctRows(accepts, "id", "typeName"),
+		},
+		{
+			action: "upsert returned types",
+			cypher: `
+            UNWIND $rows AS row
+            MATCH (fn:Function {id: row.id})
+            MATCH (t {name: row.typeName})
+            WHERE t:Type OR t:Interface OR t:Class
+            MERGE (fn)-[r:RETURNS]->(t)
+            ON CREATE SET r.created = datetime()
+            ON MATCH SET r.updated = datetime()
+            `,
+			rows: distinctRows(returns, "id", "typeName"),
+		},
+	}
+}
 
-		return nil, nil
-	})
-	return err
+// UpsertTypeUsage creates a USES_TYPE relationship.
+func (c *Neo4jClient) UpsertTypeUsage(ctx context.Context, usage TypeUsageEntity) error {
+	return c.runStatements(ctx, typeUsageStatement([]TypeUsageEntity{usage}))
 }
 
-// UpsertExtends creates an EXTENDS relationship.
+// typeUsageStatement creates USES_TYPE to the :Type nodes named by each usage,
+// falling back to :Interface nodes when no type matches.
+func typeUsageStatement(usages []TypeUsageEntity) cypherStatement {
+	rows := make([]map[string]any, 0, len(usages))
+	for _, usage := range usages {
+		rows = append(rows, map[string]any{
+			"usingFile":   usage.UsingFile,
+			"usingEntity": usage.UsingEntity,
+			"usedType":    usage.UsedType,
+			"context":     usage.UsageContext,
+			"location":    usage.UsageLocation,
+		})
+	}
+	return cypherStatement{
+		action: "upsert type usages",
+		cypher: `
+        UNWIND $rows AS row
+        MATCH (f:File {path: row.usingFile})
+        OPTIONAL MATCH (t:Type {name: row.usedType})
+        WITH row, f, collect(t) AS types
+        OPTIONAL MATCH (i:Interface {name: row.usedType})
+        WITH row, f, CASE WHEN size(types) > 0 THEN types ELSE collect(i) END AS targets
+        UNWIND targets AS target
+        MERGE (f)-[r:USES_TYPE]->(target)
+        ON CREATE SET 
+            r.context = row.context,
+            r.location = row.location,
+            r.usingEntity = row.usingEntity,
+            r.created = datetime()
+        ON MATCH SET 
+            r.context = row.context,
+            r.location = row.location,
+            r.usingEntity = row.usingEntity,
+            r.updated = datetime()
+        `,
+		rows: distinctRows(rows, "usingFile", "usedType"),
+	}
+}
+
+// UpsertExtends creates an EXTENDS relationship and OVERRIDES between the
+// methods of the two classes.
 func (c *Neo4jClient) UpsertExtends(ctx context.Context, extends ExtendsEntity) error {
-	session := c.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
-	defer session.Close(ctx)
+	entities := []ExtendsEntity{extends}
+	return c.runStatements(ctx, extendsStatement(entities), overridesStatement(entities))
+}
 
-	_, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
-		cypher := `
-        MATCH (child {name: $childName, file: $file})
+// extendsStatement creates EXTENDS from classes and interfaces to their parents.
+func extendsStatement(extends []ExtendsEntity) cypherStatement {
+	rows := make([]map[string]any, 0, len(extends))
+	for _, e := range extends {
+		rows = append(rows, map[string]any{
+			"childName":  e.ChildName,
+			"parentName": e.ParentName,
+			"file":       e.FilePath,
+		})
+	}
+	return cypherStatement{
+		action: "upsert extends",
+		cypher: `
+        UNWIND $rows AS row
+        MATCH (child {name: row.childName, file: row.file})
         WHERE (child:Class OR child:Interface)
-        WITH child
-        OPTIONAL MATCH (parent:Class {name: $parentName})
-        OPTIONAL MATCH (parentInterface:Interface {name: $parentName})
+        WITH child, row
+        OPTIONAL MATCH (parent:Class {name: row.parentName})
+        OPTIONAL MATCH (parentInterface:Interface {name: row.parentName})
         WITH child, COALESCE(parent, parentInterface) AS parentNode
         WHERE parentNode IS NOT NULL
         MERGE (child)-[r:EXTENDS]->(parentNode)
         ON CREATE SET r.created = datetime()
         ON MATCH SET r.updated = datetime()
-        `
-		params := map[string]any{
-			"childName":  extends.ChildName,
-			"parentName": extends.ParentName,
-			"file":       extends.FilePath,
-		}
-		_, err := tx.Run(ctx, cypher, params)
-		return nil, err
-	})
-	return err
+        `,
+		rows: distinctRows(rows, "childName", "parentName", "file"),
+	}
+}
+
+// overridesStatement creates OVERRIDES from each method of a child class to
+// the method of the same name and kind in its direct parent class.
+func overridesStatement(extends []ExtendsEntity) cypherStatement {
+	rows := make([]map[string]any, 0, len(extends))
+	for _, e := range extends {
+		rows = append(rows, map[string]any{
+			"childName":  e.ChildName,
+			"parentName": e.ParentName,
+			"file":       e.FilePath,
+		})
+	}
+	return cypherStatement{
+		action: "upsert overrides",
+		cypher: `
+        UNWIND $rows AS row
+        MATCH (child:Class {name: row.childName, file: row.file})-[:EXTENDS]->(parent:Class {name: row.parentName})
+        MATCH (child)-[:HAS_MEMBER]->(m:Method)
+        WHERE m.kind <> 'constructor'
+        MATCH (parent)-[:HAS_MEMBER]->(base:Method {name: m.name, kind: m.kind, isStatic: m.isStatic})
+        MERGE (m)-[r:OVERRIDES]->(base)
+        ON CREATE SET r.created = datetime()
+        ON MATCH SET r.updated = datetime()
+        `,
+		rows: distinctRows(rows, "childName", "parentName", "file"),
+	}
 }
 
 // UpsertImplements creates an IMPLEMENTS relationship.
 func (c *Neo4jClient) UpsertImplements(ctx context.Context, implements ImplementsEntity) error {
-	session := c.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
-	defer session.Close(ctx)
+	return c.runStatements(ctx, implementsStatement([]ImplementsEntity{implements}))
+}
 
-	_, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
-		cypher := `
-        MATCH (class:Class {name: $className, file: $file})
-        MATCH (interface:Interface {name: $interfaceName})
+// implementsStatement creates IMPLEMENTS from classes to interfaces.
+func implementsStatement(implements []ImplementsEntity) cypherStatement {
+	rows := make([]map[string]any, 0, len(implements))
+	for _, impl := range implements {
+		rows = append(rows, map[string]any{
+			"className":     impl.ClassName,
+			"interfaceName": impl.InterfaceName,
+			"file":          impl.FilePath,
+		})
+	}
+	return cypherStatement{
+		action: "upsert implements",
+		cypher: `
+        UNWIND $rows AS row
+        MATCH (class:Class {name: row.className, file: row.file})
+        MATCH (interface:Interface {name: row.interfaceName})
         MERGE (class)-[r:IMPLEMENTS]->(interface)
         ON CREATE SET r.created = datetime()
         ON MATCH SET r.updated = datetime()
-        `
-		params := map[string]any{
-			"className":     implements.ClassName,
-			"interfaceName": implements.InterfaceName,
-			"file":          implements.FilePath,
+        `,
+		rows: distinctRows(rows, "className", "interfaceName", "file"),
+	}
+}
+
+// UpsertExport creates an EXPORTS relationship from a file to the node it
+// exports, adding the exported name to the relationship's names.
+func (c *Neo4jClient) UpsertExport(ctx context.Context, export ExportEntity) error {
+	return c.runStatements(ctx, exportStatements([]ExportEntity{export})...)
+}
+
+// exportStatements create EXPORTS from files to the declarations and modules
+// they export, one statement per target label. A node exported under several
+// names, such as a class that is also the default export, gets one
+// relationship listing all of them.
+func exportStatements(exports []ExportEntity) []cypherStatement {
+	rows := map[string][]map[string]any{}
+	patterns := map[string]string{}
+	for _, e := range exports {
+		label, key := e.target()
+		if label == "" {
+			continue
 		}
-		_, err := tx.Run(ctx, cypher, params)
-		return nil, err
-	})
-	return err
+		rows[label] = append(rows[label], map[string]any{
+			"file":   e.FilePath,
+			"name":   e.Name,
+			"target": key,
+		})
+
+		props := make([]string, 0, len(key))
+		for prop := range key {
+			props = append(props, fmt.Sprintf("%s: row.target.%s", prop, prop))
+		}
+		slices.Sort(props)
+		patterns[label] = strings.Join(props, ", ")
+	}
+
+	var stmts []cypherStatement
+	for _, label := range []string{"Function", "Class", "Interface", "Type", "Variable", "File"} {
+		if len(rows[label]) == 0 {
+			continue
+		}
+		stmts = append(stmts, cypherStatement{
+			action: "upsert " + strings.ToLower(label) + " exports",
+			cypher: fmt.Sprintf(`
+        UNWIND $rows AS row
+        MATCH (f:File {path: row.file})
+        MATCH (t:%s {%s})
+        MERGE (f)-[r:EXPORTS]->(t)
+        ON CREATE SET 
+            r.names = [row.name],
+            r.created = datetime()
+        ON MATCH SET 
+            r.names = CASE WHEN row.name IN r.names THEN r.names ELSE r.names + row.name END,
+            r.updated = datetime()
+        `, label, patterns[label]),
+			rows: distinctRows(rows[label], "file", "name"),
+		})
+	}
+	return stmts
 }
 
 // UpsertReference creates a generic REFERENCES relationship.
 func (c *Neo4jClient) UpsertReference(ctx context.Context, ref ReferenceEntity) error {
-	session := c.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
-	defer session.Close(ctx)
+	return c.runStatements(ctx, referenceStatement([]ReferenceEntity{ref}))
+}
 
-	_, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
-		cypher := `
-		MATCH (f:File {path: $sourceFile})
+// referenceStatement upserts :Reference nodes contained by their source file.
+func referenceStatement(refs []ReferenceEntity) cypherStatement {
+	rows := make([]map[string]any, 0, len(refs))
+	for _, ref := range refs {
+		rows = append(rows, map[string]any{
+			"sourceFile":   ref.SourceFile,
+			"sourceEntity": ref.SourceEntity,
+			"targetEntity": ref.TargetEntity,
+			"refType":      ref.RefType,
+			"line":         ref.Line,
+		})
+	}
+	return cypherStatement{
+		action: "upsert references",
+		cypher: `
+		UNWIND $rows AS row
+		MATCH (f:File {path: row.sourceFile})
 		MERGE (ref:Reference {
-			sourceFile: $sourceFile,
-			sourceEntity: $sourceEntity,
-			targetEntity: $targetEntity,
-			refType: $refType
+			sourceFile: row.sourceFile,
+			sourceEntity: row.sourceEntity,
+			targetEntity: row.targetEntity,
+			refType: row.refType
 		})
 		ON CREATE SET 
-			ref.line = $line,
+			ref.line = row.line,
 			ref.created = datetime()
 		ON MATCH SET 
-			ref.line = $line,
+			ref.line = row.line,
 			ref.updated = datetime()
 		WITH f, ref
 		MERGE (f)-[:CONTAINS]->(ref)
-		`
-		params := map[string]any{
-			"sourceFile":   ref.SourceFile,
-			"sourceEntity": ref.SourceEntity,
-			"targetEntity": ref.TargetEntity,
-			"refType":      ref.RefType,
-			"line":         ref.Line,
+		`,
+		rows: distinctRows(rows, "sourceFile", "sourceEntity", "targetEntity", "refType"),
+	}
+}
+
+// parsedFileStatements writes files: every node statement first, then every
+// relationship statement, so edges between the files resolve.
+func parsedFileStatements(files []ParsedFile) []cypherStatement {
+	stmts := []cypherStatement{
+		fileStatement(files),
+		functionStatement(gather(files, func(pf *ParsedFile) []FunctionEntity { return pf.Funcs })),
+		variableStatement(gather(files, func(pf *ParsedFile) []VariableEntity { return pf.Variables })),
+		typeStatement(gather(files, func(pf *ParsedFile) []TypeEntity { return pf.Types })),
+		interfaceStatement(gather(files, func(pf *ParsedFile) []InterfaceEntity { return pf.Interfaces })),
+		classStatement(gather(files, func(pf *ParsedFile) []ClassEntity { return pf.Classes })),
+		constantStatement(gather(files, func(pf *ParsedFile) []ConstantEntity { return pf.Constants })),
+		jsxStatement(gather(files, func(pf *ParsedFile) []JSXElementEntity { return pf.JSXElements })),
+		cssStatement(gather(files, func(pf *ParsedFile) []CSSRuleEntity { return pf.CSSRules })),
+	}
+	stmts = append(stmts, memberStatements(gather(files, func(pf *ParsedFile) []MemberEntity { return pf.Members }))...)
+	stmts = append(stmts, componentStatements(gather(files, func(pf *ParsedFile) []ComponentEntity { return pf.Components }))...)
+	stmts = append(stmts, decoratorStatements(gather(files, func(pf *ParsedFile) []DecoratorEntity { return pf.Decorators }))...)
+	stmts = append(stmts, importStatements(gather(files, func(pf *ParsedFile) []ImportEntity { return pf.Imports }))...)
+
+	stmts = append(stmts, callStatements(gather(files, func(pf *ParsedFile) []FunctionCallEntity { return pf.FunctionCalls }))...)
+	stmts = append(stmts, functionTypeStatements(gather(files, func(pf *ParsedFile) []FunctionEntity { return pf.Funcs }))...)
+	stmts = append(stmts,
+		typeUsageStatement(gather(files, func(pf *ParsedFile) []TypeUsageEntity { return pf.TypeUsages })),
+		extendsStatement(gather(files, func(pf *ParsedFile) []ExtendsEntity { return pf.Extends })),
+		overridesStatement(gather(files, func(pf *ParsedFile) []ExtendsEntity { return pf.Extends })),
+		implementsStatement(gather(files, func(pf *ParsedFile) []ImplementsEntity { return pf.Implements })),
+		referenceStatement(gather(files, func(pf *ParsedFile) []ReferenceEntity { return pf.References })),
+	)
+	stmts = append(stmts, exportStatements(gather(files, func(pf *ParsedFile) []ExportEntity { return pf.Exports }))...)
+	stmts = append(stmts, renderStatement(gather(files, func(pf *ParsedFile) []RenderEntity { return pf.Renders })))
+	stmts = append(stmts, hookUsageStatements(gather(files, func(pf *ParsedFile) []HookUsageEntity { return pf.HookUsages }))...)
+	stmts = append(stmts, classUsageStatements(gather(files, func(pf *ParsedFile) []ClassUsageEntity { return pf.ClassUsages }))...)
+	return append(stmts, styleReferenceStatements(gather(files, func(pf *ParsedFile) []StyleReferenceEntity { return pf.StyleReferences }))...)
+}
+
+// gather concatenates one entity collection across files.
+func gather[T any](files []ParsedFile, collection func(pf *ParsedFile) []T) []T {
+	var all []T
+	for i := range files {
+		all = append(all, collection(&files[i])...)
+	}
+	return all
+}
+
+// File Lifecycle Operations
+
+// DeleteFile removes a :File node and every node and relationship owned by it
+// in a single transaction.
+func (c *Neo4jClient) DeleteFile(ctx context.Context, path string) error {
+	return c.write(ctx, func(tx neo4j.ManagedTransaction) error {
+		// A file with no entities keeps none of its owned nodes
+		for _, stmt := range deleteFileStatements([]ParsedFile{{FilePath: path}}) {
+			if err := stmt.run(ctx, tx); err != nil {
+				return err
+			}
+		}
+		if _, err := tx.Run(ctx, `MATCH (f:File {path: $path}) DETACH DELETE f`, map[string]any{"path": path}); err != nil {
+			return fmt.Errorf("failed to delete file node: %w", err)
 		}
-		_, err := tx.Run(ctx, cypher, params)
-		return nil, err
+		return deleteOrphanImports(ctx, tx)
 	})
-	return err
+}
+
+// ReplaceFileEntities swaps the graph contents of pf.FilePath for pf in a
+// single transaction. Nodes the new parse still defines are updated in place
+// so relationships from other files keep pointing at them.
+func (c *Neo4jClient) ReplaceFileEntities(ctx context.Context, pf ParsedFile) error {
+	files := []ParsedFile{pf}
+	return c.write(ctx, func(tx neo4j.ManagedTransaction) error {
+		for _, stmt := range slices.Concat(deleteFileStatements(files), parsedFileStatements(files)) {
+			if err := stmt.run(ctx, tx); err != nil {
+				return err
+			}
+		}
+		return deleteOrphanImports(ctx, tx)
+	})
+}
+
+// deleteFileStatements remove the relationships leaving each file and its
+// owned nodes, except the owned nodes the parsed file still defines.
+func deleteFileStatements(files []ParsedFile) []cypherStatement {
+	rows := make([]map[string]any, 0, len(files))
+	for i := range files {
+		keys := make(map[string]any, len(fileOwnedNodes))
+		for _, node := range fileOwnedNodes {
+			keys[node.label] = files[i].ownedKeys(node.label)
+		}
+		rows = append(rows, map[string]any{"path": files[i].FilePath, "keys": keys})
+	}
+	rows = distinctRows(rows, "path")
+
+	queries := []string{
+		`MATCH (f:File {path: row.path})-[r]->() DELETE r`,
+		`MATCH (jsx:JSXElement {file: row.path}) DETACH DELETE jsx`,
+		`MATCH (d:Decorator {file: row.path}) DETACH DELETE d`,
+		`MATCH (call:UnresolvedCall {callerFile: row.path}) DETACH DELETE call`,
+		`MATCH (ref:Reference {sourceFile: row.path}) DETACH DELETE ref`,
+	}
+	for _, node := range fileOwnedNodes {
+		queries = append(queries, fmt.Sprintf(`MATCH (n:%s {file: row.path})-[r]->() DELETE r`, node.label))
+	}
+	var stmts []cypherStatement
+	for _, query := range queries {
+		stmts = append(stmts, cypherStatement{
+			action: "delete file relationships",
+			cypher: "UNWIND $rows AS row " + query,
+			rows:   rows,
+		})
+	}
+
+	// Nodes written before their key property existed lack it and are always stale
+	for _, node := range fileOwnedNodes {
+		stmts = append(stmts, cypherStatement{
+			action: fmt.Sprintf("delete %s nodes", node.label),
+			cypher: fmt.Sprintf(`UNWIND $rows AS row MATCH (n:%s {file: row.path}) WHERE NOT coalesce(n.%s, '') IN row.keys.%s DETACH DELETE n`, node.label, node.key, node.label),
+			rows:   rows,
+		})
+	}
+	return stmts
+}
+
+// deleteOrphanImports removes :Import and :Package nodes no file imports anymore.
+func deleteOrphanImports(ctx context.Context, tx neo4j.ManagedTransaction) error {
+	cypher := `
+    MATCH (n)
+    WHERE (n:Import OR n:Package) AND NOT ()-[:IMPORTS]->(n)
+    DELETE n
+    `
+	if _, err := tx.Run(ctx, cypher, nil); err != nil {
+		return fmt.Errorf("failed to delete orphan imports: %w", err)
+	}
+	return nil
 }
 
 // Utility Operations
@@ -796,12 +1973,23 @@ func (c *Neo4jClient) CreateIndexes(ctx context.Context) error {
 
 	indexes := []string{
 		"CREATE INDEX IF NOT EXISTS FOR (f:File) ON (f.path)",
+		"CREATE INDEX IF NOT EXISTS FOR (fn:Function) ON (fn.id)",
 		"CREATE INDEX IF NOT EXISTS FOR (fn:Function) ON (fn.name)",
 		"CREATE INDEX IF NOT EXISTS FOR (fn:Function) ON (fn.file)",
 		"CREATE INDEX IF NOT EXISTS FOR (i:Import) ON (i.module)",
+		"CREATE INDEX IF NOT EXISTS FOR (p:Package) ON (p.name)",
 		"CREATE INDEX IF NOT EXISTS FOR (t:Type) ON (t.name)",
+		"CREATE INDEX IF NOT EXISTS FOR (c:Class) ON (c.id)",
 		"CREATE INDEX IF NOT EXISTS FOR (c:Class) ON (c.name)",
+		"CREATE INDEX IF NOT EXISTS FOR (m:Method) ON (m.id)",
+		"CREATE INDEX IF NOT EXISTS FOR (m:Method) ON (m.name)",
+		"CREATE INDEX IF NOT EXISTS FOR (p:Property) ON (p.id)",
 		"CREATE INDEX IF NOT EXISTS FOR (i:Interface) ON (i.name)",
+		"CREATE INDEX IF NOT EXISTS FOR (d:Decorator) ON (d.id)",
+		"CREATE INDEX IF NOT EXISTS FOR (d:Decorator) ON (d.name)",
+		"CREATE INDEX IF NOT EXISTS FOR (c:Component) ON (c.id)",
+		"CREATE INDEX IF NOT EXISTS FOR (c:Component) ON (c.name)",
+		"CREATE INDEX IF NOT EXISTS FOR (h:Hook) ON (h.name)",
 		"CREATE INDEX IF NOT EXISTS FOR (jsx:JSXElement) ON (jsx.tagName)",
 		"CREATE INDEX IF NOT EXISTS FOR (css:CSSRule) ON (css.selector)",
 		"CREATE INDEX IF NOT EXISTS FOR (uc:UnresolvedCall) ON (uc.calledFunc)",
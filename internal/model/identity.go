@@ -0,0 +1,347 @@
+// internal/model/identity.go
+
+package model
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// EntityID returns the ID of the entity declared as qualifiedName in file,
+// e.g. "src/widget.ts#Widget.render". Qualified names join the enclosing
+// scopes with dots, so methods of different classes and nested functions get
+// distinct IDs that stay stable while the code around them changes.
+func EntityID(file, qualifiedName string) string {
+	return file + "#" + qualifiedName
+}
+
+// AssignIDs sets the ID of every function, class, class member, decorator and
+// component of pf and links each call to the function making it, each
+// decorator to what it decorates and each render and hook usage to its
+// component. Functions sharing a qualified name, such as overloads or
+// redefinitions, are told apart by signature and then by start line; classes
+// and members by start line. Members implemented by a function take its ID,
+// so a :Method and its :Function share a key, and so do components and the
+// function or class declaring them.
+func AssignIDs(pf *ParsedFile) {
+	funcNames := make([]string, len(pf.Funcs))
+	signatures := make([]string, len(pf.Funcs))
+	funcLines := make([]int, len(pf.Funcs))
+	for i, fn := range pf.Funcs {
+		funcNames[i] = qualifiedOrName(fn.QualifiedName, fn.Name)
+		signatures[i] = fn.Signature
+		funcLines[i] = fn.StartLine
+	}
+	for i, name := range disambiguate(funcNames, signatures, funcLines) {
+		pf.Funcs[i].ID = EntityID(pf.Funcs[i].FilePath, name)
+	}
+
+	classNames := make([]string, len(pf.Classes))
+	classLines := make([]int, len(pf.Classes))
+	for i, class := range pf.Classes {
+		classNames[i] = qualifiedOrName(class.QualifiedName, class.Name)
+		classLines[i] = class.StartLine
+	}
+	for i, name := range disambiguate(classNames, nil, classLines) {
+		pf.Classes[i].ID = EntityID(pf.Classes[i].FilePath, name)
+	}
+
+	var unimplemented []int
+	for i := range pf.Members {
+		m := &pf.Members[i]
+		m.ClassID = classID(pf.Classes, m.ClassName, m.StartLine)
+		if m.Kind != "property" {
+			m.ID = implementingID(pf.Funcs, m.Name, m.StartLine)
+		}
+		if m.ID == "" {
+			unimplemented = append(unimplemented, i)
+		}
+	}
+	memberNames := make([]string, len(unimplemented))
+	memberLines := make([]int, len(unimplemented))
+	for n, i := range unimplemented {
+		m := pf.Members[i]
+		memberNames[n] = m.scope() + "." + m.Name
+		memberLines[n] = m.StartLine
+	}
+	for n, name := range disambiguate(memberNames, nil, memberLines) {
+		m := &pf.Members[unimplemented[n]]
+		m.ID = EntityID(m.FilePath, name)
+	}
+
+	decoratorNames := make([]string, len(pf.Decorators))
+	decoratorLines := make([]int, len(pf.Decorators))
+	for i := range pf.Decorators {
+		d := &pf.Decorators[i]
+		if d.TargetKind == "class" {
+			d.TargetID = classID(pf.Classes, d.TargetName, d.TargetLine)
+		} else {
+			d.TargetID = memberID(pf.Members, d.ClassName, d.TargetName, d.TargetLine)
+		}
+		decoratorNames[i] = d.TargetID
+		if d.Parameter != "" {
+			decoratorNames[i] += "(" + d.Parameter + ")"
+		}
+		decoratorNames[i] += "@" + d.Name
+		decoratorLines[i] = d.Line
+	}
+	for i, id := range disambiguate(decoratorNames, nil, decoratorLines) {
+		pf.Decorators[i].ID = id
+	}
+
+	componentIDs := make([]string, len(pf.Components))
+	componentLines := make([]int, len(pf.Components))
+	for i, c := range pf.Components {
+		if c.Kind == "class" {
+			componentIDs[i] = classID(pf.Classes, c.Name, c.StartLine)
+		} else {
+			componentIDs[i] = declaringFuncID(pf.Funcs, c.Name, c.StartLine, c.EndLine)
+		}
+		if componentIDs[i] == "" {
+			componentIDs[i] = EntityID(c.FilePath, c.Name) // Wrapped in memo or forwardRef
+		}
+		componentLines[i] = c.StartLine
+	}
+	for i, id := range disambiguate(componentIDs, nil, componentLines) {
+		pf.Components[i].ID = id
+	}
+	for i := range pf.Renders {
+		r := &pf.Renders[i]
+		r.ComponentID = componentID(pf.Components, r.Component, r.Line)
+	}
+	for i := range pf.HookUsages {
+		h := &pf.HookUsages[i]
+		h.ComponentID = componentID(pf.Components, h.Component, h.Line)
+	}
+	for i := range pf.ClassUsages {
+		u := &pf.ClassUsages[i]
+		u.ComponentID = componentID(pf.Components, u.Component, u.Line)
+	}
+
+	for i := range pf.FunctionCalls {
+		call := &pf.FunctionCalls[i]
+		if call.CallerFunc != "" {
+			call.CallerID = callerID(pf.Funcs, call.CallerFunc, call.CallLocation)
+		}
+	}
+}
+
+// qualifiedOrName falls back to the plain name for entities without scopes.
+func qualifiedOrName(qualifiedName, name string) string {
+	if qualifiedName != "" {
+		return qualifiedName
+	}
+	return name
+}
+
+// disambiguate makes names unique. Colliding names get their suffix appended
+// when it tells them apart, then their line, then a counter as a last resort.
+func disambiguate(names, suffixes []string, lines []int) []string {
+	out := slices.Clone(names)
+
+	if suffixes != nil {
+		for _, group := range collisions(out) {
+			for _, i := range group {
+				out[i] += suffixes[i]
+			}
+		}
+	}
+	for _, group := range collisions(out) {
+		for _, i := range group {
+			out[i] += "@" + strconv.Itoa(lines[i])
+		}
+	}
+	for _, group := range collisions(out) {
+		for n, i := range group[1:] {
+			out[i] += "~" + strconv.Itoa(n+2)
+		}
+	}
+	return out
+}
+
+// collisions returns the indexes of each name that occurs more than once,
+// grouped by name in order of first occurrence.
+func collisions(names []string) [][]int {
+	groups := make(map[string][]int, len(names))
+	var order []string
+	for i, name := range names {
+		if _, seen := groups[name]; !seen {
+			order = append(order, name)
+		}
+		groups[name] = append(groups[name], i)
+	}
+
+	var out [][]int
+	for _, name := range order {
+		if len(groups[name]) > 1 {
+			out = append(out, groups[name])
+		}
+	}
+	return out
+}
+
+// callerID returns the ID of the innermost function named name whose lines
+// contain line, or of the first function of that name when none does.
+func callerID(funcs []FunctionEntity, name string, line int) string {
+	var best *FunctionEntity
+	for i := range funcs {
+		fn := &funcs[i]
+		if fn.Name != name || line < fn.StartLine || line > fn.EndLine {
+			continue
+		}
+		if best == nil || fn.EndLine-fn.StartLine < best.EndLine-best.StartLine {
+			best = fn
+		}
+	}
+	if best != nil {
+		return best.ID
+	}
+	for _, fn := range funcs {
+		if fn.Name == name {
+			return fn.ID
+		}
+	}
+	return ""
+}
+
+// classID returns the ID of the innermost class named name whose lines contain
+// line, or of the first class of that name when none does, as for Go methods
+// declared apart from their struct.
+func classID(classes []ClassEntity, name string, line int) string {
+	var best *ClassEntity
+	for i := range classes {
+		class := &classes[i]
+		if class.Name != name || line < class.StartLine || line > class.EndLine {
+			continue
+		}
+		if best == nil || class.EndLine-class.StartLine < best.EndLine-best.StartLine {
+			best = class
+		}
+	}
+	if best != nil {
+		return best.ID
+	}
+	for _, class := range classes {
+		if class.Name == name {
+			return class.ID
+		}
+	}
+	return ""
+}
+
+// implementingID returns the ID of the function named name starting at line.
+func implementingID(funcs []FunctionEntity, name string, line int) string {
+	for _, fn := range funcs {
+		if fn.Name == name && fn.StartLine == line {
+			return fn.ID
+		}
+	}
+	return ""
+}
+
+// declaringFuncID returns the ID of the outermost function named name
+// starting within lines start to end, as the arrow function assigned to a
+// component's variable does.
+func declaringFuncID(funcs []FunctionEntity, name string, start, end int) string {
+	var best *FunctionEntity
+	for i := range funcs {
+		fn := &funcs[i]
+		if fn.Name != name || fn.StartLine < start || fn.StartLine > end {
+			continue
+		}
+		if best == nil || fn.EndLine-fn.StartLine > best.EndLine-best.StartLine {
+			best = fn
+		}
+	}
+	if best != nil {
+		return best.ID
+	}
+	return ""
+}
+
+// componentID returns the ID of the innermost component named name whose
+// lines contain line.
+func componentID(components []ComponentEntity, name string, line int) string {
+	var best *ComponentEntity
+	for i := range components {
+		c := &components[i]
+		if c.Name != name || line < c.StartLine || line > c.EndLine {
+			continue
+		}
+		if best == nil || c.EndLine-c.StartLine < best.EndLine-best.StartLine {
+			best = c
+		}
+	}
+	if best != nil {
+		return best.ID
+	}
+	return ""
+}
+
+// memberID returns the ID of the member of className named name starting at line.
+func memberID(members []MemberEntity, className, name string, line int) string {
+	for _, m := range members {
+		if m.ClassName == className && m.Name == name && m.StartLine == line {
+			return m.ID
+		}
+	}
+	return ""
+}
+
+// rebaseID moves an ID declared in file from to file to, leaving IDs of other
+// files alone.
+func rebaseID(id, from, to string) string {
+	if rest, ok := strings.CutPrefix(id, from+"#"); ok {
+		return EntityID(to, rest)
+	}
+	return id
+}
+
+// nodeID is the graph key of fn, derived from its name when no ID was assigned.
+func (fn FunctionEntity) nodeID() string {
+	if fn.ID != "" {
+		return fn.ID
+	}
+	return EntityID(fn.FilePath, qualifiedOrName(fn.QualifiedName, fn.Name))
+}
+
+// nodeID is the graph key of class, derived from its name when no ID was assigned.
+func (class ClassEntity) nodeID() string {
+	if class.ID != "" {
+		return class.ID
+	}
+	return EntityID(class.FilePath, qualifiedOrName(class.QualifiedName, class.Name))
+}
+
+// scope is the qualified name of the class declaring m.
+func (m MemberEntity) scope() string {
+	if rest, ok := strings.CutPrefix(m.ClassID, m.FilePath+"#"); ok {
+		return rest
+	}
+	return m.ClassName
+}
+
+// nodeID is the graph key of m, derived from its class when no ID was assigned.
+func (m MemberEntity) nodeID() string {
+	if m.ID != "" {
+		return m.ID
+	}
+	return EntityID(m.FilePath, m.scope()+"."+m.Name)
+}
+
+// callerNodeID is the graph key of the function making call.
+func (call FunctionCallEntity) callerNodeID() string {
+	if call.CallerID != "" {
+		return call.CallerID
+	}
+	return EntityID(call.CallerFile, call.CallerFunc)
+}
+
+// targetNodeID is the graph key of the function call resolves to.
+func (call FunctionCallEntity) targetNodeID() string {
+	if call.TargetID != "" {
+		return call.TargetID
+	}
+	return EntityID(call.TargetFile, call.ResolvedTarget)
+}
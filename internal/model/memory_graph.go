@@ -0,0 +1,1155 @@
+// internal/model/memory_graph.go
+
+package model
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MemoryNode is a node of the in-memory graph. ID is derived from the label
+// and the MERGE key properties, so the same entity always gets the same ID.
+type MemoryNode struct {
+	ID         string         `json:"id"`
+	Label      string         `json:"label"`
+	Properties map[string]any `json:"properties"`
+}
+
+// MemoryRelationship is a relationship of the in-memory graph. Like a Cypher
+// MERGE, there is at most one relationship of a type between two nodes.
+type MemoryRelationship struct {
+	Type       string         `json:"type"`
+	From       string         `json:"from"`
+	To         string         `json:"to"`
+	Properties map[string]any `json:"properties,omitempty"`
+}
+
+// memoryNodeKeys are the MERGE key properties of each label, matching the
+// Neo4j schema.
+var memoryNodeKeys = map[string][]string{
+	"File":           {"path"},
+	"Function":       {"id"},
+	"Import":         {"module"},
+	"Package":        {"name"},
+	"Variable":       {"name", "file"},
+	"Type":           {"name", "file"},
+	"Interface":      {"name", "file"},
+	"Class":          {"id"},
+	"Method":         {"id"},
+	"Property":       {"id"},
+	"Constant":       {"name", "file"},
+	"Decorator":      {"id"},
+	"Component":      {"id"},
+	"Hook":           {"name"},
+	"JSXElement":     {"tagName", "file", "line"},
+	"CSSRule":        {"selector", "file"},
+	"UnresolvedCall": {"calledFunc", "callerFile", "callerFunc", "line"},
+	"Reference":      {"sourceFile", "sourceEntity", "targetEntity", "refType"},
+}
+
+// MemoryClient is a GraphClient that keeps the graph in Go maps. It needs no
+// database, can be saved to and loaded from a JSON file, and serves as the
+// reference for how the other backends merge nodes and relationships.
+type MemoryClient struct {
+	mu    sync.RWMutex
+	graph *memoryGraph
+	path  string // File the graph is saved to on Close, if any
+}
+
+// init registers the backend. MEMORY_GRAPH_FILE, if set, names the file the
+// graph is loaded from and saved to on Close.
+func init() {
+	RegisterBackend("memory", "In-memory graph", func() (GraphClient, error) {
+		path := os.Getenv("MEMORY_GRAPH_FILE")
+		if path == "" {
+			return NewMemoryClient(), nil
+		}
+		client, err := LoadMemoryClient(path)
+		if err != nil {
+			return nil, err
+		}
+		return client, nil
+	})
+}
+
+// NewMemoryClient returns an empty in-memory graph.
+func NewMemoryClient() *MemoryClient {
+	return &MemoryClient{graph: newMemoryGraph()}
+}
+
+// LoadMemoryClient loads the graph saved at path, or starts an empty one if
+// the file does not exist yet. Close saves the graph back to path.
+func LoadMemoryClient(path string) (*MemoryClient, error) {
+	c := NewMemoryClient()
+	c.path = path
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return c, nil // No saved graph yet
+		}
+		return nil, fmt.Errorf("failed to read graph file: %w", err)
+	}
+
+	var saved memorySnapshot
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&saved); err != nil {
+		return nil, fmt.Errorf("failed to decode graph file: %w", err)
+	}
+
+	for _, n := range saved.Nodes {
+		n.Properties = normalizeProperties(n.Properties)
+		c.graph.addNode(&n)
+	}
+	for _, r := range saved.Relationships {
+		if c.graph.nodes[r.From] == nil || c.graph.nodes[r.To] == nil {
+			return nil, fmt.Errorf("graph file has a %s relationship to a missing node", r.Type)
+		}
+		r.Properties = normalizeProperties(r.Properties)
+		c.graph.addRel(&r)
+	}
+	return c, nil
+}
+
+// memorySnapshot is the JSON form of a saved graph.
+type memorySnapshot struct {
+	Nodes         []MemoryNode         `json:"nodes"`
+	Relationships []MemoryRelationship `json:"relationships"`
+}
+
+// Save writes the graph to path as JSON, nodes and relationships sorted so
+// that equal graphs produce identical files.
+func (c *MemoryClient) Save(path string) error {
+	c.mu.RLock()
+	snapshot := memorySnapshot{
+		Nodes:         c.graph.findNodes("", nil),
+		Relationships: c.graph.findRelationships("", "", ""),
+	}
+	c.mu.RUnlock()
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode graph: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write graph file: %w", err)
+	}
+	return nil
+}
+
+// Close saves the graph if it was loaded from a file.
+func (c *MemoryClient) Close(ctx context.Context) error {
+	if c.path == "" {
+		return nil
+	}
+	return c.Save(c.path)
+}
+
+// CreateIndexes is a no-op; lookups by key properties are always indexed.
+func (c *MemoryClient) CreateIndexes(ctx context.Context) error {
+	return nil
+}
+
+// Query Operations
+
+// FindNodes returns the nodes with the given label whose properties equal
+// every entry of props, sorted by ID. An empty label matches every node.
+func (c *MemoryClient) FindNodes(label string, props map[string]any) []MemoryNode {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.graph.findNodes(label, props)
+}
+
+// Relationships returns the relationships matching relType, from and to, where
+// an empty string matches anything, sorted by endpoints and type.
+func (c *MemoryClient) Relationships(relType, from, to string) []MemoryRelationship {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.graph.findRelationships(relType, from, to)
+}
+
+// Counts returns the number of nodes per label and relationships per type.
+func (c *MemoryClient) Counts() (nodes, relationships map[string]int) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	nodes = make(map[string]int)
+	for label, ids := range c.graph.byLabel {
+		if len(ids) > 0 {
+			nodes[label] = len(ids)
+		}
+	}
+	relationships = make(map[string]int)
+	for _, r := range c.graph.rels {
+		relationships[r.Type]++
+	}
+	return nodes, relationships
+}
+
+// Write Operations
+
+// update runs fn on the graph under the write lock.
+func (c *MemoryClient) update(fn func(g *memoryGraph) error) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return fn(c.graph)
+}
+
+// UpsertFile ensures a :File node exists with the given path and language.
+func (c *MemoryClient) UpsertFile(ctx context.Context, path, language string) error {
+	return c.update(func(g *memoryGraph) error { return g.UpsertFile(ctx, path, language) })
+}
+
+// UpsertFunction ensures a :Function node exists and creates BELONGS_TO→File.
+func (c *MemoryClient) UpsertFunction(ctx context.Context, fn FunctionEntity) error {
+	return c.update(func(g *memoryGraph) error { return g.UpsertFunction(ctx, fn) })
+}
+
+// UpsertImport ensures a :Import node exists, creates File→IMPORTS→Import and
+// File→IMPORTS→File/Package for the resolved module.
+func (c *MemoryClient) UpsertImport(ctx context.Context, imp ImportEntity) error {
+	return c.update(func(g *memoryGraph) error { return g.UpsertImport(ctx, imp) })
+}
+
+// UpsertVariable ensures a :Variable node exists and creates DEFINED_IN→File.
+func (c *MemoryClient) UpsertVariable(ctx context.Context, variable VariableEntity) error {
+	return c.update(func(g *memoryGraph) error { return g.UpsertVariable(ctx, variable) })
+}
+
+// UpsertType ensures a :Type node exists and creates BELONGS_TO→File.
+func (c *MemoryClient) UpsertType(ctx context.Context, typeEntity TypeEntity) error {
+	return c.update(func(g *memoryGraph) error { return g.UpsertType(ctx, typeEntity) })
+}
+
+// UpsertInterface ensures an :Interface node exists and creates BELONGS_TO→File.
+func (c *MemoryClient) UpsertInterface(ctx context.Context, iface InterfaceEntity) error {
+	return c.update(func(g *memoryGraph) error { return g.UpsertInterface(ctx, iface) })
+}
+
+// UpsertClass ensures a :Class node exists and creates BELONGS_TO→File.
+func (c *MemoryClient) UpsertClass(ctx context.Context, class ClassEntity) error {
+	return c.update(func(g *memoryGraph) error { return g.UpsertClass(ctx, class) })
+}
+
+// UpsertMember ensures a :Method or :Property node exists, creates
+// Class→HAS_MEMBER and, for members with a body, IMPLEMENTED_BY→Function.
+func (c *MemoryClient) UpsertMember(ctx context.Context, member MemberEntity) error {
+	return c.update(func(g *memoryGraph) error { return g.UpsertMember(ctx, member) })
+}
+
+// UpsertDecorator ensures a :Decorator node exists and creates DECORATED_BY
+// from the class or member it decorates.
+func (c *MemoryClient) UpsertDecorator(ctx context.Context, decorator DecoratorEntity) error {
+	return c.update(func(g *memoryGraph) error { return g.UpsertDecorator(ctx, decorator) })
+}
+
+// UpsertComponent ensures a :Component node exists and creates DEFINED_IN→File
+// and IMPLEMENTED_BY→Function or Class.
+func (c *MemoryClient) UpsertComponent(ctx context.Context, component ComponentEntity) error {
+	return c.update(func(g *memoryGraph) error { return g.UpsertComponent(ctx, component) })
+}
+
+// UpsertConstant ensures a :Constant node exists and creates DEFINED_IN→File.
+func (c *MemoryClient) UpsertConstant(ctx context.Context, constant ConstantEntity) error {
+	return c.update(func(g *memoryGraph) error { return g.UpsertConstant(ctx, constant) })
+}
+
+// UpsertJSXElement ensures a :JSXElement node exists and creates relationships.
+func (c *MemoryClient) UpsertJSXElement(ctx context.Context, jsx JSXElementEntity) error {
+	return c.update(func(g *memoryGraph) error { return g.UpsertJSXElement(ctx, jsx) })
+}
+
+// UpsertCSSRule ensures a :CSSRule node exists and creates relationships.
+func (c *MemoryClient) UpsertCSSRule(ctx context.Context, css CSSRuleEntity) error {
+	return c.update(func(g *memoryGraph) error { return g.UpsertCSSRule(ctx, css) })
+}
+
+// UpsertFunctionCall creates a CALLS relationship between functions.
+func (c *MemoryClient) UpsertFunctionCall(ctx context.Context, call FunctionCallEntity) error {
+	return c.update(func(g *memoryGraph) error { return g.UpsertFunctionCall(ctx, call) })
+}
+
+// UpsertFunctionTypes creates ACCEPTS and RETURNS from a function to the
+// types named in its signature.
+func (c *MemoryClient) UpsertFunctionTypes(ctx context.Context, fn FunctionEntity) error {
+	return c.update(func(g *memoryGraph) error { return g.UpsertFunctionTypes(ctx, fn) })
+}
+
+// UpsertTypeUsage creates a USES_TYPE relationship.
+func (c *MemoryClient) UpsertTypeUsage(ctx context.Context, usage TypeUsageEntity) error {
+	return c.update(func(g *memoryGraph) error { return g.UpsertTypeUsage(ctx, usage) })
+}
+
+// UpsertExtends creates an EXTENDS relationship.
+func (c *MemoryClient) UpsertExtends(ctx context.Context, extends ExtendsEntity) error {
+	return c.update(func(g *memoryGraph) error { return g.UpsertExtends(ctx, extends) })
+}
+
+// UpsertImplements creates an IMPLEMENTS relationship.
+func (c *MemoryClient) UpsertImplements(ctx context.Context, implements ImplementsEntity) error {
+	return c.update(func(g *memoryGraph) error { return g.UpsertImplements(ctx, implements) })
+}
+
+// UpsertReference creates a generic REFERENCES relationship.
+func (c *MemoryClient) UpsertReference(ctx context.Context, ref ReferenceEntity) error {
+	return c.update(func(g *memoryGraph) error { return g.UpsertReference(ctx, ref) })
+}
+
+// UpsertExport creates an EXPORTS relationship from a file to the node it exports.
+func (c *MemoryClient) UpsertExport(ctx context.Context, export ExportEntity) error {
+	return c.update(func(g *memoryGraph) error { return g.UpsertExport(ctx, export) })
+}
+
+// UpsertRender creates RENDERS from a component to a component it renders.
+func (c *MemoryClient) UpsertRender(ctx context.Context, render RenderEntity) error {
+	return c.update(func(g *memoryGraph) error { return g.UpsertRender(ctx, render) })
+}
+
+// UpsertHookUsage creates USES_HOOK from a component to the hook it calls.
+func (c *MemoryClient) UpsertHookUsage(ctx context.Context, usage HookUsageEntity) error {
+	return c.update(func(g *memoryGraph) error { return g.UpsertHookUsage(ctx, usage) })
+}
+
+// UpsertClassUsage creates STYLED_BY from a JSX element and its component to
+// the :CSSRule nodes of a class it names.
+func (c *MemoryClient) UpsertClassUsage(ctx context.Context, usage ClassUsageEntity) error {
+	return c.update(func(g *memoryGraph) error { return g.UpsertClassUsage(ctx, usage) })
+}
+
+// UpsertStyleReference creates INCLUDES or EXTENDS between the :CSSRule nodes
+// of an SCSS @include or @extend.
+func (c *MemoryClient) UpsertStyleReference(ctx context.Context, ref StyleReferenceEntity) error {
+	return c.update(func(g *memoryGraph) error { return g.UpsertStyleReference(ctx, ref) })
+}
+
+// DeleteFile removes a :File node and every node and relationship owned by it.
+func (c *MemoryClient) DeleteFile(ctx context.Context, path string) error {
+	return c.update(func(g *memoryGraph) error {
+		g.deleteFileContents(ParsedFile{FilePath: path})
+		if f := g.node("File", map[string]any{"path": path}); f != nil {
+			g.detachDelete(f.ID)
+		}
+		g.deleteOrphanImports()
+		return nil
+	})
+}
+
+// ReplaceFileEntities swaps the graph contents of pf.FilePath for pf under a
+// single lock. Nodes the new parse still defines are updated in place so
+// relationships from other files keep pointing at them.
+func (c *MemoryClient) ReplaceFileEntities(ctx context.Context, pf ParsedFile) error {
+	return c.update(func(g *memoryGraph) error {
+		g.deleteFileContents(pf)
+		if err := upsertParsedFile(ctx, g, pf); err != nil {
+			return err
+		}
+		g.deleteOrphanImports()
+		return nil
+	})
+}
+
+// memoryGraph holds the nodes and relationships with the indexes used to look
+// them up. It is not safe for concurrent use; MemoryClient guards it.
+type memoryGraph struct {
+	nodes   map[string]*MemoryNode
+	rels    map[string]*MemoryRelationship
+	byLabel map[string]map[string]bool // Label -> node IDs
+	byKey   map[string]map[string]bool // Label, key property and value -> node IDs
+	out     map[string]map[string]bool // Node ID -> keys of relationships leaving it
+	in      map[string]map[string]bool // Node ID -> keys of relationships entering it
+}
+
+func newMemoryGraph() *memoryGraph {
+	return &memoryGraph{
+		nodes:   make(map[string]*MemoryNode),
+		rels:    make(map[string]*MemoryRelationship),
+		byLabel: make(map[string]map[string]bool),
+		byKey:   make(map[string]map[string]bool),
+		out:     make(map[string]map[string]bool),
+		in:      make(map[string]map[string]bool),
+	}
+}
+
+// memoryNodeID derives a node ID from its label and key properties, e.g.
+// Variable{file:"src/a.ts",name:"count"}.
+func memoryNodeID(label string, key map[string]any) string {
+	names := make([]string, 0, len(key))
+	for name := range key {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s:%q", name, fmt.Sprint(key[name])))
+	}
+	return label + "{" + strings.Join(parts, ",") + "}"
+}
+
+// memoryRelKey identifies the relationship of relType from one node to another.
+func memoryRelKey(from, relType, to string) string {
+	return from + "-[" + relType + "]->" + to
+}
+
+// memoryIndexKey is the byKey entry for a key property value.
+func memoryIndexKey(label, prop string, value any) string {
+	return label + "\x00" + prop + "\x00" + fmt.Sprint(value)
+}
+
+// addNode stores n and indexes it by label and key properties.
+func (g *memoryGraph) addNode(n *MemoryNode) {
+	g.nodes[n.ID] = n
+	addToSet(g.byLabel, n.Label, n.ID)
+	for _, prop := range memoryNodeKeys[n.Label] {
+		addToSet(g.byKey, memoryIndexKey(n.Label, prop, n.Properties[prop]), n.ID)
+	}
+}
+
+// addRel stores r and indexes it by its endpoints.
+func (g *memoryGraph) addRel(r *MemoryRelationship) {
+	key := memoryRelKey(r.From, r.Type, r.To)
+	g.rels[key] = r
+	addToSet(g.out, r.From, key)
+	addToSet(g.in, r.To, key)
+}
+
+// node returns the node with the given label and key properties, or nil.
+func (g *memoryGraph) node(label string, key map[string]any) *MemoryNode {
+	return g.nodes[memoryNodeID(label, normalizeProperties(key))]
+}
+
+// mergeNode returns the node with the given label and key properties,
+// creating it if it does not exist.
+func (g *memoryGraph) mergeNode(label string, key map[string]any) *MemoryNode {
+	key = normalizeProperties(key)
+	id := memoryNodeID(label, key)
+	if n := g.nodes[id]; n != nil {
+		return n
+	}
+	n := &MemoryNode{ID: id, Label: label, Properties: key}
+	g.addNode(n)
+	return n
+}
+
+// mergeRel returns the relType relationship between from and to, creating it
+// if it does not exist, and whether it was created.
+func (g *memoryGraph) mergeRel(from *MemoryNode, relType string, to *MemoryNode) (*MemoryRelationship, bool) {
+	if r := g.rels[memoryRelKey(from.ID, relType, to.ID)]; r != nil {
+		return r, false
+	}
+	r := &MemoryRelationship{Type: relType, From: from.ID, To: to.ID, Properties: map[string]any{}}
+	g.addRel(r)
+	return r, true
+}
+
+// setProperties copies props onto properties.
+func setProperties(properties, props map[string]any) {
+	for name, value := range normalizeProperties(props) {
+		properties[name] = value
+	}
+}
+
+// findNodes returns copies of the nodes with label (any if empty) whose
+// properties equal props, sorted by ID.
+func (g *memoryGraph) findNodes(label string, props map[string]any) []MemoryNode {
+	var found []MemoryNode
+	for _, n := range g.match(label, props) {
+		found = append(found, MemoryNode{ID: n.ID, Label: n.Label, Properties: cloneProperties(n.Properties)})
+	}
+	return found
+}
+
+// match returns the nodes with label (any if empty) whose properties equal
+// props, sorted by ID. A key property in props narrows the scan to its index.
+func (g *memoryGraph) match(label string, props map[string]any) []*MemoryNode {
+	props = normalizeProperties(props)
+
+	candidates := g.byLabel[label]
+	for _, prop := range memoryNodeKeys[label] {
+		if value, ok := props[prop]; ok {
+			candidates = g.byKey[memoryIndexKey(label, prop, value)]
+			break
+		}
+	}
+
+	var found []*MemoryNode
+	consider := func(n *MemoryNode) {
+		for name, value := range props {
+			if !reflect.DeepEqual(n.Properties[name], value) {
+				return
+			}
+		}
+		found = append(found, n)
+	}
+	if label == "" {
+		for _, n := range g.nodes {
+			consider(n)
+		}
+	} else {
+		for id := range candidates {
+			consider(g.nodes[id])
+		}
+	}
+
+	sort.Slice(found, func(i, j int) bool { return found[i].ID < found[j].ID })
+	return found
+}
+
+// findRelationships returns copies of the relationships matching relType,
+// from and to (any if empty), sorted by key.
+func (g *memoryGraph) findRelationships(relType, from, to string) []MemoryRelationship {
+	keys := make([]string, 0)
+	switch {
+	case from != "":
+		for key := range g.out[from] {
+			keys = append(keys, key)
+		}
+	case to != "":
+		for key := range g.in[to] {
+			keys = append(keys, key)
+		}
+	default:
+		for key := range g.rels {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var found []MemoryRelationship
+	for _, key := range keys {
+		r := g.rels[key]
+		if (relType != "" && r.Type != relType) || (from != "" && r.From != from) || (to != "" && r.To != to) {
+			continue
+		}
+		found = append(found, MemoryRelationship{Type: r.Type, From: r.From, To: r.To, Properties: cloneProperties(r.Properties)})
+	}
+	return found
+}
+
+// deleteRel removes the relationship stored under key.
+func (g *memoryGraph) deleteRel(key string) {
+	r := g.rels[key]
+	if r == nil {
+		return
+	}
+	delete(g.rels, key)
+	delete(g.out[r.From], key)
+	delete(g.in[r.To], key)
+}
+
+// deleteOutgoing removes every relationship leaving the node.
+func (g *memoryGraph) deleteOutgoing(id string) {
+	for key := range g.out[id] {
+		g.deleteRel(key)
+	}
+}
+
+// detachDelete removes a node and every relationship touching it.
+func (g *memoryGraph) detachDelete(id string) {
+	n := g.nodes[id]
+	if n == nil {
+		return
+	}
+	g.deleteOutgoing(id)
+	for key := range g.in[id] {
+		g.deleteRel(key)
+	}
+	delete(g.out, id)
+	delete(g.in, id)
+	delete(g.nodes, id)
+	delete(g.byLabel[n.Label], id)
+	for _, prop := range memoryNodeKeys[n.Label] {
+		delete(g.byKey[memoryIndexKey(n.Label, prop, n.Properties[prop])], id)
+	}
+}
+
+// deleteFileContents removes the relationships leaving pf's file and its owned
+// nodes, except the owned nodes pf still defines.
+func (g *memoryGraph) deleteFileContents(pf ParsedFile) {
+	path := pf.FilePath
+	if f := g.node("File", map[string]any{"path": path}); f != nil {
+		g.deleteOutgoing(f.ID)
+	}
+	for _, n := range g.match("JSXElement", map[string]any{"file": path}) {
+		g.detachDelete(n.ID)
+	}
+	for _, n := range g.match("Decorator", map[string]any{"file": path}) {
+		g.detachDelete(n.ID)
+	}
+	for _, n := range g.match("UnresolvedCall", map[string]any{"callerFile": path}) {
+		g.detachDelete(n.ID)
+	}
+	for _, n := range g.match("Reference", map[string]any{"sourceFile": path}) {
+		g.detachDelete(n.ID)
+	}
+
+	for _, owned := range fileOwnedNodes {
+		keys := pf.ownedKeys(owned.label)
+		for _, n := range g.match(owned.label, map[string]any{"file": path}) {
+			g.deleteOutgoing(n.ID)
+			if key, _ := n.Properties[owned.key].(string); !slices.Contains(keys, key) {
+				g.detachDelete(n.ID)
+			}
+		}
+	}
+}
+
+// deleteOrphanImports removes :Import and :Package nodes no file imports anymore.
+func (g *memoryGraph) deleteOrphanImports() {
+	for _, label := range []string{"Import", "Package"} {
+		for _, n := range g.match(label, nil) {
+			if len(g.relsOfType(g.in[n.ID], "IMPORTS")) == 0 {
+				g.detachDelete(n.ID)
+			}
+		}
+	}
+}
+
+// relsOfType filters a set of relationship keys down to relType.
+func (g *memoryGraph) relsOfType(keys map[string]bool, relType string) []*MemoryRelationship {
+	var found []*MemoryRelationship
+	for key := range keys {
+		if r := g.rels[key]; r.Type == relType {
+			found = append(found, r)
+		}
+	}
+	return found
+}
+
+// linkToFile creates relType from n to the :File at path, if it exists.
+func (g *memoryGraph) linkToFile(n *MemoryNode, relType, path string) {
+	if f := g.node("File", map[string]any{"path": path}); f != nil {
+		g.mergeRel(n, relType, f)
+	}
+}
+
+// The methods below mirror the Neo4j Cypher of the same name, including which
+// MATCHes must succeed for a relationship to be created.
+
+func (g *memoryGraph) UpsertFile(_ context.Context, path, language string) error {
+	f := g.mergeNode("File", map[string]any{"path": path})
+	setProperties(f.Properties, map[string]any{"language": language})
+	return nil
+}
+
+func (g *memoryGraph) UpsertFunction(_ context.Context, fn FunctionEntity) error {
+	n := g.mergeNode("Function", map[string]any{"id": fn.nodeID()})
+	setProperties(n.Properties, withDoc(map[string]any{
+		"name":           fn.Name,
+		"qualifiedName":  fn.QualifiedName,
+		"file":           fn.FilePath,
+		"startLine":      fn.StartLine,
+		"endLine":        fn.EndLine,
+		"signature":      fn.Signature,
+		"parameterNames": fn.parameterNames(),
+		"parameterTypes": fn.parameterTypes(),
+		"returnType":     fn.ReturnType,
+		"typeParameters": fn.typeParameterNames(),
+		"isAsync":        fn.IsAsync,
+		"isGenerator":    fn.IsGenerator,
+		"isExport":       fn.IsExport,
+	}, fn.Doc))
+	g.linkToFile(n, "BELONGS_TO", fn.FilePath)
+	return nil
+}
+
+func (g *memoryGraph) UpsertImport(_ context.Context, imp ImportEntity) error {
+	i := g.mergeNode("Import", map[string]any{"module": imp.Module})
+	f := g.node("File", map[string]any{"path": imp.FilePath})
+	if f != nil {
+		if r, created := g.mergeRel(f, "IMPORTS", i); created {
+			setProperties(r.Properties, map[string]any{
+				"importedNames": imp.ImportedNames,
+				"isDefault":     imp.IsDefault,
+				"isNamespace":   imp.IsNamespace,
+				"isDynamic":     imp.IsDynamic,
+			})
+		}
+	}
+
+	// Link the importing file to the resolved File or external Package
+	var target *MemoryNode
+	switch {
+	case imp.ResolvedFile != "":
+		target = g.mergeNode("File", map[string]any{"path": imp.ResolvedFile})
+	case imp.Package != "":
+		target = g.mergeNode("Package", map[string]any{"name": imp.Package})
+	}
+	if target != nil && f != nil {
+		r, _ := g.mergeRel(f, "IMPORTS", target)
+		setProperties(r.Properties, map[string]any{
+			"module":        imp.Module,
+			"importedNames": imp.ImportedNames,
+			"isDefault":     imp.IsDefault,
+			"isNamespace":   imp.IsNamespace,
+			"isDynamic":     imp.IsDynamic,
+		})
+	}
+	return nil
+}
+
+func (g *memoryGraph) UpsertVariable(_ context.Context, variable VariableEntity) error {
+	n := g.mergeNode("Variable", map[string]any{"name": variable.Name, "file": variable.FilePath})
+	setProperties(n.Properties, withDoc(map[string]any{
+		"type":      variable.Type,
+		"isConst":   variable.IsConst,
+		"isLet":     variable.IsLet,
+		"startLine": variable.StartLine,
+	}, variable.Doc))
+	g.linkToFile(n, "DEFINED_IN", variable.FilePath)
+	return nil
+}
+
+func (g *memoryGraph) UpsertType(_ context.Context, typeEntity TypeEntity) error {
+	n := g.mergeNode("Type", map[string]any{"name": typeEntity.Name, "file": typeEntity.FilePath})
+	setProperties(n.Properties, withDoc(map[string]any{
+		"kind":       typeEntity.Kind,
+		"definition": typeEntity.Definition,
+		"isExport":   typeEntity.IsExport,
+	}, typeEntity.Doc))
+	g.linkToFile(n, "BELONGS_TO", typeEntity.FilePath)
+	return nil
+}
+
+func (g *memoryGraph) UpsertInterface(_ context.Context, iface InterfaceEntity) error {
+	n := g.mergeNode("Interface", map[string]any{"name": iface.Name, "file": iface.FilePath})
+	setProperties(n.Properties, withDoc(map[string]any{
+		"isExport":   iface.IsExport,
+		"properties": iface.Properties,
+	}, iface.Doc))
+	g.linkToFile(n, "BELONGS_TO", iface.FilePath)
+	return nil
+}
+
+func (g *memoryGraph) UpsertClass(_ context.Context, class ClassEntity) error {
+	n := g.mergeNode("Class", map[string]any{"id": class.nodeID()})
+	setProperties(n.Properties, withDoc(map[string]any{
+		"name":          class.Name,
+		"qualifiedName": class.QualifiedName,
+		"file":          class.FilePath,
+		"startLine":     class.StartLine,
+		"endLine":       class.EndLine,
+		"isExport":      class.IsExport,
+		"isAbstract":    class.IsAbstract,
+		"methods":       class.Methods,
+	}, class.Doc))
+	g.linkToFile(n, "BELONGS_TO", class.FilePath)
+	return nil
+}
+
+func (g *memoryGraph) UpsertMember(_ context.Context, member MemberEntity) error {
+	n := g.mergeNode(member.label(), map[string]any{"id": member.nodeID()})
+	setProperties(n.Properties, map[string]any{
+		"name":       member.Name,
+		"kind":       member.Kind,
+		"className":  member.ClassName,
+		"file":       member.FilePath,
+		"startLine":  member.StartLine,
+		"endLine":    member.EndLine,
+		"type":       member.Type,
+		"visibility": member.Visibility,
+		"isStatic":   member.IsStatic,
+		"isAbstract": member.IsAbstract,
+		"isReadonly": member.IsReadonly,
+	})
+
+	class := g.node("Class", map[string]any{"id": member.ClassID})
+	if class == nil {
+		return nil
+	}
+	g.mergeRel(class, "HAS_MEMBER", n)
+	if fn := g.node("Function", map[string]any{"id": member.nodeID()}); fn != nil {
+		g.mergeRel(n, "IMPLEMENTED_BY", fn)
+	}
+	return nil
+}
+
+func (g *memoryGraph) UpsertDecorator(_ context.Context, decorator DecoratorEntity) error {
+	target := g.node(decorator.targetLabel(), map[string]any{"id": decorator.TargetID})
+	if target == nil {
+		return nil
+	}
+	n := g.mergeNode("Decorator", map[string]any{"id": decorator.ID})
+	setProperties(n.Properties, map[string]any{
+		"name":       decorator.Name,
+		"arguments":  nonNil(decorator.Arguments),
+		"targetKind": decorator.TargetKind,
+		"parameter":  decorator.Parameter,
+		"file":       decorator.FilePath,
+		"line":       decorator.Line,
+	})
+	g.mergeRel(target, "DECORATED_BY", n)
+	return nil
+}
+
+func (g *memoryGraph) UpsertComponent(_ context.Context, component ComponentEntity) error {
+	n := g.mergeNode("Component", map[string]any{"id": component.ID})
+	setProperties(n.Properties, map[string]any{
+		"name":      component.Name,
+		"kind":      component.Kind,
+		"file":      component.FilePath,
+		"startLine": component.StartLine,
+		"endLine":   component.EndLine,
+		"propsType": component.PropsType,
+	})
+	g.linkToFile(n, "DEFINED_IN", component.FilePath)
+	if impl := g.node(component.implementationLabel(), map[string]any{"id": component.ID}); impl != nil {
+		g.mergeRel(n, "IMPLEMENTED_BY", impl)
+	}
+	return nil
+}
+
+func (g *memoryGraph) UpsertConstant(_ context.Context, constant ConstantEntity) error {
+	n := g.mergeNode("Constant", map[string]any{"name": constant.Name, "file": constant.FilePath})
+	setProperties(n.Properties, withDoc(map[string]any{"value": constant.Value}, constant.Doc))
+	g.linkToFile(n, "DEFINED_IN", constant.FilePath)
+	return nil
+}
+
+func (g *memoryGraph) UpsertJSXElement(_ context.Context, jsx JSXElementEntity) error {
+	n := g.mergeNode("JSXElement", map[string]any{"tagName": jsx.TagName, "file": jsx.FilePath, "line": jsx.Line})
+	setProperties(n.Properties, map[string]any{
+		"containingComponent": jsx.ContainingComponent,
+		"props":               jsx.Props,
+		"isCustomComponent":   jsx.IsCustomComponent,
+		"classNames":          jsx.ClassNames,
+	})
+
+	f := g.node("File", map[string]any{"path": jsx.FilePath})
+	if f == nil {
+		return nil
+	}
+	g.mergeRel(n, "USED_IN", f)
+	if jsx.ContainingComponent != "" {
+		for _, fn := range g.match("Function", map[string]any{"name": jsx.ContainingComponent, "file": jsx.FilePath}) {
+			g.mergeRel(fn, "RENDERS", n)
+		}
+	}
+	return nil
+}
+
+func (g *memoryGraph) UpsertCSSRule(_ context.Context, css CSSRuleEntity) error {
+	n := g.mergeNode("CSSRule", map[string]any{"selector": css.Selector, "file": css.FilePath})
+	setProperties(n.Properties, map[string]any{
+		"fullSelector": css.FullSelector,
+		"ruleType":     css.RuleType,
+		"line":         css.Line,
+		"propertyName": css.PropertyName,
+		"value":        css.Value,
+	})
+	g.linkToFile(n, "DEFINED_IN", css.FilePath)
+	return nil
+}
+
+func (g *memoryGraph) UpsertFunctionCall(_ context.Context, call FunctionCallEntity) error {
+	// If we have a resolved target, create a direct function-to-function relationship
+	if call.ResolvedTarget != "" && call.TargetFile != "" {
+		caller := g.node("Function", map[string]any{"id": call.callerNodeID()})
+		target := g.node("Function", map[string]any{"id": call.targetNodeID()})
+		if caller != nil && target != nil {
+			r, _ := g.mergeRel(caller, "CALLS", target)
+			setProperties(r.Properties, map[string]any{
+				"callLocation": call.CallLocation,
+				"callContext":  call.CallContext,
+			})
+		}
+		return nil
+	}
+
+	// Create an unresolved call node
+	f := g.node("File", map[string]any{"path": call.CallerFile})
+	if f == nil {
+		return nil
+	}
+	n := g.mergeNode("UnresolvedCall", map[string]any{
+		"calledFunc": call.CalledFunc,
+		"callerFile": call.CallerFile,
+		"callerFunc": call.CallerFunc,
+		"line":       call.CallLocation,
+	})
+	if _, ok := n.Properties["callContext"]; !ok {
+		setProperties(n.Properties, map[string]any{"callContext": call.CallContext})
+	}
+	g.mergeRel(f, "CONTAINS_CALL", n)
+	if call.CallerFunc != "" {
+		if caller := g.node("Function", map[string]any{"id": call.callerNodeID()}); caller != nil {
+			g.mergeRel(caller, "MAKES_CALL", n)
+		}
+	}
+	return nil
+}
+
+func (g *memoryGraph) UpsertFunctionTypes(_ context.Context, fn FunctionEntity) error {
+	n := g.node("Function", map[string]any{"id": fn.nodeID()})
+	if n == nil {
+		return nil
+	}
+	for _, label := range []string{"Type", "Interface", "Class"} {
+		for _, t := range fn.acceptedTypes() {
+			for _, target := range g.match(label, map[string]any{"name": t.name}) {
+				r, _ := g.mergeRel(n, "ACCEPTS", target)
+				setProperties(r.Properties, map[string]any{"parameters": t.parameters})
+			}
+		}
+		for _, name := range fn.returnedTypes() {
+			for _, target := range g.match(label, map[string]any{"name": name}) {
+				g.mergeRel(n, "RETURNS", target)
+			}
+		}
+	}
+	return nil
+}
+
+func (g *memoryGraph) UpsertTypeUsage(_ context.Context, usage TypeUsageEntity) error {
+	f := g.node("File", map[string]any{"path": usage.UsingFile})
+	if f == nil {
+		return nil
+	}
+
+	// Link to types of that name, or to interfaces if no type matches
+	targets := g.match("Type", map[string]any{"name": usage.UsedType})
+	if len(targets) == 0 {
+		targets = g.match("Interface", map[string]any{"name": usage.UsedType})
+	}
+	for _, target := range targets {
+		r, _ := g.mergeRel(f, "USES_TYPE", target)
+		setProperties(r.Properties, map[string]any{
+			"context":     usage.UsageContext,
+			"location":    usage.UsageLocation,
+			"usingEntity": usage.UsingEntity,
+		})
+	}
+	return nil
+}
+
+func (g *memoryGraph) UpsertExtends(_ context.Context, extends ExtendsEntity) error {
+	parents := g.match("Class", map[string]any{"name": extends.ParentName})
+	if len(parents) == 0 {
+		parents = g.match("Interface", map[string]any{"name": extends.ParentName})
+	}
+	for _, label := range []string{"Class", "Interface"} {
+		for _, child := range g.match(label, map[string]any{"name": extends.ChildName, "file": extends.FilePath}) {
+			for _, parent := range parents {
+				g.mergeRel(child, "EXTENDS", parent)
+			}
+		}
+	}
+
+	for _, child := range g.match("Class", map[string]any{"name": extends.ChildName, "file": extends.FilePath}) {
+		for _, parent := range g.match("Class", map[string]any{"name": extends.ParentName}) {
+			g.mergeOverrides(child, parent)
+		}
+	}
+	return nil
+}
+
+// mergeOverrides creates OVERRIDES from each method of child to the method of
+// the same name and kind in parent.
+func (g *memoryGraph) mergeOverrides(child, parent *MemoryNode) {
+	for _, has := range g.relsOfType(g.out[child.ID], "HAS_MEMBER") {
+		m := g.nodes[has.To]
+		if m.Label != "Method" || m.Properties["kind"] == "constructor" {
+			continue
+		}
+		for _, parentHas := range g.relsOfType(g.out[parent.ID], "HAS_MEMBER") {
+			base := g.nodes[parentHas.To]
+			if base.Label == "Method" && base.Properties["name"] == m.Properties["name"] &&
+				base.Properties["kind"] == m.Properties["kind"] && base.Properties["isStatic"] == m.Properties["isStatic"] {
+				g.mergeRel(m, "OVERRIDES", base)
+			}
+		}
+	}
+}
+
+func (g *memoryGraph) UpsertImplements(_ context.Context, implements ImplementsEntity) error {
+	for _, class := range g.match("Class", map[string]any{"name": implements.ClassName, "file": implements.FilePath}) {
+		for _, iface := range g.match("Interface", map[string]any{"name": implements.InterfaceName}) {
+			g.mergeRel(class, "IMPLEMENTS", iface)
+		}
+	}
+	return nil
+}
+
+func (g *memoryGraph) UpsertReference(_ context.Context, ref ReferenceEntity) error {
+	f := g.node("File", map[string]any{"path": ref.SourceFile})
+	if f == nil {
+		return nil
+	}
+	n := g.mergeNode("Reference", map[string]any{
+		"sourceFile":   ref.SourceFile,
+		"sourceEntity": ref.SourceEntity,
+		"targetEntity": ref.TargetEntity,
+		"refType":      ref.RefType,
+	})
+	setProperties(n.Properties, map[string]any{"line": ref.Line})
+	g.mergeRel(f, "CONTAINS", n)
+	return nil
+}
+
+func (g *memoryGraph) UpsertExport(_ context.Context, export ExportEntity) error {
+	label, key := export.target()
+	if label == "" {
+		return nil
+	}
+	f := g.node("File", map[string]any{"path": export.FilePath})
+	target := g.node(label, key)
+	if f == nil || target == nil {
+		return nil
+	}
+	r, _ := g.mergeRel(f, "EXPORTS", target)
+	names, _ := r.Properties["names"].([]any)
+	if !slices.Contains(names, any(export.Name)) {
+		names = append(names, export.Name)
+	}
+	setProperties(r.Properties, map[string]any{"names": names})
+	return nil
+}
+
+func (g *memoryGraph) UpsertRender(_ context.Context, render RenderEntity) error {
+	if render.TargetID == "" {
+		return nil
+	}
+	c := g.node("Component", map[string]any{"id": render.ComponentID})
+	target := g.node("Component", map[string]any{"id": render.TargetID})
+	if c == nil || target == nil {
+		return nil
+	}
+	r, _ := g.mergeRel(c, "RENDERS", target)
+	setProperties(r.Properties, map[string]any{"tagName": render.TagName, "line": render.Line})
+	return nil
+}
+
+func (g *memoryGraph) UpsertHookUsage(_ context.Context, usage HookUsageEntity) error {
+	c := g.node("Component", map[string]any{"id": usage.ComponentID})
+	if c == nil {
+		return nil
+	}
+	var hook *MemoryNode
+	if usage.TargetID != "" {
+		if hook = g.node("Function", map[string]any{"id": usage.TargetID}); hook == nil {
+			return nil
+		}
+	} else {
+		hook = g.mergeNode("Hook", map[string]any{"name": usage.Hook})
+	}
+	r, _ := g.mergeRel(c, "USES_HOOK", hook)
+	setProperties(r.Properties, map[string]any{"line": usage.Line})
+	return nil
+}
+
+func (g *memoryGraph) UpsertClassUsage(_ context.Context, usage ClassUsageEntity) error {
+	if usage.Module != "" && usage.ResolvedFile == "" {
+		return nil
+	}
+	var sources []*MemoryNode
+	if jsx := g.node("JSXElement", map[string]any{"tagName": usage.TagName, "file": usage.FilePath, "line": usage.Line}); jsx != nil {
+		sources = append(sources, jsx)
+	}
+	if usage.ComponentID != "" {
+		if c := g.node("Component", map[string]any{"id": usage.ComponentID}); c != nil {
+			sources = append(sources, c)
+		}
+	}
+
+	for _, css := range g.match("CSSRule", map[string]any{"selector": "." + usage.ClassName}) {
+		file, _ := css.Properties["file"].(string)
+		if usage.Module != "" && file != usage.ResolvedFile || usage.Module == "" && strings.Contains(file, ".module.") {
+			continue
+		}
+		for _, source := range sources {
+			r, _ := g.mergeRel(source, "STYLED_BY", css)
+			setProperties(r.Properties, map[string]any{"line": usage.Line})
+		}
+	}
+	return nil
+}
+
+func (g *memoryGraph) UpsertStyleReference(_ context.Context, ref StyleReferenceEntity) error {
+	if ref.Source == "" || ref.TargetFile == "" {
+		return nil
+	}
+	source := g.node("CSSRule", map[string]any{"selector": ref.Source, "file": ref.FilePath})
+	target := g.node("CSSRule", map[string]any{"selector": ref.Target, "file": ref.TargetFile})
+	if source == nil || target == nil {
+		return nil
+	}
+	relType := "EXTENDS"
+	if ref.Kind == "include" {
+		relType = "INCLUDES"
+	}
+	r, _ := g.mergeRel(source, relType, target)
+	setProperties(r.Properties, map[string]any{"line": ref.Line})
+	return nil
+}
+
+// addToSet adds member to the set stored under key.
+func addToSet(sets map[string]map[string]bool, key, member string) {
+	if sets[key] == nil {
+		sets[key] = make(map[string]bool)
+	}
+	sets[key][member] = true
+}
+
+// normalizeProperties converts property values to the types a saved graph
+// loads back as: int64 for integers, float64 for other numbers and []any for
+// lists. A nil map normalizes to an empty one.
+func normalizeProperties(props map[string]any) map[string]any {
+	normalized := make(map[string]any, len(props))
+	for name, value := range props {
+		normalized[name] = normalizeValue(value)
+	}
+	return normalized
+}
+
+// normalizeValue converts a single property value; see normalizeProperties.
+func normalizeValue(value any) any {
+	switch v := value.(type) {
+	case int:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case float32:
+		return float64(v)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case []string:
+		list := make([]any, len(v))
+		for i, s := range v {
+			list[i] = s
+		}
+		return list
+	case []any:
+		list := make([]any, len(v))
+		for i, item := range v {
+			list[i] = normalizeValue(item)
+		}
+		return list
+	default:
+		return v
+	}
+}
+
+// cloneProperties copies a property map so callers cannot modify the graph.
+func cloneProperties(props map[string]any) map[string]any {
+	clone := make(map[string]any, len(props))
+	for name, value := range props {
+		if list, ok := value.([]any); ok {
+			value = slices.Clone(list)
+		}
+		clone[name] = value
+	}
+	return clone
+}
@@ -0,0 +1,342 @@
+// internal/model/memory_graph_test.go
+
+package model
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// graphInspector reads back what a GraphClient stored. GraphClient only
+// writes, so each backend under test supplies its own reader: the memory
+// backend through FindNodes and Relationships, a database backend through a
+// query.
+type graphInspector interface {
+	// nodeCount returns the number of label nodes whose properties equal
+	// every entry of props.
+	nodeCount(t *testing.T, label string, props map[string]any) int
+
+	// relCount returns the number of relType relationships from a node
+	// matching from to a node matching to.
+	relCount(t *testing.T, from nodeMatch, relType string, to nodeMatch) int
+}
+
+// nodeMatch selects nodes by label and properties for a graphInspector.
+type nodeMatch struct {
+	label string
+	props map[string]any
+}
+
+// graphBackend opens a backend for testGraphConformance.
+type graphBackend struct {
+	// open returns a client of an empty graph.
+	open func(t *testing.T) GraphClient
+
+	// reopen closes client and returns a new client of the graph it wrote.
+	reopen func(t *testing.T, client GraphClient) GraphClient
+
+	// inspect returns a reader of the graph client writes to.
+	inspect func(client GraphClient) graphInspector
+}
+
+// testGraphConformance checks the behavior every GraphClient shares with the
+// Neo4j schema: which properties identify a node, what replacing and deleting
+// a file removes, and that the graph outlives its client.
+func testGraphConformance(t *testing.T, backend graphBackend) {
+	ctx := context.Background()
+
+	t.Run("MergeKeyIdentity", func(t *testing.T) {
+		client := backend.open(t)
+		graph := backend.inspect(client)
+		pf := conformanceFile()
+
+		// Writing the same file twice merges onto the same nodes
+		for i := 0; i < 2; i++ {
+			if err := client.ReplaceFileEntities(ctx, pf); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if n := graph.nodeCount(t, "Function", map[string]any{"name": "render"}); n != 2 {
+			t.Errorf("got %d render functions, want 2, one per class", n)
+		}
+		for _, class := range []string{"Widget", "Panel"} {
+			if n := graph.nodeCount(t, "Function", map[string]any{"qualifiedName": class + ".render"}); n != 1 {
+				t.Errorf("got %d %s.render functions, want 1", n, class)
+			}
+			members := graph.relCount(t,
+				nodeMatch{"Class", map[string]any{"name": class}}, "HAS_MEMBER",
+				nodeMatch{"Method", map[string]any{"name": "render"}})
+			if members != 1 {
+				t.Errorf("got %d render members of %s, want 1", members, class)
+			}
+		}
+		if n := graph.nodeCount(t, "Method", map[string]any{"name": "render"}); n != 2 {
+			t.Errorf("got %d render methods, want 2", n)
+		}
+		if n := graph.nodeCount(t, "File", map[string]any{"path": pf.FilePath}); n != 1 {
+			t.Errorf("got %d File nodes, want 1", n)
+		}
+	})
+
+	t.Run("ReplaceFileEntitiesRemovesStale", func(t *testing.T) {
+		client := backend.open(t)
+		graph := backend.inspect(client)
+		if err := client.ReplaceFileEntities(ctx, conformanceFile()); err != nil {
+			t.Fatal(err)
+		}
+		funcs := nodeMatch{"Function", nil}
+		if n := graph.relCount(t, funcs, "CALLS", funcs); n != 2 {
+			t.Fatalf("got %d CALLS before replacing, want 2", n)
+		}
+		if n := graph.nodeCount(t, "Import", nil); n != 2 {
+			t.Fatalf("got %d imports before replacing, want 2", n)
+		}
+
+		// The new parse drops format, both calls and both imports
+		pf := conformanceFile()
+		pf.Funcs = pf.Funcs[:3]
+		pf.FunctionCalls = nil
+		pf.Imports = nil
+		if err := client.ReplaceFileEntities(ctx, pf); err != nil {
+			t.Fatal(err)
+		}
+
+		if n := graph.nodeCount(t, "Function", map[string]any{"name": "format"}); n != 0 {
+			t.Errorf("got %d format functions after it was removed, want 0", n)
+		}
+		if n := graph.nodeCount(t, "Function", map[string]any{"name": "helper"}); n != 1 {
+			t.Errorf("got %d helper functions, want 1", n)
+		}
+		if n := graph.relCount(t, funcs, "CALLS", funcs); n != 0 {
+			t.Errorf("got %d CALLS after the calls were removed, want 0", n)
+		}
+		file := nodeMatch{"File", map[string]any{"path": pf.FilePath}}
+		if n := graph.relCount(t, file, "IMPORTS", nodeMatch{"Import", nil}); n != 0 {
+			t.Errorf("got %d IMPORTS after the imports were removed, want 0", n)
+		}
+		for _, module := range []string{"./util", "react"} {
+			if n := graph.nodeCount(t, "Import", map[string]any{"module": module}); n != 0 {
+				t.Errorf("got %d %s imports no file imports, want 0", n, module)
+			}
+		}
+	})
+
+	t.Run("DeleteFileDropsOrphanImports", func(t *testing.T) {
+		client := backend.open(t)
+		graph := backend.inspect(client)
+		pf := conformanceFile()
+		app := ParsedFile{
+			FilePath: "src/app.ts",
+			Language: "ts",
+			Imports:  []ImportEntity{{Module: "react", FilePath: "src/app.ts", ImportedNames: []string{"useState"}, Package: "react"}},
+		}
+		for _, f := range []ParsedFile{pf, app} {
+			if err := client.ReplaceFileEntities(ctx, f); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if err := client.DeleteFile(ctx, pf.FilePath); err != nil {
+			t.Fatal(err)
+		}
+
+		if n := graph.nodeCount(t, "File", map[string]any{"path": pf.FilePath}); n != 0 {
+			t.Errorf("got %d File nodes for the deleted file, want 0", n)
+		}
+		if n := graph.nodeCount(t, "Function", map[string]any{"file": pf.FilePath}); n != 0 {
+			t.Errorf("got %d functions of the deleted file, want 0", n)
+		}
+		if n := graph.nodeCount(t, "Import", map[string]any{"module": "./util"}); n != 0 {
+			t.Errorf("got %d ./util imports only the deleted file made, want 0", n)
+		}
+		if n := graph.nodeCount(t, "Import", map[string]any{"module": "react"}); n != 1 {
+			t.Errorf("got %d react imports still made by %s, want 1", n, app.FilePath)
+		}
+		if n := graph.relCount(t, nodeMatch{"File", map[string]any{"path": app.FilePath}}, "IMPORTS", nodeMatch{"Package", map[string]any{"name": "react"}}); n != 1 {
+			t.Errorf("got %d IMPORTS of the react package, want 1", n)
+		}
+	})
+
+	t.Run("Reopen", func(t *testing.T) {
+		client := backend.open(t)
+		pf := conformanceFile()
+		if err := client.ReplaceFileEntities(ctx, pf); err != nil {
+			t.Fatal(err)
+		}
+		before := conformanceCounts(t, backend.inspect(client), pf)
+
+		reopened := backend.reopen(t, client)
+		after := conformanceCounts(t, backend.inspect(reopened), pf)
+		if !reflect.DeepEqual(before, after) {
+			t.Errorf("graph changed on reopening\nbefore: %v\n after: %v", before, after)
+		}
+
+		// Writing the same file again after reopening merges onto the loaded nodes
+		if err := reopened.ReplaceFileEntities(ctx, pf); err != nil {
+			t.Fatal(err)
+		}
+		if again := conformanceCounts(t, backend.inspect(reopened), pf); !reflect.DeepEqual(before, again) {
+			t.Errorf("rewriting after reopening changed the graph\nbefore: %v\n after: %v", before, again)
+		}
+	})
+}
+
+// conformanceFile is a parsed file with two classes declaring a method of the
+// same name, top-level functions calling each other, and a project and a
+// package import.
+func conformanceFile() ParsedFile {
+	const path = "src/widgets.ts"
+	pf := ParsedFile{
+		FilePath: path,
+		Language: "ts",
+		Funcs: []FunctionEntity{
+			{Name: "render", QualifiedName: "Widget.render", FilePath: path, StartLine: 3, EndLine: 5},
+			{Name: "render", QualifiedName: "Panel.render", FilePath: path, StartLine: 9, EndLine: 11},
+			{Name: "helper", QualifiedName: "helper", FilePath: path, StartLine: 13, EndLine: 15},
+			{Name: "format", QualifiedName: "format", FilePath: path, StartLine: 17, EndLine: 19},
+		},
+		Classes: []ClassEntity{
+			{Name: "Widget", QualifiedName: "Widget", FilePath: path, StartLine: 2, EndLine: 6},
+			{Name: "Panel", QualifiedName: "Panel", FilePath: path, StartLine: 8, EndLine: 12},
+		},
+		Members: []MemberEntity{
+			{Name: "render", Kind: "method", ClassName: "Widget", FilePath: path, StartLine: 3, EndLine: 5},
+			{Name: "render", Kind: "method", ClassName: "Panel", FilePath: path, StartLine: 9, EndLine: 11},
+		},
+		Imports: []ImportEntity{
+			{Module: "./util", FilePath: path, ImportedNames: []string{"clamp"}, ResolvedFile: "src/util.ts"},
+			{Module: "react", FilePath: path, ImportedNames: []string{"useState"}, Package: "react"},
+		},
+		FunctionCalls: []FunctionCallEntity{
+			{CallerFile: path, CallerFunc: "render", CalledFunc: "helper", CallLocation: 4,
+				ResolvedTarget: "helper", TargetFile: path, TargetID: EntityID(path, "helper")},
+			{CallerFile: path, CallerFunc: "helper", CalledFunc: "format", CallLocation: 14,
+				ResolvedTarget: "format", TargetFile: path, TargetID: EntityID(path, "format")},
+		},
+	}
+	AssignIDs(&pf)
+	return pf
+}
+
+// conformanceCounts counts what conformanceFile writes, for comparing graphs.
+func conformanceCounts(t *testing.T, graph graphInspector, pf ParsedFile) map[string]int {
+	file := nodeMatch{"File", map[string]any{"path": pf.FilePath}}
+	return map[string]int{
+		"files":     graph.nodeCount(t, "File", nil),
+		"functions": graph.nodeCount(t, "Function", map[string]any{"file": pf.FilePath}),
+		"render":    graph.nodeCount(t, "Function", map[string]any{"name": "render"}),
+		"classes":   graph.nodeCount(t, "Class", nil),
+		"methods":   graph.nodeCount(t, "Method", nil),
+		"imports":   graph.nodeCount(t, "Import", nil),
+		"packages":  graph.nodeCount(t, "Package", nil),
+		"calls":     graph.relCount(t, nodeMatch{"Function", nil}, "CALLS", nodeMatch{"Function", nil}),
+		"members":   graph.relCount(t, nodeMatch{"Class", nil}, "HAS_MEMBER", nodeMatch{"Method", nil}),
+		"belongsTo": graph.relCount(t, nodeMatch{"Function", nil}, "BELONGS_TO", file),
+		"imported":  graph.relCount(t, file, "IMPORTS", nodeMatch{"", nil}),
+	}
+}
+
+// memoryInspector reads a MemoryClient's graph.
+type memoryInspector struct {
+	client *MemoryClient
+}
+
+func (m memoryInspector) nodeCount(t *testing.T, label string, props map[string]any) int {
+	return len(m.client.FindNodes(label, props))
+}
+
+func (m memoryInspector) relCount(t *testing.T, from nodeMatch, relType string, to nodeMatch) int {
+	targets := make(map[string]bool)
+	for _, n := range m.client.FindNodes(to.label, to.props) {
+		targets[n.ID] = true
+	}
+	count := 0
+	for _, n := range m.client.FindNodes(from.label, from.props) {
+		for _, r := range m.client.Relationships(relType, n.ID, "") {
+			if targets[r.To] {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// memoryBackend opens MemoryClients saved to a file in the test's temporary
+// directory, so that reopening loads what Close saved.
+var memoryBackend = graphBackend{
+	open: func(t *testing.T) GraphClient {
+		client, err := LoadMemoryClient(filepath.Join(t.TempDir(), "graph.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return client
+	},
+	reopen: func(t *testing.T, client GraphClient) GraphClient {
+		if err := client.Close(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		reopened, err := LoadMemoryClient(client.(*MemoryClient).path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return reopened
+	},
+	inspect: func(client GraphClient) graphInspector {
+		return memoryInspector{client.(*MemoryClient)}
+	},
+}
+
+func TestMemoryClientConformance(t *testing.T) {
+	testGraphConformance(t, memoryBackend)
+}
+
+// TestMemoryClientSaveLoad checks that a loaded graph equals the saved one
+// property for property, numbers included, and saves back to the same file.
+func TestMemoryClientSaveLoad(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	client := NewMemoryClient()
+	pf := conformanceFile()
+	pf.Funcs[2].Parameters = []Parameter{{Name: "value", Type: "number"}}
+	if err := client.ReplaceFileEntities(ctx, pf); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "graph.json")
+	if err := client.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadMemoryClient(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := loaded.FindNodes("", nil), client.FindNodes("", nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("loaded nodes differ from saved ones\n got: %v\nwant: %v", got, want)
+	}
+	if got, want := loaded.Relationships("", "", ""), client.Relationships("", "", ""); !reflect.DeepEqual(got, want) {
+		t.Errorf("loaded relationships differ from saved ones\n got: %v\nwant: %v", got, want)
+	}
+
+	resaved := filepath.Join(dir, "resaved.json")
+	if err := loaded.Save(resaved); err != nil {
+		t.Fatal(err)
+	}
+	first, second := readFile(t, path), readFile(t, resaved)
+	if first != second {
+		t.Errorf("saving a loaded graph changed the file")
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
@@ -5,8 +5,11 @@ package model
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/joho/godotenv"
@@ -16,11 +19,20 @@ import (
 type OracleGraphClient struct {
 	db        *sql.DB
 	graphName string
+	tx        *sql.Tx // Set on copies bound to a ReplaceFileEntities transaction
 }
 
-// init loads environment variables from .env (if present).
+// init loads environment variables from .env (if present) and registers the backend.
 func init() {
 	_ = godotenv.Load()
+
+	RegisterBackend("oracle", "Oracle Graph", func() (GraphClient, error) {
+		client, err := NewOracleGraphClient()
+		if err != nil {
+			return nil, err
+		}
+		return client, nil
+	})
 }
 
 // NewOracleGraphClient reads ORACLE_USER, ORACLE_PASS, ORACLE_DSN from env and connects.
@@ -98,9 +110,422 @@ func (c *OracleGraphClient) initializeGraph() error {
 		if err := c.createPropertyGraph(); err != nil {
 			return fmt.Errorf("failed to create property graph: %w", err)
 		}
+		return nil
 	}
 
-	return nil
+	// Bring graphs created by earlier versions up to date
+	migrations := []struct {
+		name    string
+		migrate func() (bool, error)
+	}{
+		{"FUNCTION_VT", func() (bool, error) { return c.migrateEntityIDs("FUNCTION_VT") }},
+		{"CLASS_VT", func() (bool, error) { return c.migrateEntityIDs("CLASS_VT") }},
+		{"member tables", func() (bool, error) {
+			return c.createMissingTables("METHOD_VT", append(c.memberVertexTables(), c.memberEdgeTables()...))
+		}},
+		{"function signatures", func() (bool, error) {
+			return c.addMissingColumns("FUNCTION_VT", functionSignatureColumns...)
+		}},
+		{"signature tables", func() (bool, error) {
+			return c.createMissingTables("ACCEPTS_TYPE_ET", c.signatureEdgeTables())
+		}},
+		{"decorator tables", func() (bool, error) {
+			return c.createMissingTables("DECORATOR_VT", append(c.decoratorVertexTables(), c.decoratorEdgeTables()...))
+		}},
+		{"export tables", func() (bool, error) {
+			return c.createMissingTables("EXPORTS_FILE_ET", c.exportEdgeTables())
+		}},
+		{"component tables", func() (bool, error) {
+			return c.createMissingTables("COMPONENT_VT", append(c.componentVertexTables(), c.componentEdgeTables()...))
+		}},
+		{"class names", func() (bool, error) {
+			return c.addMissingColumns("JSXELEMENT_VT", "CLASS_NAMES CLOB")
+		}},
+		{"style tables", func() (bool, error) {
+			return c.createMissingTables("JSXELEMENT_STYLED_BY_ET", c.styleEdgeTables())
+		}},
+		{"scss tables", func() (bool, error) {
+			return c.createMissingTables("CSSRULE_INCLUDES_ET", c.scssEdgeTables())
+		}},
+		{"full selectors", func() (bool, error) {
+			return c.addMissingColumns("CSSRULE_VT", "FULL_SELECTOR VARCHAR2(2000)")
+		}},
+		{"dynamic imports", func() (bool, error) {
+			changed := false
+			for _, table := range []string{"IMPORTS_ET", "IMPORTS_FILE_ET", "IMPORTS_PACKAGE_ET"} {
+				added, err := c.addMissingColumns(table, "IS_DYNAMIC NUMBER(1) DEFAULT 0")
+				if err != nil {
+					return changed, err
+				}
+				changed = changed || added
+			}
+			return changed, nil
+		}},
+		{"doc comments", func() (bool, error) {
+			changed := false
+			for _, table := range docCommentTables {
+				added, err := c.addMissingColumns(table, docCommentColumns...)
+				if err != nil {
+					return changed, err
+				}
+				changed = changed || added
+			}
+			return changed, nil
+		}},
+	}
+	changed := false
+	for _, migration := range migrations {
+		migrated, err := migration.migrate()
+		if err != nil {
+			return fmt.Errorf("failed to migrate %s: %w", migration.name, err)
+		}
+		changed = changed || migrated
+	}
+	if !changed {
+		return nil
+	}
+
+	// PROPERTIES ALL COLUMNS is resolved when the property graph is created, so
+	// it is redefined to pick up new tables and columns. Dropping a property
+	// graph leaves its tables and their rows in place.
+	if _, err := c.db.Exec(fmt.Sprintf(`DROP PROPERTY GRAPH %s`, c.graphName)); err != nil {
+		return fmt.Errorf("failed to drop property graph: %w", err)
+	}
+	return c.createPropertyGraph()
+}
+
+// migrateEntityIDs adds the ID and QUALIFIED_NAME columns to a vertex table
+// created before entities had IDs. Existing rows get the ID of an unqualified
+// name, and the old UNIQUE (NAME, FILE_PATH) constraint is dropped so methods
+// of the same name in one file can coexist. It reports whether it changed the
+// table.
+func (c *OracleGraphClient) migrateEntityIDs(table string) (bool, error) {
+	table = strings.ToUpper(c.graphName + "_" + table)
+
+	var count int
+	err := c.db.QueryRow(`
+		SELECT COUNT(*) FROM USER_TAB_COLUMNS
+		WHERE TABLE_NAME = :1 AND COLUMN_NAME = 'ID'
+	`, table).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check for ID column: %w", err)
+	}
+	if count > 0 {
+		return false, nil
+	}
+
+	rows, err := c.db.Query(`
+		SELECT CONSTRAINT_NAME FROM USER_CONSTRAINTS
+		WHERE TABLE_NAME = :1 AND CONSTRAINT_TYPE = 'U'
+	`, table)
+	if err != nil {
+		return false, fmt.Errorf("failed to list constraints: %w", err)
+	}
+	var constraints []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return false, fmt.Errorf("failed to scan constraint: %w", err)
+		}
+		constraints = append(constraints, name)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return false, fmt.Errorf("failed to list constraints: %w", err)
+	}
+
+	statements := []string{
+		fmt.Sprintf(`ALTER TABLE %s ADD (ID VARCHAR2(2000), QUALIFIED_NAME VARCHAR2(1000))`, table),
+		fmt.Sprintf(`UPDATE %s SET ID = FILE_PATH || '#' || NAME`, table),
+	}
+	for _, name := range constraints {
+		statements = append(statements, fmt.Sprintf(`ALTER TABLE %s DROP CONSTRAINT %s DROP INDEX`, table, name))
+	}
+	statements = append(statements,
+		fmt.Sprintf(`ALTER TABLE %s MODIFY (ID NOT NULL)`, table),
+		fmt.Sprintf(`ALTER TABLE %s ADD UNIQUE (ID)`, table),
+	)
+	for _, statement := range statements {
+		if _, err := c.db.Exec(statement); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// createMissingTables runs the DDL of tables added after a graph was created,
+// unless marker, the first of them, already exists. It reports whether it
+// created them.
+func (c *OracleGraphClient) createMissingTables(marker string, tables []string) (bool, error) {
+	var count int
+	err := c.db.QueryRow(`
+		SELECT COUNT(*) FROM USER_TABLES WHERE TABLE_NAME = :1
+	`, strings.ToUpper(c.graphName+"_"+marker)).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check for %s: %w", marker, err)
+	}
+	if count > 0 {
+		return false, nil
+	}
+
+	for _, table := range tables {
+		if _, err := c.db.Exec(table); err != nil {
+			return false, fmt.Errorf("failed to create table: %w", err)
+		}
+	}
+	return true, nil
+}
+
+// addMissingColumns adds the columns table lacks, given as definitions such as
+// "RETURN_TYPE VARCHAR2(1000)". It reports whether it added any.
+func (c *OracleGraphClient) addMissingColumns(table string, columns ...string) (bool, error) {
+	table = strings.ToUpper(c.graphName + "_" + table)
+
+	added := false
+	for _, column := range columns {
+		name, _, _ := strings.Cut(column, " ")
+		var count int
+		err := c.db.QueryRow(`
+			SELECT COUNT(*) FROM USER_TAB_COLUMNS
+			WHERE TABLE_NAME = :1 AND COLUMN_NAME = :2
+		`, table, name).Scan(&count)
+		if err != nil {
+			return added, fmt.Errorf("failed to check for %s column: %w", name, err)
+		}
+		if count > 0 {
+			continue
+		}
+		if _, err := c.db.Exec(fmt.Sprintf(`ALTER TABLE %s ADD (%s)`, table, column)); err != nil {
+			return added, fmt.Errorf("failed to add %s column: %w", name, err)
+		}
+		added = true
+	}
+	return added, nil
+}
+
+// functionSignatureColumns hold the structured signature of a function.
+// Parameters and type parameters are stored as JSON arrays.
+var functionSignatureColumns = []string{
+	"RETURN_TYPE VARCHAR2(1000)",
+	"IS_GENERATOR NUMBER(1) DEFAULT 0",
+	"PARAMETERS CLOB",
+	"TYPE_PARAMETERS CLOB",
+}
+
+// docCommentTables are the vertex tables of documented entities, which store
+// their DocComment as JSON in DOC
+var docCommentTables = []string{"FUNCTION_VT", "VARIABLE_VT", "TYPE_VT", "INTERFACE_VT", "CLASS_VT", "CONSTANT_VT"}
+
+// docCommentColumns are the columns added to docCommentTables for doc comments
+var docCommentColumns = []string{
+	"DOC CLOB",
+	"IS_DEPRECATED NUMBER(1) DEFAULT 0",
+}
+
+// memberVertexTables returns the DDL of the :Method and :Property vertex tables.
+func (c *OracleGraphClient) memberVertexTables() []string {
+	var tables []string
+	for _, label := range []string{"METHOD", "PROPERTY"} {
+		tables = append(tables, fmt.Sprintf(`CREATE TABLE %s_%s_VT (
+			VID NUMBER GENERATED AS IDENTITY PRIMARY KEY,
+			ID VARCHAR2(2000) UNIQUE NOT NULL,
+			NAME VARCHAR2(255) NOT NULL,
+			KIND VARCHAR2(20),
+			CLASS_NAME VARCHAR2(255),
+			FILE_PATH VARCHAR2(1000) NOT NULL,
+			START_LINE NUMBER,
+			END_LINE NUMBER,
+			DECLARED_TYPE VARCHAR2(1000),
+			VISIBILITY VARCHAR2(20),
+			IS_STATIC NUMBER(1) DEFAULT 0,
+			IS_ABSTRACT NUMBER(1) DEFAULT 0,
+			IS_READONLY NUMBER(1) DEFAULT 0,
+			CREATED TIMESTAMP DEFAULT SYSTIMESTAMP,
+			UPDATED TIMESTAMP
+		)`, c.graphName, label))
+	}
+	return tables
+}
+
+// memberEdgeTables returns the DDL of the HAS_MEMBER, IMPLEMENTED_BY and
+// OVERRIDES edge tables.
+func (c *OracleGraphClient) memberEdgeTables() []string {
+	var tables []string
+	for _, table := range []string{"HAS_METHOD_ET", "HAS_PROPERTY_ET", "IMPLEMENTED_BY_ET", "OVERRIDES_ET"} {
+		tables = append(tables, fmt.Sprintf(`CREATE TABLE %s_%s (
+			EID NUMBER GENERATED AS IDENTITY PRIMARY KEY,
+			SOURCE_VID NUMBER NOT NULL,
+			DEST_VID NUMBER NOT NULL,
+			CREATED TIMESTAMP DEFAULT SYSTIMESTAMP
+		)`, c.graphName, table))
+	}
+	return tables
+}
+
+// decoratorVertexTables returns the DDL of the :Decorator vertex table.
+func (c *OracleGraphClient) decoratorVertexTables() []string {
+	return []string{fmt.Sprintf(`CREATE TABLE %s_DECORATOR_VT (
+			VID NUMBER GENERATED AS IDENTITY PRIMARY KEY,
+			ID VARCHAR2(2000) UNIQUE NOT NULL,
+			NAME VARCHAR2(255) NOT NULL,
+			ARGUMENTS CLOB,
+			TARGET_KIND VARCHAR2(20),
+			PARAMETER_NAME VARCHAR2(255),
+			FILE_PATH VARCHAR2(1000) NOT NULL,
+			LINE_NUM NUMBER,
+			CREATED TIMESTAMP DEFAULT SYSTIMESTAMP,
+			UPDATED TIMESTAMP
+		)`, c.graphName)}
+}
+
+// decoratorEdgeTables returns the DDL of the DECORATED_BY edge tables, one per
+// kind of decorated vertex.
+func (c *OracleGraphClient) decoratorEdgeTables() []string {
+	var tables []string
+	for _, source := range []string{"CLASS", "METHOD", "PROPERTY"} {
+		tables = append(tables, fmt.Sprintf(`CREATE TABLE %s_DECORATED_BY_%s_ET (
+			EID NUMBER GENERATED AS IDENTITY PRIMARY KEY,
+			SOURCE_VID NUMBER NOT NULL,
+			DEST_VID NUMBER NOT NULL,
+			CREATED TIMESTAMP DEFAULT SYSTIMESTAMP
+		)`, c.graphName, source))
+	}
+	return tables
+}
+
+// exportEdgeTables returns the DDL of the EXPORTS edge tables, one per kind
+// of exported vertex.
+func (c *OracleGraphClient) exportEdgeTables() []string {
+	var tables []string
+	for _, target := range []string{"FUNCTION", "CLASS", "INTERFACE", "TYPE", "VARIABLE", "FILE"} {
+		tables = append(tables, fmt.Sprintf(`CREATE TABLE %s_EXPORTS_%s_ET (
+			EID NUMBER GENERATED AS IDENTITY PRIMARY KEY,
+			SOURCE_VID NUMBER NOT NULL,
+			DEST_VID NUMBER NOT NULL,
+			NAMES CLOB,
+			CREATED TIMESTAMP DEFAULT SYSTIMESTAMP,
+			UPDATED TIMESTAMP
+		)`, c.graphName, target))
+	}
+	return tables
+}
+
+// componentVertexTables returns the DDL of the :Component and :Hook vertex
+// tables.
+func (c *OracleGraphClient) componentVertexTables() []string {
+	return []string{
+		fmt.Sprintf(`CREATE TABLE %s_COMPONENT_VT (
+			VID NUMBER GENERATED AS IDENTITY PRIMARY KEY,
+			ID VARCHAR2(2000) UNIQUE NOT NULL,
+			NAME VARCHAR2(255) NOT NULL,
+			KIND VARCHAR2(20),
+			FILE_PATH VARCHAR2(1000) NOT NULL,
+			START_LINE NUMBER,
+			END_LINE NUMBER,
+			PROPS_TYPE VARCHAR2(1000),
+			CREATED TIMESTAMP DEFAULT SYSTIMESTAMP,
+			UPDATED TIMESTAMP
+		)`, c.graphName),
+		fmt.Sprintf(`CREATE TABLE %s_HOOK_VT (
+			VID NUMBER GENERATED AS IDENTITY PRIMARY KEY,
+			NAME VARCHAR2(255) UNIQUE NOT NULL,
+			CREATED TIMESTAMP DEFAULT SYSTIMESTAMP
+		)`, c.graphName),
+	}
+}
+
+// componentEdgeTables returns the DDL of the edge tables leaving components:
+// DEFINED_IN, IMPLEMENTED_BY per kind of declaration, RENDERS, and USES_HOOK
+// to library hooks and to custom hook functions.
+func (c *OracleGraphClient) componentEdgeTables() []string {
+	var tables []string
+	for _, table := range []string{"COMPONENT_DEFINED_IN_ET", "COMPONENT_IMPLEMENTED_BY_FUNCTION_ET", "COMPONENT_IMPLEMENTED_BY_CLASS_ET"} {
+		tables = append(tables, fmt.Sprintf(`CREATE TABLE %s_%s (
+			EID NUMBER GENERATED AS IDENTITY PRIMARY KEY,
+			SOURCE_VID NUMBER NOT NULL,
+			DEST_VID NUMBER NOT NULL,
+			CREATED TIMESTAMP DEFAULT SYSTIMESTAMP
+		)`, c.graphName, table))
+	}
+	tables = append(tables, fmt.Sprintf(`CREATE TABLE %s_COMPONENT_RENDERS_ET (
+			EID NUMBER GENERATED AS IDENTITY PRIMARY KEY,
+			SOURCE_VID NUMBER NOT NULL,
+			DEST_VID NUMBER NOT NULL,
+			TAG_NAME VARCHAR2(255),
+			LINE_NUM NUMBER,
+			CREATED TIMESTAMP DEFAULT SYSTIMESTAMP,
+			UPDATED TIMESTAMP
+		)`, c.graphName))
+	for _, table := range []string{"COMPONENT_USES_HOOK_ET", "COMPONENT_USES_HOOK_FUNCTION_ET"} {
+		tables = append(tables, fmt.Sprintf(`CREATE TABLE %s_%s (
+			EID NUMBER GENERATED AS IDENTITY PRIMARY KEY,
+			SOURCE_VID NUMBER NOT NULL,
+			DEST_VID NUMBER NOT NULL,
+			LINE_NUM NUMBER,
+			CREATED TIMESTAMP DEFAULT SYSTIMESTAMP,
+			UPDATED TIMESTAMP
+		)`, c.graphName, table))
+	}
+	return tables
+}
+
+// styleEdgeTables returns the DDL of the STYLED_BY edge tables leading from
+// JSX elements and components to CSS rules.
+func (c *OracleGraphClient) styleEdgeTables() []string {
+	var tables []string
+	for _, table := range []string{"JSXELEMENT_STYLED_BY_ET", "COMPONENT_STYLED_BY_ET"} {
+		tables = append(tables, fmt.Sprintf(`CREATE TABLE %s_%s (
+			EID NUMBER GENERATED AS IDENTITY PRIMARY KEY,
+			SOURCE_VID NUMBER NOT NULL,
+			DEST_VID NUMBER NOT NULL,
+			LINE_NUM NUMBER,
+			CREATED TIMESTAMP DEFAULT SYSTIMESTAMP,
+			UPDATED TIMESTAMP
+		)`, c.graphName, table))
+	}
+	return tables
+}
+
+// scssEdgeTables returns the DDL of the INCLUDES and EXTENDS edge tables
+// between CSS rules, from SCSS @include and @extend.
+func (c *OracleGraphClient) scssEdgeTables() []string {
+	var tables []string
+	for _, table := range []string{"CSSRULE_INCLUDES_ET", "CSSRULE_EXTENDS_ET"} {
+		tables = append(tables, fmt.Sprintf(`CREATE TABLE %s_%s (
+			EID NUMBER GENERATED AS IDENTITY PRIMARY KEY,
+			SOURCE_VID NUMBER NOT NULL,
+			DEST_VID NUMBER NOT NULL,
+			LINE_NUM NUMBER,
+			CREATED TIMESTAMP DEFAULT SYSTIMESTAMP,
+			UPDATED TIMESTAMP
+		)`, c.graphName, table))
+	}
+	return tables
+}
+
+// signatureEdgeTables returns the DDL of the ACCEPTS and RETURNS edge tables,
+// one per kind of type a signature can name.
+func (c *OracleGraphClient) signatureEdgeTables() []string {
+	var tables []string
+	for _, target := range []string{"TYPE", "INTERFACE", "CLASS"} {
+		tables = append(tables,
+			fmt.Sprintf(`CREATE TABLE %s_ACCEPTS_%s_ET (
+			EID NUMBER GENERATED AS IDENTITY PRIMARY KEY,
+			SOURCE_VID NUMBER NOT NULL,
+			DEST_VID NUMBER NOT NULL,
+			PARAMETERS VARCHAR2(2000),
+			CREATED TIMESTAMP DEFAULT SYSTIMESTAMP,
+			UPDATED TIMESTAMP
+		)`, c.graphName, target),
+			fmt.Sprintf(`CREATE TABLE %s_RETURNS_%s_ET (
+			EID NUMBER GENERATED AS IDENTITY PRIMARY KEY,
+			SOURCE_VID NUMBER NOT NULL,
+			DEST_VID NUMBER NOT NULL,
+			CREATED TIMESTAMP DEFAULT SYSTIMESTAMP
+		)`, c.graphName, target))
+	}
+	return tables
 }
 
 // createVertexTables creates all vertex tables
@@ -116,16 +541,23 @@ func (c *OracleGraphClient) createVertexTables() error {
 
 		fmt.Sprintf(`CREATE TABLE %s_FUNCTION_VT (
 			VID NUMBER GENERATED AS IDENTITY PRIMARY KEY,
+			ID VARCHAR2(2000) UNIQUE NOT NULL,
 			NAME VARCHAR2(255) NOT NULL,
+			QUALIFIED_NAME VARCHAR2(1000),
 			FILE_PATH VARCHAR2(1000) NOT NULL,
 			START_LINE NUMBER,
 			END_LINE NUMBER,
 			SIGNATURE VARCHAR2(1000),
+			RETURN_TYPE VARCHAR2(1000),
 			IS_ASYNC NUMBER(1) DEFAULT 0,
+			IS_GENERATOR NUMBER(1) DEFAULT 0,
 			IS_EXPORT NUMBER(1) DEFAULT 0,
+			PARAMETERS CLOB,
+			TYPE_PARAMETERS CLOB,
+			DOC CLOB,
+			IS_DEPRECATED NUMBER(1) DEFAULT 0,
 			CREATED TIMESTAMP DEFAULT SYSTIMESTAMP,
-			UPDATED TIMESTAMP,
-			UNIQUE (NAME, FILE_PATH)
+			UPDATED TIMESTAMP
 		)`, c.graphName),
 
 		fmt.Sprintf(`CREATE TABLE %s_IMPORT_VT (
@@ -135,6 +567,13 @@ func (c *OracleGraphClient) createVertexTables() error {
 			UPDATED TIMESTAMP
 		)`, c.graphName),
 
+		fmt.Sprintf(`CREATE TABLE %s_PACKAGE_VT (
+			VID NUMBER GENERATED AS IDENTITY PRIMARY KEY,
+			NAME VARCHAR2(500) UNIQUE NOT NULL,
+			CREATED TIMESTAMP DEFAULT SYSTIMESTAMP,
+			UPDATED TIMESTAMP
+		)`, c.graphName),
+
 		fmt.Sprintf(`CREATE TABLE %s_VARIABLE_VT (
 			VID NUMBER GENERATED AS IDENTITY PRIMARY KEY,
 			NAME VARCHAR2(255) NOT NULL,
@@ -143,6 +582,8 @@ func (c *OracleGraphClient) createVertexTables() error {
 			IS_CONST NUMBER(1) DEFAULT 0,
 			IS_LET NUMBER(1) DEFAULT 0,
 			START_LINE NUMBER,
+			DOC CLOB,
+			IS_DEPRECATED NUMBER(1) DEFAULT 0,
 			CREATED TIMESTAMP DEFAULT SYSTIMESTAMP,
 			UPDATED TIMESTAMP,
 			UNIQUE (NAME, FILE_PATH)
@@ -155,6 +596,8 @@ func (c *OracleGraphClient) createVertexTables() error {
 			KIND VARCHAR2(50),
 			DEFINITION CLOB,
 			IS_EXPORT NUMBER(1) DEFAULT 0,
+			DOC CLOB,
+			IS_DEPRECATED NUMBER(1) DEFAULT 0,
 			CREATED TIMESTAMP DEFAULT SYSTIMESTAMP,
 			UPDATED TIMESTAMP,
 			UNIQUE (NAME, FILE_PATH)
@@ -166,6 +609,8 @@ func (c *OracleGraphClient) createVertexTables() error {
 			FILE_PATH VARCHAR2(1000) NOT NULL,
 			IS_EXPORT NUMBER(1) DEFAULT 0,
 			PROPERTIES CLOB,
+			DOC CLOB,
+			IS_DEPRECATED NUMBER(1) DEFAULT 0,
 			CREATED TIMESTAMP DEFAULT SYSTIMESTAMP,
 			UPDATED TIMESTAMP,
 			UNIQUE (NAME, FILE_PATH)
@@ -173,16 +618,19 @@ func (c *OracleGraphClient) createVertexTables() error {
 
 		fmt.Sprintf(`CREATE TABLE %s_CLASS_VT (
 			VID NUMBER GENERATED AS IDENTITY PRIMARY KEY,
+			ID VARCHAR2(2000) UNIQUE NOT NULL,
 			NAME VARCHAR2(255) NOT NULL,
+			QUALIFIED_NAME VARCHAR2(1000),
 			FILE_PATH VARCHAR2(1000) NOT NULL,
 			START_LINE NUMBER,
 			END_LINE NUMBER,
 			IS_EXPORT NUMBER(1) DEFAULT 0,
 			IS_ABSTRACT NUMBER(1) DEFAULT 0,
 			METHODS CLOB,
+			DOC CLOB,
+			IS_DEPRECATED NUMBER(1) DEFAULT 0,
 			CREATED TIMESTAMP DEFAULT SYSTIMESTAMP,
-			UPDATED TIMESTAMP,
-			UNIQUE (NAME, FILE_PATH)
+			UPDATED TIMESTAMP
 		)`, c.graphName),
 
 		fmt.Sprintf(`CREATE TABLE %s_CONSTANT_VT (
@@ -190,6 +638,8 @@ func (c *OracleGraphClient) createVertexTables() error {
 			NAME VARCHAR2(255) NOT NULL,
 			FILE_PATH VARCHAR2(1000) NOT NULL,
 			VALUE VARCHAR2(1000),
+			DOC CLOB,
+			IS_DEPRECATED NUMBER(1) DEFAULT 0,
 			CREATED TIMESTAMP DEFAULT SYSTIMESTAMP,
 			UPDATED TIMESTAMP,
 			UNIQUE (NAME, FILE_PATH)
@@ -203,6 +653,7 @@ func (c *OracleGraphClient) createVertexTables() error {
 			CONTAINING_COMPONENT VARCHAR2(255),
 			PROPS CLOB,
 			IS_CUSTOM_COMPONENT NUMBER(1) DEFAULT 0,
+			CLASS_NAMES CLOB,
 			CREATED TIMESTAMP DEFAULT SYSTIMESTAMP,
 			UPDATED TIMESTAMP
 		)`, c.graphName),
@@ -211,6 +662,7 @@ func (c *OracleGraphClient) createVertexTables() error {
 			VID NUMBER GENERATED AS IDENTITY PRIMARY KEY,
 			SELECTOR VARCHAR2(500) NOT NULL,
 			FILE_PATH VARCHAR2(1000) NOT NULL,
+			FULL_SELECTOR VARCHAR2(2000),
 			RULE_TYPE VARCHAR2(50),
 			LINE_NUM NUMBER,
 			PROPERTY_NAME VARCHAR2(255),
@@ -231,6 +683,9 @@ func (c *OracleGraphClient) createVertexTables() error {
 			UNIQUE (CALLED_FUNC, CALLER_FILE, CALLER_FUNC, LINE_NUM)
 		)`, c.graphName),
 	}
+	tables = append(tables, c.memberVertexTables()...)
+	tables = append(tables, c.decoratorVertexTables()...)
+	tables = append(tables, c.componentVertexTables()...)
 
 	for _, table := range tables {
 		if _, err := c.db.Exec(table); err != nil {
@@ -258,6 +713,31 @@ func (c *OracleGraphClient) createEdgeTables() error {
 			IMPORTED_NAMES CLOB,
 			IS_DEFAULT NUMBER(1) DEFAULT 0,
 			IS_NAMESPACE NUMBER(1) DEFAULT 0,
+			IS_DYNAMIC NUMBER(1) DEFAULT 0,
+			CREATED TIMESTAMP DEFAULT SYSTIMESTAMP,
+			UPDATED TIMESTAMP
+		)`, c.graphName),
+
+		fmt.Sprintf(`CREATE TABLE %s_IMPORTS_FILE_ET (
+			EID NUMBER GENERATED AS IDENTITY PRIMARY KEY,
+			SOURCE_VID NUMBER NOT NULL,
+			DEST_VID NUMBER NOT NULL,
+			IMPORTED_NAMES CLOB,
+			IS_DEFAULT NUMBER(1) DEFAULT 0,
+			IS_NAMESPACE NUMBER(1) DEFAULT 0,
+			IS_DYNAMIC NUMBER(1) DEFAULT 0,
+			CREATED TIMESTAMP DEFAULT SYSTIMESTAMP,
+			UPDATED TIMESTAMP
+		)`, c.graphName),
+
+		fmt.Sprintf(`CREATE TABLE %s_IMPORTS_PACKAGE_ET (
+			EID NUMBER GENERATED AS IDENTITY PRIMARY KEY,
+			SOURCE_VID NUMBER NOT NULL,
+			DEST_VID NUMBER NOT NULL,
+			IMPORTED_NAMES CLOB,
+			IS_DEFAULT NUMBER(1) DEFAULT 0,
+			IS_NAMESPACE NUMBER(1) DEFAULT 0,
+			IS_DYNAMIC NUMBER(1) DEFAULT 0,
 			CREATED TIMESTAMP DEFAULT SYSTIMESTAMP,
 			UPDATED TIMESTAMP
 		)`, c.graphName),
@@ -341,6 +821,13 @@ func (c *OracleGraphClient) createEdgeTables() error {
 			CREATED TIMESTAMP DEFAULT SYSTIMESTAMP
 		)`, c.graphName),
 	}
+	edgeTables = append(edgeTables, c.memberEdgeTables()...)
+	edgeTables = append(edgeTables, c.signatureEdgeTables()...)
+	edgeTables = append(edgeTables, c.decoratorEdgeTables()...)
+	edgeTables = append(edgeTables, c.exportEdgeTables()...)
+	edgeTables = append(edgeTables, c.componentEdgeTables()...)
+	edgeTables = append(edgeTables, c.styleEdgeTables()...)
+	edgeTables = append(edgeTables, c.scssEdgeTables()...)
 
 	for _, table := range edgeTables {
 		if _, err := c.db.Exec(table); err != nil {
@@ -355,125 +842,287 @@ func (c *OracleGraphClient) createEdgeTables() error {
 func (c *OracleGraphClient) createPropertyGraph() error {
 	// Build the CREATE PROPERTY GRAPH statement
 	pgDef := fmt.Sprintf(`
-CREATE PROPERTY GRAPH %s
+CREATE PROPERTY GRAPH %[1]s
   VERTEX TABLES (
-    %s_FILE_VT KEY (VID) 
+    %[1]s_FILE_VT KEY (VID) 
       LABEL FILE 
       PROPERTIES (PATH, LANGUAGE),
     
-    %s_FUNCTION_VT KEY (VID) 
+    %[1]s_FUNCTION_VT KEY (VID) 
       LABEL FUNCTION 
       PROPERTIES ALL COLUMNS,
     
-    %s_IMPORT_VT KEY (VID) 
+    %[1]s_IMPORT_VT KEY (VID) 
       LABEL IMPORT 
       PROPERTIES (MODULE),
     
-    %s_VARIABLE_VT KEY (VID) 
+    %[1]s_PACKAGE_VT KEY (VID) 
+      LABEL PACKAGE 
+      PROPERTIES (NAME),
+    
+    %[1]s_VARIABLE_VT KEY (VID) 
       LABEL VARIABLE 
       PROPERTIES ALL COLUMNS,
     
-    %s_TYPE_VT KEY (VID) 
+    %[1]s_TYPE_VT KEY (VID) 
       LABEL TYPE 
       PROPERTIES ALL COLUMNS,
     
-    %s_INTERFACE_VT KEY (VID) 
+    %[1]s_INTERFACE_VT KEY (VID) 
       LABEL INTERFACE 
       PROPERTIES ALL COLUMNS,
     
-    %s_CLASS_VT KEY (VID) 
+    %[1]s_CLASS_VT KEY (VID) 
       LABEL CLASS 
       PROPERTIES ALL COLUMNS,
     
-    %s_CONSTANT_VT KEY (VID) 
+    %[1]s_METHOD_VT KEY (VID) 
+      LABEL METHOD 
+      PROPERTIES ALL COLUMNS,
+    
+    %[1]s_PROPERTY_VT KEY (VID) 
+      LABEL PROPERTY 
+      PROPERTIES ALL COLUMNS,
+    
+    %[1]s_CONSTANT_VT KEY (VID) 
       LABEL CONSTANT 
       PROPERTIES ALL COLUMNS,
     
-    %s_JSXELEMENT_VT KEY (VID) 
+    %[1]s_DECORATOR_VT KEY (VID) 
+      LABEL DECORATOR 
+      PROPERTIES ALL COLUMNS,
+    
+    %[1]s_COMPONENT_VT KEY (VID) 
+      LABEL COMPONENT 
+      PROPERTIES ALL COLUMNS,
+    
+    %[1]s_HOOK_VT KEY (VID) 
+      LABEL HOOK 
+      PROPERTIES (NAME),
+    
+    %[1]s_JSXELEMENT_VT KEY (VID) 
       LABEL JSXELEMENT 
       PROPERTIES ALL COLUMNS,
     
-    %s_CSSRULE_VT KEY (VID) 
+    %[1]s_CSSRULE_VT KEY (VID) 
       LABEL CSSRULE 
       PROPERTIES ALL COLUMNS,
     
-    %s_UNRESOLVED_CALL_VT KEY (VID) 
+    %[1]s_UNRESOLVED_CALL_VT KEY (VID) 
       LABEL UNRESOLVED_CALL 
       PROPERTIES ALL COLUMNS
   )
   EDGE TABLES (
-    %s_BELONGS_TO_ET KEY (EID) 
-      SOURCE KEY (SOURCE_VID) REFERENCES %s_FUNCTION_VT (VID)
-      DESTINATION KEY (DEST_VID) REFERENCES %s_FILE_VT (VID)
+    %[1]s_BELONGS_TO_ET KEY (EID) 
+      SOURCE KEY (SOURCE_VID) REFERENCES %[1]s_FUNCTION_VT (VID)
+      DESTINATION KEY (DEST_VID) REFERENCES %[1]s_FILE_VT (VID)
       LABEL BELONGS_TO NO PROPERTIES,
     
-    %s_IMPORTS_ET KEY (EID)
-      SOURCE KEY (SOURCE_VID) REFERENCES %s_FILE_VT (VID)
-      DESTINATION KEY (DEST_VID) REFERENCES %s_IMPORT_VT (VID)
-      LABEL IMPORTS PROPERTIES (IMPORTED_NAMES, IS_DEFAULT, IS_NAMESPACE),
+    %[1]s_IMPORTS_ET KEY (EID)
+      SOURCE KEY (SOURCE_VID) REFERENCES %[1]s_FILE_VT (VID)
+      DESTINATION KEY (DEST_VID) REFERENCES %[1]s_IMPORT_VT (VID)
+      LABEL IMPORTS PROPERTIES (IMPORTED_NAMES, IS_DEFAULT, IS_NAMESPACE, IS_DYNAMIC),
     
-    %s_CALLS_ET KEY (EID)
-      SOURCE KEY (SOURCE_VID) REFERENCES %s_FUNCTION_VT (VID)
-      DESTINATION KEY (DEST_VID) REFERENCES %s_FUNCTION_VT (VID)
+    %[1]s_IMPORTS_FILE_ET KEY (EID)
+      SOURCE KEY (SOURCE_VID) REFERENCES %[1]s_FILE_VT (VID)
+      DESTINATION KEY (DEST_VID) REFERENCES %[1]s_FILE_VT (VID)
+      LABEL IMPORTS PROPERTIES (IMPORTED_NAMES, IS_DEFAULT, IS_NAMESPACE, IS_DYNAMIC),
+    
+    %[1]s_IMPORTS_PACKAGE_ET KEY (EID)
+      SOURCE KEY (SOURCE_VID) REFERENCES %[1]s_FILE_VT (VID)
+      DESTINATION KEY (DEST_VID) REFERENCES %[1]s_PACKAGE_VT (VID)
+      LABEL IMPORTS PROPERTIES (IMPORTED_NAMES, IS_DEFAULT, IS_NAMESPACE, IS_DYNAMIC),
+    
+    %[1]s_CALLS_ET KEY (EID)
+      SOURCE KEY (SOURCE_VID) REFERENCES %[1]s_FUNCTION_VT (VID)
+      DESTINATION KEY (DEST_VID) REFERENCES %[1]s_FUNCTION_VT (VID)
       LABEL CALLS PROPERTIES (CALL_LOCATION, CALL_CONTEXT),
     
-    %s_USES_TYPE_ET KEY (EID)
-      SOURCE KEY (SOURCE_VID) REFERENCES %s_FILE_VT (VID)
-      DESTINATION KEY (DEST_VID) REFERENCES %s_TYPE_VT (VID)
+    %[1]s_USES_TYPE_ET KEY (EID)
+      SOURCE KEY (SOURCE_VID) REFERENCES %[1]s_FILE_VT (VID)
+      DESTINATION KEY (DEST_VID) REFERENCES %[1]s_TYPE_VT (VID)
       LABEL USES_TYPE PROPERTIES (USAGE_CONTEXT, USAGE_LOCATION, USING_ENTITY),
     
-    %s_EXTENDS_ET KEY (EID)
-      SOURCE KEY (SOURCE_VID) REFERENCES %s_CLASS_VT (VID)
-      DESTINATION KEY (DEST_VID) REFERENCES %s_CLASS_VT (VID)
+    %[1]s_EXTENDS_ET KEY (EID)
+      SOURCE KEY (SOURCE_VID) REFERENCES %[1]s_CLASS_VT (VID)
+      DESTINATION KEY (DEST_VID) REFERENCES %[1]s_CLASS_VT (VID)
       LABEL EXTENDS NO PROPERTIES,
     
-    %s_IMPLEMENTS_ET KEY (EID)
-      SOURCE KEY (SOURCE_VID) REFERENCES %s_CLASS_VT (VID)
-      DESTINATION KEY (DEST_VID) REFERENCES %s_INTERFACE_VT (VID)
+    %[1]s_IMPLEMENTS_ET KEY (EID)
+      SOURCE KEY (SOURCE_VID) REFERENCES %[1]s_CLASS_VT (VID)
+      DESTINATION KEY (DEST_VID) REFERENCES %[1]s_INTERFACE_VT (VID)
       LABEL IMPLEMENTS NO PROPERTIES,
     
-    %s_DEFINED_IN_ET KEY (EID)
-      SOURCE KEY (SOURCE_VID) REFERENCES %s_VARIABLE_VT (VID)
-      DESTINATION KEY (DEST_VID) REFERENCES %s_FILE_VT (VID)
+    %[1]s_HAS_METHOD_ET KEY (EID)
+      SOURCE KEY (SOURCE_VID) REFERENCES %[1]s_CLASS_VT (VID)
+      DESTINATION KEY (DEST_VID) REFERENCES %[1]s_METHOD_VT (VID)
+      LABEL HAS_MEMBER NO PROPERTIES,
+    
+    %[1]s_HAS_PROPERTY_ET KEY (EID)
+      SOURCE KEY (SOURCE_VID) REFERENCES %[1]s_CLASS_VT (VID)
+      DESTINATION KEY (DEST_VID) REFERENCES %[1]s_PROPERTY_VT (VID)
+      LABEL HAS_MEMBER NO PROPERTIES,
+    
+    %[1]s_IMPLEMENTED_BY_ET KEY (EID)
+      SOURCE KEY (SOURCE_VID) REFERENCES %[1]s_METHOD_VT (VID)
+      DESTINATION KEY (DEST_VID) REFERENCES %[1]s_FUNCTION_VT (VID)
+      LABEL IMPLEMENTED_BY NO PROPERTIES,
+    
+    %[1]s_OVERRIDES_ET KEY (EID)
+      SOURCE KEY (SOURCE_VID) REFERENCES %[1]s_METHOD_VT (VID)
+      DESTINATION KEY (DEST_VID) REFERENCES %[1]s_METHOD_VT (VID)
+      LABEL OVERRIDES NO PROPERTIES,
+    
+    %[1]s_ACCEPTS_TYPE_ET KEY (EID)
+      SOURCE KEY (SOURCE_VID) REFERENCES %[1]s_FUNCTION_VT (VID)
+      DESTINATION KEY (DEST_VID) REFERENCES %[1]s_TYPE_VT (VID)
+      LABEL ACCEPTS PROPERTIES (PARAMETERS),
+    
+    %[1]s_ACCEPTS_INTERFACE_ET KEY (EID)
+      SOURCE KEY (SOURCE_VID) REFERENCES %[1]s_FUNCTION_VT (VID)
+      DESTINATION KEY (DEST_VID) REFERENCES %[1]s_INTERFACE_VT (VID)
+      LABEL ACCEPTS PROPERTIES (PARAMETERS),
+    
+    %[1]s_ACCEPTS_CLASS_ET KEY (EID)
+      SOURCE KEY (SOURCE_VID) REFERENCES %[1]s_FUNCTION_VT (VID)
+      DESTINATION KEY (DEST_VID) REFERENCES %[1]s_CLASS_VT (VID)
+      LABEL ACCEPTS PROPERTIES (PARAMETERS),
+    
+    %[1]s_RETURNS_TYPE_ET KEY (EID)
+      SOURCE KEY (SOURCE_VID) REFERENCES %[1]s_FUNCTION_VT (VID)
+      DESTINATION KEY (DEST_VID) REFERENCES %[1]s_TYPE_VT (VID)
+      LABEL RETURNS NO PROPERTIES,
+    
+    %[1]s_RETURNS_INTERFACE_ET KEY (EID)
+      SOURCE KEY (SOURCE_VID) REFERENCES %[1]s_FUNCTION_VT (VID)
+      DESTINATION KEY (DEST_VID) REFERENCES %[1]s_INTERFACE_VT (VID)
+      LABEL RETURNS NO PROPERTIES,
+    
+    %[1]s_RETURNS_CLASS_ET KEY (EID)
+      SOURCE KEY (SOURCE_VID) REFERENCES %[1]s_FUNCTION_VT (VID)
+      DESTINATION KEY (DEST_VID) REFERENCES %[1]s_CLASS_VT (VID)
+      LABEL RETURNS NO PROPERTIES,
+    
+    %[1]s_DECORATED_BY_CLASS_ET KEY (EID)
+      SOURCE KEY (SOURCE_VID) REFERENCES %[1]s_CLASS_VT (VID)
+      DESTINATION KEY (DEST_VID) REFERENCES %[1]s_DECORATOR_VT (VID)
+      LABEL DECORATED_BY NO PROPERTIES,
+    
+    %[1]s_DECORATED_BY_METHOD_ET KEY (EID)
+      SOURCE KEY (SOURCE_VID) REFERENCES %[1]s_METHOD_VT (VID)
+      DESTINATION KEY (DEST_VID) REFERENCES %[1]s_DECORATOR_VT (VID)
+      LABEL DECORATED_BY NO PROPERTIES,
+    
+    %[1]s_DECORATED_BY_PROPERTY_ET KEY (EID)
+      SOURCE KEY (SOURCE_VID) REFERENCES %[1]s_PROPERTY_VT (VID)
+      DESTINATION KEY (DEST_VID) REFERENCES %[1]s_DECORATOR_VT (VID)
+      LABEL DECORATED_BY NO PROPERTIES,
+    
+    %[1]s_EXPORTS_FUNCTION_ET KEY (EID)
+      SOURCE KEY (SOURCE_VID) REFERENCES %[1]s_FILE_VT (VID)
+      DESTINATION KEY (DEST_VID) REFERENCES %[1]s_FUNCTION_VT (VID)
+      LABEL EXPORTS PROPERTIES (NAMES),
+    
+    %[1]s_EXPORTS_CLASS_ET KEY (EID)
+      SOURCE KEY (SOURCE_VID) REFERENCES %[1]s_FILE_VT (VID)
+      DESTINATION KEY (DEST_VID) REFERENCES %[1]s_CLASS_VT (VID)
+      LABEL EXPORTS PROPERTIES (NAMES),
+    
+    %[1]s_EXPORTS_INTERFACE_ET KEY (EID)
+      SOURCE KEY (SOURCE_VID) REFERENCES %[1]s_FILE_VT (VID)
+      DESTINATION KEY (DEST_VID) REFERENCES %[1]s_INTERFACE_VT (VID)
+      LABEL EXPORTS PROPERTIES (NAMES),
+    
+    %[1]s_EXPORTS_TYPE_ET KEY (EID)
+      SOURCE KEY (SOURCE_VID) REFERENCES %[1]s_FILE_VT (VID)
+      DESTINATION KEY (DEST_VID) REFERENCES %[1]s_TYPE_VT (VID)
+      LABEL EXPORTS PROPERTIES (NAMES),
+    
+    %[1]s_EXPORTS_VARIABLE_ET KEY (EID)
+      SOURCE KEY (SOURCE_VID) REFERENCES %[1]s_FILE_VT (VID)
+      DESTINATION KEY (DEST_VID) REFERENCES %[1]s_VARIABLE_VT (VID)
+      LABEL EXPORTS PROPERTIES (NAMES),
+    
+    %[1]s_EXPORTS_FILE_ET KEY (EID)
+      SOURCE KEY (SOURCE_VID) REFERENCES %[1]s_FILE_VT (VID)
+      DESTINATION KEY (DEST_VID) REFERENCES %[1]s_FILE_VT (VID)
+      LABEL EXPORTS PROPERTIES (NAMES),
+    
+    %[1]s_DEFINED_IN_ET KEY (EID)
+      SOURCE KEY (SOURCE_VID) REFERENCES %[1]s_VARIABLE_VT (VID)
+      DESTINATION KEY (DEST_VID) REFERENCES %[1]s_FILE_VT (VID)
       LABEL DEFINED_IN NO PROPERTIES,
     
-    %s_USED_IN_ET KEY (EID)
-      SOURCE KEY (SOURCE_VID) REFERENCES %s_JSXELEMENT_VT (VID)
-      DESTINATION KEY (DEST_VID) REFERENCES %s_FILE_VT (VID)
+    %[1]s_USED_IN_ET KEY (EID)
+      SOURCE KEY (SOURCE_VID) REFERENCES %[1]s_JSXELEMENT_VT (VID)
+      DESTINATION KEY (DEST_VID) REFERENCES %[1]s_FILE_VT (VID)
       LABEL USED_IN NO PROPERTIES,
     
-    %s_RENDERS_ET KEY (EID)
-      SOURCE KEY (SOURCE_VID) REFERENCES %s_FUNCTION_VT (VID)
-      DESTINATION KEY (DEST_VID) REFERENCES %s_JSXELEMENT_VT (VID)
+    %[1]s_RENDERS_ET KEY (EID)
+      SOURCE KEY (SOURCE_VID) REFERENCES %[1]s_FUNCTION_VT (VID)
+      DESTINATION KEY (DEST_VID) REFERENCES %[1]s_JSXELEMENT_VT (VID)
       LABEL RENDERS NO PROPERTIES,
     
-    %s_CONTAINS_CALL_ET KEY (EID)
-      SOURCE KEY (SOURCE_VID) REFERENCES %s_FILE_VT (VID)
-      DESTINATION KEY (DEST_VID) REFERENCES %s_UNRESOLVED_CALL_VT (VID)
+    %[1]s_COMPONENT_DEFINED_IN_ET KEY (EID)
+      SOURCE KEY (SOURCE_VID) REFERENCES %[1]s_COMPONENT_VT (VID)
+      DESTINATION KEY (DEST_VID) REFERENCES %[1]s_FILE_VT (VID)
+      LABEL DEFINED_IN NO PROPERTIES,
+    
+    %[1]s_COMPONENT_IMPLEMENTED_BY_FUNCTION_ET KEY (EID)
+      SOURCE KEY (SOURCE_VID) REFERENCES %[1]s_COMPONENT_VT (VID)
+      DESTINATION KEY (DEST_VID) REFERENCES %[1]s_FUNCTION_VT (VID)
+      LABEL IMPLEMENTED_BY NO PROPERTIES,
+    
+    %[1]s_COMPONENT_IMPLEMENTED_BY_CLASS_ET KEY (EID)
+      SOURCE KEY (SOURCE_VID) REFERENCES %[1]s_COMPONENT_VT (VID)
+      DESTINATION KEY (DEST_VID) REFERENCES %[1]s_CLASS_VT (VID)
+      LABEL IMPLEMENTED_BY NO PROPERTIES,
+    
+    %[1]s_COMPONENT_RENDERS_ET KEY (EID)
+      SOURCE KEY (SOURCE_VID) REFERENCES %[1]s_COMPONENT_VT (VID)
+      DESTINATION KEY (DEST_VID) REFERENCES %[1]s_COMPONENT_VT (VID)
+      LABEL RENDERS PROPERTIES (TAG_NAME, LINE_NUM),
+    
+    %[1]s_COMPONENT_USES_HOOK_ET KEY (EID)
+      SOURCE KEY (SOURCE_VID) REFERENCES %[1]s_COMPONENT_VT (VID)
+      DESTINATION KEY (DEST_VID) REFERENCES %[1]s_HOOK_VT (VID)
+      LABEL USES_HOOK PROPERTIES (LINE_NUM),
+    
+    %[1]s_COMPONENT_USES_HOOK_FUNCTION_ET KEY (EID)
+      SOURCE KEY (SOURCE_VID) REFERENCES %[1]s_COMPONENT_VT (VID)
+      DESTINATION KEY (DEST_VID) REFERENCES %[1]s_FUNCTION_VT (VID)
+      LABEL USES_HOOK PROPERTIES (LINE_NUM),
+    
+    %[1]s_JSXELEMENT_STYLED_BY_ET KEY (EID)
+      SOURCE KEY (SOURCE_VID) REFERENCES %[1]s_JSXELEMENT_VT (VID)
+      DESTINATION KEY (DEST_VID) REFERENCES %[1]s_CSSRULE_VT (VID)
+      LABEL STYLED_BY PROPERTIES (LINE_NUM),
+    
+    %[1]s_COMPONENT_STYLED_BY_ET KEY (EID)
+      SOURCE KEY (SOURCE_VID) REFERENCES %[1]s_COMPONENT_VT (VID)
+      DESTINATION KEY (DEST_VID) REFERENCES %[1]s_CSSRULE_VT (VID)
+      LABEL STYLED_BY PROPERTIES (LINE_NUM),
+    
+    %[1]s_CSSRULE_INCLUDES_ET KEY (EID)
+      SOURCE KEY (SOURCE_VID) REFERENCES %[1]s_CSSRULE_VT (VID)
+      DESTINATION KEY (DEST_VID) REFERENCES %[1]s_CSSRULE_VT (VID)
+      LABEL INCLUDES PROPERTIES (LINE_NUM),
+    
+    %[1]s_CSSRULE_EXTENDS_ET KEY (EID)
+      SOURCE KEY (SOURCE_VID) REFERENCES %[1]s_CSSRULE_VT (VID)
+      DESTINATION KEY (DEST_VID) REFERENCES %[1]s_CSSRULE_VT (VID)
+      LABEL EXTENDS PROPERTIES (LINE_NUM),
+    
+    %[1]s_CONTAINS_CALL_ET KEY (EID)
+      SOURCE KEY (SOURCE_VID) REFERENCES %[1]s_FILE_VT (VID)
+      DESTINATION KEY (DEST_VID) REFERENCES %[1]s_UNRESOLVED_CALL_VT (VID)
       LABEL CONTAINS_CALL NO PROPERTIES,
     
-    %s_MAKES_CALL_ET KEY (EID)
-      SOURCE KEY (SOURCE_VID) REFERENCES %s_FUNCTION_VT (VID)
-      DESTINATION KEY (DEST_VID) REFERENCES %s_UNRESOLVED_CALL_VT (VID)
+    %[1]s_MAKES_CALL_ET KEY (EID)
+      SOURCE KEY (SOURCE_VID) REFERENCES %[1]s_FUNCTION_VT (VID)
+      DESTINATION KEY (DEST_VID) REFERENCES %[1]s_UNRESOLVED_CALL_VT (VID)
       LABEL MAKES_CALL NO PROPERTIES
-  )`,
-		c.graphName,
-		// Vertex tables (11 entries)
-		c.graphName, c.graphName, c.graphName, c.graphName, c.graphName,
-		c.graphName, c.graphName, c.graphName, c.graphName, c.graphName, c.graphName,
-		// Edge tables with their references
-		c.graphName, c.graphName, c.graphName, // BELONGS_TO
-		c.graphName, c.graphName, c.graphName, // IMPORTS
-		c.graphName, c.graphName, c.graphName, // CALLS
-		c.graphName, c.graphName, c.graphName, // USES_TYPE
-		c.graphName, c.graphName, c.graphName, // EXTENDS
-		c.graphName, c.graphName, c.graphName, // IMPLEMENTS
-		c.graphName, c.graphName, c.graphName, // DEFINED_IN
-		c.graphName, c.graphName, c.graphName, // USED_IN
-		c.graphName, c.graphName, c.graphName, // RENDERS
-		c.graphName, c.graphName, c.graphName, // CONTAINS_CALL
-		c.graphName, c.graphName, c.graphName, // MAKES_CALL
-	)
+  )`, c.graphName)
 
 	_, err := c.db.Exec(pgDef)
 	if err != nil {
@@ -488,6 +1137,22 @@ func (c *OracleGraphClient) Close(ctx context.Context) error {
 	return c.db.Close()
 }
 
+// exec runs a statement on the bound transaction if any, otherwise on the pool.
+func (c *OracleGraphClient) exec(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	if c.tx != nil {
+		return c.tx.ExecContext(ctx, query, args...)
+	}
+	return c.db.ExecContext(ctx, query, args...)
+}
+
+// queryRow runs a single-row query on the bound transaction if any, otherwise on the pool.
+func (c *OracleGraphClient) queryRow(ctx context.Context, query string, args ...any) *sql.Row {
+	if c.tx != nil {
+		return c.tx.QueryRowContext(ctx, query, args...)
+	}
+	return c.db.QueryRowContext(ctx, query, args...)
+}
+
 // Helper function to convert values to Oracle format
 func oracleValue(v any) any {
 	switch val := v.(type) {
@@ -503,6 +1168,30 @@ func oracleValue(v any) any {
 	}
 }
 
+// oracleJSON encodes a slice as a JSON array for a CLOB column, or NULL when empty
+func oracleJSON[T any](values []T) (any, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %T: %w", values, err)
+	}
+	return string(data), nil
+}
+
+// oracleDoc encodes a doc comment as JSON for a DOC column, or NULL when absent
+func oracleDoc(doc *DocComment) (any, error) {
+	if doc == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode doc comment: %w", err)
+	}
+	return string(data), nil
+}
+
 // File Operations
 
 // UpsertFile ensures a File vertex exists with the given path and language
@@ -518,7 +1207,7 @@ func (c *OracleGraphClient) UpsertFile(ctx context.Context, path, language strin
 			VALUES (s.PATH, s.LANGUAGE, SYSTIMESTAMP)
 	`, c.graphName)
 
-	_, err := c.db.ExecContext(ctx, query, path, language)
+	_, err := c.exec(ctx, query, path, language)
 	return err
 }
 
@@ -529,35 +1218,59 @@ func (c *OracleGraphClient) UpsertFunction(ctx context.Context, fn FunctionEntit
 	// First, ensure the function exists
 	query := fmt.Sprintf(`
 		MERGE INTO %s_FUNCTION_VT f
-		USING (SELECT :1 AS NAME, :2 AS FILE_PATH FROM DUAL) s
-		ON (f.NAME = s.NAME AND f.FILE_PATH = s.FILE_PATH)
+		USING (SELECT :1 AS ID FROM DUAL) s
+		ON (f.ID = s.ID)
 		WHEN MATCHED THEN
 			UPDATE SET 
-				f.START_LINE = :3,
-				f.END_LINE = :4,
-				f.SIGNATURE = :5,
-				f.IS_ASYNC = :6,
-				f.IS_EXPORT = :7,
+				f.NAME = :2,
+				f.QUALIFIED_NAME = :3,
+				f.FILE_PATH = :4,
+				f.START_LINE = :5,
+				f.END_LINE = :6,
+				f.SIGNATURE = :7,
+				f.IS_ASYNC = :8,
+				f.IS_EXPORT = :9,
+				f.RETURN_TYPE = :10,
+				f.IS_GENERATOR = :11,
+				f.PARAMETERS = :12,
+				f.TYPE_PARAMETERS = :13,
+				f.DOC = :14,
+				f.IS_DEPRECATED = :15,
 				f.UPDATED = SYSTIMESTAMP
 		WHEN NOT MATCHED THEN
-			INSERT (NAME, FILE_PATH, START_LINE, END_LINE, SIGNATURE, IS_ASYNC, IS_EXPORT, CREATED)
-			VALUES (:1, :2, :3, :4, :5, :6, :7, SYSTIMESTAMP)
+			INSERT (ID, NAME, QUALIFIED_NAME, FILE_PATH, START_LINE, END_LINE, SIGNATURE, IS_ASYNC, IS_EXPORT,
+				RETURN_TYPE, IS_GENERATOR, PARAMETERS, TYPE_PARAMETERS, DOC, IS_DEPRECATED, CREATED)
+			VALUES (:1, :2, :3, :4, :5, :6, :7, :8, :9, :10, :11, :12, :13, :14, :15, SYSTIMESTAMP)
 	`, c.graphName)
 
-	_, err := c.db.ExecContext(ctx, query,
-		fn.Name, fn.FilePath, fn.StartLine, fn.EndLine,
-		fn.Signature, oracleValue(fn.IsAsync), oracleValue(fn.IsExport))
+	parameters, err := oracleJSON(fn.Parameters)
 	if err != nil {
 		return err
 	}
-
+	typeParameters, err := oracleJSON(fn.TypeParameters)
+	if err != nil {
+		return err
+	}
+	doc, err := oracleDoc(fn.Doc)
+	if err != nil {
+		return err
+	}
+	_, err = c.exec(ctx, query,
+		fn.nodeID(), fn.Name, fn.QualifiedName, fn.FilePath, fn.StartLine, fn.EndLine,
+		fn.Signature, oracleValue(fn.IsAsync), oracleValue(fn.IsExport),
+		fn.ReturnType, oracleValue(fn.IsGenerator), parameters, typeParameters,
+		doc, oracleValue(fn.Doc != nil && fn.Doc.Deprecated))
+	if err != nil {
+		return err
+	}
+
 	// Create BELONGS_TO edge
 	query2 := fmt.Sprintf(`
 		MERGE INTO %s_BELONGS_TO_ET e
 		USING (
 			SELECT func.VID AS SOURCE_VID, file.VID AS DEST_VID
 			FROM %s_FUNCTION_VT func, %s_FILE_VT file
-			WHERE func.NAME = :1 AND func.FILE_PATH = :2 AND file.PATH = :2
+			WHERE func.ID = :1 AND file.PATH = :2
 		) s
 		ON (e.SOURCE_VID = s.SOURCE_VID AND e.DEST_VID = s.DEST_VID)
 		WHEN NOT MATCHED THEN
@@ -565,13 +1278,14 @@ func (c *OracleGraphClient) UpsertFunction(ctx context.Context, fn FunctionEntit
 			VALUES (s.SOURCE_VID, s.DEST_VID, SYSTIMESTAMP)
 	`, c.graphName, c.graphName, c.graphName)
 
-	_, err = c.db.ExecContext(ctx, query2, fn.Name, fn.FilePath)
+	_, err = c.exec(ctx, query2, fn.nodeID(), fn.FilePath)
 	return err
 }
 
 // Import Operations
 
-// UpsertImport ensures an Import vertex exists and creates IMPORTS edge
+// UpsertImport ensures an Import vertex exists and creates IMPORTS edges to it
+// and to the resolved File or external Package
 func (c *OracleGraphClient) UpsertImport(ctx context.Context, imp ImportEntity) error {
 	// First, ensure the import exists
 	query := fmt.Sprintf(`
@@ -585,7 +1299,7 @@ func (c *OracleGraphClient) UpsertImport(ctx context.Context, imp ImportEntity)
 			VALUES (s.MODULE, SYSTIMESTAMP)
 	`, c.graphName)
 
-	_, err := c.db.ExecContext(ctx, query, imp.Module)
+	_, err := c.exec(ctx, query, imp.Module)
 	if err != nil {
 		return err
 	}
@@ -604,17 +1318,85 @@ func (c *OracleGraphClient) UpsertImport(ctx context.Context, imp ImportEntity)
 				e.IMPORTED_NAMES = :3,
 				e.IS_DEFAULT = :4,
 				e.IS_NAMESPACE = :5,
+				e.IS_DYNAMIC = :6,
 				e.UPDATED = SYSTIMESTAMP
 		WHEN NOT MATCHED THEN
-			INSERT (SOURCE_VID, DEST_VID, IMPORTED_NAMES, IS_DEFAULT, IS_NAMESPACE, CREATED)
-			VALUES (s.SOURCE_VID, s.DEST_VID, :3, :4, :5, SYSTIMESTAMP)
+			INSERT (SOURCE_VID, DEST_VID, IMPORTED_NAMES, IS_DEFAULT, IS_NAMESPACE, IS_DYNAMIC, CREATED)
+			VALUES (s.SOURCE_VID, s.DEST_VID, :3, :4, :5, :6, SYSTIMESTAMP)
 	`, c.graphName, c.graphName, c.graphName)
 
-	_, err = c.db.ExecContext(ctx, query2,
+	_, err = c.exec(ctx, query2,
 		imp.FilePath, imp.Module,
 		oracleValue(imp.ImportedNames),
 		oracleValue(imp.IsDefault),
-		oracleValue(imp.IsNamespace))
+		oracleValue(imp.IsNamespace),
+		oracleValue(imp.IsDynamic))
+	if err != nil {
+		return err
+	}
+
+	// Link the importing file to the resolved File or external Package
+	var edgeTable, destTable, destKey, destValue string
+	switch {
+	case imp.ResolvedFile != "":
+		// Ensure the target file exists; its language is filled in when it is parsed
+		query3 := fmt.Sprintf(`
+			MERGE INTO %s_FILE_VT f
+			USING (SELECT :1 AS PATH FROM DUAL) s
+			ON (f.PATH = s.PATH)
+			WHEN NOT MATCHED THEN
+				INSERT (PATH, CREATED)
+				VALUES (s.PATH, SYSTIMESTAMP)
+		`, c.graphName)
+		if _, err := c.exec(ctx, query3, imp.ResolvedFile); err != nil {
+			return err
+		}
+		edgeTable, destTable, destKey, destValue = "IMPORTS_FILE_ET", "FILE_VT", "PATH", imp.ResolvedFile
+	case imp.Package != "":
+		query3 := fmt.Sprintf(`
+			MERGE INTO %s_PACKAGE_VT p
+			USING (SELECT :1 AS NAME FROM DUAL) s
+			ON (p.NAME = s.NAME)
+			WHEN MATCHED THEN
+				UPDATE SET p.UPDATED = SYSTIMESTAMP
+			WHEN NOT MATCHED THEN
+				INSERT (NAME, CREATED)
+				VALUES (s.NAME, SYSTIMESTAMP)
+		`, c.graphName)
+		if _, err := c.exec(ctx, query3, imp.Package); err != nil {
+			return err
+		}
+		edgeTable, destTable, destKey, destValue = "IMPORTS_PACKAGE_ET", "PACKAGE_VT", "NAME", imp.Package
+	default:
+		return nil
+	}
+
+	query4 := fmt.Sprintf(`
+		MERGE INTO %[1]s_%[2]s e
+		USING (
+			SELECT file.VID AS SOURCE_VID, dest.VID AS DEST_VID
+			FROM %[1]s_FILE_VT file, %[1]s_%[3]s dest
+			WHERE file.PATH = :1 AND dest.%[4]s = :2
+		) s
+		ON (e.SOURCE_VID = s.SOURCE_VID AND e.DEST_VID = s.DEST_VID)
+		WHEN MATCHED THEN
+			UPDATE SET 
+				e.IMPORTED_NAMES = :3,
+				e.IS_DEFAULT = :4,
+				e.IS_NAMESPACE = :5,
+				e.IS_DYNAMIC = :6,
+				e.UPDATED = SYSTIMESTAMP
+		WHEN NOT MATCHED THEN
+			INSERT (SOURCE_VID, DEST_VID, IMPORTED_NAMES, IS_DEFAULT, IS_NAMESPACE, IS_DYNAMIC, CREATED)
+			VALUES (s.SOURCE_VID, s.DEST_VID, :3, :4, :5, :6, SYSTIMESTAMP)
+	`, c.graphName, edgeTable, destTable, destKey)
+
+	_, err = c.exec(ctx, query4,
+		imp.FilePath, destValue,
+		oracleValue(imp.ImportedNames),
+		oracleValue(imp.IsDefault),
+		oracleValue(imp.IsNamespace),
+		oracleValue(imp.IsDynamic))
 	return err
 }
 
@@ -632,15 +1414,22 @@ func (c *OracleGraphClient) UpsertVariable(ctx context.Context, variable Variabl
 				v.IS_CONST = :4,
 				v.IS_LET = :5,
 				v.START_LINE = :6,
+				v.DOC = :7,
+				v.IS_DEPRECATED = :8,
 				v.UPDATED = SYSTIMESTAMP
 		WHEN NOT MATCHED THEN
-			INSERT (NAME, FILE_PATH, VAR_TYPE, IS_CONST, IS_LET, START_LINE, CREATED)
-			VALUES (:1, :2, :3, :4, :5, :6, SYSTIMESTAMP)
+			INSERT (NAME, FILE_PATH, VAR_TYPE, IS_CONST, IS_LET, START_LINE, DOC, IS_DEPRECATED, CREATED)
+			VALUES (:1, :2, :3, :4, :5, :6, :7, :8, SYSTIMESTAMP)
 	`, c.graphName)
 
-	_, err := c.db.ExecContext(ctx, query,
+	doc, err := oracleDoc(variable.Doc)
+	if err != nil {
+		return err
+	}
+	_, err = c.exec(ctx, query,
 		variable.Name, variable.FilePath, variable.Type,
-		oracleValue(variable.IsConst), oracleValue(variable.IsLet), variable.StartLine)
+		oracleValue(variable.IsConst), oracleValue(variable.IsLet), variable.StartLine,
+		doc, oracleValue(variable.Doc != nil && variable.Doc.Deprecated))
 	if err != nil {
 		return err
 	}
@@ -659,7 +1448,7 @@ func (c *OracleGraphClient) UpsertVariable(ctx context.Context, variable Variabl
 			VALUES (s.SOURCE_VID, s.DEST_VID, SYSTIMESTAMP)
 	`, c.graphName, c.graphName, c.graphName)
 
-	_, err = c.db.ExecContext(ctx, query2, variable.Name, variable.FilePath)
+	_, err = c.exec(ctx, query2, variable.Name, variable.FilePath)
 	return err
 }
 
@@ -676,15 +1465,22 @@ func (c *OracleGraphClient) UpsertType(ctx context.Context, typeEntity TypeEntit
 				t.KIND = :3,
 				t.DEFINITION = :4,
 				t.IS_EXPORT = :5,
+				t.DOC = :6,
+				t.IS_DEPRECATED = :7,
 				t.UPDATED = SYSTIMESTAMP
 		WHEN NOT MATCHED THEN
-			INSERT (NAME, FILE_PATH, KIND, DEFINITION, IS_EXPORT, CREATED)
-			VALUES (:1, :2, :3, :4, :5, SYSTIMESTAMP)
+			INSERT (NAME, FILE_PATH, KIND, DEFINITION, IS_EXPORT, DOC, IS_DEPRECATED, CREATED)
+			VALUES (:1, :2, :3, :4, :5, :6, :7, SYSTIMESTAMP)
 	`, c.graphName)
 
-	_, err := c.db.ExecContext(ctx, query,
+	doc, err := oracleDoc(typeEntity.Doc)
+	if err != nil {
+		return err
+	}
+	_, err = c.exec(ctx, query,
 		typeEntity.Name, typeEntity.FilePath, typeEntity.Kind,
-		typeEntity.Definition, oracleValue(typeEntity.IsExport))
+		typeEntity.Definition, oracleValue(typeEntity.IsExport),
+		doc, oracleValue(typeEntity.Doc != nil && typeEntity.Doc.Deprecated))
 	if err != nil {
 		return err
 	}
@@ -703,7 +1499,7 @@ func (c *OracleGraphClient) UpsertType(ctx context.Context, typeEntity TypeEntit
 			VALUES (s.SOURCE_VID, s.DEST_VID, SYSTIMESTAMP)
 	`, c.graphName, c.graphName, c.graphName)
 
-	_, err = c.db.ExecContext(ctx, query2, typeEntity.Name, typeEntity.FilePath)
+	_, err = c.exec(ctx, query2, typeEntity.Name, typeEntity.FilePath)
 	return err
 }
 
@@ -719,15 +1515,22 @@ func (c *OracleGraphClient) UpsertInterface(ctx context.Context, iface Interface
 			UPDATE SET 
 				i.IS_EXPORT = :3,
 				i.PROPERTIES = :4,
+				i.DOC = :5,
+				i.IS_DEPRECATED = :6,
 				i.UPDATED = SYSTIMESTAMP
 		WHEN NOT MATCHED THEN
-			INSERT (NAME, FILE_PATH, IS_EXPORT, PROPERTIES, CREATED)
-			VALUES (:1, :2, :3, :4, SYSTIMESTAMP)
+			INSERT (NAME, FILE_PATH, IS_EXPORT, PROPERTIES, DOC, IS_DEPRECATED, CREATED)
+			VALUES (:1, :2, :3, :4, :5, :6, SYSTIMESTAMP)
 	`, c.graphName)
 
-	_, err := c.db.ExecContext(ctx, query,
+	doc, err := oracleDoc(iface.Doc)
+	if err != nil {
+		return err
+	}
+	_, err = c.exec(ctx, query,
 		iface.Name, iface.FilePath,
-		oracleValue(iface.IsExport), oracleValue(iface.Properties))
+		oracleValue(iface.IsExport), oracleValue(iface.Properties),
+		doc, oracleValue(iface.Doc != nil && iface.Doc.Deprecated))
 	if err != nil {
 		return err
 	}
@@ -746,7 +1549,7 @@ func (c *OracleGraphClient) UpsertInterface(ctx context.Context, iface Interface
 			VALUES (s.SOURCE_VID, s.DEST_VID, SYSTIMESTAMP)
 	`, c.graphName, c.graphName, c.graphName)
 
-	_, err = c.db.ExecContext(ctx, query2, iface.Name, iface.FilePath)
+	_, err = c.exec(ctx, query2, iface.Name, iface.FilePath)
 	return err
 }
 
@@ -756,25 +1559,35 @@ func (c *OracleGraphClient) UpsertInterface(ctx context.Context, iface Interface
 func (c *OracleGraphClient) UpsertClass(ctx context.Context, class ClassEntity) error {
 	query := fmt.Sprintf(`
 		MERGE INTO %s_CLASS_VT c
-		USING (SELECT :1 AS NAME, :2 AS FILE_PATH FROM DUAL) s
-		ON (c.NAME = s.NAME AND c.FILE_PATH = s.FILE_PATH)
+		USING (SELECT :1 AS ID FROM DUAL) s
+		ON (c.ID = s.ID)
 		WHEN MATCHED THEN
 			UPDATE SET 
-				c.START_LINE = :3,
-				c.END_LINE = :4,
-				c.IS_EXPORT = :5,
-				c.IS_ABSTRACT = :6,
-				c.METHODS = :7,
+				c.NAME = :2,
+				c.QUALIFIED_NAME = :3,
+				c.FILE_PATH = :4,
+				c.START_LINE = :5,
+				c.END_LINE = :6,
+				c.IS_EXPORT = :7,
+				c.IS_ABSTRACT = :8,
+				c.METHODS = :9,
+				c.DOC = :10,
+				c.IS_DEPRECATED = :11,
 				c.UPDATED = SYSTIMESTAMP
 		WHEN NOT MATCHED THEN
-			INSERT (NAME, FILE_PATH, START_LINE, END_LINE, IS_EXPORT, IS_ABSTRACT, METHODS, CREATED)
-			VALUES (:1, :2, :3, :4, :5, :6, :7, SYSTIMESTAMP)
+			INSERT (ID, NAME, QUALIFIED_NAME, FILE_PATH, START_LINE, END_LINE, IS_EXPORT, IS_ABSTRACT, METHODS,
+				DOC, IS_DEPRECATED, CREATED)
+			VALUES (:1, :2, :3, :4, :5, :6, :7, :8, :9, :10, :11, SYSTIMESTAMP)
 	`, c.graphName)
 
-	_, err := c.db.ExecContext(ctx, query,
-		class.Name, class.FilePath, class.StartLine, class.EndLine,
+	doc, err := oracleDoc(class.Doc)
+	if err != nil {
+		return err
+	}
+	_, err = c.exec(ctx, query,
+		class.nodeID(), class.Name, class.QualifiedName, class.FilePath, class.StartLine, class.EndLine,
 		oracleValue(class.IsExport), oracleValue(class.IsAbstract),
-		oracleValue(class.Methods))
+		oracleValue(class.Methods), doc, oracleValue(class.Doc != nil && class.Doc.Deprecated))
 	if err != nil {
 		return err
 	}
@@ -785,7 +1598,7 @@ func (c *OracleGraphClient) UpsertClass(ctx context.Context, class ClassEntity)
 		USING (
 			SELECT c.VID AS SOURCE_VID, f.VID AS DEST_VID
 			FROM %s_CLASS_VT c, %s_FILE_VT f
-			WHERE c.NAME = :1 AND c.FILE_PATH = :2 AND f.PATH = :2
+			WHERE c.ID = :1 AND f.PATH = :2
 		) s
 		ON (e.SOURCE_VID = s.SOURCE_VID AND e.DEST_VID = s.DEST_VID)
 		WHEN NOT MATCHED THEN
@@ -793,7 +1606,349 @@ func (c *OracleGraphClient) UpsertClass(ctx context.Context, class ClassEntity)
 			VALUES (s.SOURCE_VID, s.DEST_VID, SYSTIMESTAMP)
 	`, c.graphName, c.graphName, c.graphName)
 
-	_, err = c.db.ExecContext(ctx, query2, class.Name, class.FilePath)
+	_, err = c.exec(ctx, query2, class.nodeID(), class.FilePath)
+	return err
+}
+
+// Member Operations
+
+// UpsertMember ensures a Method or Property vertex exists and creates the
+// HAS_MEMBER edge from its class and, for methods, IMPLEMENTED_BY to their
+// Function vertex
+func (c *OracleGraphClient) UpsertMember(ctx context.Context, member MemberEntity) error {
+	table := strings.ToUpper(member.label())
+	query := fmt.Sprintf(`
+		MERGE INTO %s_%s_VT m
+		USING (SELECT :1 AS ID FROM DUAL) s
+		ON (m.ID = s.ID)
+		WHEN MATCHED THEN
+			UPDATE SET 
+				m.NAME = :2,
+				m.KIND = :3,
+				m.CLASS_NAME = :4,
+				m.FILE_PATH = :5,
+				m.START_LINE = :6,
+				m.END_LINE = :7,
+				m.DECLARED_TYPE = :8,
+				m.VISIBILITY = :9,
+				m.IS_STATIC = :10,
+				m.IS_ABSTRACT = :11,
+				m.IS_READONLY = :12,
+				m.UPDATED = SYSTIMESTAMP
+		WHEN NOT MATCHED THEN
+			INSERT (ID, NAME, KIND, CLASS_NAME, FILE_PATH, START_LINE, END_LINE, DECLARED_TYPE, VISIBILITY, IS_STATIC, IS_ABSTRACT, IS_READONLY, CREATED)
+			VALUES (:1, :2, :3, :4, :5, :6, :7, :8, :9, :10, :11, :12, SYSTIMESTAMP)
+	`, c.graphName, table)
+
+	_, err := c.exec(ctx, query,
+		member.nodeID(), member.Name, member.Kind, member.ClassName, member.FilePath,
+		member.StartLine, member.EndLine, member.Type, member.Visibility,
+		oracleValue(member.IsStatic), oracleValue(member.IsAbstract), oracleValue(member.IsReadonly))
+	if err != nil {
+		return err
+	}
+
+	// Create HAS_MEMBER edge
+	query2 := fmt.Sprintf(`
+		MERGE INTO %[1]s_HAS_%[2]s_ET e
+		USING (
+			SELECT c.VID AS SOURCE_VID, m.VID AS DEST_VID
+			FROM %[1]s_CLASS_VT c, %[1]s_%[2]s_VT m
+			WHERE c.ID = :1 AND m.ID = :2
+		) s
+		ON (e.SOURCE_VID = s.SOURCE_VID AND e.DEST_VID = s.DEST_VID)
+		WHEN NOT MATCHED THEN
+			INSERT (SOURCE_VID, DEST_VID, CREATED)
+			VALUES (s.SOURCE_VID, s.DEST_VID, SYSTIMESTAMP)
+	`, c.graphName, table)
+
+	if _, err := c.exec(ctx, query2, member.ClassID, member.nodeID()); err != nil {
+		return err
+	}
+	if table != "METHOD" {
+		return nil
+	}
+
+	// Create IMPLEMENTED_BY edge to the Function sharing the method's ID
+	query3 := fmt.Sprintf(`
+		MERGE INTO %[1]s_IMPLEMENTED_BY_ET e
+		USING (
+			SELECT m.VID AS SOURCE_VID, f.VID AS DEST_VID
+			FROM %[1]s_METHOD_VT m, %[1]s_FUNCTION_VT f
+			WHERE m.ID = :1 AND f.ID = m.ID
+		) s
+		ON (e.SOURCE_VID = s.SOURCE_VID AND e.DEST_VID = s.DEST_VID)
+		WHEN NOT MATCHED THEN
+			INSERT (SOURCE_VID, DEST_VID, CREATED)
+			VALUES (s.SOURCE_VID, s.DEST_VID, SYSTIMESTAMP)
+	`, c.graphName)
+
+	_, err = c.exec(ctx, query3, member.nodeID())
+	return err
+}
+
+// Decorator Operations
+
+// UpsertDecorator ensures a Decorator vertex exists and creates the
+// DECORATED_BY edge from the class or member it decorates
+func (c *OracleGraphClient) UpsertDecorator(ctx context.Context, decorator DecoratorEntity) error {
+	query := fmt.Sprintf(`
+		MERGE INTO %s_DECORATOR_VT d
+		USING (SELECT :1 AS ID FROM DUAL) s
+		ON (d.ID = s.ID)
+		WHEN MATCHED THEN
+			UPDATE SET 
+				d.NAME = :2,
+				d.ARGUMENTS = :3,
+				d.TARGET_KIND = :4,
+				d.PARAMETER_NAME = :5,
+				d.FILE_PATH = :6,
+				d.LINE_NUM = :7,
+				d.UPDATED = SYSTIMESTAMP
+		WHEN NOT MATCHED THEN
+			INSERT (ID, NAME, ARGUMENTS, TARGET_KIND, PARAMETER_NAME, FILE_PATH, LINE_NUM, CREATED)
+			VALUES (:1, :2, :3, :4, :5, :6, :7, SYSTIMESTAMP)
+	`, c.graphName)
+
+	arguments, err := oracleJSON(decorator.Arguments)
+	if err != nil {
+		return err
+	}
+	_, err = c.exec(ctx, query,
+		decorator.ID, decorator.Name, arguments, decorator.TargetKind,
+		decorator.Parameter, decorator.FilePath, decorator.Line)
+	if err != nil {
+		return err
+	}
+
+	// Create DECORATED_BY edge
+	query2 := fmt.Sprintf(`
+		MERGE INTO %[1]s_DECORATED_BY_%[2]s_ET e
+		USING (
+			SELECT t.VID AS SOURCE_VID, d.VID AS DEST_VID
+			FROM %[1]s_%[2]s_VT t, %[1]s_DECORATOR_VT d
+			WHERE t.ID = :1 AND d.ID = :2
+		) s
+		ON (e.SOURCE_VID = s.SOURCE_VID AND e.DEST_VID = s.DEST_VID)
+		WHEN NOT MATCHED THEN
+			INSERT (SOURCE_VID, DEST_VID, CREATED)
+			VALUES (s.SOURCE_VID, s.DEST_VID, SYSTIMESTAMP)
+	`, c.graphName, strings.ToUpper(decorator.targetLabel()))
+
+	_, err = c.exec(ctx, query2, decorator.TargetID, decorator.ID)
+	return err
+}
+
+// Component Operations
+
+// UpsertComponent ensures a Component vertex exists and creates the
+// DEFINED_IN edge to its file and IMPLEMENTED_BY to its function or class
+func (c *OracleGraphClient) UpsertComponent(ctx context.Context, component ComponentEntity) error {
+	query := fmt.Sprintf(`
+		MERGE INTO %s_COMPONENT_VT c
+		USING (SELECT :1 AS ID FROM DUAL) s
+		ON (c.ID = s.ID)
+		WHEN MATCHED THEN
+			UPDATE SET 
+				c.NAME = :2,
+				c.KIND = :3,
+				c.FILE_PATH = :4,
+				c.START_LINE = :5,
+				c.END_LINE = :6,
+				c.PROPS_TYPE = :7,
+				c.UPDATED = SYSTIMESTAMP
+		WHEN NOT MATCHED THEN
+			INSERT (ID, NAME, KIND, FILE_PATH, START_LINE, END_LINE, PROPS_TYPE, CREATED)
+			VALUES (:1, :2, :3, :4, :5, :6, :7, SYSTIMESTAMP)
+	`, c.graphName)
+
+	_, err := c.exec(ctx, query,
+		component.ID, component.Name, component.Kind, component.FilePath,
+		component.StartLine, component.EndLine, component.PropsType)
+	if err != nil {
+		return err
+	}
+
+	// Create DEFINED_IN edge
+	query2 := fmt.Sprintf(`
+		MERGE INTO %[1]s_COMPONENT_DEFINED_IN_ET e
+		USING (
+			SELECT c.VID AS SOURCE_VID, f.VID AS DEST_VID
+			FROM %[1]s_COMPONENT_VT c, %[1]s_FILE_VT f
+			WHERE c.ID = :1 AND f.PATH = :2
+		) s
+		ON (e.SOURCE_VID = s.SOURCE_VID AND e.DEST_VID = s.DEST_VID)
+		WHEN NOT MATCHED THEN
+			INSERT (SOURCE_VID, DEST_VID, CREATED)
+			VALUES (s.SOURCE_VID, s.DEST_VID, SYSTIMESTAMP)
+	`, c.graphName)
+
+	_, err = c.exec(ctx, query2, component.ID, component.FilePath)
+	if err != nil {
+		return err
+	}
+
+	// Create IMPLEMENTED_BY edge, when the component has a declaration of its own
+	query3 := fmt.Sprintf(`
+		MERGE INTO %[1]s_COMPONENT_IMPLEMENTED_BY_%[2]s_ET e
+		USING (
+			SELECT c.VID AS SOURCE_VID, t.VID AS DEST_VID
+			FROM %[1]s_COMPONENT_VT c, %[1]s_%[2]s_VT t
+			WHERE c.ID = :1 AND t.ID = :1
+		) s
+		ON (e.SOURCE_VID = s.SOURCE_VID AND e.DEST_VID = s.DEST_VID)
+		WHEN NOT MATCHED THEN
+			INSERT (SOURCE_VID, DEST_VID, CREATED)
+			VALUES (s.SOURCE_VID, s.DEST_VID, SYSTIMESTAMP)
+	`, c.graphName, strings.ToUpper(component.implementationLabel()))
+
+	_, err = c.exec(ctx, query3, component.ID)
+	return err
+}
+
+// UpsertRender creates a RENDERS edge from a component to a component it
+// renders. Tags that resolve to no component are left to their JSXElement
+func (c *OracleGraphClient) UpsertRender(ctx context.Context, render RenderEntity) error {
+	if render.TargetID == "" {
+		return nil
+	}
+	query := fmt.Sprintf(`
+		MERGE INTO %[1]s_COMPONENT_RENDERS_ET e
+		USING (
+			SELECT c.VID AS SOURCE_VID, t.VID AS DEST_VID
+			FROM %[1]s_COMPONENT_VT c, %[1]s_COMPONENT_VT t
+			WHERE c.ID = :1 AND t.ID = :2
+		) s
+		ON (e.SOURCE_VID = s.SOURCE_VID AND e.DEST_VID = s.DEST_VID)
+		WHEN MATCHED THEN
+			UPDATE SET e.TAG_NAME = :3, e.LINE_NUM = :4, e.UPDATED = SYSTIMESTAMP
+		WHEN NOT MATCHED THEN
+			INSERT (SOURCE_VID, DEST_VID, TAG_NAME, LINE_NUM, CREATED)
+			VALUES (s.SOURCE_VID, s.DEST_VID, :3, :4, SYSTIMESTAMP)
+	`, c.graphName)
+
+	_, err := c.exec(ctx, query, render.ComponentID, render.TargetID, render.TagName, render.Line)
+	return err
+}
+
+// UpsertHookUsage creates a USES_HOOK edge from a component to the function
+// of a custom hook, or to a Hook vertex for library hooks
+func (c *OracleGraphClient) UpsertHookUsage(ctx context.Context, usage HookUsageEntity) error {
+	edges, target, key := "COMPONENT_USES_HOOK_FUNCTION_ET", "FUNCTION_VT t WHERE t.ID = :2", usage.TargetID
+	if usage.TargetID == "" {
+		edges, target, key = "COMPONENT_USES_HOOK_ET", "HOOK_VT t WHERE t.NAME = :2", usage.Hook
+
+		query := fmt.Sprintf(`
+			MERGE INTO %s_HOOK_VT h
+			USING (SELECT :1 AS NAME FROM DUAL) s
+			ON (h.NAME = s.NAME)
+			WHEN NOT MATCHED THEN
+				INSERT (NAME, CREATED)
+				VALUES (:1, SYSTIMESTAMP)
+		`, c.graphName)
+		if _, err := c.exec(ctx, query, usage.Hook); err != nil {
+			return err
+		}
+	}
+
+	query := fmt.Sprintf(`
+		MERGE INTO %[1]s_%[2]s e
+		USING (
+			SELECT c.VID AS SOURCE_VID, t.VID AS DEST_VID
+			FROM %[1]s_COMPONENT_VT c, %[1]s_%[3]s AND c.ID = :1
+		) s
+		ON (e.SOURCE_VID = s.SOURCE_VID AND e.DEST_VID = s.DEST_VID)
+		WHEN MATCHED THEN
+			UPDATE SET e.LINE_NUM = :3, e.UPDATED = SYSTIMESTAMP
+		WHEN NOT MATCHED THEN
+			INSERT (SOURCE_VID, DEST_VID, LINE_NUM, CREATED)
+			VALUES (s.SOURCE_VID, s.DEST_VID, :3, SYSTIMESTAMP)
+	`, c.graphName, edges, target)
+
+	_, err := c.exec(ctx, query, usage.ComponentID, key, usage.Line)
+	return err
+}
+
+// UpsertClassUsage creates STYLED_BY edges from a JSX element and its
+// component to the CSS rules of a class it names. CSS Modules classes match
+// the rules of their stylesheet, other classes those of global stylesheets
+func (c *OracleGraphClient) UpsertClassUsage(ctx context.Context, usage ClassUsageEntity) error {
+	if usage.Module != "" && usage.ResolvedFile == "" {
+		return nil
+	}
+	rules := `r.SELECTOR = :4 AND r.FILE_PATH NOT LIKE '%.module.%'`
+	args := []any{usage.TagName, usage.FilePath, usage.Line, "." + usage.ClassName}
+	if usage.Module != "" {
+		rules = `r.SELECTOR = :4 AND r.FILE_PATH = :5`
+		args = append(args, usage.ResolvedFile)
+	}
+
+	query := fmt.Sprintf(`
+		MERGE INTO %[1]s_JSXELEMENT_STYLED_BY_ET e
+		USING (
+			SELECT j.VID AS SOURCE_VID, r.VID AS DEST_VID
+			FROM %[1]s_JSXELEMENT_VT j, %[1]s_CSSRULE_VT r
+			WHERE j.TAG_NAME = :1 AND j.FILE_PATH = :2 AND j.LINE_NUM = :3 AND %[2]s
+		) s
+		ON (e.SOURCE_VID = s.SOURCE_VID AND e.DEST_VID = s.DEST_VID)
+		WHEN MATCHED THEN
+			UPDATE SET e.LINE_NUM = :3, e.UPDATED = SYSTIMESTAMP
+		WHEN NOT MATCHED THEN
+			INSERT (SOURCE_VID, DEST_VID, LINE_NUM, CREATED)
+			VALUES (s.SOURCE_VID, s.DEST_VID, :3, SYSTIMESTAMP)
+	`, c.graphName, rules)
+	if _, err := c.exec(ctx, query, args...); err != nil {
+		return err
+	}
+	if usage.ComponentID == "" {
+		return nil
+	}
+
+	args[0] = usage.ComponentID
+	query = fmt.Sprintf(`
+		MERGE INTO %[1]s_COMPONENT_STYLED_BY_ET e
+		USING (
+			SELECT c.VID AS SOURCE_VID, r.VID AS DEST_VID
+			FROM %[1]s_COMPONENT_VT c, %[1]s_CSSRULE_VT r
+			WHERE c.ID = :1 AND c.FILE_PATH = :2 AND %[2]s
+		) s
+		ON (e.SOURCE_VID = s.SOURCE_VID AND e.DEST_VID = s.DEST_VID)
+		WHEN MATCHED THEN
+			UPDATE SET e.LINE_NUM = :3, e.UPDATED = SYSTIMESTAMP
+		WHEN NOT MATCHED THEN
+			INSERT (SOURCE_VID, DEST_VID, LINE_NUM, CREATED)
+			VALUES (s.SOURCE_VID, s.DEST_VID, :3, SYSTIMESTAMP)
+	`, c.graphName, rules)
+	_, err := c.exec(ctx, query, args...)
+	return err
+}
+
+// UpsertStyleReference creates an INCLUDES edge from a CSS rule to the SCSS
+// mixin it includes, or an EXTENDS edge to the selector it extends
+func (c *OracleGraphClient) UpsertStyleReference(ctx context.Context, ref StyleReferenceEntity) error {
+	if ref.Source == "" || ref.TargetFile == "" {
+		return nil
+	}
+	edges := "CSSRULE_EXTENDS_ET"
+	if ref.Kind == "include" {
+		edges = "CSSRULE_INCLUDES_ET"
+	}
+	query := fmt.Sprintf(`
+		MERGE INTO %[1]s_%[2]s e
+		USING (
+			SELECT s.VID AS SOURCE_VID, t.VID AS DEST_VID
+			FROM %[1]s_CSSRULE_VT s, %[1]s_CSSRULE_VT t
+			WHERE s.SELECTOR = :1 AND s.FILE_PATH = :2 AND t.SELECTOR = :3 AND t.FILE_PATH = :4
+		) s
+		ON (e.SOURCE_VID = s.SOURCE_VID AND e.DEST_VID = s.DEST_VID)
+		WHEN MATCHED THEN
+			UPDATE SET e.LINE_NUM = :5, e.UPDATED = SYSTIMESTAMP
+		WHEN NOT MATCHED THEN
+			INSERT (SOURCE_VID, DEST_VID, LINE_NUM, CREATED)
+			VALUES (s.SOURCE_VID, s.DEST_VID, :5, SYSTIMESTAMP)
+	`, c.graphName, edges)
+
+	_, err := c.exec(ctx, query, ref.Source, ref.FilePath, ref.Target, ref.TargetFile, ref.Line)
 	return err
 }
 
@@ -808,14 +1963,21 @@ func (c *OracleGraphClient) UpsertConstant(ctx context.Context, constant Constan
 		WHEN MATCHED THEN
 			UPDATE SET 
 				c.VALUE = :3,
+				c.DOC = :4,
+				c.IS_DEPRECATED = :5,
 				c.UPDATED = SYSTIMESTAMP
 		WHEN NOT MATCHED THEN
-			INSERT (NAME, FILE_PATH, VALUE, CREATED)
-			VALUES (:1, :2, :3, SYSTIMESTAMP)
+			INSERT (NAME, FILE_PATH, VALUE, DOC, IS_DEPRECATED, CREATED)
+			VALUES (:1, :2, :3, :4, :5, SYSTIMESTAMP)
 	`, c.graphName)
 
-	_, err := c.db.ExecContext(ctx, query,
-		constant.Name, constant.FilePath, constant.Value)
+	doc, err := oracleDoc(constant.Doc)
+	if err != nil {
+		return err
+	}
+	_, err = c.exec(ctx, query,
+		constant.Name, constant.FilePath, constant.Value,
+		doc, oracleValue(constant.Doc != nil && constant.Doc.Deprecated))
 	if err != nil {
 		return err
 	}
@@ -834,7 +1996,7 @@ func (c *OracleGraphClient) UpsertConstant(ctx context.Context, constant Constan
 			VALUES (s.SOURCE_VID, s.DEST_VID, SYSTIMESTAMP)
 	`, c.graphName, c.graphName, c.graphName)
 
-	_, err = c.db.ExecContext(ctx, query2, constant.Name, constant.FilePath)
+	_, err = c.exec(ctx, query2, constant.Name, constant.FilePath)
 	return err
 }
 
@@ -842,27 +2004,24 @@ func (c *OracleGraphClient) UpsertConstant(ctx context.Context, constant Constan
 
 // UpsertJSXElement ensures a JSXElement vertex exists and creates relationships
 func (c *OracleGraphClient) UpsertJSXElement(ctx context.Context, jsx JSXElementEntity) error {
-	// Determine if it's a custom component
-	jsx.IsCustomComponent = len(jsx.TagName) > 0 && strings.ToUpper(jsx.TagName[:1]) == jsx.TagName[:1]
-
 	// Use a sequence for unique ID if line is not unique enough
 	query := fmt.Sprintf(`
 		INSERT INTO %s_JSXELEMENT_VT 
-		(TAG_NAME, FILE_PATH, LINE_NUM, CONTAINING_COMPONENT, PROPS, IS_CUSTOM_COMPONENT, CREATED)
-		VALUES (:1, :2, :3, :4, :5, :6, SYSTIMESTAMP)
+		(TAG_NAME, FILE_PATH, LINE_NUM, CONTAINING_COMPONENT, PROPS, IS_CUSTOM_COMPONENT, CLASS_NAMES, CREATED)
+		VALUES (:1, :2, :3, :4, :5, :6, :7, SYSTIMESTAMP)
 	`, c.graphName)
 
-	result, err := c.db.ExecContext(ctx, query,
+	result, err := c.exec(ctx, query,
 		jsx.TagName, jsx.FilePath, jsx.Line,
 		jsx.ContainingComponent, oracleValue(jsx.Props),
-		oracleValue(jsx.IsCustomComponent))
+		oracleValue(jsx.IsCustomComponent), oracleValue(jsx.ClassNames))
 	if err != nil {
 		return err
 	}
 
 	// Get the inserted VID using RETURNING clause would be better, but for now we'll query
 	var vid int64
-	err = c.db.QueryRowContext(ctx, fmt.Sprintf(`
+	err = c.queryRow(ctx, fmt.Sprintf(`
 		SELECT VID FROM %s_JSXELEMENT_VT 
 		WHERE TAG_NAME = :1 AND FILE_PATH = :2 AND LINE_NUM = :3
 		ORDER BY CREATED DESC
@@ -885,7 +2044,7 @@ func (c *OracleGraphClient) UpsertJSXElement(ctx context.Context, jsx JSXElement
 		WHERE f.PATH = :2
 	`, c.graphName, c.graphName)
 
-	_, err = c.db.ExecContext(ctx, query2, vid, jsx.FilePath)
+	_, err = c.exec(ctx, query2, vid, jsx.FilePath)
 	if err != nil {
 		return err
 	}
@@ -899,7 +2058,7 @@ func (c *OracleGraphClient) UpsertJSXElement(ctx context.Context, jsx JSXElement
 			WHERE func.NAME = :2 AND func.FILE_PATH = :3
 		`, c.graphName, c.graphName)
 
-		_, _ = c.db.ExecContext(ctx, query3, vid, jsx.ContainingComponent, jsx.FilePath)
+		_, _ = c.exec(ctx, query3, vid, jsx.ContainingComponent, jsx.FilePath)
 	}
 
 	return nil
@@ -919,15 +2078,16 @@ func (c *OracleGraphClient) UpsertCSSRule(ctx context.Context, css CSSRuleEntity
 				c.LINE_NUM = :4,
 				c.PROPERTY_NAME = :5,
 				c.VALUE = :6,
+				c.FULL_SELECTOR = :7,
 				c.UPDATED = SYSTIMESTAMP
 		WHEN NOT MATCHED THEN
-			INSERT (SELECTOR, FILE_PATH, RULE_TYPE, LINE_NUM, PROPERTY_NAME, VALUE, CREATED)
-			VALUES (:1, :2, :3, :4, :5, :6, SYSTIMESTAMP)
+			INSERT (SELECTOR, FILE_PATH, RULE_TYPE, LINE_NUM, PROPERTY_NAME, VALUE, FULL_SELECTOR, CREATED)
+			VALUES (:1, :2, :3, :4, :5, :6, :7, SYSTIMESTAMP)
 	`, c.graphName)
 
-	_, err := c.db.ExecContext(ctx, query,
+	_, err := c.exec(ctx, query,
 		css.Selector, css.FilePath, css.RuleType,
-		css.Line, css.PropertyName, css.Value)
+		css.Line, css.PropertyName, css.Value, css.FullSelector)
 	if err != nil {
 		return err
 	}
@@ -946,7 +2106,7 @@ func (c *OracleGraphClient) UpsertCSSRule(ctx context.Context, css CSSRuleEntity
 			VALUES (s.SOURCE_VID, s.DEST_VID, SYSTIMESTAMP)
 	`, c.graphName, c.graphName, c.graphName)
 
-	_, err = c.db.ExecContext(ctx, query2, css.Selector, css.FilePath)
+	_, err = c.exec(ctx, query2, css.Selector, css.FilePath)
 	return err
 }
 
@@ -961,23 +2121,21 @@ func (c *OracleGraphClient) UpsertFunctionCall(ctx context.Context, call Functio
 			USING (
 				SELECT caller.VID AS SOURCE_VID, target.VID AS DEST_VID
 				FROM %s_FUNCTION_VT caller, %s_FUNCTION_VT target
-				WHERE caller.NAME = :1 AND caller.FILE_PATH = :2
-				  AND target.NAME = :3 AND target.FILE_PATH = :4
+				WHERE caller.ID = :1 AND target.ID = :2
 			) s
 			ON (e.SOURCE_VID = s.SOURCE_VID AND e.DEST_VID = s.DEST_VID)
 			WHEN MATCHED THEN
 				UPDATE SET 
-					e.CALL_LOCATION = :5,
-					e.CALL_CONTEXT = :6,
+					e.CALL_LOCATION = :3,
+					e.CALL_CONTEXT = :4,
 					e.UPDATED = SYSTIMESTAMP
 			WHEN NOT MATCHED THEN
 				INSERT (SOURCE_VID, DEST_VID, CALL_LOCATION, CALL_CONTEXT, CREATED)
-				VALUES (s.SOURCE_VID, s.DEST_VID, :5, :6, SYSTIMESTAMP)
+				VALUES (s.SOURCE_VID, s.DEST_VID, :3, :4, SYSTIMESTAMP)
 		`, c.graphName, c.graphName, c.graphName)
 
-		_, err := c.db.ExecContext(ctx, query,
-			call.CallerFunc, call.CallerFile,
-			call.ResolvedTarget, call.TargetFile,
+		_, err := c.exec(ctx, query,
+			call.callerNodeID(), call.targetNodeID(),
 			call.CallLocation, call.CallContext)
 		return err
 	} else {
@@ -997,7 +2155,7 @@ func (c *OracleGraphClient) UpsertFunctionCall(ctx context.Context, call Functio
 				VALUES (:1, :2, :3, :4, :5, SYSTIMESTAMP)
 		`, c.graphName)
 
-		_, err := c.db.ExecContext(ctx, query,
+		_, err := c.exec(ctx, query,
 			call.CalledFunc, call.CallerFile, call.CallerFunc,
 			call.CallLocation, call.CallContext)
 		if err != nil {
@@ -1007,7 +2165,7 @@ func (c *OracleGraphClient) UpsertFunctionCall(ctx context.Context, call Functio
 		// Create edges for unresolved calls
 		// Get the unresolved call VID
 		var ucVID int64
-		err = c.db.QueryRowContext(ctx, fmt.Sprintf(`
+		err = c.queryRow(ctx, fmt.Sprintf(`
 			SELECT VID FROM %s_UNRESOLVED_CALL_VT
 			WHERE CALLED_FUNC = :1 AND CALLER_FILE = :2 
 			  AND CALLER_FUNC = :3 AND LINE_NUM = :4
@@ -1028,7 +2186,7 @@ func (c *OracleGraphClient) UpsertFunctionCall(ctx context.Context, call Functio
 			  )
 		`, c.graphName, c.graphName, c.graphName)
 
-		_, err = c.db.ExecContext(ctx, query2, ucVID, call.CallerFile)
+		_, err = c.exec(ctx, query2, ucVID, call.CallerFile)
 		if err != nil {
 			return err
 		}
@@ -1039,20 +2197,65 @@ func (c *OracleGraphClient) UpsertFunctionCall(ctx context.Context, call Functio
 				INSERT INTO %s_MAKES_CALL_ET (SOURCE_VID, DEST_VID, CREATED)
 				SELECT func.VID, :1, SYSTIMESTAMP
 				FROM %s_FUNCTION_VT func
-				WHERE func.NAME = :2 AND func.FILE_PATH = :3
+				WHERE func.ID = :2
 				  AND NOT EXISTS (
 				    SELECT 1 FROM %s_MAKES_CALL_ET e3
 				    WHERE e3.SOURCE_VID = func.VID AND e3.DEST_VID = :1
 				  )
 			`, c.graphName, c.graphName, c.graphName)
 
-			_, _ = c.db.ExecContext(ctx, query3, ucVID, call.CallerFunc, call.CallerFile)
+			_, _ = c.exec(ctx, query3, ucVID, call.callerNodeID())
 		}
 	}
 
 	return nil
 }
 
+// UpsertFunctionTypes creates ACCEPTS and RETURNS edges from a function to the
+// Type, Interface and Class vertices named in its signature
+func (c *OracleGraphClient) UpsertFunctionTypes(ctx context.Context, fn FunctionEntity) error {
+	for _, target := range []string{"TYPE", "INTERFACE", "CLASS"} {
+		for _, t := range fn.acceptedTypes() {
+			query := fmt.Sprintf(`
+				MERGE INTO %[1]s_ACCEPTS_%[2]s_ET e
+				USING (
+					SELECT f.VID AS SOURCE_VID, t.VID AS DEST_VID
+					FROM %[1]s_FUNCTION_VT f, %[1]s_%[2]s_VT t
+					WHERE f.ID = :1 AND t.NAME = :2
+				) s
+				ON (e.SOURCE_VID = s.SOURCE_VID AND e.DEST_VID = s.DEST_VID)
+				WHEN MATCHED THEN
+					UPDATE SET e.PARAMETERS = :3, e.UPDATED = SYSTIMESTAMP
+				WHEN NOT MATCHED THEN
+					INSERT (SOURCE_VID, DEST_VID, PARAMETERS, CREATED)
+					VALUES (s.SOURCE_VID, s.DEST_VID, :3, SYSTIMESTAMP)
+			`, c.graphName, target)
+
+			if _, err := c.exec(ctx, query, fn.nodeID(), t.name, oracleValue(t.parameters)); err != nil {
+				return err
+			}
+		}
+
+		for _, name := range fn.returnedTypes() {
+			query := fmt.Sprintf(`
+				INSERT INTO %[1]s_RETURNS_%[2]s_ET (SOURCE_VID, DEST_VID, CREATED)
+				SELECT f.VID, t.VID, SYSTIMESTAMP
+				FROM %[1]s_FUNCTION_VT f, %[1]s_%[2]s_VT t
+				WHERE f.ID = :1 AND t.NAME = :2
+				  AND NOT EXISTS (
+					SELECT 1 FROM %[1]s_RETURNS_%[2]s_ET e
+					WHERE e.SOURCE_VID = f.VID AND e.DEST_VID = t.VID
+				  )
+			`, c.graphName, target)
+
+			if _, err := c.exec(ctx, query, fn.nodeID(), name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // UpsertTypeUsage creates a USES_TYPE edge
 func (c *OracleGraphClient) UpsertTypeUsage(ctx context.Context, usage TypeUsageEntity) error {
 	// Try to create edge to Type
@@ -1075,7 +2278,7 @@ func (c *OracleGraphClient) UpsertTypeUsage(ctx context.Context, usage TypeUsage
 			VALUES (s.SOURCE_VID, s.DEST_VID, :3, :4, :5, SYSTIMESTAMP)
 	`, c.graphName, c.graphName, c.graphName)
 
-	result, err := c.db.ExecContext(ctx, query,
+	result, err := c.exec(ctx, query,
 		usage.UsingFile, usage.UsedType,
 		usage.UsageContext, usage.UsageLocation, usage.UsingEntity)
 
@@ -1105,14 +2308,15 @@ func (c *OracleGraphClient) UpsertTypeUsage(ctx context.Context, usage TypeUsage
 			VALUES (s.SOURCE_VID, s.DEST_VID, :3, :4, :5, SYSTIMESTAMP)
 	`, c.graphName, c.graphName, c.graphName)
 
-	_, err = c.db.ExecContext(ctx, query2,
+	_, err = c.exec(ctx, query2,
 		usage.UsingFile, usage.UsedType,
 		usage.UsageContext, usage.UsageLocation, usage.UsingEntity)
 
 	return err
 }
 
-// UpsertExtends creates an EXTENDS edge
+// UpsertExtends creates an EXTENDS edge, and OVERRIDES edges between the
+// methods of two classes
 func (c *OracleGraphClient) UpsertExtends(ctx context.Context, extends ExtendsEntity) error {
 	// Try Class to Class
 	query := fmt.Sprintf(`
@@ -1127,8 +2331,11 @@ func (c *OracleGraphClient) UpsertExtends(ctx context.Context, extends ExtendsEn
 		  )
 	`, c.graphName, c.graphName, c.graphName, c.graphName)
 
-	result, err := c.db.ExecContext(ctx, query, extends.ChildName, extends.ParentName, extends.FilePath)
-	if err == nil && result != nil {
+	result, err := c.exec(ctx, query, extends.ChildName, extends.ParentName, extends.FilePath)
+	if err == nil {
+		if err := c.upsertOverrides(ctx, extends); err != nil {
+			return err
+		}
 		if rows, _ := result.RowsAffected(); rows > 0 {
 			return nil
 		}
@@ -1147,7 +2354,34 @@ func (c *OracleGraphClient) UpsertExtends(ctx context.Context, extends ExtendsEn
 		  )
 	`, c.graphName, c.graphName, c.graphName, c.graphName)
 
-	_, err = c.db.ExecContext(ctx, query2, extends.ChildName, extends.ParentName, extends.FilePath)
+	_, err = c.exec(ctx, query2, extends.ChildName, extends.ParentName, extends.FilePath)
+	return err
+}
+
+// upsertOverrides creates OVERRIDES edges from each method of the child class
+// to the method of the same name and kind in its parent class
+func (c *OracleGraphClient) upsertOverrides(ctx context.Context, extends ExtendsEntity) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %[1]s_OVERRIDES_ET (SOURCE_VID, DEST_VID, CREATED)
+		SELECT m.VID, base.VID, SYSTIMESTAMP
+		FROM %[1]s_CLASS_VT child
+		JOIN %[1]s_EXTENDS_ET e ON e.SOURCE_VID = child.VID
+		JOIN %[1]s_CLASS_VT parent ON parent.VID = e.DEST_VID
+		JOIN %[1]s_HAS_METHOD_ET hm ON hm.SOURCE_VID = child.VID
+		JOIN %[1]s_METHOD_VT m ON m.VID = hm.DEST_VID
+		JOIN %[1]s_HAS_METHOD_ET hb ON hb.SOURCE_VID = parent.VID
+		JOIN %[1]s_METHOD_VT base ON base.VID = hb.DEST_VID
+		WHERE child.NAME = :1 AND child.FILE_PATH = :3
+		  AND parent.NAME = :2
+		  AND m.KIND <> 'constructor'
+		  AND base.NAME = m.NAME AND base.KIND = m.KIND AND base.IS_STATIC = m.IS_STATIC
+		  AND NOT EXISTS (
+			SELECT 1 FROM %[1]s_OVERRIDES_ET o
+			WHERE o.SOURCE_VID = m.VID AND o.DEST_VID = base.VID
+		  )
+	`, c.graphName)
+
+	_, err := c.exec(ctx, query, extends.ChildName, extends.ParentName, extends.FilePath)
 	return err
 }
 
@@ -1165,7 +2399,7 @@ func (c *OracleGraphClient) UpsertImplements(ctx context.Context, implements Imp
 		  )
 	`, c.graphName, c.graphName, c.graphName, c.graphName)
 
-	_, err := c.db.ExecContext(ctx, query,
+	_, err := c.exec(ctx, query,
 		implements.ClassName, implements.InterfaceName, implements.FilePath)
 	return err
 }
@@ -1178,6 +2412,282 @@ func (c *OracleGraphClient) UpsertReference(ctx context.Context, ref ReferenceEn
 	return nil
 }
 
+// UpsertExport creates an EXPORTS edge from a file to the vertex it exports,
+// adding the exported name to the edge's NAMES
+func (c *OracleGraphClient) UpsertExport(ctx context.Context, export ExportEntity) error {
+	label, key := export.target()
+	if label == "" {
+		return nil
+	}
+
+	// Match the exported vertex by the keys the other backends merge it on
+	args := []any{export.FilePath}
+	var match string
+	switch label {
+	case "Function", "Class":
+		match = "t.ID = :2"
+		args = append(args, key["id"])
+	case "File":
+		match = "t.PATH = :2"
+		args = append(args, key["path"])
+	default:
+		match = "t.NAME = :2 AND t.FILE_PATH = :3"
+		args = append(args, key["name"], key["file"])
+	}
+	edges := fmt.Sprintf(`%s_EXPORTS_%s_ET`, c.graphName, strings.ToUpper(label))
+	source := fmt.Sprintf(`
+			SELECT f.VID AS SOURCE_VID, t.VID AS DEST_VID
+			FROM %[1]s_FILE_VT f, %[1]s_%[2]s_VT t
+			WHERE f.PATH = :1 AND %[3]s`, c.graphName, strings.ToUpper(label), match)
+
+	// Read the names already exported so this one is added to them
+	var stored sql.NullString
+	query := fmt.Sprintf(`
+		SELECT e.NAMES FROM %s e, (%s) s
+		WHERE e.SOURCE_VID = s.SOURCE_VID AND e.DEST_VID = s.DEST_VID
+	`, edges, source)
+	err := c.queryRow(ctx, query, args...).Scan(&stored)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to read export names: %w", err)
+	}
+	var names []string
+	if stored.Valid {
+		if err := json.Unmarshal([]byte(stored.String), &names); err != nil {
+			return fmt.Errorf("failed to decode export names: %w", err)
+		}
+	}
+	if !slices.Contains(names, export.Name) {
+		names = append(names, export.Name)
+	}
+	encoded, err := oracleJSON(names)
+	if err != nil {
+		return err
+	}
+
+	query = fmt.Sprintf(`
+		MERGE INTO %[1]s e
+		USING (%[2]s
+		) s
+		ON (e.SOURCE_VID = s.SOURCE_VID AND e.DEST_VID = s.DEST_VID)
+		WHEN MATCHED THEN
+			UPDATE SET e.NAMES = :%[3]d, e.UPDATED = SYSTIMESTAMP
+		WHEN NOT MATCHED THEN
+			INSERT (SOURCE_VID, DEST_VID, NAMES, CREATED)
+			VALUES (s.SOURCE_VID, s.DEST_VID, :%[3]d, SYSTIMESTAMP)
+	`, edges, source, len(args)+1)
+
+	_, err = c.exec(ctx, query, append(args, encoded)...)
+	return err
+}
+
+// File Lifecycle Operations
+
+// oracleIncomingEdges lists, per owned node label, the edge tables whose rows
+// from other files point at that label's vertices.
+var oracleIncomingEdges = map[string][]string{
+	"Function":  {"CALLS_ET", "EXPORTS_FUNCTION_ET", "COMPONENT_USES_HOOK_FUNCTION_ET"},
+	"Variable":  {"EXPORTS_VARIABLE_ET"},
+	"Type":      {"USES_TYPE_ET", "ACCEPTS_TYPE_ET", "RETURNS_TYPE_ET", "EXPORTS_TYPE_ET"},
+	"Interface": {"USES_TYPE_ET", "EXTENDS_ET", "IMPLEMENTS_ET", "ACCEPTS_INTERFACE_ET", "RETURNS_INTERFACE_ET", "EXPORTS_INTERFACE_ET"},
+	"Class":     {"EXTENDS_ET", "ACCEPTS_CLASS_ET", "RETURNS_CLASS_ET", "EXPORTS_CLASS_ET"},
+	"Method":    {"OVERRIDES_ET"},
+	"Component": {"COMPONENT_RENDERS_ET"},
+	"CSSRule":   {"JSXELEMENT_STYLED_BY_ET", "COMPONENT_STYLED_BY_ET", "CSSRULE_INCLUDES_ET", "CSSRULE_EXTENDS_ET"},
+}
+
+// DeleteFile removes a File vertex and every vertex and edge owned by it in a
+// single transaction.
+func (c *OracleGraphClient) DeleteFile(ctx context.Context, path string) error {
+	return c.inTransaction(ctx, func(bound *OracleGraphClient) error {
+		if err := bound.deleteFileContents(ctx, path, nil); err != nil {
+			return err
+		}
+
+		queries := []string{
+			fmt.Sprintf(`DELETE FROM %[1]s_IMPORTS_FILE_ET WHERE DEST_VID IN (SELECT VID FROM %[1]s_FILE_VT WHERE PATH = :1)`, c.graphName),
+			fmt.Sprintf(`DELETE FROM %[1]s_EXPORTS_FILE_ET WHERE DEST_VID IN (SELECT VID FROM %[1]s_FILE_VT WHERE PATH = :1)`, c.graphName),
+			fmt.Sprintf(`DELETE FROM %s_FILE_VT WHERE PATH = :1`, c.graphName),
+		}
+		for _, query := range queries {
+			if _, err := bound.exec(ctx, query, path); err != nil {
+				return fmt.Errorf("failed to delete file vertex: %w", err)
+			}
+		}
+		return bound.deleteOrphanImports(ctx)
+	})
+}
+
+// ReplaceFileEntities swaps the graph contents of pf.FilePath for pf in a
+// single transaction. Vertices the new parse still defines are updated in
+// place so edges from other files keep pointing at them.
+func (c *OracleGraphClient) ReplaceFileEntities(ctx context.Context, pf ParsedFile) error {
+	return c.inTransaction(ctx, func(bound *OracleGraphClient) error {
+		if err := bound.deleteFileContents(ctx, pf.FilePath, &pf); err != nil {
+			return err
+		}
+		if err := upsertParsedFile(ctx, bound, pf); err != nil {
+			return err
+		}
+		return bound.deleteOrphanImports(ctx)
+	})
+}
+
+// inTransaction runs fn with a copy of the client bound to a new transaction,
+// committing if fn succeeds.
+func (c *OracleGraphClient) inTransaction(ctx context.Context, fn func(bound *OracleGraphClient) error) error {
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(&OracleGraphClient{db: c.db, graphName: c.graphName, tx: tx}); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// deleteFileContents removes the edges leaving path and its owned vertices.
+// When keep is set, owned vertices it still defines are left in place.
+func (c *OracleGraphClient) deleteFileContents(ctx context.Context, path string, keep *ParsedFile) error {
+	fileVIDs := fmt.Sprintf(`SELECT VID FROM %s_FILE_VT WHERE PATH = :1`, c.graphName)
+	ownedVIDs := func(table string) string {
+		return fmt.Sprintf(`SELECT VID FROM %s_%s WHERE FILE_PATH = :1`, c.graphName, table)
+	}
+
+	// Edges are keyed by the vertex table declared for each end in the property graph
+	edges := []struct{ table, column, vids string }{
+		{"IMPORTS_ET", "SOURCE_VID", fileVIDs},
+		{"IMPORTS_FILE_ET", "SOURCE_VID", fileVIDs},
+		{"IMPORTS_PACKAGE_ET", "SOURCE_VID", fileVIDs},
+		{"USES_TYPE_ET", "SOURCE_VID", fileVIDs},
+		{"CONTAINS_CALL_ET", "SOURCE_VID", fileVIDs},
+		{"BELONGS_TO_ET", "DEST_VID", fileVIDs},
+		{"DEFINED_IN_ET", "DEST_VID", fileVIDs},
+		{"USED_IN_ET", "DEST_VID", fileVIDs},
+		{"CALLS_ET", "SOURCE_VID", ownedVIDs("FUNCTION_VT")},
+		{"RENDERS_ET", "SOURCE_VID", ownedVIDs("FUNCTION_VT")},
+		{"MAKES_CALL_ET", "SOURCE_VID", ownedVIDs("FUNCTION_VT")},
+		{"ACCEPTS_TYPE_ET", "SOURCE_VID", ownedVIDs("FUNCTION_VT")},
+		{"ACCEPTS_INTERFACE_ET", "SOURCE_VID", ownedVIDs("FUNCTION_VT")},
+		{"ACCEPTS_CLASS_ET", "SOURCE_VID", ownedVIDs("FUNCTION_VT")},
+		{"RETURNS_TYPE_ET", "SOURCE_VID", ownedVIDs("FUNCTION_VT")},
+		{"RETURNS_INTERFACE_ET", "SOURCE_VID", ownedVIDs("FUNCTION_VT")},
+		{"RETURNS_CLASS_ET", "SOURCE_VID", ownedVIDs("FUNCTION_VT")},
+		{"IMPLEMENTS_ET", "SOURCE_VID", ownedVIDs("CLASS_VT")},
+		{"EXTENDS_ET", "SOURCE_VID", ownedVIDs("CLASS_VT")},
+		{"EXTENDS_ET", "SOURCE_VID", ownedVIDs("INTERFACE_VT")},
+		{"HAS_METHOD_ET", "SOURCE_VID", ownedVIDs("CLASS_VT")},
+		{"HAS_PROPERTY_ET", "SOURCE_VID", ownedVIDs("CLASS_VT")},
+		{"IMPLEMENTED_BY_ET", "SOURCE_VID", ownedVIDs("METHOD_VT")},
+		{"OVERRIDES_ET", "SOURCE_VID", ownedVIDs("METHOD_VT")},
+		{"DECORATED_BY_CLASS_ET", "DEST_VID", ownedVIDs("DECORATOR_VT")},
+		{"DECORATED_BY_METHOD_ET", "DEST_VID", ownedVIDs("DECORATOR_VT")},
+		{"DECORATED_BY_PROPERTY_ET", "DEST_VID", ownedVIDs("DECORATOR_VT")},
+		{"EXPORTS_FUNCTION_ET", "SOURCE_VID", fileVIDs},
+		{"EXPORTS_CLASS_ET", "SOURCE_VID", fileVIDs},
+		{"EXPORTS_INTERFACE_ET", "SOURCE_VID", fileVIDs},
+		{"EXPORTS_TYPE_ET", "SOURCE_VID", fileVIDs},
+		{"EXPORTS_VARIABLE_ET", "SOURCE_VID", fileVIDs},
+		{"EXPORTS_FILE_ET", "SOURCE_VID", fileVIDs},
+		{"COMPONENT_DEFINED_IN_ET", "SOURCE_VID", ownedVIDs("COMPONENT_VT")},
+		{"COMPONENT_IMPLEMENTED_BY_FUNCTION_ET", "SOURCE_VID", ownedVIDs("COMPONENT_VT")},
+		{"COMPONENT_IMPLEMENTED_BY_CLASS_ET", "SOURCE_VID", ownedVIDs("COMPONENT_VT")},
+		{"COMPONENT_RENDERS_ET", "SOURCE_VID", ownedVIDs("COMPONENT_VT")},
+		{"COMPONENT_USES_HOOK_ET", "SOURCE_VID", ownedVIDs("COMPONENT_VT")},
+		{"COMPONENT_USES_HOOK_FUNCTION_ET", "SOURCE_VID", ownedVIDs("COMPONENT_VT")},
+		{"JSXELEMENT_STYLED_BY_ET", "SOURCE_VID", ownedVIDs("JSXELEMENT_VT")},
+		{"COMPONENT_STYLED_BY_ET", "SOURCE_VID", ownedVIDs("COMPONENT_VT")},
+		{"CSSRULE_INCLUDES_ET", "SOURCE_VID", ownedVIDs("CSSRULE_VT")},
+		{"CSSRULE_EXTENDS_ET", "SOURCE_VID", ownedVIDs("CSSRULE_VT")},
+	}
+	for _, edge := range edges {
+		query := fmt.Sprintf(`DELETE FROM %s_%s WHERE %s IN (%s)`, c.graphName, edge.table, edge.column, edge.vids)
+		if _, err := c.exec(ctx, query, path); err != nil {
+			return fmt.Errorf("failed to delete %s edges: %w", edge.table, err)
+		}
+	}
+
+	vertices := []string{
+		fmt.Sprintf(`DELETE FROM %s_JSXELEMENT_VT WHERE FILE_PATH = :1`, c.graphName),
+		fmt.Sprintf(`DELETE FROM %s_DECORATOR_VT WHERE FILE_PATH = :1`, c.graphName),
+		fmt.Sprintf(`DELETE FROM %s_UNRESOLVED_CALL_VT WHERE CALLER_FILE = :1`, c.graphName),
+	}
+	for _, query := range vertices {
+		if _, err := c.exec(ctx, query, path); err != nil {
+			return fmt.Errorf("failed to delete file vertices: %w", err)
+		}
+	}
+
+	for _, node := range fileOwnedNodes {
+		table := strings.ToUpper(node.label) + "_VT"
+		stale, err := c.staleVIDs(ctx, node, path, keep)
+		if err != nil {
+			return err
+		}
+		for _, vid := range stale {
+			for _, edgeTable := range oracleIncomingEdges[node.label] {
+				query := fmt.Sprintf(`DELETE FROM %s_%s WHERE DEST_VID = :1`, c.graphName, edgeTable)
+				if _, err := c.exec(ctx, query, vid); err != nil {
+					return fmt.Errorf("failed to delete %s edges: %w", edgeTable, err)
+				}
+			}
+			query := fmt.Sprintf(`DELETE FROM %s_%s WHERE VID = :1`, c.graphName, table)
+			if _, err := c.exec(ctx, query, vid); err != nil {
+				return fmt.Errorf("failed to delete %s vertex: %w", node.label, err)
+			}
+		}
+	}
+	return nil
+}
+
+// staleVIDs returns the VIDs of path's node vertices whose key keep no longer
+// defines, or all of them when keep is nil. It must run inside inTransaction.
+func (c *OracleGraphClient) staleVIDs(ctx context.Context, node ownedNode, path string, keep *ParsedFile) ([]int64, error) {
+	label := node.label
+	kept := make(map[string]bool)
+	if keep != nil {
+		for _, key := range keep.ownedKeys(label) {
+			kept[key] = true
+		}
+	}
+
+	query := fmt.Sprintf(`SELECT VID, %s FROM %s_%s_VT WHERE FILE_PATH = :1`,
+		strings.ToUpper(node.key), c.graphName, strings.ToUpper(label))
+	rows, err := c.tx.QueryContext(ctx, query, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s vertices: %w", label, err)
+	}
+	defer rows.Close()
+
+	var stale []int64
+	for rows.Next() {
+		var vid int64
+		var key string
+		if err := rows.Scan(&vid, &key); err != nil {
+			return nil, fmt.Errorf("failed to scan %s vertex: %w", label, err)
+		}
+		if !kept[key] {
+			stale = append(stale, vid)
+		}
+	}
+	return stale, rows.Err()
+}
+
+// deleteOrphanImports removes Import and Package vertices no file imports anymore.
+func (c *OracleGraphClient) deleteOrphanImports(ctx context.Context) error {
+	queries := []string{
+		fmt.Sprintf(`DELETE FROM %[1]s_IMPORT_VT v WHERE NOT EXISTS (SELECT 1 FROM %[1]s_IMPORTS_ET e WHERE e.DEST_VID = v.VID)`, c.graphName),
+		fmt.Sprintf(`DELETE FROM %[1]s_PACKAGE_VT v WHERE NOT EXISTS (SELECT 1 FROM %[1]s_IMPORTS_PACKAGE_ET e WHERE e.DEST_VID = v.VID)`, c.graphName),
+	}
+	for _, query := range queries {
+		if _, err := c.exec(ctx, query); err != nil {
+			return fmt.Errorf("failed to delete orphan imports: %w", err)
+		}
+	}
+	return nil
+}
+
 // Utility Operations
 
 // CreateIndexes creates recommended indexes for better query performance
@@ -1190,9 +2700,17 @@ func (c *OracleGraphClient) CreateIndexes(ctx context.Context) error {
 		{fmt.Sprintf("%s_FUNCTION_VT", c.graphName), "NAME"},
 		{fmt.Sprintf("%s_FUNCTION_VT", c.graphName), "FILE_PATH"},
 		{fmt.Sprintf("%s_IMPORT_VT", c.graphName), "MODULE"},
+		{fmt.Sprintf("%s_PACKAGE_VT", c.graphName), "NAME"},
 		{fmt.Sprintf("%s_TYPE_VT", c.graphName), "NAME"},
 		{fmt.Sprintf("%s_CLASS_VT", c.graphName), "NAME"},
+		{fmt.Sprintf("%s_METHOD_VT", c.graphName), "NAME"},
+		{fmt.Sprintf("%s_METHOD_VT", c.graphName), "FILE_PATH"},
+		{fmt.Sprintf("%s_PROPERTY_VT", c.graphName), "FILE_PATH"},
 		{fmt.Sprintf("%s_INTERFACE_VT", c.graphName), "NAME"},
+		{fmt.Sprintf("%s_DECORATOR_VT", c.graphName), "NAME"},
+		{fmt.Sprintf("%s_DECORATOR_VT", c.graphName), "FILE_PATH"},
+		{fmt.Sprintf("%s_COMPONENT_VT", c.graphName), "NAME"},
+		{fmt.Sprintf("%s_COMPONENT_VT", c.graphName), "FILE_PATH"},
 		{fmt.Sprintf("%s_JSXELEMENT_VT", c.graphName), "TAG_NAME"},
 		{fmt.Sprintf("%s_CSSRULE_VT", c.graphName), "SELECTOR"},
 		{fmt.Sprintf("%s_UNRESOLVED_CALL_VT", c.graphName), "CALLED_FUNC"},
@@ -1201,6 +2719,10 @@ func (c *OracleGraphClient) CreateIndexes(ctx context.Context) error {
 		{fmt.Sprintf("%s_BELONGS_TO_ET", c.graphName), "DEST_VID"},
 		{fmt.Sprintf("%s_IMPORTS_ET", c.graphName), "SOURCE_VID"},
 		{fmt.Sprintf("%s_IMPORTS_ET", c.graphName), "DEST_VID"},
+		{fmt.Sprintf("%s_IMPORTS_FILE_ET", c.graphName), "SOURCE_VID"},
+		{fmt.Sprintf("%s_IMPORTS_FILE_ET", c.graphName), "DEST_VID"},
+		{fmt.Sprintf("%s_IMPORTS_PACKAGE_ET", c.graphName), "SOURCE_VID"},
+		{fmt.Sprintf("%s_IMPORTS_PACKAGE_ET", c.graphName), "DEST_VID"},
 		{fmt.Sprintf("%s_CALLS_ET", c.graphName), "SOURCE_VID"},
 		{fmt.Sprintf("%s_CALLS_ET", c.graphName), "DEST_VID"},
 		{fmt.Sprintf("%s_USES_TYPE_ET", c.graphName), "SOURCE_VID"},
@@ -1209,6 +2731,15 @@ func (c *OracleGraphClient) CreateIndexes(ctx context.Context) error {
 		{fmt.Sprintf("%s_EXTENDS_ET", c.graphName), "DEST_VID"},
 		{fmt.Sprintf("%s_IMPLEMENTS_ET", c.graphName), "SOURCE_VID"},
 		{fmt.Sprintf("%s_IMPLEMENTS_ET", c.graphName), "DEST_VID"},
+		{fmt.Sprintf("%s_HAS_METHOD_ET", c.graphName), "SOURCE_VID"},
+		{fmt.Sprintf("%s_HAS_PROPERTY_ET", c.graphName), "SOURCE_VID"},
+		{fmt.Sprintf("%s_OVERRIDES_ET", c.graphName), "DEST_VID"},
+		{fmt.Sprintf("%s_ACCEPTS_TYPE_ET", c.graphName), "DEST_VID"},
+		{fmt.Sprintf("%s_ACCEPTS_INTERFACE_ET", c.graphName), "DEST_VID"},
+		{fmt.Sprintf("%s_ACCEPTS_CLASS_ET", c.graphName), "DEST_VID"},
+		{fmt.Sprintf("%s_RETURNS_TYPE_ET", c.graphName), "DEST_VID"},
+		{fmt.Sprintf("%s_RETURNS_INTERFACE_ET", c.graphName), "DEST_VID"},
+		{fmt.Sprintf("%s_RETURNS_CLASS_ET", c.graphName), "DEST_VID"},
 	}
 
 	for _, idx := range indexes {
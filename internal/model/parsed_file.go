@@ -0,0 +1,412 @@
+// internal/model/parsed_file.go
+
+package model
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+)
+
+// ParsedFile holds normalized entities extracted from a single source file.
+type ParsedFile struct {
+	FilePath string `json:"filePath"`
+	Language string `json:"language"`
+
+	// Entity collections
+	Funcs       []FunctionEntity   `json:"funcs,omitempty"`
+	Imports     []ImportEntity     `json:"imports,omitempty"`
+	Variables   []VariableEntity   `json:"variables,omitempty"`
+	Types       []TypeEntity       `json:"types,omitempty"`
+	Interfaces  []InterfaceEntity  `json:"interfaces,omitempty"`
+	Classes     []ClassEntity      `json:"classes,omitempty"`
+	Members     []MemberEntity     `json:"members,omitempty"`
+	Decorators  []DecoratorEntity  `json:"decorators,omitempty"`
+	Components  []ComponentEntity  `json:"components,omitempty"`
+	Constants   []ConstantEntity   `json:"constants,omitempty"`
+	JSXElements []JSXElementEntity `json:"jsxElements,omitempty"`
+	CSSRules    []CSSRuleEntity    `json:"cssRules,omitempty"`
+
+	// Relationship collections
+	FunctionCalls   []FunctionCallEntity   `json:"functionCalls,omitempty"`
+	TypeUsages      []TypeUsageEntity      `json:"typeUsages,omitempty"`
+	Extends         []ExtendsEntity        `json:"extends,omitempty"`
+	Implements      []ImplementsEntity     `json:"implements,omitempty"`
+	References      []ReferenceEntity      `json:"references,omitempty"`
+	Exports         []ExportEntity         `json:"exports,omitempty"`
+	Renders         []RenderEntity         `json:"renders,omitempty"`
+	HookUsages      []HookUsageEntity      `json:"hookUsages,omitempty"`
+	ClassUsages     []ClassUsageEntity     `json:"classUsages,omitempty"`
+	StyleReferences []StyleReferenceEntity `json:"styleReferences,omitempty"`
+}
+
+// RelativeTo rewrites every file path held by pf, including resolved import
+// and call targets and the entity IDs built from them, relative to root.
+func (pf *ParsedFile) RelativeTo(root string) {
+	rel := func(path string) string {
+		if path == "" {
+			return path
+		}
+		if r, err := filepath.Rel(root, path); err == nil {
+			return r
+		}
+		return path
+	}
+
+	pf.FilePath = rel(pf.FilePath)
+	for i := range pf.Funcs {
+		fn := &pf.Funcs[i]
+		fn.ID = rebaseID(fn.ID, fn.FilePath, rel(fn.FilePath))
+		fn.FilePath = rel(fn.FilePath)
+	}
+	for i := range pf.Imports {
+		pf.Imports[i].FilePath = rel(pf.Imports[i].FilePath)
+		pf.Imports[i].ResolvedFile = rel(pf.Imports[i].ResolvedFile)
+	}
+	for i := range pf.Variables {
+		pf.Variables[i].FilePath = rel(pf.Variables[i].FilePath)
+	}
+	for i := range pf.Types {
+		pf.Types[i].FilePath = rel(pf.Types[i].FilePath)
+	}
+	for i := range pf.Interfaces {
+		pf.Interfaces[i].FilePath = rel(pf.Interfaces[i].FilePath)
+	}
+	for i := range pf.Classes {
+		class := &pf.Classes[i]
+		class.ID = rebaseID(class.ID, class.FilePath, rel(class.FilePath))
+		class.FilePath = rel(class.FilePath)
+	}
+	for i := range pf.Members {
+		m := &pf.Members[i]
+		m.ID = rebaseID(m.ID, m.FilePath, rel(m.FilePath))
+		m.ClassID = rebaseID(m.ClassID, m.FilePath, rel(m.FilePath))
+		m.FilePath = rel(m.FilePath)
+	}
+	for i := range pf.Decorators {
+		d := &pf.Decorators[i]
+		d.ID = rebaseID(d.ID, d.FilePath, rel(d.FilePath))
+		d.TargetID = rebaseID(d.TargetID, d.FilePath, rel(d.FilePath))
+		d.FilePath = rel(d.FilePath)
+	}
+	for i := range pf.Components {
+		c := &pf.Components[i]
+		c.ID = rebaseID(c.ID, c.FilePath, rel(c.FilePath))
+		c.FilePath = rel(c.FilePath)
+	}
+	for i := range pf.Constants {
+		pf.Constants[i].FilePath = rel(pf.Constants[i].FilePath)
+	}
+	for i := range pf.JSXElements {
+		pf.JSXElements[i].FilePath = rel(pf.JSXElements[i].FilePath)
+	}
+	for i := range pf.CSSRules {
+		pf.CSSRules[i].FilePath = rel(pf.CSSRules[i].FilePath)
+	}
+	for i := range pf.FunctionCalls {
+		call := &pf.FunctionCalls[i]
+		call.CallerID = rebaseID(call.CallerID, call.CallerFile, rel(call.CallerFile))
+		call.TargetID = rebaseID(call.TargetID, call.TargetFile, rel(call.TargetFile))
+		call.CallerFile = rel(call.CallerFile)
+		call.TargetFile = rel(call.TargetFile)
+	}
+	for i := range pf.TypeUsages {
+		pf.TypeUsages[i].UsingFile = rel(pf.TypeUsages[i].UsingFile)
+	}
+	for i := range pf.Extends {
+		pf.Extends[i].FilePath = rel(pf.Extends[i].FilePath)
+	}
+	for i := range pf.Implements {
+		pf.Implements[i].FilePath = rel(pf.Implements[i].FilePath)
+	}
+	for i := range pf.References {
+		pf.References[i].SourceFile = rel(pf.References[i].SourceFile)
+	}
+	for i := range pf.Exports {
+		e := &pf.Exports[i]
+		e.TargetID = rebaseID(e.TargetID, e.TargetFile, rel(e.TargetFile))
+		e.FilePath = rel(e.FilePath)
+		e.ResolvedFile = rel(e.ResolvedFile)
+		e.TargetFile = rel(e.TargetFile)
+	}
+	for i := range pf.Renders {
+		r := &pf.Renders[i]
+		r.ComponentID = rebaseID(r.ComponentID, r.FilePath, rel(r.FilePath))
+		r.TargetID = rebaseID(r.TargetID, r.TargetFile, rel(r.TargetFile))
+		r.FilePath = rel(r.FilePath)
+		r.TargetFile = rel(r.TargetFile)
+	}
+	for i := range pf.HookUsages {
+		h := &pf.HookUsages[i]
+		h.ComponentID = rebaseID(h.ComponentID, h.FilePath, rel(h.FilePath))
+		h.TargetID = rebaseID(h.TargetID, h.TargetFile, rel(h.TargetFile))
+		h.FilePath = rel(h.FilePath)
+		h.TargetFile = rel(h.TargetFile)
+	}
+	for i := range pf.ClassUsages {
+		u := &pf.ClassUsages[i]
+		u.ComponentID = rebaseID(u.ComponentID, u.FilePath, rel(u.FilePath))
+		u.FilePath = rel(u.FilePath)
+		u.ResolvedFile = rel(u.ResolvedFile)
+	}
+	for i := range pf.StyleReferences {
+		ref := &pf.StyleReferences[i]
+		ref.FilePath = rel(ref.FilePath)
+		ref.TargetFile = rel(ref.TargetFile)
+	}
+}
+
+// Clone returns a copy of pf whose entity and relationship collections can be
+// changed without affecting pf. Class method lists are copied as well; other
+// values nested within entities, such as parameter lists, are shared.
+func (pf ParsedFile) Clone() ParsedFile {
+	pf.Funcs = slices.Clone(pf.Funcs)
+	pf.Imports = slices.Clone(pf.Imports)
+	pf.Variables = slices.Clone(pf.Variables)
+	pf.Types = slices.Clone(pf.Types)
+	pf.Interfaces = slices.Clone(pf.Interfaces)
+	pf.Classes = slices.Clone(pf.Classes)
+	for i := range pf.Classes {
+		pf.Classes[i].Methods = slices.Clone(pf.Classes[i].Methods)
+	}
+	pf.Members = slices.Clone(pf.Members)
+	pf.Decorators = slices.Clone(pf.Decorators)
+	pf.Components = slices.Clone(pf.Components)
+	pf.Constants = slices.Clone(pf.Constants)
+	pf.JSXElements = slices.Clone(pf.JSXElements)
+	pf.CSSRules = slices.Clone(pf.CSSRules)
+
+	pf.FunctionCalls = slices.Clone(pf.FunctionCalls)
+	pf.TypeUsages = slices.Clone(pf.TypeUsages)
+	pf.Extends = slices.Clone(pf.Extends)
+	pf.Implements = slices.Clone(pf.Implements)
+	pf.References = slices.Clone(pf.References)
+	pf.Exports = slices.Clone(pf.Exports)
+	pf.Renders = slices.Clone(pf.Renders)
+	pf.HookUsages = slices.Clone(pf.HookUsages)
+	pf.ClassUsages = slices.Clone(pf.ClassUsages)
+	pf.StyleReferences = slices.Clone(pf.StyleReferences)
+	return pf
+}
+
+// ownedNode names a node label whose nodes belong to one file, and the
+// property telling them apart within it.
+type ownedNode struct {
+	label string
+	key   string
+}
+
+// fileOwnedNodes are the labels whose nodes survive a replace when the new
+// parse still defines them, so incoming edges from other files are kept.
+var fileOwnedNodes = []ownedNode{
+	{"Function", "id"},
+	{"Variable", "name"},
+	{"Type", "name"},
+	{"Interface", "name"},
+	{"Class", "id"},
+	{"Method", "id"},
+	{"Property", "id"},
+	{"Component", "id"},
+	{"Constant", "name"},
+	{"CSSRule", "selector"},
+}
+
+// ownedKeys returns the keys pf defines for label, as listed in fileOwnedNodes.
+func (pf *ParsedFile) ownedKeys(label string) []string {
+	keys := []string{}
+	switch label {
+	case "Function":
+		for _, fn := range pf.Funcs {
+			keys = append(keys, fn.nodeID())
+		}
+	case "Variable":
+		for _, v := range pf.Variables {
+			keys = append(keys, v.Name)
+		}
+	case "Type":
+		for _, t := range pf.Types {
+			keys = append(keys, t.Name)
+		}
+	case "Interface":
+		for _, i := range pf.Interfaces {
+			keys = append(keys, i.Name)
+		}
+	case "Class":
+		for _, c := range pf.Classes {
+			keys = append(keys, c.nodeID())
+		}
+	case "Method", "Property":
+		for _, m := range pf.Members {
+			if m.label() == label {
+				keys = append(keys, m.nodeID())
+			}
+		}
+	case "Component":
+		for _, c := range pf.Components {
+			keys = append(keys, c.ID)
+		}
+	case "Constant":
+		for _, c := range pf.Constants {
+			keys = append(keys, c.Name)
+		}
+	case "CSSRule":
+		for _, css := range pf.CSSRules {
+			keys = append(keys, css.Selector)
+		}
+	}
+	return keys
+}
+
+// fileWriter is the set of upserts a ParsedFile is written with.
+type fileWriter interface {
+	UpsertFile(ctx context.Context, path, language string) error
+	UpsertFunction(ctx context.Context, fn FunctionEntity) error
+	UpsertImport(ctx context.Context, imp ImportEntity) error
+	UpsertVariable(ctx context.Context, variable VariableEntity) error
+	UpsertType(ctx context.Context, typeEntity TypeEntity) error
+	UpsertInterface(ctx context.Context, iface InterfaceEntity) error
+	UpsertClass(ctx context.Context, class ClassEntity) error
+	UpsertMember(ctx context.Context, member MemberEntity) error
+	UpsertDecorator(ctx context.Context, decorator DecoratorEntity) error
+	UpsertComponent(ctx context.Context, component ComponentEntity) error
+	UpsertConstant(ctx context.Context, constant ConstantEntity) error
+	UpsertJSXElement(ctx context.Context, jsx JSXElementEntity) error
+	UpsertCSSRule(ctx context.Context, css CSSRuleEntity) error
+	UpsertFunctionCall(ctx context.Context, call FunctionCallEntity) error
+	UpsertFunctionTypes(ctx context.Context, fn FunctionEntity) error
+	UpsertTypeUsage(ctx context.Context, usage TypeUsageEntity) error
+	UpsertExtends(ctx context.Context, extends ExtendsEntity) error
+	UpsertImplements(ctx context.Context, implements ImplementsEntity) error
+	UpsertReference(ctx context.Context, ref ReferenceEntity) error
+	UpsertExport(ctx context.Context, export ExportEntity) error
+	UpsertRender(ctx context.Context, render RenderEntity) error
+	UpsertHookUsage(ctx context.Context, usage HookUsageEntity) error
+	UpsertClassUsage(ctx context.Context, usage ClassUsageEntity) error
+	UpsertStyleReference(ctx context.Context, ref StyleReferenceEntity) error
+}
+
+// upsertParsedFile writes the File node, then its entities, then its
+// relationships, stopping at the first error.
+func upsertParsedFile(ctx context.Context, w fileWriter, pf ParsedFile) error {
+	if err := w.UpsertFile(ctx, pf.FilePath, pf.Language); err != nil {
+		return err
+	}
+
+	for _, fn := range pf.Funcs {
+		if err := w.UpsertFunction(ctx, fn); err != nil {
+			return err
+		}
+	}
+	for _, imp := range pf.Imports {
+		if err := w.UpsertImport(ctx, imp); err != nil {
+			return err
+		}
+	}
+	for _, variable := range pf.Variables {
+		if err := w.UpsertVariable(ctx, variable); err != nil {
+			return err
+		}
+	}
+	for _, typeEntity := range pf.Types {
+		if err := w.UpsertType(ctx, typeEntity); err != nil {
+			return err
+		}
+	}
+	for _, iface := range pf.Interfaces {
+		if err := w.UpsertInterface(ctx, iface); err != nil {
+			return err
+		}
+	}
+	for _, class := range pf.Classes {
+		if err := w.UpsertClass(ctx, class); err != nil {
+			return err
+		}
+	}
+	for _, member := range pf.Members {
+		if err := w.UpsertMember(ctx, member); err != nil {
+			return err
+		}
+	}
+	for _, decorator := range pf.Decorators {
+		if err := w.UpsertDecorator(ctx, decorator); err != nil {
+			return err
+		}
+	}
+	for _, component := range pf.Components {
+		if err := w.UpsertComponent(ctx, component); err != nil {
+			return err
+		}
+	}
+	for _, constant := range pf.Constants {
+		if err := w.UpsertConstant(ctx, constant); err != nil {
+			return err
+		}
+	}
+	for _, jsx := range pf.JSXElements {
+		if err := w.UpsertJSXElement(ctx, jsx); err != nil {
+			return err
+		}
+	}
+	for _, css := range pf.CSSRules {
+		if err := w.UpsertCSSRule(ctx, css); err != nil {
+			return err
+		}
+	}
+
+	for _, call := range pf.FunctionCalls {
+		if err := w.UpsertFunctionCall(ctx, call); err != nil {
+			return err
+		}
+	}
+	for _, fn := range pf.Funcs {
+		if err := w.UpsertFunctionTypes(ctx, fn); err != nil {
+			return err
+		}
+	}
+	for _, usage := range pf.TypeUsages {
+		if err := w.UpsertTypeUsage(ctx, usage); err != nil {
+			return err
+		}
+	}
+	for _, extends := range pf.Extends {
+		if err := w.UpsertExtends(ctx, extends); err != nil {
+			return err
+		}
+	}
+	for _, implements := range pf.Implements {
+		if err := w.UpsertImplements(ctx, implements); err != nil {
+			return err
+		}
+	}
+	for _, ref := range pf.References {
+		if err := w.UpsertReference(ctx, ref); err != nil {
+			return err
+		}
+	}
+	for _, export := range pf.Exports {
+		if err := w.UpsertExport(ctx, export); err != nil {
+			return err
+		}
+	}
+	for _, render := range pf.Renders {
+		if err := w.UpsertRender(ctx, render); err != nil {
+			return err
+		}
+	}
+	for _, usage := range pf.HookUsages {
+		if err := w.UpsertHookUsage(ctx, usage); err != nil {
+			return err
+		}
+	}
+	for _, usage := range pf.ClassUsages {
+		if err := w.UpsertClassUsage(ctx, usage); err != nil {
+			return err
+		}
+	}
+	for _, ref := range pf.StyleReferences {
+		if err := w.UpsertStyleReference(ctx, ref); err != nil {
+			return err
+		}
+	}
+	return nil
+}
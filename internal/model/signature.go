@@ -0,0 +1,129 @@
+// internal/model/signature.go
+
+package model
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// signatureType is a type named in the signature of a function, with the
+// parameters declared with it.
+type signatureType struct {
+	name       string
+	parameters []string
+}
+
+// acceptedTypes returns the types fn's parameters refer to, in order of first
+// use, with the names of the parameters declared with each; unnamed Go
+// parameters add none. The function's own type parameters are left out.
+func (fn FunctionEntity) acceptedTypes() []signatureType {
+	var types []signatureType
+	for _, param := range fn.Parameters {
+		for _, name := range fn.signatureTypeNames(param.Type) {
+			i := slices.IndexFunc(types, func(t signatureType) bool { return t.name == name })
+			if i < 0 {
+				types = append(types, signatureType{name: name})
+				i = len(types) - 1
+			}
+			if param.Name != "" && !slices.Contains(types[i].parameters, param.Name) {
+				types[i].parameters = append(types[i].parameters, param.Name)
+			}
+		}
+	}
+	return types
+}
+
+// returnedTypes returns the types fn's return type refers to, leaving out
+// its own type parameters.
+func (fn FunctionEntity) returnedTypes() []string {
+	return fn.signatureTypeNames(fn.ReturnType)
+}
+
+// signatureTypeNames returns typeNames(typeText) without fn's type parameters.
+func (fn FunctionEntity) signatureTypeNames(typeText string) []string {
+	return slices.DeleteFunc(typeNames(typeText), func(name string) bool {
+		return slices.ContainsFunc(fn.TypeParameters, func(tp TypeParameter) bool { return tp.Name == name })
+	})
+}
+
+// parameterNames lists the names of fn's parameters.
+func (fn FunctionEntity) parameterNames() []string {
+	names := make([]string, 0, len(fn.Parameters))
+	for _, param := range fn.Parameters {
+		names = append(names, param.Name)
+	}
+	return names
+}
+
+// parameterTypes lists the declared types of fn's parameters, "" when untyped,
+// in the same order as parameterNames.
+func (fn FunctionEntity) parameterTypes() []string {
+	types := make([]string, 0, len(fn.Parameters))
+	for _, param := range fn.Parameters {
+		types = append(types, param.Type)
+	}
+	return types
+}
+
+// typeParameterNames lists fn's type parameters as written, with their
+// constraint and default, e.g. "T extends Base = Base".
+func (fn FunctionEntity) typeParameterNames() []string {
+	names := make([]string, 0, len(fn.TypeParameters))
+	for _, tp := range fn.TypeParameters {
+		name := tp.Name
+		if tp.Constraint != "" {
+			name += " extends " + tp.Constraint
+		}
+		if tp.Default != "" {
+			name += " = " + tp.Default
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// typeNames returns the names a type expression refers to, in order of first
+// occurrence: Promise and Order for Promise<Order>, User for []*User or
+// Optional[User]. Qualified names keep their last segment, as nodes are named
+// without their module, and string literal types are skipped.
+func typeNames(typeText string) []string {
+	var names []string
+	var quote rune
+	start := -1
+	flush := func(end int) {
+		if start < 0 {
+			return
+		}
+		token := typeText[start:end]
+		start = -1
+		if i := strings.LastIndex(token, "."); i >= 0 {
+			token = token[i+1:]
+		}
+		if token == "" || unicode.IsDigit(rune(token[0])) || slices.Contains(names, token) {
+			return
+		}
+		names = append(names, token)
+	}
+
+	for i, r := range typeText {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'' || r == '`':
+			flush(i)
+			quote = r
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '$' || r == '.':
+			if start < 0 {
+				start = i
+			}
+		default:
+			flush(i)
+		}
+	}
+	flush(len(typeText))
+	return names
+}
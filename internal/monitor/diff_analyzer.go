@@ -3,9 +3,12 @@
 package monitor
 
 import (
+	"context"
+	"fmt"
 	"goParse/internal/driver"
 	"goParse/internal/model"
 	"reflect"
+	"slices"
 )
 
 // DiffAnalyzer compares parsed files to determine what actually changed
@@ -23,6 +26,10 @@ type CachedParse struct {
 type EntityChanges struct {
 	FilePath string
 
+	// FirstParse is set when the file was not cached, as on its first change
+	// or after a failed update, so what the graph holds for it is unknown
+	FirstParse bool
+
 	// Entity changes
 	AddedFunctions    []model.FunctionEntity
 	ModifiedFunctions []model.FunctionEntity
@@ -32,6 +39,18 @@ type EntityChanges struct {
 	ModifiedClasses []model.ClassEntity
 	RemovedClasses  []model.ClassEntity
 
+	AddedMembers    []model.MemberEntity
+	ModifiedMembers []model.MemberEntity
+	RemovedMembers  []model.MemberEntity
+
+	AddedDecorators    []model.DecoratorEntity
+	ModifiedDecorators []model.DecoratorEntity
+	RemovedDecorators  []model.DecoratorEntity
+
+	AddedComponents    []model.ComponentEntity
+	ModifiedComponents []model.ComponentEntity
+	RemovedComponents  []model.ComponentEntity
+
 	AddedInterfaces    []model.InterfaceEntity
 	ModifiedInterfaces []model.InterfaceEntity
 	RemovedInterfaces  []model.InterfaceEntity
@@ -46,6 +65,112 @@ type EntityChanges struct {
 
 	AddedFunctionCalls   []model.FunctionCallEntity
 	RemovedFunctionCalls []model.FunctionCallEntity
+
+	AddedExports   []model.ExportEntity
+	RemovedExports []model.ExportEntity
+
+	AddedRenders   []model.RenderEntity
+	RemovedRenders []model.RenderEntity
+
+	AddedHookUsages   []model.HookUsageEntity
+	RemovedHookUsages []model.HookUsageEntity
+
+	AddedClassUsages   []model.ClassUsageEntity
+	RemovedClassUsages []model.ClassUsageEntity
+
+	AddedStyleReferences   []model.StyleReferenceEntity
+	RemovedStyleReferences []model.StyleReferenceEntity
+}
+
+// HasRemovals reports whether any entity or relationship was removed from the file
+func (c *EntityChanges) HasRemovals() bool {
+	return len(c.RemovedFunctions) > 0 || len(c.RemovedClasses) > 0 || len(c.RemovedMembers) > 0 ||
+		len(c.RemovedDecorators) > 0 || len(c.RemovedComponents) > 0 || len(c.RemovedInterfaces) > 0 ||
+		len(c.RemovedTypes) > 0 || len(c.RemovedImports) > 0 || len(c.RemovedFunctionCalls) > 0 ||
+		len(c.RemovedExports) > 0 || len(c.RemovedRenders) > 0 || len(c.RemovedHookUsages) > 0 ||
+		len(c.RemovedClassUsages) > 0 || len(c.RemovedStyleReferences) > 0
+}
+
+// Apply upserts the added and modified entities and relationships through
+// client, stopping at the first error. Removals are not applied; callers
+// replace the whole file when HasRemovals reports any.
+func (c *EntityChanges) Apply(ctx context.Context, client model.GraphClient) error {
+	for _, fn := range slices.Concat(c.AddedFunctions, c.ModifiedFunctions) {
+		if err := client.UpsertFunction(ctx, fn); err != nil {
+			return fmt.Errorf("failed to upsert function %s: %w", fn.Name, err)
+		}
+	}
+	for _, class := range slices.Concat(c.AddedClasses, c.ModifiedClasses) {
+		if err := client.UpsertClass(ctx, class); err != nil {
+			return fmt.Errorf("failed to upsert class %s: %w", class.Name, err)
+		}
+	}
+	for _, member := range slices.Concat(c.AddedMembers, c.ModifiedMembers) {
+		if err := client.UpsertMember(ctx, member); err != nil {
+			return fmt.Errorf("failed to upsert member %s.%s: %w", member.ClassName, member.Name, err)
+		}
+	}
+	for _, decorator := range slices.Concat(c.AddedDecorators, c.ModifiedDecorators) {
+		if err := client.UpsertDecorator(ctx, decorator); err != nil {
+			return fmt.Errorf("failed to upsert decorator @%s on %s: %w", decorator.Name, decorator.TargetName, err)
+		}
+	}
+	for _, component := range slices.Concat(c.AddedComponents, c.ModifiedComponents) {
+		if err := client.UpsertComponent(ctx, component); err != nil {
+			return fmt.Errorf("failed to upsert component %s: %w", component.Name, err)
+		}
+	}
+	for _, iface := range slices.Concat(c.AddedInterfaces, c.ModifiedInterfaces) {
+		if err := client.UpsertInterface(ctx, iface); err != nil {
+			return fmt.Errorf("failed to upsert interface %s: %w", iface.Name, err)
+		}
+	}
+	for _, typeEntity := range slices.Concat(c.AddedTypes, c.ModifiedTypes) {
+		if err := client.UpsertType(ctx, typeEntity); err != nil {
+			return fmt.Errorf("failed to upsert type %s: %w", typeEntity.Name, err)
+		}
+	}
+	for _, imp := range c.AddedImports {
+		if err := client.UpsertImport(ctx, imp); err != nil {
+			return fmt.Errorf("failed to upsert import %s: %w", imp.Module, err)
+		}
+	}
+	for _, call := range c.AddedFunctionCalls {
+		if err := client.UpsertFunctionCall(ctx, call); err != nil {
+			return fmt.Errorf("failed to upsert call to %s: %w", call.CalledFunc, err)
+		}
+	}
+	for _, fn := range slices.Concat(c.AddedFunctions, c.ModifiedFunctions) {
+		if err := client.UpsertFunctionTypes(ctx, fn); err != nil {
+			return fmt.Errorf("failed to upsert types of function %s: %w", fn.Name, err)
+		}
+	}
+	for _, export := range c.AddedExports {
+		if err := client.UpsertExport(ctx, export); err != nil {
+			return fmt.Errorf("failed to upsert export %s: %w", export.Name, err)
+		}
+	}
+	for _, render := range c.AddedRenders {
+		if err := client.UpsertRender(ctx, render); err != nil {
+			return fmt.Errorf("failed to upsert render of %s in %s: %w", render.TagName, render.Component, err)
+		}
+	}
+	for _, usage := range c.AddedHookUsages {
+		if err := client.UpsertHookUsage(ctx, usage); err != nil {
+			return fmt.Errorf("failed to upsert use of %s in %s: %w", usage.Hook, usage.Component, err)
+		}
+	}
+	for _, usage := range c.AddedClassUsages {
+		if err := client.UpsertClassUsage(ctx, usage); err != nil {
+			return fmt.Errorf("failed to upsert class %s on %s: %w", usage.ClassName, usage.TagName, err)
+		}
+	}
+	for _, ref := range c.AddedStyleReferences {
+		if err := client.UpsertStyleReference(ctx, ref); err != nil {
+			return fmt.Errorf("failed to upsert @%s %s in %s: %w", ref.Kind, ref.Target, ref.Source, err)
+		}
+	}
+	return nil
 }
 
 // NewDiffAnalyzer creates a new diff analyzer
@@ -55,8 +180,12 @@ func NewDiffAnalyzer() *DiffAnalyzer {
 	}
 }
 
-// AnalyzeChanges compares old and new parse results
-func (da *DiffAnalyzer) AnalyzeChanges(filePath string, newParse driver.ParsedFile) (*EntityChanges, bool) {
+// AnalyzeChanges compares old and new parse results. edited holds the lines
+// changed since the cached parse, as returned by ParseIncremental; functions,
+// classes, members and components overlapping them count as modified even
+// when none of their fields changed, as after an edit inside a body. A nil
+// edited compares fields only.
+func (da *DiffAnalyzer) AnalyzeChanges(filePath string, newParse driver.ParsedFile, edited []driver.LineRange) (*EntityChanges, bool) {
 	changes := &EntityChanges{FilePath: filePath}
 	hasChanges := false
 
@@ -64,12 +193,21 @@ func (da *DiffAnalyzer) AnalyzeChanges(filePath string, newParse driver.ParsedFi
 	cached, exists := da.cache[filePath]
 	if !exists {
 		// First time seeing this file, everything is new
+		changes.FirstParse = true
 		changes.AddedFunctions = newParse.Funcs
 		changes.AddedClasses = newParse.Classes
+		changes.AddedMembers = newParse.Members
+		changes.AddedDecorators = newParse.Decorators
+		changes.AddedComponents = newParse.Components
 		changes.AddedInterfaces = newParse.Interfaces
 		changes.AddedTypes = newParse.Types
 		changes.AddedImports = newParse.Imports
 		changes.AddedFunctionCalls = newParse.FunctionCalls
+		changes.AddedExports = newParse.Exports
+		changes.AddedRenders = newParse.Renders
+		changes.AddedHookUsages = newParse.HookUsages
+		changes.AddedClassUsages = newParse.ClassUsages
+		changes.AddedStyleReferences = newParse.StyleReferences
 
 		// Cache the parse
 		da.cache[filePath] = &CachedParse{
@@ -82,7 +220,7 @@ func (da *DiffAnalyzer) AnalyzeChanges(filePath string, newParse driver.ParsedFi
 	oldParse := cached.ParsedFile
 
 	// Analyze function changes
-	funcChanges := da.analyzeFunctionChanges(oldParse.Funcs, newParse.Funcs)
+	funcChanges := da.analyzeFunctionChanges(oldParse.Funcs, newParse.Funcs, edited)
 	if funcChanges.hasChanges() {
 		hasChanges = true
 		changes.AddedFunctions = funcChanges.added
@@ -91,7 +229,7 @@ func (da *DiffAnalyzer) AnalyzeChanges(filePath string, newParse driver.ParsedFi
 	}
 
 	// Analyze class changes
-	classChanges := da.analyzeClassChanges(oldParse.Classes, newParse.Classes)
+	classChanges := da.analyzeClassChanges(oldParse.Classes, newParse.Classes, edited)
 	if classChanges.hasChanges() {
 		hasChanges = true
 		changes.AddedClasses = classChanges.added
@@ -99,6 +237,77 @@ func (da *DiffAnalyzer) AnalyzeChanges(filePath string, newParse driver.ParsedFi
 		changes.RemovedClasses = classChanges.removed
 	}
 
+	// Analyze class member changes
+	memberChanges := da.analyzeMemberChanges(oldParse.Members, newParse.Members, edited)
+	if memberChanges.hasChanges() {
+		hasChanges = true
+		changes.AddedMembers = memberChanges.added
+		changes.ModifiedMembers = memberChanges.modified
+		changes.RemovedMembers = memberChanges.removed
+	}
+
+	// Analyze decorator changes
+	decoratorChanges := da.analyzeDecoratorChanges(oldParse.Decorators, newParse.Decorators)
+	if decoratorChanges.hasChanges() {
+		hasChanges = true
+		changes.AddedDecorators = decoratorChanges.added
+		changes.ModifiedDecorators = decoratorChanges.modified
+		changes.RemovedDecorators = decoratorChanges.removed
+	}
+
+	// Analyze component changes
+	componentChanges := da.analyzeComponentChanges(oldParse.Components, newParse.Components, edited)
+	if componentChanges.hasChanges() {
+		hasChanges = true
+		changes.AddedComponents = componentChanges.added
+		changes.ModifiedComponents = componentChanges.modified
+		changes.RemovedComponents = componentChanges.removed
+	}
+
+	// Analyze export changes
+	exportChanges := da.analyzeExportChanges(oldParse.Exports, newParse.Exports)
+	if exportChanges.hasChanges() {
+		hasChanges = true
+		changes.AddedExports = exportChanges.added
+		changes.RemovedExports = exportChanges.removed
+	}
+
+	// Analyze render and hook usage changes
+	renderChanges := analyzeEdgeChanges(oldParse.Renders, newParse.Renders, func(r model.RenderEntity) string {
+		return r.ComponentID + "|" + r.TagName + "|" + r.TargetID
+	})
+	if renderChanges.hasChanges() {
+		hasChanges = true
+		changes.AddedRenders = renderChanges.added
+		changes.RemovedRenders = renderChanges.removed
+	}
+	hookChanges := analyzeEdgeChanges(oldParse.HookUsages, newParse.HookUsages, func(h model.HookUsageEntity) string {
+		return h.ComponentID + "|" + h.Hook + "|" + h.TargetID
+	})
+	if hookChanges.hasChanges() {
+		hasChanges = true
+		changes.AddedHookUsages = hookChanges.added
+		changes.RemovedHookUsages = hookChanges.removed
+	}
+
+	// Analyze class name usages
+	classUsageChanges := analyzeEdgeChanges(oldParse.ClassUsages, newParse.ClassUsages, func(u model.ClassUsageEntity) string {
+		return fmt.Sprintf("%s|%d|%s|%s", u.TagName, u.Line, u.ClassName, u.ResolvedFile)
+	})
+	if classUsageChanges.hasChanges() {
+		hasChanges = true
+		changes.AddedClassUsages = classUsageChanges.added
+		changes.RemovedClassUsages = classUsageChanges.removed
+	}
+	styleChanges := analyzeEdgeChanges(oldParse.StyleReferences, newParse.StyleReferences, func(r model.StyleReferenceEntity) string {
+		return r.Kind + "|" + r.Source + "|" + r.Target + "|" + r.TargetFile
+	})
+	if styleChanges.hasChanges() {
+		hasChanges = true
+		changes.AddedStyleReferences = styleChanges.added
+		changes.RemovedStyleReferences = styleChanges.removed
+	}
+
 	// Analyze other entity types...
 	// (Similar analysis for interfaces, types, etc.)
 
@@ -113,25 +322,31 @@ func (da *DiffAnalyzer) AnalyzeChanges(filePath string, newParse driver.ParsedFi
 }
 
 // analyzeFunctionChanges compares function lists
-func (da *DiffAnalyzer) analyzeFunctionChanges(oldFuncs, newFuncs []model.FunctionEntity) entityDiff[model.FunctionEntity] {
+func (da *DiffAnalyzer) analyzeFunctionChanges(oldFuncs, newFuncs []model.FunctionEntity, edited []driver.LineRange) entityDiff[model.FunctionEntity] {
 	diff := entityDiff[model.FunctionEntity]{}
 
 	// Create maps for efficient lookup
+	// Functions are matched by ID, so methods sharing a name stay apart
 	oldMap := make(map[string]model.FunctionEntity)
 	for _, f := range oldFuncs {
-		oldMap[f.Name] = f
+		oldMap[f.ID] = f
 	}
 
 	newMap := make(map[string]model.FunctionEntity)
 	for _, f := range newFuncs {
-		newMap[f.Name] = f
+		newMap[f.ID] = f
 	}
 
 	// Find added and modified functions
-	for name, newFunc := range newMap {
-		if oldFunc, exists := oldMap[name]; exists {
-			// Check if modified
-			if !da.functionsEqual(oldFunc, newFunc) {
+	for id, newFunc := range newMap {
+		if oldFunc, exists := oldMap[id]; exists {
+			// Check if modified. Changed parameter or return types can leave
+			// stale ACCEPTS and RETURNS edges, which only replacing the file
+			// removes, so the old function counts as removed.
+			if !da.signatureTypesEqual(oldFunc, newFunc) {
+				diff.removed = append(diff.removed, oldFunc)
+				diff.added = append(diff.added, newFunc)
+			} else if !da.functionsEqual(oldFunc, newFunc) || overlapsEdit(edited, newFunc.StartLine, newFunc.EndLine) {
 				diff.modified = append(diff.modified, newFunc)
 			}
 		} else {
@@ -141,8 +356,8 @@ func (da *DiffAnalyzer) analyzeFunctionChanges(oldFuncs, newFuncs []model.Functi
 	}
 
 	// Find removed functions
-	for name, oldFunc := range oldMap {
-		if _, exists := newMap[name]; !exists {
+	for id, oldFunc := range oldMap {
+		if _, exists := newMap[id]; !exists {
 			diff.removed = append(diff.removed, oldFunc)
 		}
 	}
@@ -151,22 +366,22 @@ func (da *DiffAnalyzer) analyzeFunctionChanges(oldFuncs, newFuncs []model.Functi
 }
 
 // analyzeClassChanges compares class lists
-func (da *DiffAnalyzer) analyzeClassChanges(oldClasses, newClasses []model.ClassEntity) entityDiff[model.ClassEntity] {
+func (da *DiffAnalyzer) analyzeClassChanges(oldClasses, newClasses []model.ClassEntity, edited []driver.LineRange) entityDiff[model.ClassEntity] {
 	diff := entityDiff[model.ClassEntity]{}
 
 	oldMap := make(map[string]model.ClassEntity)
 	for _, c := range oldClasses {
-		oldMap[c.Name] = c
+		oldMap[c.ID] = c
 	}
 
 	newMap := make(map[string]model.ClassEntity)
 	for _, c := range newClasses {
-		newMap[c.Name] = c
+		newMap[c.ID] = c
 	}
 
-	for name, newClass := range newMap {
-		if oldClass, exists := oldMap[name]; exists {
-			if !da.classesEqual(oldClass, newClass) {
+	for id, newClass := range newMap {
+		if oldClass, exists := oldMap[id]; exists {
+			if !da.classesEqual(oldClass, newClass) || overlapsEdit(edited, newClass.StartLine, newClass.EndLine) {
 				diff.modified = append(diff.modified, newClass)
 			}
 		} else {
@@ -174,8 +389,8 @@ func (da *DiffAnalyzer) analyzeClassChanges(oldClasses, newClasses []model.Class
 		}
 	}
 
-	for name, oldClass := range oldMap {
-		if _, exists := newMap[name]; !exists {
+	for id, oldClass := range oldMap {
+		if _, exists := newMap[id]; !exists {
 			diff.removed = append(diff.removed, oldClass)
 		}
 	}
@@ -183,6 +398,187 @@ func (da *DiffAnalyzer) analyzeClassChanges(oldClasses, newClasses []model.Class
 	return diff
 }
 
+// analyzeMemberChanges compares class member lists
+func (da *DiffAnalyzer) analyzeMemberChanges(oldMembers, newMembers []model.MemberEntity, edited []driver.LineRange) entityDiff[model.MemberEntity] {
+	diff := entityDiff[model.MemberEntity]{}
+
+	oldMap := make(map[string]model.MemberEntity)
+	for _, m := range oldMembers {
+		oldMap[m.ID] = m
+	}
+
+	newMap := make(map[string]model.MemberEntity)
+	for _, m := range newMembers {
+		newMap[m.ID] = m
+	}
+
+	for id, newMember := range newMap {
+		if oldMember, exists := oldMap[id]; exists {
+			if oldMember != newMember || overlapsEdit(edited, newMember.StartLine, newMember.EndLine) {
+				diff.modified = append(diff.modified, newMember)
+			}
+		} else {
+			diff.added = append(diff.added, newMember)
+		}
+	}
+
+	for id, oldMember := range oldMap {
+		if _, exists := newMap[id]; !exists {
+			diff.removed = append(diff.removed, oldMember)
+		}
+	}
+
+	return diff
+}
+
+// analyzeDecoratorChanges compares decorator lists
+func (da *DiffAnalyzer) analyzeDecoratorChanges(oldDecorators, newDecorators []model.DecoratorEntity) entityDiff[model.DecoratorEntity] {
+	diff := entityDiff[model.DecoratorEntity]{}
+
+	oldMap := make(map[string]model.DecoratorEntity)
+	for _, d := range oldDecorators {
+		oldMap[d.ID] = d
+	}
+
+	newMap := make(map[string]model.DecoratorEntity)
+	for _, d := range newDecorators {
+		newMap[d.ID] = d
+	}
+
+	for id, newDecorator := range newMap {
+		if oldDecorator, exists := oldMap[id]; exists {
+			if !reflect.DeepEqual(oldDecorator, newDecorator) {
+				diff.modified = append(diff.modified, newDecorator)
+			}
+		} else {
+			diff.added = append(diff.added, newDecorator)
+		}
+	}
+
+	for id, oldDecorator := range oldMap {
+		if _, exists := newMap[id]; !exists {
+			diff.removed = append(diff.removed, oldDecorator)
+		}
+	}
+
+	return diff
+}
+
+// analyzeComponentChanges compares component lists
+func (da *DiffAnalyzer) analyzeComponentChanges(oldComponents, newComponents []model.ComponentEntity, edited []driver.LineRange) entityDiff[model.ComponentEntity] {
+	diff := entityDiff[model.ComponentEntity]{}
+
+	oldMap := make(map[string]model.ComponentEntity)
+	for _, c := range oldComponents {
+		oldMap[c.ID] = c
+	}
+
+	newMap := make(map[string]model.ComponentEntity)
+	for _, c := range newComponents {
+		newMap[c.ID] = c
+	}
+
+	for id, newComponent := range newMap {
+		if oldComponent, exists := oldMap[id]; exists {
+			if !reflect.DeepEqual(oldComponent, newComponent) || overlapsEdit(edited, newComponent.StartLine, newComponent.EndLine) {
+				diff.modified = append(diff.modified, newComponent)
+			}
+		} else {
+			diff.added = append(diff.added, newComponent)
+		}
+	}
+
+	for id, oldComponent := range oldMap {
+		if _, exists := newMap[id]; !exists {
+			diff.removed = append(diff.removed, oldComponent)
+		}
+	}
+
+	return diff
+}
+
+// overlapsEdit reports whether any edited range shares a line with the span
+// from start to end.
+func overlapsEdit(edited []driver.LineRange, start, end int) bool {
+	return slices.ContainsFunc(edited, func(r driver.LineRange) bool {
+		return r.Overlaps(start, end)
+	})
+}
+
+// analyzeEdgeChanges compares relationship lists by the nodes each one joins,
+// as given by key. Relationships whose properties changed are added again,
+// since upserting updates them in place.
+func analyzeEdgeChanges[T any](oldEdges, newEdges []T, key func(T) string) entityDiff[T] {
+	diff := entityDiff[T]{}
+
+	oldMap := make(map[string]T)
+	for _, e := range oldEdges {
+		oldMap[key(e)] = e
+	}
+
+	newMap := make(map[string]T)
+	for _, e := range newEdges {
+		newMap[key(e)] = e
+	}
+
+	for k, newEdge := range newMap {
+		if oldEdge, exists := oldMap[k]; !exists || !reflect.DeepEqual(oldEdge, newEdge) {
+			diff.added = append(diff.added, newEdge)
+		}
+	}
+
+	for k, oldEdge := range oldMap {
+		if _, exists := newMap[k]; !exists {
+			diff.removed = append(diff.removed, oldEdge)
+		}
+	}
+
+	return diff
+}
+
+// analyzeExportChanges compares export lists by exported name and module, as
+// every export * statement is named "*". An export that now names a different
+// declaration is both removed and added, since upserting cannot take its name
+// off the previous target.
+func (da *DiffAnalyzer) analyzeExportChanges(oldExports, newExports []model.ExportEntity) entityDiff[model.ExportEntity] {
+	diff := entityDiff[model.ExportEntity]{}
+
+	// Lines are not stored on EXPORTS, so moving an export changes nothing
+	key := func(e model.ExportEntity) model.ExportEntity {
+		e.Line = 0
+		return e
+	}
+
+	oldMap := make(map[string]model.ExportEntity)
+	for _, e := range oldExports {
+		oldMap[e.Name+"|"+e.Module] = e
+	}
+
+	newMap := make(map[string]model.ExportEntity)
+	for _, e := range newExports {
+		newMap[e.Name+"|"+e.Module] = e
+	}
+
+	for name, newExport := range newMap {
+		oldExport, exists := oldMap[name]
+		if exists && reflect.DeepEqual(key(oldExport), key(newExport)) {
+			continue
+		}
+		if exists {
+			diff.removed = append(diff.removed, oldExport)
+		}
+		diff.added = append(diff.added, newExport)
+	}
+
+	for name, oldExport := range oldMap {
+		if _, exists := newMap[name]; !exists {
+			diff.removed = append(diff.removed, oldExport)
+		}
+	}
+
+	return diff
+}
+
 // Helper types and methods
 type entityDiff[T any] struct {
 	added    []T
@@ -199,7 +595,27 @@ func (da *DiffAnalyzer) functionsEqual(f1, f2 model.FunctionEntity) bool {
 		f1.EndLine == f2.EndLine &&
 		f1.Signature == f2.Signature &&
 		f1.IsAsync == f2.IsAsync &&
-		f1.IsExport == f2.IsExport
+		f1.IsGenerator == f2.IsGenerator &&
+		f1.IsExport == f2.IsExport &&
+		reflect.DeepEqual(f1.Parameters, f2.Parameters) &&
+		reflect.DeepEqual(f1.TypeParameters, f2.TypeParameters) &&
+		reflect.DeepEqual(f1.Doc, f2.Doc)
+}
+
+// signatureTypesEqual reports whether two functions declare the same
+// parameter, return and type parameter types.
+func (da *DiffAnalyzer) signatureTypesEqual(f1, f2 model.FunctionEntity) bool {
+	types := func(fn model.FunctionEntity) []string {
+		out := []string{fn.ReturnType}
+		for _, param := range fn.Parameters {
+			out = append(out, param.Type)
+		}
+		for _, typeParam := range fn.TypeParameters {
+			out = append(out, typeParam.Name)
+		}
+		return out
+	}
+	return slices.Equal(types(f1), types(f2))
 }
 
 func (da *DiffAnalyzer) classesEqual(c1, c2 model.ClassEntity) bool {
@@ -207,7 +623,8 @@ func (da *DiffAnalyzer) classesEqual(c1, c2 model.ClassEntity) bool {
 		c1.EndLine == c2.EndLine &&
 		c1.IsExport == c2.IsExport &&
 		c1.IsAbstract == c2.IsAbstract &&
-		reflect.DeepEqual(c1.Methods, c2.Methods)
+		reflect.DeepEqual(c1.Methods, c2.Methods) &&
+		reflect.DeepEqual(c1.Doc, c2.Doc)
 }
 
 // RemoveFromCache removes a file from the cache
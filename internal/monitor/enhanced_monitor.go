@@ -5,7 +5,6 @@ package monitor
 import (
 	"context"
 	"goParse/internal/driver"
-	"goParse/internal/model"
 	"log"
 	"path/filepath"
 	"sync"
@@ -142,17 +141,60 @@ func (em *EnhancedMonitor) processFile(ctx context.Context, filePath string) {
 
 // processFileImmediate processes a file immediately
 func (em *EnhancedMonitor) processFileImmediate(ctx context.Context, filePath string) {
+	pf, edited, ok := em.parseChangedFile(filePath)
+	if !ok {
+		return
+	}
+	pf = em.resolveProject(pf)[0]
+	changes, ok := em.analyzeChanges(filePath, pf, edited)
+	if !ok {
+		return
+	}
+
+	var err error
+	if changes != nil && !changes.FirstParse && !changes.HasRemovals() {
+		log.Printf("Entity changes detected in %s, applying changes", pf.FilePath)
+		// Apply only the changes
+		err = em.applyEntityChanges(ctx, changes)
+	} else {
+		// Removals need the file's old contents dropped, and so does a file
+		// the analyzer has not seen, so replace it whole
+		log.Printf("Updating all entities in %s", pf.FilePath)
+		err = em.updateAllEntities(ctx, pf, filePath)
+	}
+	if err != nil {
+		// Leave the tracked state alone so the next event retries the file,
+		// and forget the parse so it is not mistaken for the graph's contents
+		log.Printf("Failed to update %s: %v", pf.FilePath, err)
+		em.metrics.RecordError()
+		if em.diffAnalyzer != nil {
+			em.diffAnalyzer.RemoveFromCache(filePath)
+		}
+		return
+	}
+
+	// Update file tracker
+	if err := em.fileTracker.UpdateState(filePath); err != nil {
+		log.Printf("Failed to update file state: %v", err)
+	}
+
+	log.Printf("Successfully processed file: %s", pf.FilePath)
+}
+
+// parseChangedFile parses filePath if the tracker reports it changed,
+// returning the lines edited since its previous parse.
+func (em *EnhancedMonitor) parseChangedFile(filePath string) (pf driver.ParsedFile, edited []driver.LineRange, ok bool) {
 	// Check if file has actually changed
 	changed, err := em.fileTracker.HasChanged(filePath)
 	if err != nil {
 		log.Printf("Error checking file change status: %v", err)
 		em.metrics.RecordError()
-		return
+		return pf, nil, false
 	}
 
 	if !changed {
 		log.Printf("File hasn't changed, skipping: %s", filePath)
-		return
+		return pf, nil, false
 	}
 
 	em.metrics.RecordChange()
@@ -165,146 +207,85 @@ func (em *EnhancedMonitor) processFileImmediate(ctx context.Context, filePath st
 	}
 
 	// Parse the file
-	pf, err := em.driver.Parse(filePath)
+	pf, edited, err = em.parseFileEdits(filePath)
 	if err != nil {
 		log.Printf("Failed to parse %s: %v", relPath, err)
 		em.metrics.RecordError()
-		return
+		return pf, nil, false
 	}
 
-	// Update file path to relative
-	pf.FilePath = relPath
-
-	// If diff analysis is enabled, compute diff
-	if em.diffAnalyzer != nil {
-		changes, hasChanges := em.diffAnalyzer.AnalyzeChanges(filePath, pf)
-		if !hasChanges {
-			log.Printf("No entity changes detected in %s", relPath)
-			return
-		}
+	return pf, edited, true
+}
 
-		log.Printf("Entity changes detected in %s, applying changes", relPath)
-		// Apply only the changes
-		em.applyEntityChanges(ctx, changes)
-	} else {
-		// Apply all entities (original behavior)
-		log.Printf("Updating all entities in %s", relPath)
-		em.updateAllEntities(ctx, pf, filePath)
+// analyzeChanges returns the entity changes of a parsed file when diff
+// analysis is enabled, and ok is false when its entities are unchanged.
+func (em *EnhancedMonitor) analyzeChanges(filePath string, pf driver.ParsedFile, edited []driver.LineRange) (changes *EntityChanges, ok bool) {
+	if em.diffAnalyzer == nil {
+		return nil, true
 	}
 
-	// Update file tracker
-	if err := em.fileTracker.UpdateState(filePath); err != nil {
-		log.Printf("Failed to update file state: %v", err)
+	diff, hasChanges := em.diffAnalyzer.AnalyzeChanges(filePath, pf, edited)
+	if !hasChanges {
+		log.Printf("No entity changes detected in %s", pf.FilePath)
+		return nil, false
 	}
-
-	log.Printf("Successfully processed file: %s", relPath)
+	return diff, true
 }
 
 // updateAllEntities updates all entities in a parsed file
-func (em *EnhancedMonitor) updateAllEntities(ctx context.Context, pf driver.ParsedFile, filePath string) {
+func (em *EnhancedMonitor) updateAllEntities(ctx context.Context, pf driver.ParsedFile, filePath string) error {
 	// This is the same as the original updateEntities method from the base monitor
-	em.updateEntities(ctx, em.graphClient, pf, filePath)
+	return em.updateEntities(ctx, pf, filePath)
 }
 
-// processBatch processes a batch of file changes
+// processBatch processes a batch of file changes. Removals are applied one by
+// one; changed files are parsed and then written together.
 func (em *EnhancedMonitor) processBatch(ctx context.Context, changes []FileChange) error {
 	log.Printf("Processing batch of %d changes", len(changes))
 
+	var parsed []driver.ParsedFile
+	var parsedPaths []string
+	var edits [][]driver.LineRange
 	for _, change := range changes {
 		switch change.Type {
 		case ChangeTypeCreate, ChangeTypeModify:
 			log.Printf("Batch processing file: %s", change.Path)
-			em.processFileImmediate(ctx, change.Path)
+			if pf, edited, ok := em.parseChangedFile(change.Path); ok {
+				parsed = append(parsed, pf)
+				parsedPaths = append(parsedPaths, change.Path)
+				edits = append(edits, edited)
+			}
 		case ChangeTypeDelete:
 			log.Printf("Batch processing removal: %s", change.Path)
 			em.handleRemoval(ctx, change.Path)
 		}
 	}
 
-	return nil
-}
-
-// applyEntityChanges applies only the changed entities
-func (em *EnhancedMonitor) applyEntityChanges(ctx context.Context, changes *EntityChanges) {
-	// This is much more efficient than updating everything
-	log.Printf("Applying entity changes for: %s", changes.FilePath)
-
-	// Apply based on graph client type
-	switch client := em.graphClient.(type) {
-	case *model.Neo4jClient:
-		em.applyChangesToNeo4j(ctx, client, changes)
-	case *model.AGEClient:
-		em.applyChangesToAGE(ctx, client, changes)
-	case *model.OracleGraphClient:
-		em.applyChangesToOracle(ctx, client, changes)
-	}
-}
-
-// applyChangesToNeo4j applies entity changes to Neo4j
-func (em *EnhancedMonitor) applyChangesToNeo4j(ctx context.Context, client *model.Neo4jClient, changes *EntityChanges) {
-	// Added functions
-	for _, fn := range changes.AddedFunctions {
-		if err := client.UpsertFunction(ctx, fn); err != nil {
-			log.Printf("Failed to add function %s: %v", fn.Name, err)
-		}
-	}
-
-	// Modified functions
-	for _, fn := range changes.ModifiedFunctions {
-		if err := client.UpsertFunction(ctx, fn); err != nil {
-			log.Printf("Failed to update function %s: %v", fn.Name, err)
-		}
-	}
-
-	// Removed functions - need to implement RemoveFunction
-	for _, fn := range changes.RemovedFunctions {
-		log.Printf("Function removal not implemented: %s", fn.Name)
-		// TODO: client.RemoveFunction(ctx, fn.Name, fn.FilePath)
-	}
-
-	// Similar for other entity types...
-}
-
-// applyChangesToAGE applies entity changes to Apache AGE
-func (em *EnhancedMonitor) applyChangesToAGE(ctx context.Context, client *model.AGEClient, changes *EntityChanges) {
-	// Similar to Neo4j implementation
-	// Added functions
-	for _, fn := range changes.AddedFunctions {
-		if err := client.UpsertFunction(ctx, fn); err != nil {
-			log.Printf("Failed to add function %s: %v", fn.Name, err)
+	// Resolve the batch together, so calls between its files are resolved
+	// against their new contents
+	var pfs []driver.ParsedFile
+	var paths []string
+	for i, pf := range em.resolveProject(parsed...) {
+		if _, ok := em.analyzeChanges(parsedPaths[i], pf, edits[i]); ok {
+			pfs = append(pfs, pf)
+			paths = append(paths, parsedPaths[i])
 		}
 	}
 
-	// Modified functions
-	for _, fn := range changes.ModifiedFunctions {
-		if err := client.UpsertFunction(ctx, fn); err != nil {
-			log.Printf("Failed to update function %s: %v", fn.Name, err)
-		}
+	if len(pfs) > 0 {
+		em.updateFiles(ctx, pfs, paths)
+		log.Printf("Batch updated %d files", len(pfs))
 	}
 
-	// Process other entity types similarly...
-	// TODO: Implement removal methods in AGE client
+	return nil
 }
 
-// applyChangesToOracle applies entity changes to Oracle Graph
-func (em *EnhancedMonitor) applyChangesToOracle(ctx context.Context, client *model.OracleGraphClient, changes *EntityChanges) {
-	// Similar to Neo4j implementation
-	// Added functions
-	for _, fn := range changes.AddedFunctions {
-		if err := client.UpsertFunction(ctx, fn); err != nil {
-			log.Printf("Failed to add function %s: %v", fn.Name, err)
-		}
-	}
-
-	// Modified functions
-	for _, fn := range changes.ModifiedFunctions {
-		if err := client.UpsertFunction(ctx, fn); err != nil {
-			log.Printf("Failed to update function %s: %v", fn.Name, err)
-		}
-	}
+// applyEntityChanges applies only the changed entities
+func (em *EnhancedMonitor) applyEntityChanges(ctx context.Context, changes *EntityChanges) error {
+	// This is much more efficient than updating everything
+	log.Printf("Applying entity changes for: %s", changes.FilePath)
 
-	// Process other entity types similarly...
-	// TODO: Implement removal methods in Oracle client
+	return changes.Apply(ctx, em.graphClient)
 }
 
 // watchGit monitors git for changes
@@ -335,7 +316,7 @@ func (em *EnhancedMonitor) watchGit(ctx context.Context) {
 
 			for _, change := range changes {
 				// Only process supported files
-				if !isSupportedFile(change.Path) {
+				if !em.isSupportedFile(change.Path) {
 					continue
 				}
 
@@ -424,6 +405,10 @@ func (em *EnhancedMonitor) GetStats() MonitorStats {
 		stats.CacheSize = em.diffAnalyzer.GetCacheSize()
 	}
 
+	if em.Monitor.embeddingGen != nil {
+		stats.EmbeddingCacheHits, stats.EmbeddingCacheMisses = em.Monitor.embeddingGen.CacheStats()
+	}
+
 	return stats
 }
 
@@ -437,6 +422,8 @@ type MonitorStats struct {
 	AverageProcessingTime time.Duration `json:"average_processing_time"`
 	BatchMetrics          *BatchMetrics `json:"batch_metrics,omitempty"`
 	CacheSize             int           `json:"cache_size,omitempty"`
+	EmbeddingCacheHits    int64         `json:"embedding_cache_hits,omitempty"`
+	EmbeddingCacheMisses  int64         `json:"embedding_cache_misses,omitempty"`
 }
 
 // IsRunning returns whether the monitor is running
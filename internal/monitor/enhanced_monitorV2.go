@@ -5,11 +5,12 @@ package monitor
 
 import (
 	"context"
-	"goParse/internal/model"
 	"log"
 	"path/filepath"
 	"sync"
 	"time"
+
+	"goParse/internal/driver"
 )
 
 // EnhancedMonitorV2 wraps the base monitor and intercepts all file processing
@@ -153,17 +154,59 @@ func (em *EnhancedMonitorV2) processFile(ctx context.Context, filePath string) {
 
 // processFileImmediate processes a file immediately
 func (em *EnhancedMonitorV2) processFileImmediate(ctx context.Context, filePath string) {
+	pf, edited, ok := em.parseChangedFile(filePath)
+	if !ok {
+		return
+	}
+	pf = em.baseMonitor.resolveProject(pf)[0]
+	changes, ok := em.analyzeChanges(filePath, pf, edited)
+	if !ok {
+		return
+	}
+
+	var err error
+	if changes != nil && !changes.FirstParse && !changes.HasRemovals() {
+		log.Printf("[EnhancedV2] Entity changes detected in %s, applying changes", pf.FilePath)
+		err = em.applyEntityChanges(ctx, changes)
+	} else {
+		// Removals need the file's old contents dropped, and so does a file
+		// the analyzer has not seen, so replace it whole
+		log.Printf("[EnhancedV2] Updating all entities in %s", pf.FilePath)
+		err = em.baseMonitor.updateEntities(ctx, pf, filePath)
+	}
+	if err != nil {
+		// Leave the tracked state alone so the next event retries the file,
+		// and forget the parse so it is not mistaken for the graph's contents
+		log.Printf("[EnhancedV2] Failed to update %s: %v", pf.FilePath, err)
+		em.metrics.RecordError()
+		if em.diffAnalyzer != nil {
+			em.diffAnalyzer.RemoveFromCache(filePath)
+		}
+		return
+	}
+
+	// Update file tracker
+	if err := em.baseMonitor.fileTracker.UpdateState(filePath); err != nil {
+		log.Printf("[EnhancedV2] Failed to update file state: %v", err)
+	}
+
+	log.Printf("[EnhancedV2] Successfully processed file: %s", pf.FilePath)
+}
+
+// parseChangedFile parses filePath if the tracker reports it changed,
+// returning the lines edited since its previous parse.
+func (em *EnhancedMonitorV2) parseChangedFile(filePath string) (pf driver.ParsedFile, edited []driver.LineRange, ok bool) {
 	// Check if file has actually changed
 	changed, err := em.baseMonitor.fileTracker.HasChanged(filePath)
 	if err != nil {
 		log.Printf("[EnhancedV2] Error checking file change status: %v", err)
 		em.metrics.RecordError()
-		return
+		return pf, nil, false
 	}
 
 	if !changed {
 		log.Printf("[EnhancedV2] File hasn't changed, skipping: %s", filePath)
-		return
+		return pf, nil, false
 	}
 
 	em.metrics.RecordChange()
@@ -176,114 +219,78 @@ func (em *EnhancedMonitorV2) processFileImmediate(ctx context.Context, filePath
 	}
 
 	// Parse the file
-	pf, err := em.baseMonitor.driver.Parse(filePath)
+	pf, edited, err = em.baseMonitor.parseFileEdits(filePath)
 	if err != nil {
 		log.Printf("[EnhancedV2] Failed to parse %s: %v", relPath, err)
 		em.metrics.RecordError()
-		return
+		return pf, nil, false
 	}
 
-	// Update file path to relative
-	pf.FilePath = relPath
-
-	// If diff analysis is enabled, compute diff
-	if em.diffAnalyzer != nil {
-		changes, hasChanges := em.diffAnalyzer.AnalyzeChanges(filePath, pf)
-		if !hasChanges {
-			log.Printf("[EnhancedV2] No entity changes detected in %s", relPath)
-			return
-		}
+	return pf, edited, true
+}
 
-		log.Printf("[EnhancedV2] Entity changes detected in %s, applying changes", relPath)
-		em.applyEntityChanges(ctx, changes)
-	} else {
-		// Use the original file handler logic from base monitor
-		log.Printf("[EnhancedV2] Updating all entities in %s", relPath)
-		em.baseMonitor.updateEntities(ctx, em.baseMonitor.graphClient, pf, filePath)
+// analyzeChanges returns the entity changes of a parsed file when diff
+// analysis is enabled, and ok is false when its entities are unchanged.
+func (em *EnhancedMonitorV2) analyzeChanges(filePath string, pf driver.ParsedFile, edited []driver.LineRange) (changes *EntityChanges, ok bool) {
+	if em.diffAnalyzer == nil {
+		return nil, true
 	}
 
-	// Update file tracker
-	if err := em.baseMonitor.fileTracker.UpdateState(filePath); err != nil {
-		log.Printf("[EnhancedV2] Failed to update file state: %v", err)
+	diff, hasChanges := em.diffAnalyzer.AnalyzeChanges(filePath, pf, edited)
+	if !hasChanges {
+		log.Printf("[EnhancedV2] No entity changes detected in %s", pf.FilePath)
+		return nil, false
 	}
-
-	log.Printf("[EnhancedV2] Successfully processed file: %s", relPath)
+	return diff, true
 }
 
-// processBatch processes a batch of file changes
+// processBatch processes a batch of file changes. Removals are applied one by
+// one; changed files are parsed and then written together.
 func (em *EnhancedMonitorV2) processBatch(ctx context.Context, changes []FileChange) error {
 	log.Printf("[EnhancedV2] Processing batch of %d changes", len(changes))
 
+	var parsed []driver.ParsedFile
+	var parsedPaths []string
+	var edits [][]driver.LineRange
 	for _, change := range changes {
 		switch change.Type {
 		case ChangeTypeCreate, ChangeTypeModify:
 			log.Printf("[EnhancedV2] Batch processing file: %s", change.Path)
-			em.processFileImmediate(ctx, change.Path)
+			if pf, edited, ok := em.parseChangedFile(change.Path); ok {
+				parsed = append(parsed, pf)
+				parsedPaths = append(parsedPaths, change.Path)
+				edits = append(edits, edited)
+			}
 		case ChangeTypeDelete:
 			log.Printf("[EnhancedV2] Batch processing removal: %s", change.Path)
 			em.baseMonitor.handleRemoval(ctx, change.Path)
 		}
 	}
 
-	return nil
-}
-
-// applyEntityChanges applies only the changed entities
-func (em *EnhancedMonitorV2) applyEntityChanges(ctx context.Context, changes *EntityChanges) {
-	log.Printf("[EnhancedV2] Applying entity changes for: %s", changes.FilePath)
-
-	// Apply based on graph client type
-	switch client := em.baseMonitor.graphClient.(type) {
-	case *model.Neo4jClient:
-		em.applyChangesToNeo4j(ctx, client, changes)
-	case *model.AGEClient:
-		em.applyChangesToAGE(ctx, client, changes)
-	case *model.OracleGraphClient:
-		em.applyChangesToOracle(ctx, client, changes)
-	}
-}
-
-// applyChangesToNeo4j applies entity changes to Neo4j
-func (em *EnhancedMonitorV2) applyChangesToNeo4j(ctx context.Context, client *model.Neo4jClient, changes *EntityChanges) {
-	// Added functions
-	for _, fn := range changes.AddedFunctions {
-		if err := client.UpsertFunction(ctx, fn); err != nil {
-			log.Printf("[EnhancedV2] Failed to add function %s: %v", fn.Name, err)
-		}
-	}
-
-	// Modified functions
-	for _, fn := range changes.ModifiedFunctions {
-		if err := client.UpsertFunction(ctx, fn); err != nil {
-			log.Printf("[EnhancedV2] Failed to update function %s: %v", fn.Name, err)
+	// Resolve the batch together, so calls between its files are resolved
+	// against their new contents
+	var pfs []driver.ParsedFile
+	var paths []string
+	for i, pf := range em.baseMonitor.resolveProject(parsed...) {
+		if _, ok := em.analyzeChanges(parsedPaths[i], pf, edits[i]); ok {
+			pfs = append(pfs, pf)
+			paths = append(paths, parsedPaths[i])
 		}
 	}
 
-	// Similar for other entity types...
-}
-
-// applyChangesToAGE applies entity changes to Apache AGE
-func (em *EnhancedMonitorV2) applyChangesToAGE(ctx context.Context, client *model.AGEClient, changes *EntityChanges) {
-	// Similar implementation
-	for _, fn := range changes.AddedFunctions {
-		if err := client.UpsertFunction(ctx, fn); err != nil {
-			log.Printf("[EnhancedV2] Failed to add function %s: %v", fn.Name, err)
-		}
+	if len(pfs) > 0 {
+		em.baseMonitor.updateFiles(ctx, pfs, paths)
+		log.Printf("[EnhancedV2] Batch updated %d files", len(pfs))
 	}
 
-	// Process other entity types...
+	return nil
 }
 
-// applyChangesToOracle applies entity changes to Oracle Graph
-func (em *EnhancedMonitorV2) applyChangesToOracle(ctx context.Context, client *model.OracleGraphClient, changes *EntityChanges) {
-	// Similar implementation
-	for _, fn := range changes.AddedFunctions {
-		if err := client.UpsertFunction(ctx, fn); err != nil {
-			log.Printf("[EnhancedV2] Failed to add function %s: %v", fn.Name, err)
-		}
-	}
+// applyEntityChanges applies only the changed entities
+func (em *EnhancedMonitorV2) applyEntityChanges(ctx context.Context, changes *EntityChanges) error {
+	log.Printf("[EnhancedV2] Applying entity changes for: %s", changes.FilePath)
 
-	// Process other entity types...
+	return changes.Apply(ctx, em.baseMonitor.graphClient)
 }
 
 // watchGit monitors git for changes
@@ -313,7 +320,7 @@ func (em *EnhancedMonitorV2) watchGit(ctx context.Context) {
 			}
 
 			for _, change := range changes {
-				if !isSupportedFile(change.Path) {
+				if !em.baseMonitor.isSupportedFile(change.Path) {
 					continue
 				}
 
@@ -396,6 +403,10 @@ func (em *EnhancedMonitorV2) GetStats() MonitorStats {
 		stats.CacheSize = em.diffAnalyzer.GetCacheSize()
 	}
 
+	if em.baseMonitor.embeddingGen != nil {
+		stats.EmbeddingCacheHits, stats.EmbeddingCacheMisses = em.baseMonitor.embeddingGen.CacheStats()
+	}
+
 	return stats
 }
 
@@ -9,7 +9,6 @@ import (
 	"log"
 	"os"
 	"path/filepath"
-	"strings"
 	"sync"
 	"time"
 
@@ -25,62 +24,54 @@ type Monitor struct {
 	rootPath       string
 	watcher        *fsnotify.Watcher
 	driver         *driver.TreeSitterDriver
-	graphClient    GraphClient
+	moduleResolver *driver.ModuleResolver
+	graphClient    model.GraphClient
 	embeddingGen   *embeddings.CodeEmbeddingGenerator
 	fileTracker    *FileTracker
+	batchOptions   model.BatchOptions
 	stopChan       chan struct{}
 	wg             sync.WaitGroup
+	projectMu      sync.Mutex
+	project        map[string]driver.ParsedFile // Last parse of every file, keyed by path relative to the root
 	fileHandler    func(context.Context, string)
 	eventPublisher func(MonitorEvent)
 	isRunning      bool
 	startTime      time.Time
 }
 
-// GraphClient interface that all database clients must implement
-type GraphClient interface {
-	Close(ctx context.Context) error
-	CreateIndexes(ctx context.Context) error
-	UpsertFile(ctx context.Context, path, language string) error
-	UpsertFunction(ctx context.Context, fn model.FunctionEntity) error
-	UpsertImport(ctx context.Context, imp model.ImportEntity) error
-	UpsertVariable(ctx context.Context, variable model.VariableEntity) error
-	UpsertType(ctx context.Context, typeEntity model.TypeEntity) error
-	UpsertInterface(ctx context.Context, iface model.InterfaceEntity) error
-	UpsertClass(ctx context.Context, class model.ClassEntity) error
-	UpsertConstant(ctx context.Context, constant model.ConstantEntity) error
-	UpsertJSXElement(ctx context.Context, jsx model.JSXElementEntity) error
-	UpsertCSSRule(ctx context.Context, css model.CSSRuleEntity) error
-	UpsertFunctionCall(ctx context.Context, call model.FunctionCallEntity) error
-	UpsertTypeUsage(ctx context.Context, usage model.TypeUsageEntity) error
-	UpsertExtends(ctx context.Context, extends model.ExtendsEntity) error
-	UpsertImplements(ctx context.Context, implements model.ImplementsEntity) error
-	UpsertReference(ctx context.Context, ref model.ReferenceEntity) error
-}
-
 // Config holds monitor configuration
 type Config struct {
 	RootPath     string
-	GraphClient  GraphClient
+	GraphClient  model.GraphClient
 	EmbeddingGen *embeddings.CodeEmbeddingGenerator
+	BatchOptions model.BatchOptions // Used when writing several files through a model.BatchWriter
 }
 
 // NewMonitor creates a new file monitor
 func NewMonitor(config Config) (*Monitor, error) {
 	log.Printf("[DEBUG] Creating new monitor for path: %s", config.RootPath)
 
+	tsDriver, err := driver.NewTreeSitterDriver()
+	if err != nil {
+		return nil, err
+	}
+
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
 		return nil, err
 	}
 
 	monitor := &Monitor{
-		rootPath:     config.RootPath,
-		watcher:      watcher,
-		driver:       driver.NewTreeSitterDriver(),
-		graphClient:  config.GraphClient,
-		embeddingGen: config.EmbeddingGen,
-		fileTracker:  NewFileTracker(config.RootPath),
-		stopChan:     make(chan struct{}),
+		rootPath:       config.RootPath,
+		watcher:        watcher,
+		driver:         tsDriver,
+		moduleResolver: driver.NewModuleResolver(config.RootPath),
+		graphClient:    config.GraphClient,
+		embeddingGen:   config.EmbeddingGen,
+		fileTracker:    NewFileTracker(config.RootPath),
+		batchOptions:   config.BatchOptions,
+		stopChan:       make(chan struct{}),
+		project:        make(map[string]driver.ParsedFile),
 	}
 
 	// Set default file handler
@@ -111,7 +102,7 @@ func (m *Monitor) Start(ctx context.Context) error {
 
 	// Count directories to watch
 	dirCount := 0
-	fileCount := 0
+	var files []string
 
 	// Add directories to watch
 	err := filepath.Walk(m.rootPath, func(path string, info os.FileInfo, err error) error {
@@ -122,7 +113,7 @@ func (m *Monitor) Start(ctx context.Context) error {
 
 		if info.IsDir() {
 			// Skip certain directories
-			if shouldSkipDir(info.Name()) {
+			if m.driver.SkipDir(info.Name()) {
 				log.Printf("[DEBUG] Skipping directory: %s", path)
 				return filepath.SkipDir
 			}
@@ -137,9 +128,9 @@ func (m *Monitor) Start(ctx context.Context) error {
 			return nil
 		}
 
-		// Count supported files
-		if isSupportedFile(path) {
-			fileCount++
+		// Collect supported files
+		if m.isSupportedFile(path) {
+			files = append(files, path)
 		}
 
 		return nil
@@ -149,7 +140,11 @@ func (m *Monitor) Start(ctx context.Context) error {
 		return err
 	}
 
-	log.Printf("[INFO] Monitor started - watching %d directories with %d supported files", dirCount, fileCount)
+	// Parse the project before watching so the first change is resolved
+	// against every file, as the codeparser resolves it
+	m.parseProject(files)
+
+	log.Printf("[INFO] Monitor started - watching %d directories with %d supported files", dirCount, len(files))
 
 	// Start watching
 	m.wg.Add(1)
@@ -180,6 +175,11 @@ func (m *Monitor) Stop() error {
 		return err
 	}
 
+	if m.embeddingGen != nil {
+		hits, misses := m.embeddingGen.CacheStats()
+		log.Printf("[INFO] Embedding cache: %d hits, %d misses", hits, misses)
+	}
+
 	log.Printf("[INFO] Monitor stopped successfully")
 	return nil
 }
@@ -223,7 +223,7 @@ func (m *Monitor) handleEvent(ctx context.Context, event fsnotify.Event) {
 
 		// Check if it's a directory
 		if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
-			if !shouldSkipDir(info.Name()) {
+			if !m.driver.SkipDir(info.Name()) {
 				if err := m.watcher.Add(event.Name); err != nil {
 					log.Printf("[ERROR] Failed to watch new directory %s: %v", event.Name, err)
 				} else {
@@ -236,7 +236,7 @@ func (m *Monitor) handleEvent(ctx context.Context, event fsnotify.Event) {
 			return
 		}
 
-		if !isSupportedFile(event.Name) {
+		if !m.isSupportedFile(event.Name) {
 			log.Printf("[DEBUG] Skipping unsupported file: %s", event.Name)
 			return
 		}
@@ -249,7 +249,7 @@ func (m *Monitor) handleEvent(ctx context.Context, event fsnotify.Event) {
 		}
 
 	case event.Op&fsnotify.Write == fsnotify.Write:
-		if !isSupportedFile(event.Name) {
+		if !m.isSupportedFile(event.Name) {
 			log.Printf("[DEBUG] Skipping write to unsupported file: %s", event.Name)
 			return
 		}
@@ -275,7 +275,7 @@ func (m *Monitor) handleEvent(ctx context.Context, event fsnotify.Event) {
 
 		// If the file still exists at the same path, treat this as a modification
 		if _, err := os.Stat(event.Name); err == nil {
-			if isSupportedFile(event.Name) {
+			if m.isSupportedFile(event.Name) {
 				log.Printf("[DEBUG] Treating rename as modification for: %s", event.Name)
 				m.fileHandler(ctx, event.Name)
 
@@ -319,7 +319,7 @@ func (m *Monitor) processFile(ctx context.Context, filePath string) {
 	}
 
 	// Parse the file
-	pf, err := m.driver.Parse(filePath)
+	pf, err := m.parseFile(filePath)
 	if err != nil {
 		log.Printf("[ERROR] Failed to parse %s: %v", relPath, err)
 		return
@@ -327,22 +327,12 @@ func (m *Monitor) processFile(ctx context.Context, filePath string) {
 
 	log.Printf("[DEBUG] Successfully parsed %s", relPath)
 
-	// Update file path to relative
-	pf.FilePath = relPath
-
-	// Update graph based on client type
-	switch client := m.graphClient.(type) {
-	case *model.Neo4jClient:
-		log.Printf("[DEBUG] Updating Neo4j for: %s", relPath)
-		m.updateNeo4j(ctx, client, pf, filePath)
-	case *model.AGEClient:
-		log.Printf("[DEBUG] Updating AGE for: %s", relPath)
-		m.updateAGE(ctx, client, pf, filePath)
-	case *model.OracleGraphClient:
-		log.Printf("[DEBUG] Updating Oracle for: %s", relPath)
-		m.updateOracle(ctx, client, pf, filePath)
-	default:
-		log.Printf("[ERROR] Unknown graph client type")
+	// Resolve the file against the rest of the project, then replace the
+	// file node and all of its entities
+	pf = m.resolveProject(pf)[0]
+	if err := m.updateEntities(ctx, pf, filePath); err != nil {
+		// Leave the tracked state alone so the next event retries the file
+		log.Printf("[ERROR] Failed to replace entities for %s: %v", pf.FilePath, err)
 		return
 	}
 
@@ -354,69 +344,102 @@ func (m *Monitor) processFile(ctx context.Context, filePath string) {
 	}
 }
 
-// updateNeo4j updates Neo4j with the parsed file data
-func (m *Monitor) updateNeo4j(ctx context.Context, client *model.Neo4jClient, pf driver.ParsedFile, filePath string) {
-	// Update file node
-	if err := client.UpsertFile(ctx, pf.FilePath, pf.Language); err != nil {
-		log.Printf("[ERROR] Failed to upsert file: %v", err)
-		return
-	}
-
-	// Update all entities
-	m.updateEntities(ctx, client, pf, filePath)
+// parseFile parses a file, resolves its import specifiers and rewrites every
+// path it holds relative to the monitored root.
+func (m *Monitor) parseFile(filePath string) (driver.ParsedFile, error) {
+	pf, _, err := m.parseFileEdits(filePath)
+	return pf, err
 }
 
-// updateAGE updates Apache AGE with the parsed file data
-func (m *Monitor) updateAGE(ctx context.Context, client *model.AGEClient, pf driver.ParsedFile, filePath string) {
-	// Update file node
-	if err := client.UpsertFile(ctx, pf.FilePath, pf.Language); err != nil {
-		log.Printf("[ERROR] Failed to upsert file: %v", err)
-		return
+// parseFileEdits is parseFile, also returning the lines edited since the
+// file was last parsed. The driver keeps each file's syntax tree and only
+// reparses the edited region. The parse is recorded for resolveProject.
+func (m *Monitor) parseFileEdits(filePath string) (driver.ParsedFile, []driver.LineRange, error) {
+	pf, edited, err := m.driver.ParseIncremental(filePath)
+	if err != nil {
+		return pf, nil, err
 	}
 
-	// Update all entities
-	m.updateEntities(ctx, client, pf, filePath)
+	m.moduleResolver.ResolveImports(&pf)
+	pf.RelativeTo(m.rootPath)
+
+	m.projectMu.Lock()
+	m.project[pf.FilePath] = pf
+	m.projectMu.Unlock()
+	return pf, edited, nil
 }
 
-// updateOracle updates Oracle Graph with the parsed file data
-func (m *Monitor) updateOracle(ctx context.Context, client *model.OracleGraphClient, pf driver.ParsedFile, filePath string) {
-	// Update file node
-	if err := client.UpsertFile(ctx, pf.FilePath, pf.Language); err != nil {
-		log.Printf("[ERROR] Failed to upsert file: %v", err)
-		return
-	}
+// parseProject records a parse of every file in files for resolveProject.
+// Syntax trees are not kept, so the first incremental parse of each file
+// starts from scratch.
+func (m *Monitor) parseProject(files []string) {
+	for _, filePath := range files {
+		pf, err := m.driver.Parse(filePath)
+		if err != nil {
+			log.Printf("[ERROR] Failed to parse %s: %v", filePath, err)
+			continue
+		}
+		m.moduleResolver.ResolveImports(&pf)
+		pf.RelativeTo(m.rootPath)
 
-	// Update all entities
-	m.updateEntities(ctx, client, pf, filePath)
+		m.projectMu.Lock()
+		m.project[pf.FilePath] = pf
+		m.projectMu.Unlock()
+	}
+	log.Printf("[DEBUG] Parsed %d files for cross-file resolution", len(files))
 }
 
-// updateEntities updates all entities for a parsed file
-func (m *Monitor) updateEntities(ctx context.Context, client GraphClient, pf driver.ParsedFile, filePath string) {
-	log.Printf("[DEBUG] Updating entities for: %s", pf.FilePath)
+// resolveProject runs the codeparser's project-wide passes over the last
+// parse of every file and returns pfs with their calls, exports, renders,
+// hook usages, SCSS references and Go implementations resolved across files.
+// Replacing a file drops all of its outgoing edges, so without this pass a
+// change would turn its cross-file calls back into unresolved ones. The
+// passes run on copies, leaving the recorded parses as the parser left them.
+func (m *Monitor) resolveProject(pfs ...driver.ParsedFile) []driver.ParsedFile {
+	if len(pfs) == 0 {
+		return nil
+	}
 
-	entityCount := 0
+	m.projectMu.Lock()
+	defer m.projectMu.Unlock()
 
-	// Update functions
-	for _, fn := range pf.Funcs {
-		if err := client.UpsertFunction(ctx, fn); err != nil {
-			log.Printf("[ERROR] Failed to update function %s: %v", fn.Name, err)
-		} else {
-			entityCount++
-		}
+	project := make([]driver.ParsedFile, 0, len(m.project))
+	index := make(map[string]int, len(m.project))
+	for path, pf := range m.project {
+		index[path] = len(project)
+		project = append(project, pf.Clone())
 	}
 
-	// Update imports
-	for _, imp := range pf.Imports {
-		if err := client.UpsertImport(ctx, imp); err != nil {
-			log.Printf("[ERROR] Failed to update import %s: %v", imp.Module, err)
-		} else {
-			entityCount++
+	exports := driver.ResolveProjectExports(project)
+	calls := driver.ResolveProjectCalls(project)
+	components := driver.ResolveProjectComponents(project)
+	styles := driver.ResolveProjectStyles(project)
+	implementations := driver.ResolveGoInterfaces(project)
+	log.Printf("[DEBUG] Resolved %d exports, %d calls, %d renders and hook usages, %d style references and %d implementations across %d files",
+		exports, calls, components, styles, implementations, len(project))
+
+	resolved := make([]driver.ParsedFile, len(pfs))
+	for i, pf := range pfs {
+		resolved[i] = pf
+		if j, ok := index[pf.FilePath]; ok {
+			resolved[i] = project[j]
 		}
 	}
+	return resolved
+}
+
+// updateEntities replaces the graph contents of a parsed file in one
+// transaction and then updates its embeddings. When the transaction fails the
+// embeddings are left alone and the error is returned.
+func (m *Monitor) updateEntities(ctx context.Context, pf driver.ParsedFile, filePath string) error {
+	log.Printf("[DEBUG] Updating entities for: %s", pf.FilePath)
 
-	// Update other entities similarly...
-	// (keeping the rest of the entity updates as they were)
+	if err := m.graphClient.ReplaceFileEntities(ctx, pf); err != nil {
+		return err
+	}
 
+	entityCount := len(pf.Funcs) + len(pf.Imports) + len(pf.Variables) + len(pf.Types) +
+		len(pf.Interfaces) + len(pf.Classes) + len(pf.Constants) + len(pf.JSXElements) + len(pf.CSSRules)
 	log.Printf("[INFO] Updated %d entities for %s", entityCount, pf.FilePath)
 
 	// Update embeddings if generator is available
@@ -424,6 +447,56 @@ func (m *Monitor) updateEntities(ctx context.Context, client GraphClient, pf dri
 		log.Printf("[DEBUG] Updating embeddings for: %s", pf.FilePath)
 		m.updateEmbeddings(ctx, pf, filePath)
 	}
+	return nil
+}
+
+// updateFiles replaces the graph contents of several parsed files, resolved
+// by resolveProject, and updates the file tracker. Backends implementing
+// model.BatchWriter write them together; others, and batches that fail,
+// fall back to updateEntities per file. Only files written are marked
+// processed.
+func (m *Monitor) updateFiles(ctx context.Context, pfs []driver.ParsedFile, filePaths []string) {
+	batchWriter, ok := m.graphClient.(model.BatchWriter)
+	if ok && len(pfs) > 1 {
+		log.Printf("[DEBUG] Writing %d files in one batch", len(pfs))
+
+		err := batchWriter.WriteParsedFiles(ctx, pfs, m.batchOptions)
+		if err == nil {
+			log.Printf("[INFO] Updated %d files in one batch", len(pfs))
+			if m.embeddingGen != nil {
+				for i, pf := range pfs {
+					m.updateEmbeddings(ctx, pf, filePaths[i])
+				}
+			}
+			m.updateStates(filePaths)
+			return
+		}
+
+		// Each chunk of a batch commits on its own, so a failed batch can
+		// leave files half replaced. Replace them one transaction per file.
+		log.Printf("[ERROR] Failed to write batch of %d files, replacing them one by one: %v", len(pfs), err)
+	}
+
+	// Files whose replace failed keep their state, so they are retried
+	var written []string
+	for i, pf := range pfs {
+		if err := m.updateEntities(ctx, pf, filePaths[i]); err != nil {
+			log.Printf("[ERROR] Failed to replace entities for %s: %v", pf.FilePath, err)
+			continue
+		}
+		written = append(written, filePaths[i])
+	}
+	m.updateStates(written)
+}
+
+// updateStates records the current state of files written to the graph, so
+// they are skipped until they change again.
+func (m *Monitor) updateStates(filePaths []string) {
+	for _, filePath := range filePaths {
+		if err := m.fileTracker.UpdateState(filePath); err != nil {
+			log.Printf("[ERROR] Failed to update file state for %s: %v", filePath, err)
+		}
+	}
 }
 
 // updateEmbeddings updates embeddings for the file
@@ -434,27 +507,9 @@ func (m *Monitor) updateEmbeddings(ctx context.Context, pf driver.ParsedFile, fi
 		return
 	}
 
-	parsedFileData := embeddings.ParsedFileData{
-		FilePath:    pf.FilePath,
-		Language:    pf.Language,
-		FileContent: string(fileContent),
-	}
-
-	// Convert entities to embedding format
-	for _, fn := range pf.Funcs {
-		parsedFileData.Functions = append(parsedFileData.Functions, embeddings.FunctionData{
-			Name:      fn.Name,
-			Content:   extractContent(fileContent, fn.StartLine, fn.EndLine),
-			StartLine: fn.StartLine,
-			EndLine:   fn.EndLine,
-			Signature: fn.Signature,
-			IsAsync:   fn.IsAsync,
-			IsExport:  fn.IsExport,
-		})
-	}
-
-	// Process other entities...
+	parsedFileData := embeddings.NewParsedFileData(pf, fileContent)
 
+	// ProcessFile replaces the file's previous chunks, re-embedding only the changed ones
 	if err := m.embeddingGen.ProcessFile(ctx, parsedFileData); err != nil {
 		log.Printf("[ERROR] Failed to update embeddings: %v", err)
 	} else {
@@ -471,16 +526,28 @@ func (m *Monitor) handleRemoval(ctx context.Context, filePath string) {
 		relPath = filePath
 	}
 
-	// TODO: Implement removal from graph databases
-	log.Printf("[WARNING] File removal not fully implemented for: %s", relPath)
+	// Remove the file and everything it owns from the graph
+	if err := m.graphClient.DeleteFile(ctx, relPath); err != nil {
+		log.Printf("[ERROR] Failed to remove %s from graph: %v", relPath, err)
+	} else {
+		log.Printf("[INFO] Removed %s from graph", relPath)
+	}
 
-	// Remove from file tracker
+	// Remove from file tracker and drop the file's syntax tree and parse
 	m.fileTracker.RemoveState(filePath)
+	m.driver.Forget(filePath)
+	m.projectMu.Lock()
+	delete(m.project, relPath)
+	m.projectMu.Unlock()
 	log.Printf("[DEBUG] Removed from file tracker: %s", filePath)
 
 	// Remove embeddings if available
 	if m.embeddingGen != nil {
-		log.Printf("[WARNING] Embedding removal not fully implemented for: %s", relPath)
+		if err := m.embeddingGen.DeleteFile(ctx, relPath); err != nil {
+			log.Printf("[ERROR] Failed to remove embeddings for %s: %v", relPath, err)
+		} else {
+			log.Printf("[DEBUG] Removed embeddings for: %s", relPath)
+		}
 	}
 }
 
@@ -509,59 +576,15 @@ func (m *Monitor) periodicStateSave() {
 
 // Helper functions
 
-func shouldSkipDir(name string) bool {
-	skipDirs := map[string]bool{
-		"node_modules":  true,
-		".git":          true,
-		"dist":          true,
-		"build":         true,
-		".next":         true,
-		"coverage":      true,
-		"vendor":        true,
-		".vscode":       true,
-		".idea":         true,
-		"__pycache__":   true,
-		".pytest_cache": true,
-	}
-	return skipDirs[name]
-}
-
-func isSupportedFile(path string) bool {
-	supportedExts := map[string]bool{
-		".ts":   true,
-		".tsx":  true,
-		".js":   true,
-		".jsx":  true,
-		".css":  true,
-		".scss": true,
-	}
-	ext := filepath.Ext(path)
-	supported := supportedExts[ext]
-
+// isSupportedFile reports whether the driver can parse the file at path.
+func (m *Monitor) isSupportedFile(path string) bool {
+	supported := m.driver.Supports(path)
 	if !supported {
-		log.Printf("[DEBUG] Unsupported file extension: %s for file: %s", ext, path)
+		log.Printf("[DEBUG] Unsupported file extension: %s for file: %s", filepath.Ext(path), path)
 	}
-
 	return supported
 }
 
-func extractContent(fileContent []byte, startLine, endLine int) string {
-	if startLine <= 0 || endLine <= 0 {
-		return ""
-	}
-
-	lines := strings.Split(string(fileContent), "\n")
-	if startLine > len(lines) {
-		return ""
-	}
-
-	if endLine > len(lines) {
-		endLine = len(lines)
-	}
-
-	return strings.Join(lines[startLine-1:endLine], "\n")
-}
-
 // IsRunning returns whether the monitor is running
 func (m *Monitor) IsRunning() bool {
 	return m.isRunning